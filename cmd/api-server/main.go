@@ -1,23 +1,48 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
 
+	"trl-research-backend/internal/audit"
 	"trl-research-backend/internal/config"
 	"trl-research-backend/internal/database"
+	"trl-research-backend/internal/handlers"
+	"trl-research-backend/internal/repository"
+	"trl-research-backend/internal/repository/memory"
+	"trl-research-backend/internal/repository/postgres"
 	"trl-research-backend/internal/router"
+	"trl-research-backend/internal/search"
 	"trl-research-backend/internal/storage"
+	"trl-research-backend/internal/trash"
 )
 
 func main() {
 	// Load environment variables (.env)
-	config.LoadConfig()
+	cfg := config.LoadConfig()
 
-	// init Firestore
-	database.InitFirebase("trl-research-service-account.json")
-	defer database.CloseFirebase()
+	// init storage backend
+	var repos *repository.Repositories
+	switch cfg.StorageBackend {
+	case "memory":
+		log.Println("🧪 Using in-memory storage (data is lost on restart)")
+		repos = memory.NewRepositories()
+	case "postgres":
+		if cfg.DBUrl == "" {
+			log.Fatal("❌ STORAGE_BACKEND=postgres requires DB_URL or DATABASE_URL")
+		}
+		database.InitPostgres(cfg.DBUrl)
+		defer database.ClosePostgres()
+		repos = postgres.NewRepositories(database.PostgresPool)
+	case "firestore":
+		database.InitFirebase("trl-research-service-account.json")
+		defer database.CloseFirebase()
+		repos = repository.NewFirestoreRepositories(database.FirestoreClient)
+	default:
+		log.Fatalf("❌ Unknown STORAGE_BACKEND %q (expected firestore, postgres or memory)", cfg.StorageBackend)
+	}
 
 	// Initialize GCSClient 
 	bucket := os.Getenv("GCS_BUCKET_NAME")
@@ -25,8 +50,32 @@ func main() {
 
 	gcsClient := storage.NewGCSClient(bucket, saEmail)
 
+	// search index: on disk, brought up to date now and then every SearchSyncInterval, which
+	// also picks up what other instances wrote
+	index, err := search.Open(cfg.SearchIndexPath, cfg.SearchThaiDict)
+	if err != nil {
+		log.Fatalf("❌ Failed to open the search index %q: %v", cfg.SearchIndexPath, err)
+	}
+	defer index.Close()
+	searchIndex := &handlers.SearchIndex{
+		Index:       index,
+		Cases:       repos.Case,
+		Researchers: repos.Researcher,
+		IPs:         repos.IntellectualProperty,
+	}
+	if err := searchIndex.Sync(); err != nil {
+		log.Printf("❌ search index: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go searchIndex.Run(ctx, cfg.SearchSyncInterval)
+
+	// trash: records deleted more than TRASH_RETENTION_DAYS ago go for good, now and once a day
+	purger := &trash.Purger{Repos: repos, Retention: trash.Retention(), Audit: &audit.Logger{Repo: repos.Audit}}
+	go purger.Run(ctx, trash.PurgeInterval)
+
 	// pass gcsClient here
-	r := router.SetupRouter(gcsClient)
+	r := router.SetupRouter(repos, gcsClient, searchIndex, purger)
 
 	// Run server
 	port := os.Getenv("PORT")
@@ -0,0 +1,225 @@
+// Package audit records every mutating API call in the audit log: who made it (from the JWT
+// claims the auth middleware put on the context), the record it touched, the fields that
+// changed and the request ID.
+//
+// The changes come from snapshots of the record taken by a loader before and after the handler
+// ran, so they show what was stored, not what the client sent. Which record a route touches is
+// listed in the route table (routes.go); mutating routes missing there are still logged, only
+// without an entity and changes. What a call writes besides that record, and the scheduled
+// trash purge, is recorded by the code doing it with Logger.Record.
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"trl-research-backend/internal/models"
+	"trl-research-backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// SystemActor - the actor of the entries of background jobs
+const SystemActor = "system"
+
+// RequestIDHeader - taken from the request when the client (or a proxy) sent one, otherwise
+// generated; answered in the response either way
+const RequestIDHeader = "X-Request-ID"
+
+// Route - what one mutating route does, for the audit log
+type Route struct {
+	Entity string
+	Action string // "" = by method: create (POST), update (PATCH / PUT), delete (DELETE)
+	Param  string // URL parameter holding the ID of the record
+	Key    string // field of the JSON response holding the ID, for creates
+	Self   bool   // the record is the caller's own (its ID is the caller's user ID)
+	// Load reads the record for the before / after snapshots; nil for records whose content
+	// isn't logged. An ErrNotFound means the record doesn't exist (before a create, after a delete).
+	Load func(id string) (any, error)
+	Skip bool // the route changes nothing, e.g. scoring a questionnaire without saving
+}
+
+// Logger writes the audit log
+type Logger struct {
+	Repo   repository.AuditRepository
+	Routes map[string]Route // key: "METHOD /full/path/:param"
+}
+
+// RequestID gives every request an ID, kept on the context as "requestID"
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if id == "" || len(id) > 128 {
+			id = uuid.NewString()
+		}
+		c.Set("requestID", id)
+		c.Header(RequestIDHeader, id)
+		c.Next()
+	}
+}
+
+// Middleware logs the mutating calls of the routes it is used on. It has to run after the auth
+// middleware, which sets the actor. Calls that failed are only logged when they changed something
+// anyway, e.g. a merge that stopped halfway.
+func (l *Logger) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead || c.Request.Method == http.MethodOptions {
+			c.Next()
+			return
+		}
+		route, known := l.Routes[c.Request.Method+" "+c.FullPath()]
+		if route.Skip {
+			c.Next()
+			return
+		}
+
+		id := c.Param(route.Param)
+		if route.Self {
+			id = c.GetString("userID")
+		}
+		var before any
+		if route.Load != nil && id != "" {
+			before = load(route, id)
+		}
+		var body *bodyWriter
+		if route.Key != "" {
+			body = &bodyWriter{ResponseWriter: c.Writer}
+			c.Writer = body
+		}
+
+		c.Next()
+
+		if body != nil && id == "" {
+			id = responseID(body.buf.Bytes(), route.Key)
+		}
+		var after any
+		if route.Load != nil && id != "" {
+			after = load(route, id)
+		}
+		changes := Diff(before, after)
+		status := c.Writer.Status()
+		if status >= http.StatusBadRequest && len(changes) == 0 {
+			return
+		}
+		if !known {
+			log.Printf("⚠️ [Audit] %s %s is not in the route table", c.Request.Method, c.FullPath())
+		}
+
+		entry := &models.AuditEntry{
+			RequestID:  c.GetString("requestID"),
+			ActorID:    c.GetString("userID"),
+			ActorRole:  c.GetString("role"),
+			ActorEmail: c.GetString("userEmail"),
+			Method:     c.Request.Method,
+			Route:      c.FullPath(),
+			Path:       c.Request.URL.Path,
+			Entity:     route.Entity,
+			EntityID:   id,
+			Action:     action(route, c.Request.Method),
+			Status:     status,
+			Changes:    changes,
+		}
+		if err := l.Repo.CreateAuditEntry(entry); err != nil {
+			log.Printf("❌ [Audit] %s %s (request %s): %v", entry.Method, entry.Path, entry.RequestID, err)
+		}
+	}
+}
+
+// Record writes the entry of a change the route table can't see: the records a call writes
+// besides the one of its route (the parts of a submission, the cases a taxonomy merge repoints,
+// TRL fields derived again, what a case delete / restore takes with it) and the records the trash
+// purge removes. c is the request it happened in, nil for a background job (actor "system").
+// before and after are snapshots like the middleware's, nil for a record that doesn't exist;
+// nothing is written when both exist and don't differ. A nil Logger records nothing.
+func (l *Logger) Record(c *gin.Context, entity, id, action string, before, after any) {
+	if l == nil {
+		return
+	}
+	changes := Diff(before, after)
+	if fields(before) != nil && fields(after) != nil && len(changes) == 0 {
+		return
+	}
+	entry := &models.AuditEntry{
+		ActorID:   SystemActor,
+		ActorRole: SystemActor,
+		Entity:    entity,
+		EntityID:  id,
+		Action:    action,
+		Changes:   changes,
+	}
+	if c != nil {
+		entry.RequestID = c.GetString("requestID")
+		entry.ActorID = c.GetString("userID")
+		entry.ActorRole = c.GetString("role")
+		entry.ActorEmail = c.GetString("userEmail")
+		entry.Method = c.Request.Method
+		entry.Route = c.FullPath()
+		entry.Path = c.Request.URL.Path
+		entry.Status = c.Writer.Status()
+	}
+	if err := l.Repo.CreateAuditEntry(entry); err != nil {
+		log.Printf("❌ [Audit] %s %s %s: %v", action, entity, id, err)
+	}
+}
+
+// load - the snapshot of a record, nil when it doesn't exist or can't be read
+func load(route Route, id string) any {
+	v, err := route.Load(id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		log.Printf("❌ [Audit] load %s %s: %v", route.Entity, id, err)
+		return nil
+	}
+	return v
+}
+
+func action(route Route, method string) string {
+	if route.Action != "" {
+		return route.Action
+	}
+	switch method {
+	case http.MethodPost:
+		return "create"
+	case http.MethodDelete:
+		return "delete"
+	}
+	return "update"
+}
+
+// responseID reads the ID of a created record from the JSON response
+func responseID(body []byte, key string) string {
+	var fields map[string]any
+	if json.Unmarshal(body, &fields) != nil {
+		return ""
+	}
+	switch v := fields[key].(type) {
+	case string:
+		return v
+	case float64: // questionnaire versions
+		return fmt.Sprint(v)
+	}
+	return ""
+}
+
+// bodyWriter keeps a copy of the response for responseID
+type bodyWriter struct {
+	gin.ResponseWriter
+	buf bytes.Buffer
+}
+
+func (w *bodyWriter) Write(b []byte) (int, error) {
+	w.buf.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *bodyWriter) WriteString(s string) (int, error) {
+	w.buf.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
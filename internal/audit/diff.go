@@ -0,0 +1,95 @@
+package audit
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	"trl-research-backend/internal/models"
+)
+
+// Redacted stands in for the value of a secret field that changed
+const Redacted = "[redacted]"
+
+// sensitive - parts of field names whose values never go into the log
+var sensitive = []string{"password", "secret", "token", "recovery_code", "hash"}
+
+// skipped - fields every write touches, they would only add noise
+var skipped = map[string]bool{"updated_at": true, "revision": true}
+
+// Diff - the fields that differ between two snapshots of a record, by JSON name and in name
+// order. A nil snapshot is a record that doesn't exist: on a create or delete only the fields
+// with a value are listed.
+func Diff(before, after any) []models.AuditChange {
+	b, a := fields(before), fields(after)
+	names := map[string]bool{}
+	for k := range b {
+		names[k] = true
+	}
+	for k := range a {
+		names[k] = true
+	}
+	sorted := make([]string, 0, len(names))
+	for k := range names {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	changes := []models.AuditChange{}
+	for _, name := range sorted {
+		was, now := b[name], a[name]
+		if skipped[name] || reflect.DeepEqual(was, now) || (isEmpty(was) && isEmpty(now)) {
+			continue
+		}
+		if isSensitive(name) {
+			was, now = redact(was), redact(now)
+		}
+		changes = append(changes, models.AuditChange{Field: name, Before: was, After: now})
+	}
+	return changes
+}
+
+// fields - a snapshot as its JSON object
+func fields(v any) map[string]any {
+	if v == nil || reflect.ValueOf(v).Kind() == reflect.Pointer && reflect.ValueOf(v).IsNil() {
+		return nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var m map[string]any
+	json.Unmarshal(b, &m)
+	return m
+}
+
+func isEmpty(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []any:
+		return len(v) == 0
+	case map[string]any:
+		return len(v) == 0
+	}
+	return false
+}
+
+func isSensitive(name string) bool {
+	for _, s := range sensitive {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func redact(v any) any {
+	if isEmpty(v) {
+		return v
+	}
+	return Redacted
+}
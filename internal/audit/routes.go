@@ -0,0 +1,131 @@
+package audit
+
+import (
+	"context"
+	"strconv"
+
+	"trl-research-backend/internal/repository"
+)
+
+// Routes - the route table of the API in internal/router. A new mutating route belongs here
+// too, otherwise its calls are logged without entity and changes.
+func Routes(repos *repository.Repositories) map[string]Route {
+	admin := loader(repos.Admin.GetAdminByID)
+	researcher := loader(repos.Researcher.GetResearcherByID)
+	coordinator := loader(repos.Coordinator.GetCoordinatorByEmail)
+	supporter := loader(repos.Supporter.GetSupporterByID)
+	appointment := loader(repos.Appointment.GetAppointmentByID)
+	cs := loader(repos.Case.GetCaseByID)
+	ip := loader(repos.IntellectualProperty.GetIPByID)
+	assessment := loader(repos.AssessmentTrl.GetAssessmentTrlByID)
+	term := loader(repos.Taxonomy.GetTaxonomyTermByID)
+	draft := loader(repos.Draft.GetDraftByID)
+	questionnaire := func(id string) (any, error) {
+		version, err := strconv.Atoi(id)
+		if err != nil {
+			return nil, repository.ErrNotFound
+		}
+		return repos.Questionnaire.GetQuestionnaireTemplate(version)
+	}
+	file := func(id string) (any, error) {
+		return repos.File.GetFileByID(context.Background(), id)
+	}
+
+	return map[string]Route{
+		// signing in and out: login_attempts and sessions are their record. A password set under
+		// /auth and 2FA enabled at login are logged by their handlers (Logger.Record).
+		"POST /auth/login":                  {Skip: true},
+		"POST /auth/mfa/verify":             {Skip: true},
+		"POST /auth/mfa/enroll":             {Skip: true},
+		"POST /auth/mfa/enroll/confirm":     {Skip: true},
+		"POST /auth/refresh":                {Skip: true},
+		"POST /auth/logout":                 {Skip: true},
+		"POST /auth/forgot-password":        {Skip: true},
+		"POST /auth/reset-password":         {Skip: true},
+		"POST /auth/reset-password/confirm": {Skip: true},
+
+		"POST /admin":                 {Entity: "admin", Key: "admin_id", Load: admin},
+		"POST /trl/admin":             {Entity: "admin", Key: "admin_id", Load: admin},
+		"PATCH /trl/admin/:id":        {Entity: "admin", Param: "id", Load: admin},
+		"DELETE /trl/admin/:id":       {Entity: "admin", Param: "id", Load: admin},
+		"POST /trl/admin/:id/restore": {Entity: "admin", Action: "restore", Param: "id", Load: admin},
+
+		// sessions, lockouts and 2FA: who did it is logged, the secrets behind them aren't
+		"POST /trl/sessions/user/:id/revoke": {Entity: "session", Action: "revoke", Param: "id"},
+		"POST /trl/login-lock/unlock":        {Entity: "login_lock", Action: "unlock"},
+		"POST /trl/mfa/enroll":               {Entity: "mfa", Action: "enroll", Self: true},
+		"POST /trl/mfa/enroll/confirm":       {Entity: "mfa", Action: "enable", Self: true},
+		"POST /trl/mfa/recovery-codes":       {Entity: "mfa", Action: "regenerate_recovery_codes", Self: true},
+		"POST /trl/mfa/disable":              {Entity: "mfa", Action: "disable", Self: true},
+		"POST /trl/mfa/user/:id/reset":       {Entity: "mfa", Action: "reset", Param: "id"},
+
+		"POST /trl/researcher":             {Entity: "researcher", Key: "researcher_id", Load: researcher},
+		"PATCH /trl/researcher/:id":        {Entity: "researcher", Param: "id", Load: researcher},
+		"DELETE /trl/researcher/:id":       {Entity: "researcher", Param: "id", Load: researcher},
+		"POST /trl/researcher/:id/restore": {Entity: "researcher", Action: "restore", Param: "id", Load: researcher},
+
+		"POST /trl/coordinator":             {Entity: "coordinator", Key: "coordinator_email", Load: coordinator},
+		"PATCH /trl/coordinator/:id":        {Entity: "coordinator", Param: "id", Load: coordinator},
+		"DELETE /trl/coordinator/:id":       {Entity: "coordinator", Param: "id", Load: coordinator},
+		"POST /trl/coordinator/:id/restore": {Entity: "coordinator", Action: "restore", Param: "id", Load: coordinator},
+
+		"POST /trl/supporter":             {Entity: "supporter", Key: "supporter_id", Load: supporter},
+		"PATCH /trl/supporter/:id":        {Entity: "supporter", Param: "id", Load: supporter},
+		"DELETE /trl/supporter/:id":       {Entity: "supporter", Param: "id", Load: supporter},
+		"POST /trl/supporter/:id/restore": {Entity: "supporter", Action: "restore", Param: "id", Load: supporter},
+
+		"POST /trl/appointment":             {Entity: "appointment", Key: "appointment_id", Load: appointment},
+		"PATCH /trl/appointment/:id":        {Entity: "appointment", Param: "id", Load: appointment},
+		"DELETE /trl/appointment/:id":       {Entity: "appointment", Param: "id", Load: appointment},
+		"POST /trl/appointment/:id/restore": {Entity: "appointment", Action: "restore", Param: "id", Load: appointment},
+
+		"POST /trl/case":                    {Entity: "case", Key: "case_id", Load: cs},
+		"PATCH /trl/case/:id":               {Entity: "case", Param: "id", Load: cs},
+		"PATCH /trl/case/update-status/:id": {Entity: "case", Action: "transition", Param: "id", Load: cs},
+		"POST /trl/case/:id/transition":     {Entity: "case", Action: "transition", Param: "id", Load: cs},
+		"POST /trl/case/:id/trl-suggestion": {Entity: "case", Action: "refresh_trl_suggestion", Param: "id", Load: cs},
+		"DELETE /trl/case/:id":              {Entity: "case", Param: "id", Load: cs},
+		"POST /trl/case/:id/restore":        {Entity: "case", Action: "restore", Param: "id", Load: cs},
+		"POST /trl/submissions":             {Entity: "case", Action: "submit", Key: "case_id", Load: cs},
+		"POST /trl/drafts/:id/submit":       {Entity: "case", Action: "submit", Key: "case_id", Load: cs},
+
+		"POST /trl/drafts":          {Entity: "draft", Key: "id", Load: draft},
+		"PUT /trl/drafts/:id":       {Skip: true}, // autosaves of the form; the submit is logged
+		"PUT /trl/drafts/:id/:part": {Skip: true},
+		"DELETE /trl/drafts/:id":    {Entity: "draft", Param: "id", Load: draft},
+
+		"POST /trl/ip":             {Entity: "ip", Key: "id", Load: ip},
+		"PATCH /trl/ip/:id":        {Entity: "ip", Param: "id", Load: ip},
+		"DELETE /trl/ip/:id":       {Entity: "ip", Param: "id", Load: ip},
+		"POST /trl/ip/:id/restore": {Entity: "ip", Action: "restore", Param: "id", Load: ip},
+
+		"POST /trl/taxonomy":           {Entity: "taxonomy_term", Key: "id", Load: term},
+		"PATCH /trl/taxonomy/:id":      {Entity: "taxonomy_term", Param: "id", Load: term},
+		"POST /trl/taxonomy/:id/merge": {Entity: "taxonomy_term", Action: "merge", Param: "id", Load: term},
+
+		"POST /trl/questionnaire":                   {Entity: "questionnaire", Key: "version", Load: questionnaire},
+		"PUT /trl/questionnaire/:version":           {Entity: "questionnaire", Param: "version", Load: questionnaire},
+		"DELETE /trl/questionnaire/:version":        {Entity: "questionnaire", Param: "version", Load: questionnaire},
+		"POST /trl/questionnaire/:version/publish":  {Entity: "questionnaire", Action: "publish", Param: "version", Load: questionnaire},
+		"POST /trl/questionnaire/:version/activate": {Entity: "questionnaire", Action: "activate", Param: "version", Load: questionnaire},
+
+		"POST /trl/assessment_trl":             {Entity: "assessment_trl", Key: "id", Load: assessment},
+		"PATCH /trl/assessment_trl/:id":        {Entity: "assessment_trl", Param: "id", Load: assessment},
+		"POST /trl/assessment_trl/:id/review":  {Entity: "assessment_trl", Action: "review", Param: "id", Load: assessment},
+		"POST /trl/assessment_trl/score":       {Skip: true},
+		"DELETE /trl/assessment_trl/:id":       {Entity: "assessment_trl", Param: "id", Load: assessment},
+		"POST /trl/assessment_trl/:id/restore": {Entity: "assessment_trl", Action: "restore", Param: "id", Load: assessment},
+
+		"POST /trl/trash/purge": {Entity: "trash", Action: "purge"},
+
+		"POST /trl/presign/upload": {Skip: true}, // only signs a URL, the upload is recorded by /file/upload
+		"POST /trl/file/upload":    {Entity: "file", Key: "id", Load: file},
+	}
+}
+
+// loader adapts a GetXByID of a repository to Route.Load
+func loader[T any](get func(string) (*T, error)) func(string) (any, error) {
+	return func(id string) (any, error) {
+		return get(id)
+	}
+}
@@ -2,27 +2,47 @@ package auth
 
 import (
 	"fmt"
-	"math/rand"
+	"log"
 	"net/http"
 	"net/smtp"
+	"net/url"
 	"os"
 	"strconv"
 	"time"
 
+	"trl-research-backend/internal/models"
 	"trl-research-backend/internal/repository"
 
 	"github.com/gin-gonic/gin"
-	"golang.org/x/crypto/bcrypt"
 )
 
 type ForgotHandler struct {
-	AdminRepo repository.AdminRepo
+	AdminRepo      repository.AdminRepository
+	ResearcherRepo repository.ResearcherRepository
+	// coordinators get their first password through this link too
+	CoordinatorRepo repository.CoordinatorRepository
+	Resets          repository.PasswordResetRepository
+	// SendMail ส่งลิงก์รีเซ็ต ถ้าเป็น nil จะใช้ SMTP จาก EMAIL_* env
+	SendMail func(to, subject, body string) error
 }
 
 type ForgotReq struct {
 	Email string `json:"email"`
 }
 
+const forgotMessage = "If the email exists, a password reset link has been sent"
+
+// passwordResetTTL - PASSWORD_RESET_EXPIRY_MINUTES (default 30)
+func passwordResetTTL() time.Duration {
+	m, _ := strconv.Atoi(os.Getenv("PASSWORD_RESET_EXPIRY_MINUTES"))
+	if m <= 0 {
+		m = 30
+	}
+	return time.Duration(m) * time.Minute
+}
+
+// 🟢 POST /auth/forgot-password - email a single-use reset link to an admin, researcher or coordinator.
+// Nothing about the account changes until the link is used.
 func (h *ForgotHandler) ForgotPassword(c *gin.Context) {
 	var req ForgotReq
 	if err := c.ShouldBindJSON(&req); err != nil || req.Email == "" {
@@ -30,39 +50,83 @@ func (h *ForgotHandler) ForgotPassword(c *gin.Context) {
 		return
 	}
 
-	// ตรวจว่ามี user ไหม
-	if _, err := h.AdminRepo.GetAdminByEmail(req.Email); err != nil {
+	// ตรวจว่ามี user ไหม (admin ก่อน แล้วค่อย researcher แล้วค่อย coordinator)
+	var userID, role string
+	if admin, err := h.AdminRepo.GetAdminByEmail(req.Email); err == nil {
+		userID, role = admin.AdminID, RoleAdmin
+	} else if researcher, err := h.ResearcherRepo.GetResearcherByEmail(req.Email); err == nil {
+		userID, role = researcher.ResearcherID, RoleResearcher
+	} else if coordinator, err := h.CoordinatorRepo.GetCoordinatorByEmail(req.Email); err == nil {
+		userID, role = coordinator.CoordinatorEmail, RoleCoordinator
+	}
+	if userID == "" {
 		// ป้องกัน enumeration: ตอบ 200 แต่ไม่บอกว่าไม่มี
-		c.JSON(http.StatusOK, gin.H{"message": "If the email exists, a temporary password has been sent"})
+		c.JSON(http.StatusOK, gin.H{"message": forgotMessage})
 		return
 	}
 
-	// รหัสชั่วคราว
-	rand.Seed(time.Now().UnixNano())
-	tempPass := fmt.Sprintf("%06d", rand.Intn(1000000))
-
-	hash, err := bcrypt.GenerateFromPassword([]byte(tempPass), bcrypt.DefaultCost)
+	token, err := newRandomToken()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
 		return
 	}
-	if err := h.AdminRepo.UpdatePasswordByEmail(req.Email, string(hash)); err != nil {
+	// ลิงก์เก่าที่ยังไม่ได้ใช้ถือว่าหมดอายุทันที
+	if err := h.Resets.InvalidatePasswordResetsByUserID(userID); err != nil {
+		log.Printf("❌ [ForgotPassword] invalidate old tokens of %s: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+	ttl := passwordResetTTL()
+	if err := h.Resets.CreatePasswordReset(&models.PasswordReset{
+		TokenHash: sha256Hex(token),
+		UserID:    userID,
+		UserEmail: req.Email,
+		Role:      role,
+		ExpiresAt: time.Now().Add(ttl),
+	}); err != nil {
+		log.Printf("❌ [ForgotPassword] store token for %s: %v", userID, err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
 		return
 	}
 
-	// ส่งอีเมล (ตัวอย่าง SMTP พื้นฐาน; คุณใช้ gomail หรือบริการอื่นแทนได้)
+	body := resetEmailBody(token, ttl)
+	send := h.SendMail
+	if send == nil {
+		send = sendSMTP
+	}
+	// ส่งเมลแบบ async เวลาตอบจะได้ไม่ต่างจากกรณีไม่มี user
+	go func() {
+		if err := send(req.Email, "Password reset", body); err != nil {
+			// ถึงส่งเมลพลาด ก็ไม่ leak ให้ attacker รู้
+			log.Printf("❌ [ForgotPassword] send mail to %s: %v", userID, err)
+		}
+	}()
+
+	c.JSON(http.StatusOK, gin.H{"message": forgotMessage})
+}
+
+// resetEmailBody - PASSWORD_RESET_URL (frontend page) gets ?token=..., otherwise the raw token is sent
+func resetEmailBody(token string, ttl time.Duration) string {
+	link := token
+	if base := os.Getenv("PASSWORD_RESET_URL"); base != "" {
+		link = base + "?token=" + url.QueryEscape(token)
+	}
+	return fmt.Sprintf("Use this link to set a new password (valid for %d minutes, works once):\r\n\r\n%s\r\n\r\n"+
+		"If you did not ask for a password reset you can ignore this email.", int(ttl.Minutes()), link)
+}
+
+// sendSMTP ส่งอีเมล (SMTP พื้นฐาน; ใช้ gomail หรือบริการอื่นแทนได้)
+func sendSMTP(to, subject, body string) error {
 	host := os.Getenv("EMAIL_HOST")
 	port, _ := strconv.Atoi(os.Getenv("EMAIL_PORT"))
 	user := os.Getenv("EMAIL_SENDER")
 	pass := os.Getenv("EMAIL_PASSWORD")
+	if host == "" {
+		return fmt.Errorf("EMAIL_HOST not set")
+	}
 
 	addr := fmt.Sprintf("%s:%d", host, port)
 	auth := smtp.PlainAuth("", user, pass, host)
-	msg := []byte("Subject: Temporary Password\r\n\r\nYour temporary password is: " + tempPass)
-	if err := smtp.SendMail(addr, auth, user, []string{req.Email}, msg); err != nil {
-		// ถึงส่งเมลพลาด ก็ไม่ leak ให้ attacker รู้
-	}
-
-	c.JSON(http.StatusOK, gin.H{"message": "If the email exists, a temporary password has been sent"})
+	msg := []byte("To: " + to + "\r\nSubject: " + subject + "\r\n\r\n" + body)
+	return smtp.SendMail(addr, auth, user, []string{to}, msg)
 }
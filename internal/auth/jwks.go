@@ -0,0 +1,22 @@
+package auth
+
+import (
+	"log"
+	"net/http"
+
+	"trl-research-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 🟢 GET /.well-known/jwks.json - public keys other services use to validate our tokens
+func JWKS(c *gin.Context) {
+	kp, err := utils.SharedKeyProvider()
+	if err != nil {
+		log.Printf("❌ [JWKS] Key provider error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "key provider error"})
+		return
+	}
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, kp.JWKS())
+}
@@ -0,0 +1,241 @@
+package auth
+
+import (
+	"errors"
+	"log"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"trl-research-backend/internal/models"
+	"trl-research-backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LoginGuard slows down password guessing. Every failure of an account adds an exponential
+// delay (1s, 2s, 4s, ... capped at 60s) before the next attempt is looked at; a client IP
+// gets the same delay once it passes LOGIN_MAX_FAILURES, so a shared campus NAT isn't slowed
+// down by one typo. After LOGIN_MAX_FAILURES (per account) or LOGIN_MAX_FAILURES_PER_IP
+// failures the key is locked for LOGIN_LOCKOUT_MINUTES. Failures older than that are forgotten.
+type LoginGuard struct {
+	Attempts repository.LoginAttemptRepository
+}
+
+type guardKey struct {
+	key          string
+	freeFailures int // failures allowed before backoff starts
+	maxFailures  int // failures that lock the key
+}
+
+const maxBackoff = 60 * time.Second
+
+func envInt(name string, def int) int {
+	n, _ := strconv.Atoi(os.Getenv(name))
+	if n <= 0 {
+		return def
+	}
+	return n
+}
+
+func lockoutDuration() time.Duration {
+	return time.Duration(envInt("LOGIN_LOCKOUT_MINUTES", 15)) * time.Minute
+}
+
+func accountKey(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
+
+func (g *LoginGuard) keys(c *gin.Context, email string) []guardKey {
+	return []guardKey{accountGuardKey(email), ipGuardKey(c.ClientIP())}
+}
+
+func accountGuardKey(email string) guardKey {
+	return guardKey{accountKey(email), 0, envInt("LOGIN_MAX_FAILURES", 5)}
+}
+
+func ipGuardKey(ip string) guardKey {
+	return guardKey{ipKey(ip), envInt("LOGIN_MAX_FAILURES", 5), envInt("LOGIN_MAX_FAILURES_PER_IP", 20)}
+}
+
+// waitFor - how long a key with this throttle state must still wait, and whether that is a lockout
+func waitFor(t *models.LoginThrottle, k guardKey, now time.Time) (time.Duration, bool) {
+	lockout := lockoutDuration()
+	if t.Failures <= k.freeFailures || now.Sub(t.LastFailureAt) > lockout {
+		return 0, false
+	}
+	if t.Failures >= k.maxFailures {
+		return t.LastFailureAt.Add(lockout).Sub(now), true
+	}
+	backoff := time.Duration(math.Pow(2, float64(t.Failures-k.freeFailures-1))) * time.Second
+	if backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+	return t.LastFailureAt.Add(backoff).Sub(now), false
+}
+
+// Allow answers 429 and returns false when the account or IP must wait. The rejected
+// attempt is audited but not counted, so a locked account doesn't stay locked forever.
+func (g *LoginGuard) Allow(c *gin.Context, email, action string) bool {
+	now := time.Now()
+	for _, k := range g.keys(c, email) {
+		t, err := g.Attempts.GetLoginThrottle(k.key)
+		if errors.Is(err, repository.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			// fail open: a storage hiccup must not lock everybody out
+			log.Printf("⚠️ [LoginGuard] read %s: %v", k.key, err)
+			continue
+		}
+		wait, locked := waitFor(t, k, now)
+		if wait <= 0 {
+			continue
+		}
+
+		reason := "throttled"
+		msg := "too many attempts, slow down"
+		if locked {
+			reason = "locked"
+			msg = "too many failed attempts, try again later"
+		}
+		g.audit(c, &models.LoginAudit{Email: email, Action: action, Reason: reason})
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": msg, "retry_after": int(math.Ceil(wait.Seconds()))})
+		return false
+	}
+	return true
+}
+
+// Failed counts a wrong password against both the account and the IP
+func (g *LoginGuard) Failed(c *gin.Context, email, action string) {
+	for _, k := range g.keys(c, email) {
+		t, err := g.Attempts.RecordLoginFailure(k.key, lockoutDuration())
+		if err != nil {
+			log.Printf("❌ [LoginGuard] record failure %s: %v", k.key, err)
+			continue
+		}
+		if t.Failures == k.maxFailures {
+			log.Printf("🔒 [LoginGuard] %s locked after %d failures", k.key, t.Failures)
+		}
+	}
+	g.audit(c, &models.LoginAudit{Email: email, Action: action, Reason: "invalid_credentials"})
+}
+
+// Succeeded clears the account counter; the IP counter only decays, so logging into an
+// own account doesn't reset a guessing run against others
+func (g *LoginGuard) Succeeded(c *gin.Context, email, userID, role, action string) {
+	if err := g.Attempts.ClearLoginFailures(accountKey(email)); err != nil {
+		log.Printf("❌ [LoginGuard] clear %s: %v", accountKey(email), err)
+	}
+	g.audit(c, &models.LoginAudit{Email: email, UserID: userID, Role: role, Action: action, Success: true})
+}
+
+func (g *LoginGuard) audit(c *gin.Context, a *models.LoginAudit) {
+	a.Email = strings.ToLower(strings.TrimSpace(a.Email))
+	a.IPAddress = c.ClientIP()
+	a.UserAgent = c.Request.UserAgent()
+	if err := g.Attempts.CreateLoginAudit(a); err != nil {
+		log.Printf("❌ [LoginGuard] audit: %v", err)
+	}
+}
+
+type UnlockReq struct {
+	Email string `json:"email"`
+	IP    string `json:"ip"`
+}
+
+// 🟢 POST /trl/login-lock/unlock - clear the failure counter of an account and/or IP (admin)
+func (g *LoginGuard) Unlock(c *gin.Context) {
+	var req UnlockReq
+	if err := c.ShouldBindJSON(&req); err != nil || (req.Email == "" && req.IP == "") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "email or ip is required"})
+		return
+	}
+
+	var keys []string
+	if req.Email != "" {
+		keys = append(keys, accountKey(req.Email))
+	}
+	if req.IP != "" {
+		keys = append(keys, ipKey(req.IP))
+	}
+	for _, key := range keys {
+		if err := g.Attempts.ClearLoginFailures(key); err != nil {
+			log.Printf("❌ [Unlock] %s: %v", key, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	log.Printf("🔓 %s unlocked %v", c.GetString("userEmail"), keys)
+	c.JSON(http.StatusOK, gin.H{"message": "unlocked", "keys": keys})
+}
+
+// 🟢 GET /trl/login-lock?email=...&ip=... - current counters (admin)
+func (g *LoginGuard) GetLockStatus(c *gin.Context) {
+	email, ip := c.Query("email"), c.Query("ip")
+	if email == "" && ip == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "email or ip is required"})
+		return
+	}
+
+	now := time.Now()
+	status := []gin.H{}
+	check := func(k guardKey) bool {
+		t, err := g.Attempts.GetLoginThrottle(k.key)
+		if errors.Is(err, repository.ErrNotFound) {
+			t, err = &models.LoginThrottle{Key: k.key}, nil
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return false
+		}
+		wait, locked := waitFor(t, k, now)
+		status = append(status, gin.H{
+			"key":             k.key,
+			"failures":        t.Failures,
+			"last_failure_at": t.LastFailureAt,
+			"locked":          locked,
+			"retry_after":     int(math.Ceil(math.Max(wait.Seconds(), 0))),
+		})
+		return true
+	}
+	if email != "" && !check(accountGuardKey(email)) {
+		return
+	}
+	if ip != "" && !check(ipGuardKey(ip)) {
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// 🟢 GET /trl/login-audit?email=...&limit=100 - login history of an account (admin)
+func (g *LoginGuard) GetLoginAudit(c *gin.Context) {
+	email := strings.ToLower(strings.TrimSpace(c.Query("email")))
+	if email == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "email is required"})
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if limit <= 0 || limit > 1000 {
+		limit = 100
+	}
+
+	audits, err := g.Attempts.GetLoginAuditsByEmail(email, limit)
+	if err != nil {
+		log.Printf("❌ [GetLoginAudit] %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if audits == nil {
+		audits = []models.LoginAudit{}
+	}
+	c.JSON(http.StatusOK, audits)
+}
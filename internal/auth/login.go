@@ -1,22 +1,23 @@
 package auth
 
 import (
-	"fmt"
+	"log"
 	"net/http"
-	"os"
-	"strconv"
-	"time"
 
+	"trl-research-backend/internal/models"
 	"trl-research-backend/internal/repository"
-	"trl-research-backend/internal/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
-// LoginHandler รวม repository ของทั้ง admin และ researcher
+// LoginHandler รวม repository ของ admin, researcher และ coordinator
 type LoginHandler struct {
-	AdminRepo      *repository.AdminRepo
-	ResearcherRepo *repository.ResearcherRepo
+	AdminRepo       repository.AdminRepository
+	ResearcherRepo  repository.ResearcherRepository
+	CoordinatorRepo repository.CoordinatorRepository
+	Sessions        repository.SessionRepository
+	MFA             repository.MFARepository
+	Guard           *LoginGuard
 }
 
 // LoginRequest รับข้อมูลจาก frontend
@@ -33,75 +34,71 @@ func (h *LoginHandler) Login(c *gin.Context) {
 		return
 	}
 
-	fmt.Printf("🔍 Login attempt: %s\n", req.Email)
+	// 0️⃣ ถูกล็อก / ต้องรอ backoff อยู่หรือไม่
+	if !h.Guard.Allow(c, req.Email, "login") {
+		return
+	}
 
 	var userID, userEmail, userRole string
 
 	// 1️⃣ ตรวจสอบในตาราง Admin ก่อน
 	admin, errA := h.AdminRepo.Login(req.Email, req.Password)
-	fmt.Println("admin", admin)
-	fmt.Println("errA", errA)
 	if errA == nil && admin != nil {
 		userID = admin.AdminID
 		userEmail = admin.AdminEmail
-		userRole = "admin"
+		userRole = RoleAdmin
 	}
 
 	// 2️⃣ ถ้ายังไม่เจอใน admin ให้ลองเช็ก researcher
 	if userRole == "" {
 		researcher, errR := h.ResearcherRepo.Login(req.Email, req.Password)
-		fmt.Println("researcher", researcher)
-		fmt.Println("errR", errR)
 		if errR == nil && researcher != nil {
 			userID = researcher.ResearcherID
 			userEmail = researcher.ResearcherEmail
-			userRole = "researcher"
+			userRole = RoleResearcher
 		}
 	}
 
-	// 3️⃣ ถ้าไม่เจอทั้งสองกลุ่ม
+	// 3️⃣ แล้วค่อย coordinator (อีเมลคือ key ของ coordinator จึงใช้เป็น userID)
 	if userRole == "" {
+		coordinator, errC := h.CoordinatorRepo.Login(req.Email, req.Password)
+		if errC == nil && coordinator != nil {
+			userID = coordinator.CoordinatorEmail
+			userEmail = coordinator.CoordinatorEmail
+			userRole = RoleCoordinator
+		}
+	}
+
+	// 4️⃣ ถ้าไม่เจอทั้งสามกลุ่ม (ไม่บอกว่าผิดที่ email หรือ password)
+	if userRole == "" {
+		h.Guard.Failed(c, req.Email, "login")
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid credentials"})
 		return
 	}
 
-	fmt.Printf("✅ Verified user: %s (role: %s)\n", userEmail, userRole)
-
-	// 4️⃣ โหลด key provider จาก environment
-	kp, err := utils.NewEnvKeyProvider()
+	// 5️⃣ admin ที่เปิด 2FA (หรือถูกบังคับด้วย ADMIN_MFA_REQUIRED) ต้องผ่าน /auth/mfa/* ก่อน
+	// ตัวนับความผิดพลาดยังไม่ถูกล้าง จนกว่าจะยืนยันรหัสสำเร็จ
+	pending, err := mfaChallenge(h.MFA, userID, userEmail, userRole)
 	if err != nil {
-		fmt.Println("❌ key provider init failed:", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal key provider error"})
+		log.Printf("❌ [Login] mfa check for %s: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
 		return
 	}
-
-	// 5️⃣ สร้าง JWT token
-	expH, _ := strconv.Atoi(os.Getenv("JWT_EXPIRY"))
-	if expH <= 0 {
-		expH = 24
+	if pending != nil {
+		h.Guard.audit(c, &models.LoginAudit{Email: req.Email, UserID: userID, Role: userRole, Action: "login", Success: true, Reason: "mfa_pending"})
+		c.JSON(http.StatusOK, pending)
+		return
 	}
+	h.Guard.Succeeded(c, req.Email, userID, userRole, "login")
 
-	token, err := utils.GenerateJWT(
-		userID,        // user id
-		userEmail,     // email
-		userRole,      // role (admin/researcher)
-		"", "",        // clientID, clientName (optional)
-		os.Getenv("JWT_ISSUER"),
-		os.Getenv("JWT_AUDIENCE"),
-		"v1",          // key id
-		time.Duration(expH),
-		*kp,
-	)
+	// 6️⃣ สร้าง session + access token อายุสั้น + refresh token
+	resp, err := startSession(c, h.Sessions, userID, userEmail, userRole)
 	if err != nil {
-		fmt.Println("❌ failed to generate token:", err)
+		log.Printf("❌ [Login] failed to start session for %s: %v", userID, err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "cannot generate token"})
 		return
 	}
 
-	// 6️⃣ ส่ง response กลับไป
-	c.JSON(http.StatusOK, gin.H{
-		"token":      token,
-		"expires_in": expH,
-		"role":       userRole,
-	})
+	// 7️⃣ ส่ง response กลับไป
+	c.JSON(http.StatusOK, resp)
 }
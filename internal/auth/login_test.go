@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"trl-research-backend/internal/models"
+	"trl-research-backend/internal/repository/memory"
+
+	"github.com/gin-gonic/gin"
+)
+
+// a coordinator has no password until they set one through the reset link, then signs in as coordinator
+func TestCoordinatorSignsInAfterSettingAPassword(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("JWT_KEYS_DIR", "")
+	t.Setenv("JWT_ACTIVE_KID", "")
+	t.Setenv("PRIVATE_KEY_TEST_B64", base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})))
+	t.Setenv("PASSWORD_RESET_URL", "")
+
+	gin.SetMode(gin.TestMode)
+	repos := memory.NewRepositories()
+	if err := repos.Coordinator.CreateCoordinator(&models.CoordinatorInfo{CoordinatorEmail: "co@example.com", CoordinatorName: "Co"}); err != nil {
+		t.Fatal(err)
+	}
+	guard := &LoginGuard{Attempts: repos.LoginAttempt}
+	mail := make(chan string, 1) // sent in the background
+	forgot := &ForgotHandler{
+		AdminRepo:       repos.Admin,
+		ResearcherRepo:  repos.Researcher,
+		CoordinatorRepo: repos.Coordinator,
+		Resets:          repos.PasswordReset,
+		SendMail:        func(to, subject, body string) error { mail <- body; return nil },
+	}
+	reset := &ResetHandler{
+		AdminRepo:       repos.Admin,
+		ResearcherRepo:  repos.Researcher,
+		CoordinatorRepo: repos.Coordinator,
+		Resets:          repos.PasswordReset,
+		Sessions:        repos.Session,
+		Guard:           guard,
+	}
+	login := &LoginHandler{
+		AdminRepo:       repos.Admin,
+		ResearcherRepo:  repos.Researcher,
+		CoordinatorRepo: repos.Coordinator,
+		Sessions:        repos.Session,
+		MFA:             repos.MFA,
+		Guard:           guard,
+	}
+	r := gin.New()
+	r.POST("/auth/login", login.Login)
+	r.POST("/auth/forgot-password", forgot.ForgotPassword)
+	r.POST("/auth/reset-password/confirm", reset.ConfirmReset)
+	send := func(path, body string) (int, map[string]interface{}) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+		var out map[string]interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil {
+			t.Fatalf("%s: %d %s", path, w.Code, w.Body)
+		}
+		return w.Code, out
+	}
+
+	if code, _ := send("/auth/login", `{"email":"co@example.com","password":"anything-at-all"}`); code != http.StatusUnauthorized {
+		t.Errorf("no password set yet: %d, want 401", code)
+	}
+	if code, _ := send("/auth/forgot-password", `{"email":"co@example.com"}`); code != http.StatusOK {
+		t.Fatalf("forgot password: %d", code)
+	}
+	token := strings.Split(<-mail, "\r\n\r\n")[1] // without PASSWORD_RESET_URL the link is the token
+	if code, _ := send("/auth/reset-password/confirm", `{"token":"`+token+`","new_password":"coordinator-pass"}`); code != http.StatusOK {
+		t.Fatalf("confirm reset: %d", code)
+	}
+	code, out := send("/auth/login", `{"email":"co@example.com","password":"coordinator-pass"}`)
+	if code != http.StatusOK || out["role"] != RoleCoordinator || out["token"] == "" {
+		t.Errorf("login: %d, %v", code, out)
+	}
+	if cs, err := repos.Coordinator.GetCoordinatorByEmail("co@example.com"); err != nil || cs.CoordinatorPassword == "coordinator-pass" {
+		t.Errorf("stored password: %v", err)
+	}
+}
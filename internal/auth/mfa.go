@@ -0,0 +1,495 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"trl-research-backend/internal/audit"
+	"trl-research-backend/internal/models"
+	"trl-research-backend/internal/repository"
+	"trl-research-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MFAHandler จัดการ TOTP 2FA ของ admin: enroll, ยืนยันตอน login, recovery code และปิด/รีเซ็ต
+type MFAHandler struct {
+	MFA      repository.MFARepository
+	Sessions repository.SessionRepository
+	Guard    *LoginGuard
+	Audit    *audit.Logger // 2FA enabled at login, the /trl routes are logged by the middleware
+}
+
+type MFACodeReq struct {
+	MFAToken     string `json:"mfa_token"`
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+const recoveryCodeCount = 10
+
+var errInvalidMFACode = errors.New("invalid code")
+
+// adminMFARequired - ADMIN_MFA_REQUIRED=true makes 2FA mandatory for every admin
+func adminMFARequired() bool {
+	required, _ := strconv.ParseBool(os.Getenv("ADMIN_MFA_REQUIRED"))
+	return required
+}
+
+// mfaAllowed - only admin accounts can enroll
+func mfaAllowed(role string) bool {
+	return role == RoleAdmin
+}
+
+func mfaRequired(role string) bool {
+	return role == RoleAdmin && adminMFARequired()
+}
+
+// mfaPendingTTL - MFA_PENDING_EXPIRY_MINUTES (default 5)
+func mfaPendingTTL() time.Duration {
+	return time.Duration(envInt("MFA_PENDING_EXPIRY_MINUTES", 5)) * time.Minute
+}
+
+// mfaAudience - the pending token gets its own audience, so AuthMiddleware never accepts it
+func mfaAudience() string {
+	return os.Getenv("JWT_AUDIENCE") + "/mfa"
+}
+
+func mfaIssuer() string {
+	if issuer := os.Getenv("MFA_ISSUER"); issuer != "" {
+		return issuer
+	}
+	return "TRL Research"
+}
+
+// signMFAToken - "mfa_pending" token: the password was right, the second factor is still missing
+func signMFAToken(userID, userEmail, role string) (string, error) {
+	kp, err := utils.SharedKeyProvider()
+	if err != nil {
+		return "", err
+	}
+	return utils.GenerateJWT(userID, userEmail, role, "", "", "",
+		os.Getenv("JWT_ISSUER"), mfaAudience(), kp.ActiveKID(), mfaPendingTTL(), *kp)
+}
+
+func parseMFAToken(token string) (*utils.Claims, error) {
+	kp, err := utils.SharedKeyProvider()
+	if err != nil {
+		return nil, err
+	}
+	return utils.ValidateJWT(token, os.Getenv("JWT_ISSUER"), mfaAudience(), *kp)
+}
+
+// mfaChallenge returns the "mfa_pending" response when the user still has to pass the
+// second factor (or enroll first because the policy requires it), nil when the password is enough
+func mfaChallenge(repo repository.MFARepository, userID, userEmail, role string) (gin.H, error) {
+	if !mfaAllowed(role) {
+		return nil, nil
+	}
+	enabled := false
+	m, err := repo.GetMFA(userID)
+	if err == nil {
+		enabled = m.Enabled
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+	if !enabled && !mfaRequired(role) {
+		return nil, nil
+	}
+
+	token, err := signMFAToken(userID, userEmail, role)
+	if err != nil {
+		return nil, err
+	}
+	return gin.H{
+		"mfa_required":            true,
+		"mfa_enrollment_required": !enabled,
+		"mfa_token":               token,
+		"expires_in":              int(mfaPendingTTL().Seconds()),
+	}, nil
+}
+
+// newRecoveryCodes - "xxxxx-xxxxx" codes shown once, plus the hashes to store
+func newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	enc := base32.StdEncoding.WithPadding(base32.NoPadding)
+	for i := 0; i < recoveryCodeCount; i++ {
+		b := make([]byte, 7)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		raw := strings.ToLower(enc.EncodeToString(b))[:10]
+		codes = append(codes, raw[:5]+"-"+raw[5:])
+		hashes = append(hashes, sha256Hex(raw))
+	}
+	return codes, hashes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
+
+// verifySecondFactor accepts either a TOTP code (each time step once) or an unused
+// recovery code; recoveryLeft is -1 when a TOTP code was used
+func (h *MFAHandler) verifySecondFactor(m *models.MFAEnrollment, code, recoveryCode string) (int, error) {
+	if recoveryCode != "" {
+		left, err := h.MFA.UseRecoveryCode(m.UserID, sha256Hex(normalizeRecoveryCode(recoveryCode)))
+		if errors.Is(err, repository.ErrNotFound) {
+			return 0, errInvalidMFACode
+		}
+		return left, err
+	}
+
+	step, ok := utils.VerifyTOTP(m.Secret, code, time.Now(), 1)
+	if !ok {
+		return 0, errInvalidMFACode
+	}
+	if err := h.MFA.UseTOTPStep(m.UserID, step); errors.Is(err, repository.ErrConflict) {
+		return 0, errInvalidMFACode
+	} else if err != nil {
+		return 0, err
+	}
+	return -1, nil
+}
+
+// checkCode runs verifySecondFactor behind the login guard and writes the error response;
+// wrong codes count as failed logins of the account
+func (h *MFAHandler) checkCode(c *gin.Context, m *models.MFAEnrollment, email, code, recoveryCode, action string) (int, bool) {
+	if code == "" && recoveryCode == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "code or recovery_code is required"})
+		return 0, false
+	}
+	if !h.Guard.Allow(c, email, action) {
+		return 0, false
+	}
+	left, err := h.verifySecondFactor(m, code, recoveryCode)
+	if errors.Is(err, errInvalidMFACode) {
+		h.Guard.Failed(c, email, action)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return 0, false
+	}
+	if err != nil {
+		log.Printf("❌ [MFA] verify %s: %v", m.UserID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return 0, false
+	}
+	return left, true
+}
+
+// pendingClaims reads the mfa_token of a /auth/mfa/* request
+func pendingClaims(c *gin.Context, token string) (*utils.Claims, bool) {
+	if token == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "mfa_token is required"})
+		return nil, false
+	}
+	claims, err := parseMFAToken(token)
+	if err != nil || !mfaAllowed(claims.Role) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired mfa_token"})
+		return nil, false
+	}
+	return claims, true
+}
+
+// beginEnrollment stores a new, not yet enabled secret and returns it with the otpauth:// URI
+func (h *MFAHandler) beginEnrollment(c *gin.Context, userID, userEmail string) {
+	if m, err := h.MFA.GetMFA(userID); err == nil && m.Enabled {
+		c.JSON(http.StatusConflict, gin.H{"error": "2FA is already enabled"})
+		return
+	} else if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		log.Printf("❌ [MFA] read %s: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+	if err := h.MFA.SaveMFA(&models.MFAEnrollment{UserID: userID, UserEmail: userEmail, Secret: secret}); err != nil {
+		log.Printf("❌ [MFA] save enrollment of %s: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"secret":      secret,
+		"otpauth_uri": utils.TOTPProvisioningURI(mfaIssuer(), userEmail, secret),
+		"message":     "scan the QR code, then confirm with a code from the app",
+	})
+}
+
+// confirmEnrollment enables 2FA once code matches the pending secret and returns the recovery codes
+func (h *MFAHandler) confirmEnrollment(c *gin.Context, userID, code string) ([]string, bool) {
+	m, err := h.MFA.GetMFA(userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "start enrollment first"})
+		return nil, false
+	}
+	if err != nil {
+		log.Printf("❌ [MFA] read %s: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return nil, false
+	}
+	if m.Enabled {
+		c.JSON(http.StatusConflict, gin.H{"error": "2FA is already enabled"})
+		return nil, false
+	}
+	if code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "code is required"})
+		return nil, false
+	}
+	if !h.Guard.Allow(c, m.UserEmail, "mfa_enroll") {
+		return nil, false
+	}
+	step, ok := utils.VerifyTOTP(m.Secret, code, time.Now(), 1)
+	if !ok {
+		h.Guard.Failed(c, m.UserEmail, "mfa_enroll")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": errInvalidMFACode.Error()})
+		return nil, false
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return nil, false
+	}
+	m.Enabled = true
+	m.EnabledAt = time.Now()
+	m.LastUsedStep = step
+	m.RecoveryCodeHashes = hashes
+	if err := h.MFA.SaveMFA(m); err != nil {
+		log.Printf("❌ [MFA] enable %s: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return nil, false
+	}
+	log.Printf("🔐 2FA enabled for %s", m.UserEmail)
+	return codes, true
+}
+
+// finishLogin - second factor passed, start the real session
+func (h *MFAHandler) finishLogin(c *gin.Context, claims *utils.Claims, action string) (gin.H, bool) {
+	h.Guard.Succeeded(c, claims.UserEmail, claims.UserID, claims.Role, action)
+	resp, err := startSession(c, h.Sessions, claims.UserID, claims.UserEmail, claims.Role)
+	if err != nil {
+		log.Printf("❌ [MFA] failed to start session for %s: %v", claims.UserID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "cannot generate token"})
+		return nil, false
+	}
+	return resp, true
+}
+
+// 🟢 POST /auth/mfa/verify - second login step: mfa_token + TOTP code (or a recovery code)
+func (h *MFAHandler) Verify(c *gin.Context) {
+	var req MFACodeReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+	claims, ok := pendingClaims(c, req.MFAToken)
+	if !ok {
+		return
+	}
+
+	m, err := h.MFA.GetMFA(claims.UserID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		log.Printf("❌ [MFA] read %s: %v", claims.UserID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+	if err != nil || !m.Enabled {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "2FA is not enrolled, use /auth/mfa/enroll"})
+		return
+	}
+
+	left, ok := h.checkCode(c, m, claims.UserEmail, req.Code, req.RecoveryCode, "mfa")
+	if !ok {
+		return
+	}
+	resp, ok := h.finishLogin(c, claims, "mfa")
+	if !ok {
+		return
+	}
+	if left >= 0 {
+		resp["recovery_codes_left"] = left
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// 🟢 POST /auth/mfa/enroll - enroll during login when ADMIN_MFA_REQUIRED forces it
+func (h *MFAHandler) EnrollPending(c *gin.Context) {
+	var req MFACodeReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+	claims, ok := pendingClaims(c, req.MFAToken)
+	if !ok {
+		return
+	}
+	h.beginEnrollment(c, claims.UserID, claims.UserEmail)
+}
+
+// 🟢 POST /auth/mfa/enroll/confirm - finish the forced enrollment and log in
+func (h *MFAHandler) ConfirmPending(c *gin.Context) {
+	var req MFACodeReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+	claims, ok := pendingClaims(c, req.MFAToken)
+	if !ok {
+		return
+	}
+	codes, ok := h.confirmEnrollment(c, claims.UserID, req.Code)
+	if !ok {
+		return
+	}
+	resp, ok := h.finishLogin(c, claims, "mfa_enroll")
+	if !ok {
+		return
+	}
+	resp["recovery_codes"] = codes
+	c.JSON(http.StatusOK, resp)
+	audited(h.Audit, c, claims.UserID, claims.UserEmail, claims.Role, "mfa", "enable")
+}
+
+// 🟢 GET /trl/mfa - 2FA state of the signed-in admin
+func (h *MFAHandler) GetStatus(c *gin.Context) {
+	userID := c.GetString("userID")
+	resp := gin.H{"enabled": false, "required": mfaRequired(c.GetString("role")), "recovery_codes_left": 0}
+	m, err := h.MFA.GetMFA(userID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		log.Printf("❌ [GetStatus] %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if err == nil && m.Enabled {
+		resp["enabled"] = true
+		resp["enabled_at"] = m.EnabledAt
+		resp["recovery_codes_left"] = len(m.RecoveryCodeHashes)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// 🟢 POST /trl/mfa/enroll - new TOTP secret + otpauth:// URI for the QR code
+func (h *MFAHandler) Enroll(c *gin.Context) {
+	h.beginEnrollment(c, c.GetString("userID"), c.GetString("userEmail"))
+}
+
+// 🟢 POST /trl/mfa/enroll/confirm - {"code": "123456"} enables 2FA and returns recovery codes once
+func (h *MFAHandler) ConfirmEnroll(c *gin.Context) {
+	var req MFACodeReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+	codes, ok := h.confirmEnrollment(c, c.GetString("userID"), req.Code)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "2FA enabled", "recovery_codes": codes})
+}
+
+// enabledMFA loads the enrollment of the signed-in user, 400 when 2FA is off
+func (h *MFAHandler) enabledMFA(c *gin.Context) (*models.MFAEnrollment, bool) {
+	m, err := h.MFA.GetMFA(c.GetString("userID"))
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		log.Printf("❌ [MFA] read %s: %v", c.GetString("userID"), err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return nil, false
+	}
+	if err != nil || !m.Enabled {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "2FA is not enabled"})
+		return nil, false
+	}
+	return m, true
+}
+
+// 🟢 POST /trl/mfa/recovery-codes - {"code": "123456"} replaces every recovery code
+func (h *MFAHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	var req MFACodeReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+	m, ok := h.enabledMFA(c)
+	if !ok {
+		return
+	}
+	if _, ok := h.checkCode(c, m, m.UserEmail, req.Code, "", "mfa"); !ok {
+		return
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+	// re-read: checkCode moved last_used_step forward
+	m, ok = h.enabledMFA(c)
+	if !ok {
+		return
+	}
+	m.RecoveryCodeHashes = hashes
+	if err := h.MFA.SaveMFA(m); err != nil {
+		log.Printf("❌ [RegenerateRecoveryCodes] %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"recovery_codes": codes})
+}
+
+// 🟢 POST /trl/mfa/disable - {"code": "123456"} turns 2FA off (not allowed while ADMIN_MFA_REQUIRED)
+func (h *MFAHandler) Disable(c *gin.Context) {
+	if mfaRequired(c.GetString("role")) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "2FA is mandatory for this role"})
+		return
+	}
+	var req MFACodeReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+	m, ok := h.enabledMFA(c)
+	if !ok {
+		return
+	}
+	if _, ok := h.checkCode(c, m, m.UserEmail, req.Code, req.RecoveryCode, "mfa"); !ok {
+		return
+	}
+	if err := h.MFA.DeleteMFA(m.UserID); err != nil {
+		log.Printf("❌ [Disable] %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+	log.Printf("🔓 2FA disabled for %s", m.UserEmail)
+	c.JSON(http.StatusOK, gin.H{"message": "2FA disabled"})
+}
+
+// 🟢 POST /trl/mfa/user/:id/reset - remove the 2FA of a user who lost the device (admin).
+// The user enrolls again on the next login when the policy requires it.
+func (h *MFAHandler) ResetUserMFA(c *gin.Context) {
+	userID := c.Param("id")
+	err := h.MFA.DeleteMFA(userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "2FA is not enrolled"})
+		return
+	}
+	if err != nil {
+		log.Printf("❌ [ResetUserMFA] %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	log.Printf("🔓 %s reset 2FA of %s", c.GetString("userEmail"), userID)
+	c.JSON(http.StatusOK, gin.H{"message": "2FA reset"})
+}
@@ -7,12 +7,16 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"time"
+
+	"trl-research-backend/internal/repository"
 	"trl-research-backend/internal/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
-func AuthMiddleware() gin.HandlerFunc {
+// AuthMiddleware validates the bearer token and rejects it once its session is revoked
+func AuthMiddleware(sessions repository.SessionRepository) gin.HandlerFunc {
     return func(c *gin.Context) {
         header := c.GetHeader("Authorization")
         if header == "" || !strings.HasPrefix(header, "Bearer ") {
@@ -39,7 +43,7 @@ func AuthMiddleware() gin.HandlerFunc {
             return
         }
 
-        kp, err := utils.NewEnvKeyProvider()
+        kp, err := utils.SharedKeyProvider()
         if err != nil {
             log.Printf("❌ Key provider error: %v", err)
             c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("key provider error: %v", err)})
@@ -60,6 +64,15 @@ func AuthMiddleware() gin.HandlerFunc {
             return
         }
 
+        // token must belong to a live session (logout / admin revoke / refresh token reuse)
+        session, err := sessions.GetSessionByID(claims.SessionID)
+        if err != nil || !session.Active(time.Now()) || session.UserID != claims.UserID {
+            log.Printf("❌ Session %q rejected for %s: %v", claims.SessionID, claims.UserID, err)
+            c.JSON(http.StatusUnauthorized, gin.H{"error": "session expired or revoked"})
+            c.Abort()
+            return
+        }
+
         log.Println("✅ AuthMiddleware: JWT claims loaded:")
 		log.Printf("→ UserID: %s", claims.UserID)
 		log.Printf("→ Email: %s", claims.UserEmail)
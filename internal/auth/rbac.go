@@ -0,0 +1,262 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"trl-research-backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Roles carried in utils.Claims.Role
+const (
+	RoleAdmin       = "admin"
+	RoleResearcher  = "researcher"
+	RoleCoordinator = "coordinator"
+)
+
+// OwnerCheck decides whether the calling researcher owns what the request touches
+type OwnerCheck func(c *gin.Context, a *Access, userID string) (bool, error)
+
+// Policy declares who may call a route. Roles are let through as-is; a researcher not
+// listed in Roles is let through only when every Owner check passes.
+type Policy struct {
+	Roles []string
+	Owner []OwnerCheck
+}
+
+func AdminOnly() Policy {
+	return Policy{Roles: []string{RoleAdmin}}
+}
+
+// Staff - admins and coordinators see every case
+func Staff() Policy {
+	return Policy{Roles: []string{RoleAdmin, RoleCoordinator}}
+}
+
+// AnyRole - any signed-in user
+func AnyRole() Policy {
+	return Policy{Roles: []string{RoleAdmin, RoleCoordinator, RoleResearcher}}
+}
+
+// ResearcherOnly - researchers, for what only they have (their drafts)
+func ResearcherOnly() Policy {
+	return Policy{Roles: []string{RoleResearcher}}
+}
+
+func StaffOrOwner(checks ...OwnerCheck) Policy {
+	return Policy{Roles: []string{RoleAdmin, RoleCoordinator}, Owner: checks}
+}
+
+func AdminOrOwner(checks ...OwnerCheck) Policy {
+	return Policy{Roles: []string{RoleAdmin}, Owner: checks}
+}
+
+// Access evaluates policies, loading records through the repositories when a
+// researcher's ownership has to be proven
+type Access struct {
+	Repos *repository.Repositories
+}
+
+// Require must run after AuthMiddleware
+func (a *Access) Require(p Policy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := c.GetString("role")
+		userID := c.GetString("userID")
+
+		for _, allowed := range p.Roles {
+			if role == allowed {
+				c.Next()
+				return
+			}
+		}
+
+		if role == RoleResearcher && len(p.Owner) > 0 {
+			for _, check := range p.Owner {
+				ok, err := check(c, a, userID)
+				if err != nil {
+					log.Printf("⚠️ [RBAC] ownership check failed for %s on %s: %v", userID, c.FullPath(), err)
+				}
+				if err != nil || !ok {
+					c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+					return
+				}
+			}
+			c.Next()
+			return
+		}
+
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+	}
+}
+
+// ownsCase - the case exists and belongs to the researcher
+func (a *Access) ownsCase(caseID, userID string) (bool, error) {
+	if caseID == "" {
+		return false, nil
+	}
+	cs, err := a.Repos.Case.GetCaseByID(caseID)
+	if err != nil {
+		return false, err
+	}
+	return cs.ResearcherID == userID, nil
+}
+
+// SelfParam - the :param is the caller's own researcher ID
+func SelfParam(param string) OwnerCheck {
+	return func(c *gin.Context, a *Access, userID string) (bool, error) {
+		return c.Param(param) == userID, nil
+	}
+}
+
+// SelfBody - the body field, when present, is the caller's own researcher ID; field may be a
+// dotted path into nested objects
+func SelfBody(field string, required bool) OwnerCheck {
+	return func(c *gin.Context, a *Access, userID string) (bool, error) {
+		value, present, err := peekBodyString(c, field)
+		if err != nil {
+			return false, err
+		}
+		if !present {
+			return !required, nil
+		}
+		return value == userID, nil
+	}
+}
+
+// OwnsCaseParam - the :param is a case ID owned by the caller
+func OwnsCaseParam(param string) OwnerCheck {
+	return func(c *gin.Context, a *Access, userID string) (bool, error) {
+		return a.ownsCase(c.Param(param), userID)
+	}
+}
+
+// OwnsBodyCase - the body field (case_id, belongs_to_case_id) names a case owned by the
+// caller. When required is false an absent field passes, so PATCH bodies that don't move
+// the record to another case are fine.
+func OwnsBodyCase(field string, required bool) OwnerCheck {
+	return func(c *gin.Context, a *Access, userID string) (bool, error) {
+		caseID, present, err := peekBodyString(c, field)
+		if err != nil {
+			return false, err
+		}
+		if !present {
+			return !required, nil
+		}
+		return a.ownsCase(caseID, userID)
+	}
+}
+
+// OwnsAppointmentParam - the :param appointment belongs to one of the caller's cases
+func OwnsAppointmentParam(param string) OwnerCheck {
+	return func(c *gin.Context, a *Access, userID string) (bool, error) {
+		ap, err := a.Repos.Appointment.GetAppointmentByID(c.Param(param))
+		if err != nil {
+			return false, err
+		}
+		return a.ownsCase(ap.CaseID, userID)
+	}
+}
+
+// OwnsIPParam - the :param IP record belongs to one of the caller's cases
+func OwnsIPParam(param string) OwnerCheck {
+	return func(c *gin.Context, a *Access, userID string) (bool, error) {
+		ip, err := a.Repos.IntellectualProperty.GetIPByID(c.Param(param))
+		if err != nil {
+			return false, err
+		}
+		return a.ownsCase(ip.CaseID, userID)
+	}
+}
+
+// OwnsSupporterParam - the :param supporter belongs to one of the caller's cases
+func OwnsSupporterParam(param string) OwnerCheck {
+	return func(c *gin.Context, a *Access, userID string) (bool, error) {
+		s, err := a.Repos.Supporter.GetSupporterByID(c.Param(param))
+		if err != nil {
+			return false, err
+		}
+		return a.ownsCase(s.CaseID, userID)
+	}
+}
+
+// OwnsAssessmentParam - the :param assessment belongs to one of the caller's cases
+func OwnsAssessmentParam(param string) OwnerCheck {
+	return func(c *gin.Context, a *Access, userID string) (bool, error) {
+		as, err := a.Repos.AssessmentTrl.GetAssessmentTrlByID(c.Param(param))
+		if err != nil {
+			return false, err
+		}
+		return a.ownsCase(as.CaseID, userID)
+	}
+}
+
+// OwnsDraftParam - the :param draft is one of the caller's
+func OwnsDraftParam(param string) OwnerCheck {
+	return func(c *gin.Context, a *Access, userID string) (bool, error) {
+		d, err := a.Repos.Draft.GetDraftByID(c.Param(param))
+		if err != nil {
+			return false, err
+		}
+		return d.ResearcherID == userID, nil
+	}
+}
+
+// peekBodyString reads one string field from a JSON body, a dotted path (case.researcher_id)
+// for a nested one, and puts the body back so the handler can still bind it. Keys match the
+// way encoding/json binds them into the handler's struct, in any letter case. A body that
+// isn't JSON, or with more than one key for the field, is an error: the check fails.
+func peekBodyString(c *gin.Context, field string) (string, bool, error) {
+	if c.Request.Body == nil {
+		return "", false, nil
+	}
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return "", false, err
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	if len(bytes.TrimSpace(body)) == 0 {
+		return "", false, nil
+	}
+
+	// the first JSON value of the body, as gin's binding reads it
+	var raw interface{}
+	if err := json.NewDecoder(bytes.NewReader(body)).Decode(&raw); err != nil {
+		return "", false, fmt.Errorf("body is not JSON: %w", err)
+	}
+	for _, name := range strings.Split(field, ".") {
+		fields, ok := raw.(map[string]interface{})
+		if !ok {
+			return "", false, nil
+		}
+		if raw, ok, err = bodyField(fields, name); err != nil || !ok {
+			return "", false, err
+		}
+	}
+	value, _ := raw.(string)
+	return value, true, nil
+}
+
+// bodyField - the value of name in a JSON object, the key matched case-insensitively like
+// encoding/json matches struct fields. With two matching keys (case_id, CASE_ID) the struct
+// gets the last one, which a map doesn't tell, so that is an error.
+func bodyField(fields map[string]interface{}, name string) (interface{}, bool, error) {
+	var value interface{}
+	found := false
+	for key, v := range fields {
+		if !strings.EqualFold(key, name) {
+			continue
+		}
+		if found {
+			return nil, false, fmt.Errorf("body has more than one %s field", name)
+		}
+		value, found = v, true
+	}
+	return value, found, nil
+}
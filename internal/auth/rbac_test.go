@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"trl-research-backend/internal/models"
+	"trl-research-backend/internal/repository/memory"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bodyRouter serves POST /x behind policy for researcher RS-me, who owns CS-00001; CS-00002 is
+// someone else's. The handler binds the body like the real ones and answers the case it got.
+func bodyRouter(t *testing.T, policy Policy) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	repos := memory.NewRepositories()
+	for _, owner := range []string{"RS-me", "RS-other"} {
+		if err := repos.Case.CreateCase(&models.CaseInfo{ResearcherID: owner}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	access := &Access{Repos: repos}
+
+	r := gin.New()
+	r.POST("/x", func(c *gin.Context) {
+		c.Set("userID", "RS-me")
+		c.Set("role", RoleResearcher)
+	}, access.Require(policy), func(c *gin.Context) {
+		var req struct {
+			models.Supporter
+			Case models.CaseInfo `json:"case"`
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		c.String(http.StatusOK, req.CaseID+req.Case.ResearcherID)
+	})
+	return r
+}
+
+func post(r *gin.Engine, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/x", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestOwnsBodyCaseMatchesTheBoundField(t *testing.T) {
+	for _, required := range []bool{true, false} {
+		r := bodyRouter(t, AdminOrOwner(OwnsBodyCase("case_id", required)))
+		tests := []struct {
+			name string
+			body string
+			code int
+		}{
+			{"own case", `{"case_id":"CS-00001"}`, http.StatusOK},
+			{"other case", `{"case_id":"CS-00002"}`, http.StatusForbidden},
+			{"case variant key", `{"CASE_ID":"CS-00002"}`, http.StatusForbidden},
+			{"unicode fold key", `{"caſe_id":"CS-00002"}`, http.StatusForbidden},
+			{"own then case variant", `{"case_id":"CS-00001","CASE_ID":"CS-00002"}`, http.StatusForbidden},
+			{"case variant then own", `{"Case_Id":"CS-00002","case_id":"CS-00001"}`, http.StatusForbidden},
+			{"duplicate key binds the last", `{"case_id":"CS-00001","case_id":"CS-00002"}`, http.StatusForbidden},
+			{"trailing data", `{"case_id":"CS-00002"} {"case_id":"CS-00001"}`, http.StatusForbidden},
+			{"not json", `{"case_id":"CS-00002"`, http.StatusForbidden},
+		}
+		for _, tt := range tests {
+			w := post(r, tt.body)
+			if w.Code != tt.code {
+				t.Errorf("required=%v %s: got %d %q, want %d", required, tt.name, w.Code, w.Body.String(), tt.code)
+			}
+			if w.Code == http.StatusOK && w.Body.String() != "CS-00001" {
+				t.Errorf("required=%v %s: handler bound %q", required, tt.name, w.Body.String())
+			}
+		}
+	}
+}
+
+func TestSelfBodyNestedField(t *testing.T) {
+	r := bodyRouter(t, AdminOrOwner(SelfBody("case.researcher_id", false)))
+	tests := []struct {
+		name string
+		body string
+		code int
+	}{
+		{"self", `{"case":{"researcher_id":"RS-me"}}`, http.StatusOK},
+		{"absent", `{"case":{}}`, http.StatusOK},
+		{"someone else", `{"case":{"researcher_id":"RS-other"}}`, http.StatusForbidden},
+		{"case variant parent", `{"case":{"researcher_id":"RS-me"},"CASE":{"researcher_id":"RS-other"}}`, http.StatusForbidden},
+		{"case variant field", `{"case":{"Researcher_ID":"RS-other"}}`, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		if w := post(r, tt.body); w.Code != tt.code {
+			t.Errorf("%s: got %d %q, want %d", tt.name, w.Code, w.Body.String(), tt.code)
+		}
+	}
+}
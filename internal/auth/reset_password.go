@@ -1,8 +1,12 @@
 package auth
 
 import (
+	"errors"
+	"fmt"
+	"log"
 	"net/http"
 
+	"trl-research-backend/internal/audit"
 	"trl-research-backend/internal/repository"
 
 	"github.com/gin-gonic/gin"
@@ -10,7 +14,13 @@ import (
 )
 
 type ResetHandler struct {
-	AdminRepo repository.AdminRepo
+	AdminRepo       repository.AdminRepository
+	ResearcherRepo  repository.ResearcherRepository
+	CoordinatorRepo repository.CoordinatorRepository
+	Resets          repository.PasswordResetRepository
+	Sessions        repository.SessionRepository
+	Guard           *LoginGuard
+	Audit           *audit.Logger
 }
 
 type ResetReq struct {
@@ -19,6 +29,12 @@ type ResetReq struct {
 	NewPassword string `json:"new_password"`
 }
 
+type ConfirmResetReq struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
+}
+
+// 🟢 POST /auth/reset-password - change an admin password with the old one
 func (h *ResetHandler) ResetPassword(c *gin.Context) {
 	var req ResetReq
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -30,11 +46,17 @@ func (h *ResetHandler) ResetPassword(c *gin.Context) {
 		return
 	}
 
-	// verify
-	if _, err := h.AdminRepo.Login(req.Email, req.OldPassword); err != nil {
+	// verify (old password guesses count against the same lockout as logins)
+	if !h.Guard.Allow(c, req.Email, "change_password") {
+		return
+	}
+	admin, err := h.AdminRepo.Login(req.Email, req.OldPassword)
+	if err != nil {
+		h.Guard.Failed(c, req.Email, "change_password")
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid credentials"})
 		return
 	}
+	h.Guard.Succeeded(c, req.Email, admin.AdminID, RoleAdmin, "change_password")
 
 	hash, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
 	if err != nil {
@@ -47,4 +69,72 @@ func (h *ResetHandler) ResetPassword(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "password updated"})
+	audited(h.Audit, c, admin.AdminID, req.Email, RoleAdmin, RoleAdmin, "change_password")
+}
+
+// 🟢 POST /auth/reset-password/confirm - set a new password with the emailed token.
+// The token works once and every session of the user is logged out afterwards.
+func (h *ResetHandler) ConfirmReset(c *gin.Context) {
+	var req ConfirmResetReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+	// check the password first so a too-short one doesn't burn the token
+	if req.Token == "" || len(req.NewPassword) < 8 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+
+	reset, err := h.Resets.ConsumePasswordReset(sha256Hex(req.Token))
+	if errors.Is(err, repository.ErrNotFound) || errors.Is(err, repository.ErrConflict) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid or expired token"})
+		return
+	}
+	if err != nil {
+		log.Printf("❌ [ConfirmReset] consume token: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+	switch reset.Role {
+	case RoleAdmin:
+		err = h.AdminRepo.UpdatePasswordByEmail(reset.UserEmail, string(hash))
+	case RoleResearcher:
+		err = h.ResearcherRepo.UpdateResearcherPasswordByID(reset.UserID, string(hash))
+	case RoleCoordinator:
+		err = h.CoordinatorRepo.UpdateCoordinatorPasswordByEmail(reset.UserEmail, string(hash))
+	default:
+		err = fmt.Errorf("unknown role %q", reset.Role)
+	}
+	if err != nil {
+		log.Printf("❌ [ConfirmReset] update password of %s: %v", reset.UserID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+
+	// proving mailbox ownership also lifts a lockout of the account
+	if err := h.Guard.Attempts.ClearLoginFailures(accountKey(reset.UserEmail)); err != nil {
+		log.Printf("⚠️ [ConfirmReset] clear lockout of %s: %v", reset.UserID, err)
+	}
+	if _, err := h.Sessions.RevokeSessionsByUserID(reset.UserID); err != nil {
+		log.Printf("⚠️ [ConfirmReset] revoke sessions of %s: %v", reset.UserID, err)
+	}
+	log.Printf("🔑 password reset for %s (%s)", reset.UserID, reset.Role)
+	c.JSON(http.StatusOK, gin.H{"message": "password updated"})
+	audited(h.Audit, c, reset.UserID, reset.UserEmail, reset.Role, reset.Role, "reset_password")
+}
+
+// audited logs a change made under /auth, before there is a session: the old password, the
+// emailed token or the pending 2FA token is what proves the actor
+func audited(l *audit.Logger, c *gin.Context, userID, email, role, entity, action string) {
+	c.Set("userID", userID)
+	c.Set("userEmail", email)
+	c.Set("role", role)
+	l.Record(c, entity, userID, action, nil, nil)
 }
@@ -0,0 +1,241 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"trl-research-backend/internal/models"
+	"trl-research-backend/internal/repository"
+	"trl-research-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// SessionHandler จัดการ refresh token, logout และการยกเลิก session
+type SessionHandler struct {
+	Sessions repository.SessionRepository
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+var errInvalidRefreshToken = errors.New("invalid refresh token")
+
+// accessTokenTTL - JWT_ACCESS_EXPIRY_MINUTES (default 15)
+func accessTokenTTL() time.Duration {
+	m, _ := strconv.Atoi(os.Getenv("JWT_ACCESS_EXPIRY_MINUTES"))
+	if m <= 0 {
+		m = 15
+	}
+	return time.Duration(m) * time.Minute
+}
+
+// refreshTokenTTL - JWT_REFRESH_EXPIRY_HOURS, falling back to the old JWT_EXPIRY (default 168)
+func refreshTokenTTL() time.Duration {
+	h, _ := strconv.Atoi(os.Getenv("JWT_REFRESH_EXPIRY_HOURS"))
+	if h <= 0 {
+		h, _ = strconv.Atoi(os.Getenv("JWT_EXPIRY"))
+	}
+	if h <= 0 {
+		h = 168
+	}
+	return time.Duration(h) * time.Hour
+}
+
+// newRandomToken - 32 random bytes, base64url (refresh and password reset secrets)
+func newRandomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func sha256Hex(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// splitRefreshToken - "<session_id>.<secret>"
+func splitRefreshToken(token string) (string, string, error) {
+	sessionID, secret, ok := strings.Cut(token, ".")
+	if !ok || sessionID == "" || secret == "" {
+		return "", "", errInvalidRefreshToken
+	}
+	return sessionID, secret, nil
+}
+
+// signAccessToken - short-lived RS256 token bound to a session
+func signAccessToken(s *models.Session) (string, error) {
+	kp, err := utils.SharedKeyProvider()
+	if err != nil {
+		return "", err
+	}
+	return utils.GenerateJWT(
+		s.UserID,
+		s.UserEmail,
+		s.Role,
+		"", "", // clientID, clientName (optional)
+		s.SessionID,
+		os.Getenv("JWT_ISSUER"),
+		os.Getenv("JWT_AUDIENCE"),
+		kp.ActiveKID(),
+		accessTokenTTL(),
+		*kp,
+	)
+}
+
+// tokenResponse - body shared by /auth/login and /auth/refresh
+func tokenResponse(accessToken, refreshToken, role string) gin.H {
+	return gin.H{
+		"token":              accessToken,
+		"token_type":         "Bearer",
+		"expires_in":         int(accessTokenTTL().Seconds()),
+		"refresh_token":      refreshToken,
+		"refresh_expires_in": int(refreshTokenTTL().Seconds()),
+		"role":               role,
+	}
+}
+
+// startSession สร้าง session ใหม่หลัง login สำเร็จ แล้วคืน access + refresh token
+func startSession(c *gin.Context, sessions repository.SessionRepository, userID, userEmail, role string) (gin.H, error) {
+	secret, err := newRandomToken()
+	if err != nil {
+		return nil, err
+	}
+	s := &models.Session{
+		SessionID:        uuid.NewString(),
+		UserID:           userID,
+		UserEmail:        userEmail,
+		Role:             role,
+		RefreshTokenHash: sha256Hex(secret),
+		UserAgent:        c.Request.UserAgent(),
+		IPAddress:        c.ClientIP(),
+		ExpiresAt:        time.Now().Add(refreshTokenTTL()),
+	}
+	if err := sessions.CreateSession(s); err != nil {
+		return nil, err
+	}
+
+	accessToken, err := signAccessToken(s)
+	if err != nil {
+		return nil, err
+	}
+	return tokenResponse(accessToken, s.SessionID+"."+secret, role), nil
+}
+
+// 🟢 POST /auth/refresh - exchange a refresh token for a new access + refresh token pair.
+// Each refresh token works once; presenting an old one again revokes the whole session.
+func (h *SessionHandler) Refresh(c *gin.Context) {
+	var req RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.RefreshToken == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+	sessionID, secret, err := splitRefreshToken(req.RefreshToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	s, err := h.Sessions.GetSessionByID(sessionID)
+	if err != nil || !s.Active(time.Now()) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": errInvalidRefreshToken.Error()})
+		return
+	}
+
+	newSecret, err := newRandomToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+	expiresAt := time.Now().Add(refreshTokenTTL())
+	err = h.Sessions.RotateSession(sessionID, sha256Hex(secret), sha256Hex(newSecret), expiresAt)
+	if errors.Is(err, repository.ErrConflict) {
+		// an already-used refresh token: assume it was stolen and end the session for everyone
+		log.Printf("⚠️ [Refresh] refresh token reuse on session %s (user %s), revoking", sessionID, s.UserID)
+		if err := h.Sessions.RevokeSession(sessionID); err != nil {
+			log.Printf("❌ [Refresh] revoke session %s: %v", sessionID, err)
+		}
+		c.JSON(http.StatusUnauthorized, gin.H{"error": errInvalidRefreshToken.Error()})
+		return
+	}
+	if err != nil {
+		log.Printf("❌ [Refresh] rotate session %s: %v", sessionID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+
+	accessToken, err := signAccessToken(s)
+	if err != nil {
+		log.Printf("❌ [Refresh] sign access token: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "cannot generate token"})
+		return
+	}
+	c.JSON(http.StatusOK, tokenResponse(accessToken, sessionID+"."+newSecret, s.Role))
+}
+
+// 🟢 POST /auth/logout - revoke the session the refresh token belongs to
+func (h *SessionHandler) Logout(c *gin.Context) {
+	var req RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.RefreshToken == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		return
+	}
+	sessionID, secret, err := splitRefreshToken(req.RefreshToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	s, err := h.Sessions.GetSessionByID(sessionID)
+	if err != nil || subtle.ConstantTimeCompare([]byte(s.RefreshTokenHash), []byte(sha256Hex(secret))) != 1 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": errInvalidRefreshToken.Error()})
+		return
+	}
+	if err := h.Sessions.RevokeSession(sessionID); err != nil {
+		log.Printf("❌ [Logout] revoke session %s: %v", sessionID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "logged out"})
+}
+
+// 🟢 GET /trl/sessions/user/:id - sessions of a user (admin)
+func (h *SessionHandler) GetSessionsByUserID(c *gin.Context) {
+	sessions, err := h.Sessions.GetSessionsByUserID(c.Param("id"))
+	if err != nil {
+		log.Printf("❌ [GetSessionsByUserID] %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if sessions == nil {
+		sessions = []models.Session{}
+	}
+	c.JSON(http.StatusOK, sessions)
+}
+
+// 🟢 POST /trl/sessions/user/:id/revoke - log a user out everywhere (admin)
+func (h *SessionHandler) RevokeSessionsByUserID(c *gin.Context) {
+	userID := c.Param("id")
+	revoked, err := h.Sessions.RevokeSessionsByUserID(userID)
+	if err != nil {
+		log.Printf("❌ [RevokeSessionsByUserID] %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	log.Printf("🔒 %s revoked %d session(s) of %s", c.GetString("userEmail"), revoked, userID)
+	c.JSON(http.StatusOK, gin.H{"message": "sessions revoked", "revoked": revoked})
+}
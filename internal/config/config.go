@@ -3,13 +3,19 @@ package config
 import (
 	"log"
 	"os"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	DBUrl string
-	Port  string
+	DBUrl          string
+	Port           string
+	StorageBackend string // "firestore", "postgres" or "memory"
+
+	SearchIndexPath    string        // "" keeps the search index in memory
+	SearchThaiDict     string        // a file of Thai words for the search tokenizer, on top of the embedded ones
+	SearchSyncInterval time.Duration // how often the search index catches up with the database
 }
 
 func LoadConfig() Config {
@@ -24,8 +30,35 @@ func LoadConfig() Config {
 		dbURL = os.Getenv("DATABASE_URL")
 	}
 
+	// DB_URL switches to PostgreSQL unless STORAGE_BACKEND says otherwise
+	storageBackend := os.Getenv("STORAGE_BACKEND")
+	if storageBackend == "" && dbURL != "" {
+		storageBackend = "postgres"
+	}
+	if storageBackend == "" {
+		storageBackend = "firestore"
+	}
+
+	// the search index lives next to the server, in memory with the in-memory storage
+	searchIndexPath, ok := os.LookupEnv("SEARCH_INDEX_PATH")
+	if !ok && storageBackend != "memory" {
+		searchIndexPath = "search.bleve"
+	}
+	searchSyncInterval := time.Minute
+	if v := os.Getenv("SEARCH_SYNC_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			log.Fatalf("❌ SEARCH_SYNC_INTERVAL %q is not a duration like 30s or 5m", v)
+		}
+		searchSyncInterval = d
+	}
+
 	return Config{
-		DBUrl: dbURL,
-		Port:  os.Getenv("PORT"),
+		DBUrl:              dbURL,
+		Port:               os.Getenv("PORT"),
+		StorageBackend:     storageBackend,
+		SearchIndexPath:    searchIndexPath,
+		SearchThaiDict:     os.Getenv("SEARCH_THAI_DICT"),
+		SearchSyncInterval: searchSyncInterval,
 	}
 }
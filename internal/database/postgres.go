@@ -0,0 +1,38 @@
+package database
+
+import (
+	"context"
+	"log"
+
+	"trl-research-backend/internal/repository/postgres"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var PostgresPool *pgxpool.Pool
+
+// InitPostgres connects to DB_URL and applies pending schema migrations
+func InitPostgres(dbURL string) {
+	ctx := context.Background()
+
+	pool, err := pgxpool.New(ctx, dbURL)
+	if err != nil {
+		log.Fatalf("❌ PostgreSQL init error: %v", err)
+	}
+	if err := pool.Ping(ctx); err != nil {
+		log.Fatalf("❌ PostgreSQL ping error: %v", err)
+	}
+	if err := postgres.Migrate(ctx, pool); err != nil {
+		log.Fatalf("❌ PostgreSQL migration error: %v", err)
+	}
+
+	PostgresPool = pool
+	log.Println("✅ PostgreSQL initialized")
+}
+
+func ClosePostgres() {
+	if PostgresPool != nil {
+		PostgresPool.Close()
+		log.Println("🛑 PostgreSQL pool closed")
+	}
+}
@@ -5,9 +5,11 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"trl-research-backend/internal/entity"
+	"trl-research-backend/internal/listing"
 	"trl-research-backend/internal/models"
 	"trl-research-backend/internal/repository"
 	"trl-research-backend/internal/utils"
@@ -16,17 +18,13 @@ import (
 )
 
 type AdminHandler struct {
-	Repo *repository.AdminRepo
+	Repo     repository.AdminRepository
+	Sessions repository.SessionRepository
 }
 
 // 🟢 GET /admins
 func (h *AdminHandler) GetAllAdmins(c *gin.Context) {
-	admins, err := h.Repo.GetAdminAll()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-	c.JSON(http.StatusOK, admins)
+	listItems(c, adminList, h.Repo.ListAdmins)
 }
 
 // 🟢 GET /admin/:id
@@ -51,7 +49,7 @@ func (h *AdminHandler) GetAdminProfile(c *gin.Context) {
 
 	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 	// Validate and decode JWT
-	kp, err := utils.NewEnvKeyProvider()
+	kp, err := utils.SharedKeyProvider()
 	if err != nil {
 		log.Printf("❌ [GetAdminProfile] Key provider error: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Key provider error"})
@@ -89,6 +87,7 @@ func (h *AdminHandler) CreateAdmin(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	req.Deletion = models.Deletion{} // only DELETE puts a record in the trash
 
 	if err := h.Repo.CreateAdmin(&req); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -98,6 +97,29 @@ func (h *AdminHandler) CreateAdmin(c *gin.Context) {
 	c.JSON(http.StatusOK, req)
 }
 
+// bootstrapMu - one first admin at a time, the check and the create belong together
+var bootstrapMu sync.Mutex
+
+// 🟢 POST /admin outside /trl (public) - creates the first admin of an empty system, body as in
+// POST /trl/admin. 409 once there is an admin, deleted ones included: from then on admins
+// are created by an admin.
+func (h *AdminHandler) BootstrapAdmin(c *gin.Context) {
+	bootstrapMu.Lock()
+	defer bootstrapMu.Unlock()
+
+	page, err := h.Repo.ListAdmins(listing.Query{Spec: adminList, Sort: adminList.Key, Limit: 1, Deleted: listing.IncludeDeleted})
+	if err != nil {
+		log.Printf("❌ [BootstrapAdmin] list admins: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if len(page.Items) > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "an admin already exists, ask an admin to create your account"})
+		return
+	}
+	h.CreateAdmin(c)
+}
+
 // update password
 func (h *AdminHandler) UpdatePassword(c *gin.Context) {
 	var req models.AdminInfo
@@ -197,12 +219,26 @@ func (h *AdminHandler) Login(c *gin.Context) {
 	}
 }
 
-// delete admin
+// 🟢 DELETE /admin/:id - soft delete, the admin is signed out everywhere; not yourself
 func (h *AdminHandler) DeleteAdmin(c *gin.Context) {
-	email := c.Param("email")
-	if err := h.Repo.DeleteAdmin(email); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	id := c.Param("id")
+	if id == c.GetString("userID") {
+		c.JSON(http.StatusConflict, gin.H{"error": "You can't delete your own admin account"})
+		return
+	}
+	if !trashed(c, "DeleteAdmin", "Admin", h.Repo.DeleteAdmin(id, deletedBy(c))) {
 		return
 	}
+	if _, err := h.Sessions.RevokeSessionsByUserID(id); err != nil {
+		log.Printf("❌ [DeleteAdmin] revoking sessions of %s: %v", id, err)
+	}
 	c.JSON(http.StatusOK, gin.H{"message": "Admin deleted successfully"})
 }
+
+// 🟢 POST /admin/:id/restore
+func (h *AdminHandler) RestoreAdmin(c *gin.Context) {
+	if !trashed(c, "RestoreAdmin", "Deleted admin", h.Repo.RestoreAdmin(c.Param("id"))) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Admin restored successfully"})
+}
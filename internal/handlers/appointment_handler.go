@@ -9,17 +9,13 @@ import (
 )
 
 type AppointmentHandler struct {
-	Repo *repository.AppointmentRepo
+	Repo  repository.AppointmentRepository
+	Cases repository.CaseRepository // a restore needs the case live
 }
 
 // 🟢 GET /appointments
 func (h *AppointmentHandler) GetAppointmentAll(c *gin.Context) {
-	appointments, err := h.Repo.GetAppointmentAll()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-	c.JSON(http.StatusOK, appointments)
+	listItems(c, appointmentList, h.Repo.ListAppointments)
 }
 
 // 🟢 GET /appointment/:id
@@ -51,6 +47,7 @@ func (h *AppointmentHandler) CreateAppointment(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	req.Deletion = models.Deletion{} // only DELETE puts a record in the trash
 
 	if err := h.Repo.CreateAppointment(&req); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -60,13 +57,16 @@ func (h *AppointmentHandler) CreateAppointment(c *gin.Context) {
 	c.JSON(http.StatusOK, req)
 }
 
-// 🟢 PATCH /appointment/:id
+// 🟢 PATCH /appointment/:id - only the fields of AppointmentPatch; 422 lists every invalid one
 func (h *AppointmentHandler) UpdateAppointmentByID(c *gin.Context) {
 	id := c.Param("id")
-	var updateData map[string]interface{}
-
-	if err := c.ShouldBindJSON(&updateData); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	current, err := h.Repo.GetAppointmentByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Appointment not found"})
+		return
+	}
+	updateData, ok := bindPatch(c, "appointment", &AppointmentPatch{}, current, appointmentPatchRules)
+	if !ok {
 		return
 	}
 
@@ -77,3 +77,27 @@ func (h *AppointmentHandler) UpdateAppointmentByID(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Appointment updated successfully"})
 }
+
+// 🟢 DELETE /appointment/:id
+func (h *AppointmentHandler) DeleteAppointment(c *gin.Context) {
+	if !trashed(c, "DeleteAppointment", "Appointment", h.Repo.DeleteAppointment(c.Param("id"), deletedBy(c))) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Appointment deleted successfully"})
+}
+
+// 🟢 POST /appointment/:id/restore - 409 while its case is deleted
+func (h *AppointmentHandler) RestoreAppointment(c *gin.Context) {
+	id := c.Param("id")
+	ap, err := findDeleted(appointmentList, id, h.Repo.ListAppointments)
+	if !trashed(c, "RestoreAppointment", "Deleted appointment", err) {
+		return
+	}
+	if !restorableIn(c, h.Cases, ap.CaseID, "appointment") {
+		return
+	}
+	if !trashed(c, "RestoreAppointment", "Deleted appointment", h.Repo.RestoreAppointment(id)) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Appointment restored successfully"})
+}
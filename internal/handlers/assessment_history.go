@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"trl-research-backend/internal/models"
+	"trl-research-backend/internal/repository"
+	"trl-research-backend/internal/trl"
+
+	"github.com/gin-gonic/gin"
+)
+
+// assessmentReviewFields - only POST /assessment_trl/:id/review writes these
+var assessmentReviewFields = []string{"status", "reviewed_by", "reviewed_at", "review_note"}
+
+// 🟢 GET /assessment_trl/case/:id/history - every assessment of the case, oldest first
+func (h *AssessmentTrlHandler) GetAssessmentTrlHistory(c *gin.Context) {
+	assessments, err := h.Repo.GetAssessmentTrlsByCaseID(c.Param("id"))
+	if err != nil {
+		log.Printf("❌ [GetAssessmentTrlHistory] %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if assessments == nil {
+		assessments = []models.AssessmentTrl{}
+	}
+	c.JSON(http.StatusOK, assessments)
+}
+
+// 🟢 GET /assessment_trl/case/:id/timeline - TRL level per assessment with the answer diff to the one before
+func (h *AssessmentTrlHandler) GetAssessmentTrlTimeline(c *gin.Context) {
+	caseID := c.Param("id")
+	assessments, err := h.Repo.GetAssessmentTrlsByCaseID(caseID)
+	if err != nil {
+		log.Printf("❌ [GetAssessmentTrlTimeline] %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	templates := map[int]*models.QuestionnaireTemplate{}
+	entries, err := trl.Timeline(assessments, func(version int) (*models.QuestionnaireTemplate, error) {
+		if t, ok := templates[version]; ok {
+			return t, nil
+		}
+		t, err := loadQuestionnaire(h.Templates, version)
+		templates[version] = t
+		return t, err
+	})
+	if err != nil {
+		log.Printf("❌ [GetAssessmentTrlTimeline] %s: %v", caseID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	current := gin.H{"trl_level": nil, "assessment_id": nil}
+	if latest := trl.LatestApproved(assessments); latest != nil {
+		current = gin.H{"trl_level": latest.TrlLevelResult, "assessment_id": latest.ID}
+	}
+	c.JSON(http.StatusOK, gin.H{"case_id": caseID, "current": current, "timeline": entries})
+}
+
+type AssessmentReviewReq struct {
+	Status string `json:"status"` // approved | rejected
+	Note   string `json:"note"`
+}
+
+// 🟢 POST /assessment_trl/:id/review - approve or reject a pending assessment (staff).
+// The assessment can't change afterwards; the case's trl_score and next steps are derived again.
+func (h *AssessmentTrlHandler) ReviewAssessmentTrl(c *gin.Context) {
+	id := c.Param("id")
+	var req AssessmentReviewReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Status != models.AssessmentApproved && req.Status != models.AssessmentRejected {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be approved or rejected"})
+		return
+	}
+	if req.Status == models.AssessmentRejected && req.Note == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "note is required for rejected"})
+		return
+	}
+
+	err := h.Repo.ReviewAssessmentTrl(id, req.Status, c.GetString("userID"), req.Note)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Assessment TRL not found"})
+		return
+	}
+	if errors.Is(err, repository.ErrConflict) {
+		c.JSON(http.StatusConflict, gin.H{"error": "assessment was already reviewed"})
+		return
+	}
+	if err != nil {
+		log.Printf("❌ [ReviewAssessmentTrl] %s: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	a, err := h.Repo.GetAssessmentTrlByID(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	log.Printf("🔎 assessment %s of case %s %s by %s", id, a.CaseID, req.Status, c.GetString("userEmail"))
+	if _, err := h.Trl.refresh(c, a.CaseID); err != nil {
+		log.Printf("❌ [ReviewAssessmentTrl] TRL fields of case %s: %v", a.CaseID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Assessment TRL reviewed successfully", "assessment": a})
+}
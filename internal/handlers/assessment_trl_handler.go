@@ -1,25 +1,28 @@
 package handlers
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"trl-research-backend/internal/auth"
 	"trl-research-backend/internal/models"
 	"trl-research-backend/internal/repository"
+	"trl-research-backend/internal/trl"
 )
 
 type AssessmentTrlHandler struct {
-	Repo *repository.AssessmentTrlRepo
+	Repo      repository.AssessmentTrlRepository
+	Templates repository.QuestionnaireRepository
+	Trl       *CaseTrl
 }
 
 // 🟢 GET /assessments
 func (h *AssessmentTrlHandler) GetAssessmentTrlAll(c *gin.Context) {
-	assessments, err := h.Repo.GetAssessmentTrlAll()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-	c.JSON(http.StatusOK, assessments)
+	listItems(c, assessmentTrlList, h.Repo.ListAssessmentTrls)
 }
 
 // 🟢 GET /assessment/:id
@@ -30,10 +33,10 @@ func (h *AssessmentTrlHandler) GetAssessmentTrlByID(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Assessment TRL not found"})
 		return
 	}
-	c.JSON(http.StatusOK, a)
+	writeTagged(c, a.Revision, a)
 }
 
-// 🟢 GET /assessment/case/:id
+// 🟢 GET /assessment/case/:id - the latest assessment of the case
 func (h *AssessmentTrlHandler) GetAssessmentTrlByCaseID(c *gin.Context) {
 	id := c.Param("id")
 	a, err := h.Repo.GetAssessmentTrlByCaseID(id)
@@ -41,39 +44,298 @@ func (h *AssessmentTrlHandler) GetAssessmentTrlByCaseID(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Assessment TRL not found"})
 		return
 	}
-	c.JSON(http.StatusOK, a)
+	writeTagged(c, a.Revision, a)
 }
 
-// 🟢 POST /assessment
+// assessmentResponse - the stored assessment plus the per-level evidence behind trl_level_result
+type assessmentResponse struct {
+	models.AssessmentTrl
+	Evidence []trl.LevelEvidence `json:"evidence"`
+}
+
+// questionnaireFor - the template an assessment is answered against: its questionnaire_version,
+// or the active one when a new assessment doesn't name a version. Writes the error response.
+func questionnaireFor(c *gin.Context, templates repository.QuestionnaireRepository, version int) (*models.QuestionnaireTemplate, bool) {
+	var t *models.QuestionnaireTemplate
+	var err error
+	if version == 0 {
+		t, err = activeQuestionnaire(templates)
+	} else {
+		t, err = loadQuestionnaire(templates, version)
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": fmt.Sprintf("questionnaire version %d does not exist", version)})
+		return nil, false
+	}
+	if err != nil {
+		questionnaireError(c, "AssessmentQuestionnaire", err)
+		return nil, false
+	}
+	return t, true
+}
+
+// scoreAssessment derives trl_level_result on the server; the client's value is ignored.
+// Writes 422 with the list of problems when the answers contradict each other; for an update
+// (stored not nil) only the problems the stored answers didn't have already. On success the
+// answers are normalized to the template (every question answered, rq/cq fields mirrored).
+func scoreAssessment(c *gin.Context, t *models.QuestionnaireTemplate, stored, a *models.AssessmentTrl) (*trl.Result, bool) {
+	var result *trl.Result
+	var err error
+	if stored != nil {
+		result, err = trl.Rescore(t, stored, a)
+	} else {
+		result, err = trl.Score(t, a)
+	}
+	var verr *trl.ValidationError
+	if errors.As(err, &verr) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "inconsistent assessment", "problems": verr.Problems})
+		return nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	trl.Normalize(t, a)
+	a.TrlLevelResult = result.Level
+	return result, true
+}
+
+// 🟢 POST /assessment - a new pending assessment; questionnaire_version defaults to the active questionnaire
 func (h *AssessmentTrlHandler) CreateAssessmentTrl(c *gin.Context) {
 	var req models.AssessmentTrl
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	req.Deletion = models.Deletion{} // only DELETE puts a record in the trash
+	req.Status = models.AssessmentPending
+	req.ReviewedBy, req.ReviewNote, req.ReviewedAt = "", "", time.Time{}
+	t, ok := questionnaireFor(c, h.Templates, req.QuestionnaireVersion)
+	if !ok {
+		return
+	}
+	result, ok := scoreAssessment(c, t, nil, &req)
+	if !ok {
+		return
+	}
 
 	if err := h.Repo.CreateAssessmentTrl(&req); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	h.Trl.refreshAfter(c, "CreateAssessmentTrl", req.CaseID)
 
-	c.JSON(http.StatusOK, req)
+	c.JSON(http.StatusOK, assessmentResponse{AssessmentTrl: req, Evidence: result.Evidence})
 }
 
-// 🟢 PATCH /assessment/:id
+// 🟢 POST /assessment_trl/score - run the scoring engine without saving (form preview)
+func (h *AssessmentTrlHandler) ScoreAssessmentTrl(c *gin.Context) {
+	var req models.AssessmentTrl
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	t, ok := questionnaireFor(c, h.Templates, req.QuestionnaireVersion)
+	if !ok {
+		return
+	}
+	result, ok := scoreAssessment(c, t, nil, &req)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// 🟢 PATCH /assessment/:id - the level is scored again from the stored answers merged with the update;
+// contradictions the stored answers already had (legacy records) don't reject it, new ones do.
+// The questionnaire version of an assessment can't change, and a reviewed assessment can't change at all.
+// 412 when If-Match no longer matches.
 func (h *AssessmentTrlHandler) UpdateAssessmentTrlByID(c *gin.Context) {
 	id := c.Param("id")
-	var updateData map[string]interface{}
-
-	if err := c.ShouldBindJSON(&updateData); err != nil {
+	current, err := h.Repo.GetAssessmentTrlByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Assessment TRL not found"})
+		return
+	}
+	revision, ok := ifMatch(c, current.Revision)
+	if !ok {
+		return
+	}
+	if current.ReviewStatus() != models.AssessmentPending {
+		c.JSON(http.StatusConflict, gin.H{"error": "assessment is " + current.Status + " and can't change, create a new assessment"})
+		return
+	}
+	// questionnaire_version is immutable: a different version is a new assessment
+	current.QuestionnaireVersion = current.Version()
+	updateData, ok := bindPatch(c, "assessment", &AssessmentTrlPatch{}, current, assessmentTrlPatchRules)
+	if !ok {
+		return
+	}
+	merged, err := mergeAssessment(*current, updateData)
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	t, ok := questionnaireFor(c, h.Templates, merged.QuestionnaireVersion)
+	if !ok {
+		return
+	}
+	result, ok := scoreAssessment(c, t, current, merged)
+	if !ok {
+		return
+	}
+	for k, v := range answerFields(merged) {
+		updateData[k] = v
+	}
 
-	if err := h.Repo.UpdateAssessmentTrlByID(id, updateData); err != nil {
+	if revision != nil {
+		err = h.Repo.UpdateAssessmentTrlIfRevision(id, *revision, updateData)
+	} else {
+		err = h.Repo.UpdateAssessmentTrlByID(id, updateData)
+	}
+	if errors.Is(err, repository.ErrStale) {
+		if a, err := h.Repo.GetAssessmentTrlByID(id); err == nil {
+			preconditionFailed(c, a.Revision)
+			return
+		}
+	}
+	if errors.Is(err, repository.ErrConflict) {
+		c.JSON(http.StatusConflict, gin.H{"error": "assessment was reviewed in the meantime, create a new assessment"})
+		return
+	}
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	h.Trl.refreshAfter(c, "UpdateAssessmentTrlByID", merged.CaseID)
+
+	if a, err := h.Repo.GetAssessmentTrlByID(id); err == nil {
+		c.Header("ETag", etag(a.Revision))
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message":               "Assessment TRL updated successfully",
+		"questionnaire_version": merged.QuestionnaireVersion,
+		"trl_level_result":      result.Level,
+		"evidence":              result.Evidence,
+	})
+}
+
+// 🟢 DELETE /assessment/:id - a researcher only deletes a pending assessment, a review stays on
+// record unless an admin deletes it
+func (h *AssessmentTrlHandler) DeleteAssessmentTrl(c *gin.Context) {
+	id := c.Param("id")
+	current, err := h.Repo.GetAssessmentTrlByID(id)
+	if !trashed(c, "DeleteAssessmentTrl", "Assessment TRL", err) {
+		return
+	}
+	if c.GetString("role") != auth.RoleAdmin && current.ReviewStatus() != models.AssessmentPending {
+		c.JSON(http.StatusConflict, gin.H{"error": "assessment is " + current.Status + ", only an admin can delete it"})
+		return
+	}
+	if !trashed(c, "DeleteAssessmentTrl", "Assessment TRL", h.Repo.DeleteAssessmentTrl(id, deletedBy(c))) {
+		return
+	}
+	h.Trl.refreshAfter(c, "DeleteAssessmentTrl", current.CaseID)
+	c.JSON(http.StatusOK, gin.H{"message": "Assessment TRL deleted successfully"})
+}
+
+// 🟢 POST /assessment/:id/restore - 409 while its case is deleted
+func (h *AssessmentTrlHandler) RestoreAssessmentTrl(c *gin.Context) {
+	id := c.Param("id")
+	a, err := findDeleted(assessmentTrlList, id, h.Repo.ListAssessmentTrls)
+	if !trashed(c, "RestoreAssessmentTrl", "Deleted assessment TRL", err) {
+		return
+	}
+	if !restorableIn(c, h.Trl.Cases, a.CaseID, "assessment") {
+		return
+	}
+	if !trashed(c, "RestoreAssessmentTrl", "Deleted assessment TRL", h.Repo.RestoreAssessmentTrl(id)) {
+		return
+	}
+	h.Trl.refreshAfter(c, "RestoreAssessmentTrl", a.CaseID)
+	c.JSON(http.StatusOK, gin.H{"message": "Assessment TRL restored successfully"})
+}
+
+// answerMaps - answers keyed by question; a PATCH merges them key by key instead of replacing the map
+var answerMaps = []string{"readiness_answers", "criteria_answers"}
+
+// mergeAssessment applies a PATCH body (JSON field names) to a copy of the stored assessment.
+// A fixed rq/cq field in the body wins over the stored answer of the same question.
+func mergeAssessment(a models.AssessmentTrl, update map[string]interface{}) (*models.AssessmentTrl, error) {
+	current, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(current, &fields); err != nil {
+		return nil, err
+	}
+	// the typed values of the patch as JSON values, like the stored fields
+	body, err := json.Marshal(update)
+	if err != nil {
+		return nil, err
+	}
+	data := map[string]interface{}{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, err
+	}
+	for k, v := range data {
+		stored, isMap := fields[k].(map[string]interface{})
+		update, ok := v.(map[string]interface{})
+		if isMap && ok && isAnswerMap(k) {
+			for key, answer := range update {
+				stored[key] = answer
+			}
+			continue
+		}
+		fields[k] = v
+	}
+	for k := range data {
+		if key, ok := models.LegacyAnswerKey(k); ok {
+			for _, m := range answerMaps {
+				if answers, ok := fields[m].(map[string]interface{}); ok {
+					delete(answers, key)
+				}
+			}
+		}
+	}
+	patched, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	var merged models.AssessmentTrl
+	if err := json.Unmarshal(patched, &merged); err != nil {
+		return nil, err
+	}
+	return &merged, nil
+}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Assessment TRL updated successfully"})
+func isAnswerMap(field string) bool {
+	for _, m := range answerMaps {
+		if m == field {
+			return true
+		}
+	}
+	return false
+}
+
+// answerFields - everything scoring and normalizing may have changed, as PATCH fields
+func answerFields(a *models.AssessmentTrl) map[string]interface{} {
+	fields := map[string]interface{}{
+		"questionnaire_version": a.QuestionnaireVersion,
+		"trl_level_result":      a.TrlLevelResult,
+		"readiness_answers":     a.ReadinessAnswers,
+		"criteria_answers":      a.CriteriaAnswers,
+	}
+	for key, v := range a.ReadinessAnswers {
+		if _, ok := models.LegacyAnswerKey(key + "_answer"); ok {
+			fields[key+"_answer"] = v
+		}
+	}
+	for key, v := range a.CriteriaAnswers {
+		if _, ok := models.LegacyAnswerKey(key + "_answer"); ok {
+			fields[key+"_answer"] = v
+		}
+	}
+	return fields
 }
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"trl-research-backend/internal/listing"
+	"trl-research-backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditHandler struct {
+	Repo repository.AuditRepository
+}
+
+// 🟢 GET /audit?entity=case&entity_id=CS-00001&actor_id=...&created_at_from=2025-01-01 - the audit
+// log, newest first (admin). Always paged, the log only grows.
+func (h *AuditHandler) GetAuditLog(c *gin.Context) {
+	q, err := listing.Parse(c.Request.URL.Query(), auditList)
+	var bad *listing.Error
+	if errors.As(err, &bad) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": bad.Error(), "param": bad.Param})
+		return
+	}
+	if q.Limit == 0 {
+		q.Limit = listing.DefaultLimit
+	}
+
+	page, err := h.Repo.ListAuditEntries(q)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"trl-research-backend/internal/listing"
+	"trl-research-backend/internal/models"
+	"trl-research-backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CaseFullHandler assembles a case with everything that refers to it, for the case page
+type CaseFullHandler struct {
+	Cases        repository.CaseRepository
+	Researchers  repository.ResearcherRepository
+	Coordinators repository.CoordinatorRepository
+	Appointments repository.AppointmentRepository
+	IPs          repository.IntellectualPropertyRepository
+	Supporters   repository.SupporterRepository
+	Assessments  repository.AssessmentTrlRepository
+	Files        repository.FileRepository
+}
+
+// caseParts - what ?include= selects, every part by default. The keys of the response are the same.
+var caseParts = []string{"researcher", "coordinator", "appointments", "ips", "supporter", "assessments", "files"}
+
+// casePart reads one part of the case
+type casePart func(caseID string) (any, error)
+
+func (h *CaseFullHandler) parts() map[string]casePart {
+	return map[string]casePart{
+		"researcher": func(caseID string) (any, error) {
+			r, err := h.Researchers.GetResearcherByCaseID(caseID)
+			if errors.Is(err, repository.ErrNotFound) {
+				return nil, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			return r.ToResponse(), nil
+		},
+		"coordinator":  optional(h.Coordinators.GetCoordinatorByCaseID),
+		"appointments": every(h.Appointments.GetAppointmentByCaseID),
+		"ips": every(func(caseID string) ([]models.IntellectualProperty, error) {
+			q, err := listing.Parse(nil, ipList)
+			if err != nil {
+				return nil, err
+			}
+			page, err := h.IPs.ListIPs(q.Where("case_id", caseID))
+			if err != nil {
+				return nil, err
+			}
+			return page.Items, nil
+		}),
+		"supporter":   optional(h.Supporters.GetSupporterByCaseID),
+		"assessments": every(h.Assessments.GetAssessmentTrlsByCaseID),
+		"files": every(func(caseID string) ([]models.FileMetadata, error) {
+			return h.Files.GetFilesByCaseID(context.Background(), caseID)
+		}),
+	}
+}
+
+// optional - a single record of the case, null when it has none
+func optional[T any](get func(caseID string) (*T, error)) casePart {
+	return func(caseID string) (any, error) {
+		v, err := get(caseID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, nil
+		}
+		return v, err
+	}
+}
+
+// every - the records of the case, [] when it has none
+func every[T any](get func(caseID string) ([]T, error)) casePart {
+	return func(caseID string) (any, error) {
+		items, err := get(caseID)
+		if items == nil {
+			items = []T{}
+		}
+		return items, err
+	}
+}
+
+// parseInclude - the parts named by ?include=a,b in the order of caseParts; all of them without it
+func parseInclude(include string) ([]string, bool) {
+	if strings.TrimSpace(include) == "" {
+		return caseParts, true
+	}
+	wanted := map[string]bool{}
+	for _, name := range strings.Split(include, ",") {
+		name = strings.TrimSpace(name)
+		if !slices.Contains(caseParts, name) {
+			return nil, false
+		}
+		wanted[name] = true
+	}
+	var names []string
+	for _, name := range caseParts {
+		if wanted[name] {
+			names = append(names, name)
+		}
+	}
+	return names, true
+}
+
+// 🟢 GET /case/:id/full?include=researcher,coordinator,appointments,ips,supporter,assessments,files
+// The case with the selected parts, read concurrently. A part the case doesn't have is null
+// (researcher, coordinator, supporter) or [].
+func (h *CaseFullHandler) GetCaseFull(c *gin.Context) {
+	names, ok := parseInclude(c.Query("include"))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "include must be a comma-separated list of " + strings.Join(caseParts, ", "),
+			"param": "include",
+		})
+		return
+	}
+
+	id := c.Param("id")
+	cs, err := h.Cases.GetCaseByID(id)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Case not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("❌ [GetCaseFull] case %s: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	parts := h.parts()
+	values := make([]any, len(names))
+	errs := make([]error, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			values[i], errs[i] = parts[name](id)
+		}()
+	}
+	wg.Wait()
+
+	response := gin.H{"case": cs}
+	for i, name := range names {
+		if errs[i] != nil {
+			log.Printf("❌ [GetCaseFull] %s of case %s: %v", name, id, errs[i])
+			c.JSON(http.StatusInternalServerError, gin.H{"error": errs[i].Error(), "part": name})
+			return
+		}
+		response[name] = values[i]
+	}
+	c.JSON(http.StatusOK, response)
+}
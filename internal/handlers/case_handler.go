@@ -1,42 +1,100 @@
 package handlers
 
 import (
-	"fmt"
+	"errors"
+	"log"
 	"net/http"
+	"net/url"
 
+	"trl-research-backend/internal/audit"
+	"trl-research-backend/internal/listing"
 	"trl-research-backend/internal/models"
 	"trl-research-backend/internal/repository"
+	"trl-research-backend/internal/search"
+	"trl-research-backend/internal/taxonomy"
 
 	"github.com/gin-gonic/gin"
 )
 
 type CaseHandler struct {
-	Repo *repository.CaseRepo
+	Repo   repository.CaseRepository
+	Trl    *CaseTrl
+	Search *SearchIndex
+	Terms  repository.TaxonomyRepository
+	// Records and Audit log the records a delete / restore takes with the case
+	Records CaseRecords
+	Audit   *audit.Logger
+}
+
+// CaseRecords - the repositories of the records that belong to a case
+type CaseRecords struct {
+	Appointments repository.AppointmentRepository
+	IPs          repository.IntellectualPropertyRepository
+	Supporters   repository.SupporterRepository
+	Assessments  repository.AssessmentTrlRepository
+}
+
+// caseRecord - one live record of a case, under its audit entity
+type caseRecord struct {
+	entity, id string
+	snapshot   any
+}
+
+// live lists the records of the case that aren't deleted. A nil repository is skipped.
+func (r CaseRecords) live(caseID string) ([]caseRecord, error) {
+	values := url.Values{"case_id": {caseID}}
+	var out []caseRecord
+	if r.Appointments != nil {
+		items, err := changed(values, appointmentList, r.Appointments.ListAppointments)
+		if err != nil {
+			return nil, err
+		}
+		for i := range items {
+			out = append(out, caseRecord{"appointment", items[i].AppointmentID, &items[i]})
+		}
+	}
+	if r.IPs != nil {
+		items, err := changed(values, ipList, r.IPs.ListIPs)
+		if err != nil {
+			return nil, err
+		}
+		for i := range items {
+			out = append(out, caseRecord{"ip", items[i].ID, &items[i]})
+		}
+	}
+	if r.Supporters != nil {
+		items, err := changed(values, supporterList, r.Supporters.ListSupporters)
+		if err != nil {
+			return nil, err
+		}
+		for i := range items {
+			out = append(out, caseRecord{"supporter", items[i].SupporterID, &items[i]})
+		}
+	}
+	if r.Assessments != nil {
+		items, err := changed(values, assessmentTrlList, r.Assessments.ListAssessmentTrls)
+		if err != nil {
+			return nil, err
+		}
+		for i := range items {
+			out = append(out, caseRecord{"assessment_trl", items[i].ID, &items[i]})
+		}
+	}
+	return out, nil
 }
 
 // 🟢 GET /cases
 func (h *CaseHandler) GetCaseAll(c *gin.Context) {
-	fmt.Println("GetCaseAll from handler")
-	fmt.Println("h", h)
-	cases, err := h.Repo.GetCaseAll()
-	fmt.Println("cases", cases)
-	if err != nil {
-		fmt.Println("err", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-	c.JSON(http.StatusOK, cases)
+	listItems(c, caseList, h.Repo.ListCases)
 }
 
 // 🟢 GET /case/researcher/:id - Get all cases for a researcher
 func (h *CaseHandler) GetCaseAllByResearcher_id(c *gin.Context) {
 	id := c.Param("id")
-	cases, err := h.Repo.GetCaseAllByResearcher_id(id)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-	c.JSON(http.StatusOK, cases)
+	listItems(c, caseList,
+		func(q listing.Query) (*listing.Page[models.CaseInfo], error) {
+			return h.Repo.ListCases(q.Where("researcher_id", id))
+		})
 }
 
 // 🟢 GET /case/:id
@@ -47,7 +105,7 @@ func (h *CaseHandler) GetCaseByID(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Case not found"})
 		return
 	}
-	c.JSON(http.StatusOK, cs)
+	writeTagged(c, cs.Revision, cs)
 }
 
 // 🟢 POST /case
@@ -57,42 +115,124 @@ func (h *CaseHandler) CreateCase(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	req.Deletion = models.Deletion{} // only DELETE puts a record in the trash
 
+	initCaseStatus(&req)
+	// derived from the assessments of the case
+	req.TrlScore, req.TrlSuggestion, req.TrlRecommendation = "", "", nil
+	if !taxonomyOK(c, taxonomy.NewResolver(h.Terms).ResolveCase(&req)) {
+		return
+	}
 	if err := h.Repo.CreateCase(&req); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	h.Search.putCase(req)
 
 	c.JSON(http.StatusOK, req)
 }
 
-// 🟢 PATCH /case/:id
+// 🟢 PATCH /case/:id - only the fields of CasePatch; 422 lists every invalid one, 412 when
+// If-Match no longer matches
 func (h *CaseHandler) UpdateCaseByID(c *gin.Context) {
 	id := c.Param("id")
-	var updateData map[string]interface{}
-
-	if err := c.ShouldBindJSON(&updateData); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	current, err := h.Repo.GetCaseByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Case not found"})
+		return
+	}
+	revision, ok := ifMatch(c, current.Revision)
+	if !ok {
+		return
+	}
+	updateData, ok := bindPatch(c, "case", &CasePatch{}, current, casePatchRules)
+	if !ok {
+		return
+	}
+	if !taxonomyOK(c, taxonomy.NewResolver(h.Terms).ResolvePatch(updateData)) {
 		return
 	}
 
-	if err := h.Repo.UpdateCaseByID(id, updateData); err != nil {
+	if revision != nil {
+		err = h.Repo.UpdateCaseIfRevision(id, *revision, updateData)
+	} else {
+		err = h.Repo.UpdateCaseByID(id, updateData)
+	}
+	if errors.Is(err, repository.ErrStale) {
+		if cs, err := h.Repo.GetCaseByID(id); err == nil {
+			preconditionFailed(c, cs.Revision)
+			return
+		}
+	}
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	h.Search.syncCase(id)
 
+	if cs, err := h.Repo.GetCaseByID(id); err == nil {
+		c.Header("ETag", etag(cs.Revision))
+	}
 	c.JSON(http.StatusOK, gin.H{"message": "Case updated successfully"})
 }
 
-// 🟢 PATCH /case/update-status/:id
-func (h *CaseHandler) UpdateCaseStatusByID(c *gin.Context) {
+// 🟢 DELETE /case/:id - soft delete; the appointments, IPs, supporters and assessments of the
+// case go to the trash with it
+func (h *CaseHandler) DeleteCase(c *gin.Context) {
 	id := c.Param("id")
-	status := c.Query("status")
+	records, err := h.Records.live(id)
+	if err != nil {
+		log.Printf("❌ [DeleteCase] records of case %s: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !trashed(c, "DeleteCase", "Case", h.Repo.DeleteCase(id, deletedBy(c))) {
+		return
+	}
+	for _, r := range records {
+		h.Audit.Record(c, r.entity, r.id, "delete", r.snapshot, nil)
+	}
+	h.Search.remove(search.TypeCase, id)
+	h.Search.syncCaseIPs(id)
+	c.JSON(http.StatusOK, gin.H{"message": "Case deleted successfully"})
+}
 
-	if err := h.Repo.UpdateCaseStatusByID(id, status); err != nil {
+// 🟢 POST /case/:id/restore - brings back the records that were deleted with the case, not the
+// ones deleted before it
+func (h *CaseHandler) RestoreCase(c *gin.Context) {
+	id := c.Param("id")
+	before, err := h.Records.live(id)
+	if err != nil {
+		log.Printf("❌ [RestoreCase] records of case %s: %v", id, err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	if !trashed(c, "RestoreCase", "Deleted case", h.Repo.RestoreCase(id)) {
+		return
+	}
+	if after, err := h.Records.live(id); err != nil {
+		log.Printf("❌ [RestoreCase] records of case %s: %v", id, err)
+	} else {
+		for _, r := range restored(before, after) {
+			h.Audit.Record(c, r.entity, r.id, "restore", nil, r.snapshot)
+		}
+	}
+	h.Search.syncCase(id)
+	h.Search.syncCaseIPs(id)
+	c.JSON(http.StatusOK, gin.H{"message": "Case restored successfully"})
+}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Case status updated successfully"})
-}
\ No newline at end of file
+// restored - the records of after that weren't live before
+func restored(before, after []caseRecord) []caseRecord {
+	live := map[string]bool{}
+	for _, r := range before {
+		live[r.entity+" "+r.id] = true
+	}
+	var out []caseRecord
+	for _, r := range after {
+		if !live[r.entity+" "+r.id] {
+			out = append(out, r)
+		}
+	}
+	return out
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"testing"
+
+	"trl-research-backend/internal/audit"
+	"trl-research-backend/internal/models"
+	"trl-research-backend/internal/repository/memory"
+	"trl-research-backend/internal/search"
+	"trl-research-backend/internal/trash"
+)
+
+// a case delete / restore logs the records it takes with it, and the purge each record it removes
+func TestCaseCascadeIsAudited(t *testing.T) {
+	repos := memory.NewRepositories()
+	log := &audit.Logger{Repo: repos.Audit}
+	index, err := search.Open("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := &CaseHandler{
+		Repo:   repos.Case,
+		Search: &SearchIndex{Index: index, Cases: repos.Case, Researchers: repos.Researcher, IPs: repos.IntellectualProperty},
+		Records: CaseRecords{
+			Appointments: repos.Appointment,
+			IPs:          repos.IntellectualProperty,
+			Supporters:   repos.Supporter,
+			Assessments:  repos.AssessmentTrl,
+		},
+		Audit: log,
+	}
+	r := adminEngine()
+	r.DELETE("/case/:id", h.DeleteCase)
+	r.POST("/case/:id/restore", h.RestoreCase)
+
+	cs := &models.CaseInfo{CaseTitle: "case"}
+	if err := repos.Case.CreateCase(cs); err != nil {
+		t.Fatal(err)
+	}
+	sp := &models.Supporter{CaseID: cs.CaseID}
+	if err := repos.Supporter.CreateSupporter(sp); err != nil {
+		t.Fatal(err)
+	}
+	var ips []string
+	for range 2 {
+		ip := &models.IntellectualProperty{CaseID: cs.CaseID}
+		if err := repos.IntellectualProperty.CreateIP(ip); err != nil {
+			t.Fatal(err)
+		}
+		ips = append(ips, ip.ID)
+	}
+	// deleted on its own before the case: neither logged with it nor restored
+	if err := repos.IntellectualProperty.DeleteIP(ips[1], "AD-00001"); err != nil {
+		t.Fatal(err)
+	}
+
+	entries := func(action string) []string {
+		t.Helper()
+		list, err := changed(url.Values{"action": {action}}, auditList, repos.Audit.ListAuditEntries)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out []string
+		for _, e := range list {
+			out = append(out, fmt.Sprintf("%s %s %s", e.Entity, e.EntityID, e.ActorID))
+		}
+		slices.Sort(out)
+		return out
+	}
+	want := []string{"ip " + ips[0] + " AD-00001", "supporter " + sp.SupporterID + " AD-00001"}
+
+	if code := call(t, r, "DELETE", "/case/"+cs.CaseID, nil, nil); code != http.StatusOK {
+		t.Fatalf("delete: %d", code)
+	}
+	if got := entries("delete"); !slices.Equal(got, want) {
+		t.Errorf("delete: %q, want %q", got, want)
+	}
+	if code := call(t, r, "POST", "/case/"+cs.CaseID+"/restore", nil, nil); code != http.StatusOK {
+		t.Fatalf("restore: %d", code)
+	}
+	if got := entries("restore"); !slices.Equal(got, want) {
+		t.Errorf("restore: %q, want %q", got, want)
+	}
+
+	// the scheduled purge (no request) with everything past retention
+	if err := repos.Case.DeleteCase(cs.CaseID, "AD-00001"); err != nil {
+		t.Fatal(err)
+	}
+	purger := &trash.Purger{Repos: repos, Audit: log}
+	counts, err := purger.Purge(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []string{
+		"case " + cs.CaseID + " system",
+		"ip " + ips[0] + " system",
+		"ip " + ips[1] + " system",
+		"supporter " + sp.SupporterID + " system",
+	}
+	if got := entries("purge"); !slices.Equal(got, want) || counts.Total() != len(want) {
+		t.Errorf("purge: %d, %q, want %q", counts.Total(), got, want)
+	}
+}
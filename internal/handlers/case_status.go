@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"trl-research-backend/internal/auth"
+	"trl-research-backend/internal/models"
+	"trl-research-backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// caseTransition - one allowed move of the case workflow. A researcher may only use it on
+// their own cases; the route policy (OwnsCaseParam) already checks that.
+type caseTransition struct {
+	To          models.CaseStatus
+	Roles       []string
+	NeedsReason bool
+}
+
+// caseWorkflow - every allowed move per current state. Sending a case back to the researcher,
+// rejecting it and reopening a closed one need a reason.
+var caseWorkflow = map[models.CaseStatus][]caseTransition{
+	models.CaseDraft: {
+		{To: models.CaseSubmitted, Roles: []string{auth.RoleResearcher, auth.RoleAdmin}},
+	},
+	models.CaseSubmitted: {
+		{To: models.CaseDraft, Roles: []string{auth.RoleResearcher, auth.RoleAdmin}}, // withdraw
+		{To: models.CaseUnderReview, Roles: []string{auth.RoleAdmin, auth.RoleCoordinator}},
+	},
+	models.CaseUnderReview: {
+		{To: models.CaseAssessment, Roles: []string{auth.RoleAdmin, auth.RoleCoordinator}},
+		{To: models.CaseRevisionRequested, Roles: []string{auth.RoleAdmin, auth.RoleCoordinator}, NeedsReason: true},
+		{To: models.CaseRejected, Roles: []string{auth.RoleAdmin}, NeedsReason: true},
+	},
+	models.CaseRevisionRequested: {
+		{To: models.CaseSubmitted, Roles: []string{auth.RoleResearcher, auth.RoleAdmin}},
+	},
+	models.CaseAssessment: {
+		{To: models.CaseApproved, Roles: []string{auth.RoleAdmin}},
+		{To: models.CaseRejected, Roles: []string{auth.RoleAdmin}, NeedsReason: true},
+		{To: models.CaseRevisionRequested, Roles: []string{auth.RoleAdmin, auth.RoleCoordinator}, NeedsReason: true},
+	},
+	models.CaseApproved: {
+		{To: models.CaseClosed, Roles: []string{auth.RoleAdmin}},
+	},
+	models.CaseRejected: {
+		{To: models.CaseClosed, Roles: []string{auth.RoleAdmin}},
+	},
+	models.CaseClosed: {
+		{To: models.CaseUnderReview, Roles: []string{auth.RoleAdmin}, NeedsReason: true}, // reopen
+	},
+}
+
+func findCaseTransition(from, to models.CaseStatus) *caseTransition {
+	for _, t := range caseWorkflow[from] {
+		if t.To == to {
+			return &t
+		}
+	}
+	return nil
+}
+
+func (t *caseTransition) allows(role string) bool {
+	for _, r := range t.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// caseStatusFields - only the workflow may write these, PATCH /case/:id drops them
+var caseStatusFields = []string{"status", "status_reason", "status_changed_at", "status_timestamps"}
+
+// initCaseStatus - a new case starts as draft when asked for, otherwise it is submitted right away
+func initCaseStatus(cs *models.CaseInfo) {
+	if cs.Status != models.CaseDraft {
+		cs.Status = models.CaseSubmitted
+	}
+	now := time.Now()
+	cs.StatusReason = ""
+	cs.StatusChangedAt = now
+	cs.StatusTimestamps = map[string]time.Time{string(cs.Status): now}
+}
+
+type CaseTransitionReq struct {
+	Status models.CaseStatus `json:"status"`
+	Reason string            `json:"reason"`
+}
+
+// transitionCase checks the workflow and the caller's role, then moves the case
+func (h *CaseHandler) transitionCase(c *gin.Context, caseID string, to models.CaseStatus, reason string) {
+	if !to.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid status", "statuses": models.CaseStatuses})
+		return
+	}
+	cs, err := h.Repo.GetCaseByID(caseID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Case not found"})
+		return
+	}
+
+	rule := findCaseTransition(cs.Status, to)
+	if rule == nil {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "cannot move case from " + string(cs.Status) + " to " + string(to),
+			"allowed": allowedCaseTransitions(cs.Status, c.GetString("role")),
+		})
+		return
+	}
+	if !rule.allows(c.GetString("role")) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	if rule.NeedsReason && reason == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "reason is required for " + string(to)})
+		return
+	}
+
+	t := &models.CaseStatusTransition{
+		CaseID:    caseID,
+		From:      cs.Status,
+		To:        to,
+		Reason:    reason,
+		ActorID:   c.GetString("userID"),
+		ActorRole: c.GetString("role"),
+	}
+	err = h.Repo.TransitionCaseStatus(t)
+	if errors.Is(err, repository.ErrConflict) {
+		c.JSON(http.StatusConflict, gin.H{"error": "case status was changed by someone else, reload and try again"})
+		return
+	}
+	if err != nil {
+		log.Printf("❌ [TransitionCase] %s %s → %s: %v", caseID, t.From, t.To, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	log.Printf("🔁 case %s: %s → %s by %s (%s)", caseID, t.From, t.To, t.ActorID, t.ActorRole)
+
+	if updated, err := h.Repo.GetCaseByID(caseID); err == nil {
+		cs = updated
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Case status updated successfully", "case": cs, "transition": t})
+}
+
+// allowedCaseTransitions - the moves a role can make from a state
+func allowedCaseTransitions(from models.CaseStatus, role string) []gin.H {
+	allowed := []gin.H{}
+	for _, t := range caseWorkflow[from] {
+		if t.allows(role) {
+			allowed = append(allowed, gin.H{"status": t.To, "reason_required": t.NeedsReason})
+		}
+	}
+	return allowed
+}
+
+// 🟢 POST /case/:id/transition - {"status": "under_review", "reason": "..."}
+func (h *CaseHandler) TransitionCaseStatus(c *gin.Context) {
+	var req CaseTransitionReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.transitionCase(c, c.Param("id"), req.Status, req.Reason)
+}
+
+// 🟢 PATCH /case/update-status/:id?status=...&reason=... - kept for old clients, same rules as /transition
+func (h *CaseHandler) UpdateCaseStatusByID(c *gin.Context) {
+	status, ok := models.ParseLegacyCaseStatus(c.Query("status"))
+	if !ok || c.Query("status") == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid status", "statuses": models.CaseStatuses})
+		return
+	}
+	h.transitionCase(c, c.Param("id"), status, c.Query("reason"))
+}
+
+// 🟢 GET /case/:id/transitions - the moves the caller can make now
+func (h *CaseHandler) GetCaseTransitions(c *gin.Context) {
+	cs, err := h.Repo.GetCaseByID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Case not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": cs.Status, "allowed": allowedCaseTransitions(cs.Status, c.GetString("role"))})
+}
+
+// 🟢 GET /case/:id/status-history
+func (h *CaseHandler) GetCaseStatusHistory(c *gin.Context) {
+	history, err := h.Repo.GetCaseStatusHistory(c.Param("id"))
+	if err != nil {
+		log.Printf("❌ [GetCaseStatusHistory] %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if history == nil {
+		history = []models.CaseStatusTransition{}
+	}
+	c.JSON(http.StatusOK, history)
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"trl-research-backend/internal/audit"
+	"trl-research-backend/internal/models"
+	"trl-research-backend/internal/repository"
+	"trl-research-backend/internal/trl"
+
+	"github.com/gin-gonic/gin"
+)
+
+// caseDerivedFields - computed from the assessments, PATCH /case/:id drops them
+var caseDerivedFields = []string{"trl_score", "trl_suggestion", "trl_recommendation"}
+
+// CaseTrl keeps the TRL fields of a case in step with its assessments: trl_score follows the
+// latest approved assessment, trl_recommendation / trl_suggestion the latest one that wasn't
+// rejected together with the supporter needs of the case.
+type CaseTrl struct {
+	Assessments repository.AssessmentTrlRepository
+	Templates   repository.QuestionnaireRepository
+	Cases       repository.CaseRepository
+	Supporters  repository.SupporterRepository
+	Audit       *audit.Logger // the fields a refresh changes, as a write of the call behind it
+}
+
+// Refresh derives the TRL fields of a case again and stores the ones that changed. Without an
+// approved assessment trl_score is "", without one that wasn't rejected there is no
+// recommendation (nil is returned then) and no suggestion.
+func (s *CaseTrl) Refresh(caseID string) (*models.TrlRecommendation, error) {
+	cs, err := s.Cases.GetCaseByID(caseID)
+	if err != nil {
+		return nil, err
+	}
+	assessments, err := s.Assessments.GetAssessmentTrlsByCaseID(caseID)
+	if err != nil {
+		return nil, err
+	}
+
+	data := map[string]interface{}{}
+	score := ""
+	if approved := trl.LatestApproved(assessments); approved != nil {
+		score = strconv.Itoa(approved.TrlLevelResult)
+	}
+	if score != cs.TrlScore {
+		data["trl_score"] = score
+	}
+	var rec *models.TrlRecommendation
+	if latest := latestOpen(assessments); latest != nil {
+		if rec, err = s.recommend(caseID, latest); err != nil {
+			return nil, err
+		}
+		data["trl_recommendation"] = rec
+		data["trl_suggestion"] = trl.SuggestionText(rec)
+	} else if cs.TrlRecommendation != nil || cs.TrlSuggestion != "" {
+		// the assessments it came from are deleted or rejected
+		data["trl_recommendation"] = (*models.TrlRecommendation)(nil)
+		data["trl_suggestion"] = ""
+	}
+	if len(data) == 0 {
+		return rec, nil
+	}
+	return rec, s.Cases.UpdateCaseByID(caseID, data)
+}
+
+func (s *CaseTrl) recommend(caseID string, a *models.AssessmentTrl) (*models.TrlRecommendation, error) {
+	t, err := loadQuestionnaire(s.Templates, a.Version())
+	if err != nil {
+		return nil, err
+	}
+	supporter, err := s.Supporters.GetSupporterByCaseID(caseID)
+	if errors.Is(err, repository.ErrNotFound) {
+		supporter, err = nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	rec, err := trl.Suggest(t, a, supporter)
+	if err != nil {
+		return nil, err
+	}
+	rec.GeneratedAt = time.Now()
+	return rec, nil
+}
+
+// refresh is Refresh as a side effect of the call c (an assessment or supporter write): what
+// it changes on the case gets an audit entry of its own
+func (s *CaseTrl) refresh(c *gin.Context, caseID string) (*models.TrlRecommendation, error) {
+	before, err := s.Cases.GetCaseByID(caseID)
+	if err != nil {
+		return nil, err
+	}
+	rec, err := s.Refresh(caseID)
+	if err != nil {
+		return nil, err
+	}
+	if after, err := s.Cases.GetCaseByID(caseID); err == nil {
+		s.Audit.Record(c, "case", caseID, "refresh_trl", before, after)
+	}
+	return rec, nil
+}
+
+// refreshAfter is refresh for writes that already succeeded: a failure is only logged, the
+// next change or POST /case/:id/trl-suggestion derives the fields again
+func (s *CaseTrl) refreshAfter(c *gin.Context, where, caseID string) {
+	if caseID == "" {
+		return
+	}
+	if _, err := s.refresh(c, caseID); err != nil && !errors.Is(err, repository.ErrNotFound) {
+		log.Printf("❌ [%s] TRL fields of case %s: %v", where, caseID, err)
+	}
+}
+
+// latestOpen - the newest assessment that wasn't rejected, from a list sorted oldest first
+func latestOpen(assessments []models.AssessmentTrl) *models.AssessmentTrl {
+	for i := len(assessments) - 1; i >= 0; i-- {
+		if assessments[i].ReviewStatus() != models.AssessmentRejected {
+			return &assessments[i]
+		}
+	}
+	return nil
+}
+
+// 🟢 POST /case/:id/trl-suggestion - generate the next steps of the case again (staff)
+func (h *CaseHandler) RefreshTrlSuggestion(c *gin.Context) {
+	caseID := c.Param("id")
+	rec, err := h.Trl.Refresh(caseID)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Case not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("❌ [RefreshTrlSuggestion] %s: %v", caseID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if rec == nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "case has no assessment to suggest from"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"trl_suggestion": trl.SuggestionText(rec), "trl_recommendation": rec})
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"trl-research-backend/internal/models"
+	"trl-research-backend/internal/repository/memory"
+	"trl-research-backend/internal/trl"
+)
+
+// trl_score follows the latest approved assessment only; a pending or rejected one doesn't move it
+func TestCaseTrlScoreFollowsApproved(t *testing.T) {
+	repos := memory.NewRepositories()
+	h := &AssessmentTrlHandler{
+		Repo:      repos.AssessmentTrl,
+		Templates: repos.Questionnaire,
+		Trl:       &CaseTrl{Assessments: repos.AssessmentTrl, Templates: repos.Questionnaire, Cases: repos.Case, Supporters: repos.Supporter},
+	}
+	r := adminEngine()
+	r.POST("/assessment_trl", h.CreateAssessmentTrl)
+	r.PATCH("/assessment_trl/:id", h.UpdateAssessmentTrlByID)
+	r.POST("/assessment_trl/:id/review", h.ReviewAssessmentTrl)
+
+	cases := map[string]*models.CaseInfo{}
+	for _, title := range []string{"first", "second"} {
+		cs := &models.CaseInfo{CaseTitle: title}
+		if err := repos.Case.CreateCase(cs); err != nil {
+			t.Fatal(err)
+		}
+		cases[title] = cs
+	}
+	caseID := cases["first"].CaseID
+	score := func() string {
+		t.Helper()
+		cs, err := repos.Case.GetCaseByID(caseID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cs.TrlScore
+	}
+	tpl := trl.BuiltInTemplate()
+	assess := func(level int) string {
+		t.Helper()
+		body := map[string]interface{}{"case_id": caseID, "readiness_answers": map[string]bool{}, "criteria_answers": map[string][]string{}}
+		for _, q := range tpl.Questions {
+			if q.Levels[0] > level {
+				continue
+			}
+			if q.Type == models.QuestionReadiness {
+				body["readiness_answers"].(map[string]bool)[q.Key] = true
+			} else {
+				var values []string
+				for _, o := range q.Options {
+					values = append(values, o.Value)
+				}
+				body["criteria_answers"].(map[string][]string)[q.Key] = values
+			}
+		}
+		var a assessmentResponse
+		if code := call(t, r, "POST", "/assessment_trl", body, &a); code != http.StatusOK || a.TrlLevelResult != level {
+			t.Fatalf("create at TRL %d: %d, scored %d", level, code, a.TrlLevelResult)
+		}
+		return a.ID
+	}
+	review := func(id, status string) {
+		t.Helper()
+		if code := call(t, r, "POST", "/assessment_trl/"+id+"/review", AssessmentReviewReq{Status: status, Note: "note"}, nil); code != http.StatusOK {
+			t.Fatalf("%s %s: %d", status, id, code)
+		}
+	}
+
+	first := assess(2)
+	if got := score(); got != "" {
+		t.Errorf("pending only: trl_score %q", got)
+	}
+	review(first, models.AssessmentApproved)
+	if got := score(); got != "2" {
+		t.Errorf("approved at 2: trl_score %q", got)
+	}
+	second := assess(4)
+	if got := score(); got != "2" {
+		t.Errorf("pending at 4 on top: trl_score %q", got)
+	}
+
+	// a pending assessment stays with its case
+	moved := map[string]string{"case_id": cases["second"].CaseID}
+	if code := call(t, r, "PATCH", "/assessment_trl/"+second, moved, nil); code != http.StatusUnprocessableEntity {
+		t.Errorf("move to another case: %d, want 422", code)
+	}
+	same := map[string]interface{}{"case_id": caseID, "rq4_answer": false, "cq4_answer": []string{}}
+	if code := call(t, r, "PATCH", "/assessment_trl/"+second, same, nil); code != http.StatusOK {
+		t.Errorf("update with its own case_id: %d", code)
+	}
+
+	review(second, models.AssessmentRejected)
+	if got := score(); got != "2" {
+		t.Errorf("rejected on top: trl_score %q", got)
+	}
+	review(assess(5), models.AssessmentApproved)
+	if got := score(); got != "5" {
+		t.Errorf("approved at 5: trl_score %q", got)
+	}
+	if cs, _ := repos.Case.GetCaseByID(cases["second"].CaseID); cs.TrlScore != "" {
+		t.Errorf("other case: trl_score %q", cs.TrlScore)
+	}
+}
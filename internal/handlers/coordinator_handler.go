@@ -9,22 +9,17 @@ import (
 )
 
 type CoordinatorHandler struct {
-	Repo *repository.CoordinatorRepo
+	Repo repository.CoordinatorRepository
 }
 
 // 🟢 GET /coordinators
 func (h *CoordinatorHandler) GetCoordinatorAll(c *gin.Context) {
-	coordinators, err := h.Repo.GetCoordinatorAll()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-	c.JSON(http.StatusOK, coordinators)
+	listItems(c, coordinatorList, h.Repo.ListCoordinators)
 }
 
-// 🟢 GET /coordinator/:email
+// 🟢 GET /coordinator/:id - the id is the coordinator's email
 func (h *CoordinatorHandler) GetCoordinatorByEmail(c *gin.Context) {
-	email := c.Param("email")
+	email := c.Param("id")
 	coordinator, err := h.Repo.GetCoordinatorByEmail(email)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Coordinator not found"})
@@ -51,6 +46,7 @@ func (h *CoordinatorHandler) CreateCoordinator(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	req.Deletion = models.Deletion{} // only DELETE puts a record in the trash
 
 	if err := h.Repo.CreateCoordinator(&req); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -60,9 +56,10 @@ func (h *CoordinatorHandler) CreateCoordinator(c *gin.Context) {
 	c.JSON(http.StatusOK, req)
 }
 
-// 🟢 PATCH /coordinator/:email
+// 🟢 PATCH /coordinator/:id - the id is the coordinator's email; the password is only set
+// through the password reset
 func (h *CoordinatorHandler) UpdateCoordinatorByEmail(c *gin.Context) {
-	email := c.Param("email")
+	email := c.Param("id")
 	var updateData map[string]interface{}
 
 	if err := c.ShouldBindJSON(&updateData); err != nil {
@@ -70,10 +67,29 @@ func (h *CoordinatorHandler) UpdateCoordinatorByEmail(c *gin.Context) {
 		return
 	}
 
-	if err := h.Repo.UpdateCoordinatorByEmail(email, updateData); err != nil {
+	delete(updateData, "coordinator_password")
+	if err := h.Repo.UpdateCoordinatorByEmail(email, withoutDeletion(updateData)); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Coordinator updated successfully"})
 }
+
+// 🟢 DELETE /coordinator/:id - the id is the coordinator's email
+func (h *CoordinatorHandler) DeleteCoordinator(c *gin.Context) {
+	email := c.Param("id")
+	if !trashed(c, "DeleteCoordinator", "Coordinator", h.Repo.DeleteCoordinator(email, deletedBy(c))) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Coordinator deleted successfully"})
+}
+
+// 🟢 POST /coordinator/:id/restore
+func (h *CoordinatorHandler) RestoreCoordinator(c *gin.Context) {
+	email := c.Param("id")
+	if !trashed(c, "RestoreCoordinator", "Deleted coordinator", h.Repo.RestoreCoordinator(email)) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Coordinator restored successfully"})
+}
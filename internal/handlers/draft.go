@@ -0,0 +1,239 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+
+	"trl-research-backend/internal/models"
+	"trl-research-backend/internal/patch"
+	"trl-research-backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Drafts keep a researcher's submission form on the server while it is filled in. A draft
+// holds the body of POST /submissions as it was last saved; saving checks what is there
+// (types, unknown fields, lengths, enums) but not what is still missing. Submitting it runs
+// the full checks and creates the case like POST /submissions.
+
+// maxDrafts - the drafts a researcher can keep at once
+const maxDrafts = 20
+
+// draftParts - the parts PUT /drafts/:id/:part saves one at a time, the keys of the body
+var draftParts = []string{"case", "supporter", "ips", "assessment", "files"}
+
+// DraftTTL - DRAFT_RETENTION_DAYS (default 30) after the last save
+func DraftTTL() time.Duration {
+	d, _ := strconv.Atoi(os.Getenv("DRAFT_RETENTION_DAYS"))
+	if d <= 0 {
+		d = 30
+	}
+	return time.Duration(d) * 24 * time.Hour
+}
+
+type DraftHandler struct {
+	Repo        repository.DraftRepository
+	Submissions *SubmissionHandler
+}
+
+// bindDraft reads a draft body: 400 when it isn't a submission body, 422 with the problems of
+// the fields it has. An empty body is an empty draft.
+func bindDraft(c *gin.Context, body []byte) (*models.DraftContent, bool) {
+	if len(body) == 0 {
+		body = []byte("{}")
+	}
+	_, problems, err := decodeSubmission(body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	// missing and empty fields only block the submit
+	problems = slices.DeleteFunc(problems, func(p patch.Problem) bool { return p.Code == "required" })
+	if len(problems) > 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "invalid draft", "fields": problems})
+		return nil, false
+	}
+
+	var content models.DraftContent
+	if err := json.Unmarshal(body, &content); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return &content, true
+}
+
+// draftError answers the error of a draft repository call; false when there was none
+func draftError(c *gin.Context, where string, err error) bool {
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Draft not found"})
+		return true
+	}
+	if err != nil {
+		log.Printf("❌ [%s] %v", where, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return true
+	}
+	return false
+}
+
+// 🟢 GET /drafts - the caller's drafts, last saved first
+func (h *DraftHandler) GetMyDrafts(c *gin.Context) {
+	drafts, err := h.Repo.GetDraftsByResearcherID(c.GetString("userID"))
+	if draftError(c, "GetMyDrafts", err) {
+		return
+	}
+	c.JSON(http.StatusOK, drafts)
+}
+
+// 🟢 GET /drafts/:id
+func (h *DraftHandler) GetDraftByID(c *gin.Context) {
+	d, err := h.Repo.GetDraftByID(c.Param("id"))
+	if draftError(c, "GetDraftByID", err) {
+		return
+	}
+	c.JSON(http.StatusOK, d)
+}
+
+// 🟢 POST /drafts - a new draft of the caller, the body (optional) as in PUT /drafts/:id
+func (h *DraftHandler) CreateDraft(c *gin.Context) {
+	userID := c.GetString("userID")
+	body, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	content, ok := bindDraft(c, body)
+	if !ok {
+		return
+	}
+	drafts, err := h.Repo.GetDraftsByResearcherID(userID)
+	if draftError(c, "CreateDraft", err) {
+		return
+	}
+	if len(drafts) >= maxDrafts {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("at most %d drafts, submit or delete one first", maxDrafts)})
+		return
+	}
+
+	d := models.Draft{ResearcherID: userID, ExpiresAt: time.Now().Add(DraftTTL()), DraftContent: *content}
+	if draftError(c, "CreateDraft", h.Repo.CreateDraft(&d)) {
+		return
+	}
+	c.JSON(http.StatusOK, d)
+}
+
+// 🟢 PUT /drafts/:id - autosave: the body {case, supporter, ips, assessment, files} replaces the
+// draft. 422 lists the invalid fields; missing ones are fine until the submit.
+func (h *DraftHandler) SaveDraft(c *gin.Context) {
+	body, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.save(c, c.Param("id"), body)
+}
+
+// 🟢 PUT /drafts/:id/:part - autosave of one part (case, supporter, ips, assessment or files) of
+// the form, the others stay as they are. The body is the part: an object, an array for ips
+// and files, or null to clear it.
+func (h *DraftHandler) SaveDraftPart(c *gin.Context) {
+	part := c.Param("part")
+	if !slices.Contains(draftParts, part) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "unknown draft part " + part})
+		return
+	}
+	body, err := c.GetRawData()
+	if err != nil || !json.Valid(body) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "body must be JSON"})
+		return
+	}
+	id := c.Param("id")
+	d, err := h.Repo.GetDraftByID(id)
+	if draftError(c, "SaveDraftPart", err) {
+		return
+	}
+
+	// the stored parts with this one replaced, saved like a whole draft
+	var parts map[string]json.RawMessage
+	stored, err := json.Marshal(d.DraftContent)
+	if err == nil {
+		err = json.Unmarshal(stored, &parts)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	parts[part] = body
+	merged, err := json.Marshal(parts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	h.save(c, id, merged)
+}
+
+func (h *DraftHandler) save(c *gin.Context, id string, body []byte) {
+	content, ok := bindDraft(c, body)
+	if !ok {
+		return
+	}
+	d := models.Draft{ID: id, ExpiresAt: time.Now().Add(DraftTTL()), DraftContent: *content}
+	if draftError(c, "SaveDraft", h.Repo.SaveDraft(&d)) {
+		return
+	}
+	c.JSON(http.StatusOK, d)
+}
+
+// 🟢 DELETE /drafts/:id - discard a draft, for good
+func (h *DraftHandler) DeleteDraft(c *gin.Context) {
+	if draftError(c, "DeleteDraft", h.Repo.DeleteDraft(c.Param("id"))) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Draft deleted successfully"})
+}
+
+// 🟢 POST /drafts/:id/submit - create the case and records of the draft like POST /submissions
+// and remove the draft. 422 lists what is still missing or invalid. The draft ID is the
+// idempotency key, so submitting it twice creates one case.
+func (h *DraftHandler) SubmitDraft(c *gin.Context) {
+	d, err := h.Repo.GetDraftByID(c.Param("id"))
+	if draftError(c, "SubmitDraft", err) {
+		return
+	}
+	// the case is the draft owner's, whoever submits it
+	delete(d.Case, "researcher_id")
+	body, err := json.Marshal(d.DraftContent)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	s, problems, err := decodeSubmission(body)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if len(problems) > 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "invalid submission", "fields": problems})
+		return
+	}
+	s.Case.ResearcherID = d.ResearcherID
+	if !h.Submissions.prepare(c, s, d.ResearcherID) {
+		return
+	}
+
+	key := keyFor(d.ResearcherID, "draft:"+d.ID, body)
+	if !h.Submissions.create(c, "SubmitDraft", key, s) {
+		return
+	}
+	if err := h.Repo.DeleteDraft(d.ID); err != nil && !errors.Is(err, repository.ErrNotFound) {
+		// submitting again replays the case instead of creating a second one
+		log.Printf("❌ [SubmitDraft] remove draft %s of case %s: %v", d.ID, s.Case.CaseID, err)
+	}
+}
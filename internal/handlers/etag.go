@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ETags of the records that carry a revision (cases, assessments). A GET answers the revision
+// as ETag and 304 to a matching If-None-Match; a PATCH with If-Match only writes while the
+// record is still at that revision and answers 412 otherwise. A PATCH without If-Match writes
+// whatever the revision is, as before.
+
+func etag(revision int64) string {
+	return `"` + strconv.FormatInt(revision, 10) + `"`
+}
+
+// matchesETag - header lists tag or is "*". weak: W/ tags count too (If-None-Match); If-Match
+// compares strong tags only.
+func matchesETag(header, tag string, weak bool) bool {
+	for _, t := range strings.Split(header, ",") {
+		t = strings.TrimSpace(t)
+		if weak {
+			t = strings.TrimPrefix(t, "W/")
+		}
+		if t == "*" || t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// writeTagged answers v with its ETag, or 304 when the client has this revision already
+func writeTagged(c *gin.Context, revision int64, v any) {
+	tag := etag(revision)
+	c.Header("ETag", tag)
+	if inm := c.GetHeader("If-None-Match"); inm != "" && matchesETag(inm, tag, true) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.JSON(http.StatusOK, v)
+}
+
+// ifMatch checks If-Match against the stored revision. Returns the revision the update has to
+// be conditional on, nil without If-Match (or "*"); false when 412 was written.
+func ifMatch(c *gin.Context, revision int64) (*int64, bool) {
+	header := c.GetHeader("If-Match")
+	if header == "" || strings.TrimSpace(header) == "*" {
+		return nil, true
+	}
+	if !matchesETag(header, etag(revision), false) {
+		preconditionFailed(c, revision)
+		return nil, false
+	}
+	return &revision, true
+}
+
+// preconditionFailed - 412 with the current ETag, the client reloads and applies its change again
+func preconditionFailed(c *gin.Context, revision int64) {
+	c.Header("ETag", etag(revision))
+	c.JSON(http.StatusPreconditionFailed, gin.H{
+		"error": "the record was changed since you read it, reload it and apply your changes again",
+		"etag":  etag(revision),
+	})
+}
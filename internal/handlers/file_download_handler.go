@@ -9,7 +9,7 @@ import (
 )
 
 type FileDownloadHandler struct {
-	FileRepo *repository.FileRepo
+	FileRepo repository.FileRepository
 	GCS      *storage.GCSClient
 }
 
@@ -12,7 +12,7 @@ import (
 )
 
 type FileHandler struct {
-    Repo *repository.FileRepo
+    Repo repository.FileRepository
 }
 
 type FileUploadedRequest struct {
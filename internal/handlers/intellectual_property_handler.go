@@ -6,20 +6,18 @@ import (
 	"github.com/gin-gonic/gin"
 	"trl-research-backend/internal/models"
 	"trl-research-backend/internal/repository"
+	"trl-research-backend/internal/search"
 )
 
 type IntellectualPropertyHandler struct {
-	Repo *repository.IntellectualPropertyRepo
+	Repo   repository.IntellectualPropertyRepository
+	Cases  repository.CaseRepository // a restore needs the case live
+	Search *SearchIndex
 }
 
 // 🟢 GET /ips
 func (h *IntellectualPropertyHandler) GetIPAll(c *gin.Context) {
-	ips, err := h.Repo.GetIPAll()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-	c.JSON(http.StatusOK, ips)
+	listItems(c, ipList, h.Repo.ListIPs)
 }
 
 // 🟢 GET /ip/:id
@@ -51,22 +49,27 @@ func (h *IntellectualPropertyHandler) CreateIP(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	req.Deletion = models.Deletion{} // only DELETE puts a record in the trash
 
 	if err := h.Repo.CreateIP(&req); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	h.Search.putIP(req)
 
 	c.JSON(http.StatusOK, req)
 }
 
-// 🟢 PATCH /ip/:id
+// 🟢 PATCH /ip/:id - only the fields of IPPatch; 422 lists every invalid one
 func (h *IntellectualPropertyHandler) UpdateIPByID(c *gin.Context) {
 	id := c.Param("id")
-	var updateData map[string]interface{}
-
-	if err := c.ShouldBindJSON(&updateData); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	current, err := h.Repo.GetIPByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Intellectual Property not found"})
+		return
+	}
+	updateData, ok := bindPatch(c, "intellectual property", &IPPatch{}, current, ipPatchRules)
+	if !ok {
 		return
 	}
 
@@ -74,6 +77,34 @@ func (h *IntellectualPropertyHandler) UpdateIPByID(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	h.Search.syncIP(id)
 
 	c.JSON(http.StatusOK, gin.H{"message": "Intellectual Property updated successfully"})
 }
+
+// 🟢 DELETE /ip/:id
+func (h *IntellectualPropertyHandler) DeleteIP(c *gin.Context) {
+	id := c.Param("id")
+	if !trashed(c, "DeleteIP", "Intellectual Property", h.Repo.DeleteIP(id, deletedBy(c))) {
+		return
+	}
+	h.Search.remove(search.TypeIP, id)
+	c.JSON(http.StatusOK, gin.H{"message": "Intellectual Property deleted successfully"})
+}
+
+// 🟢 POST /ip/:id/restore - 409 while its case is deleted
+func (h *IntellectualPropertyHandler) RestoreIP(c *gin.Context) {
+	id := c.Param("id")
+	ip, err := findDeleted(ipList, id, h.Repo.ListIPs)
+	if !trashed(c, "RestoreIP", "Deleted intellectual property", err) {
+		return
+	}
+	if !restorableIn(c, h.Cases, ip.CaseID, "intellectual property") {
+		return
+	}
+	if !trashed(c, "RestoreIP", "Deleted intellectual property", h.Repo.RestoreIP(id)) {
+		return
+	}
+	h.Search.syncIP(id)
+	c.JSON(http.StatusOK, gin.H{"message": "Intellectual Property restored successfully"})
+}
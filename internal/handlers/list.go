@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"trl-research-backend/internal/auth"
+	"trl-research-backend/internal/listing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// What each GET collection endpoint can filter and sort on. Every spec sorts by its key by
+// default, and the key is also the tie-breaker of the other sorts. SoftDelete specs also take
+// ?deleted=include|only (admins) and filter the trash on deleted_at / deleted_by; deleted_at
+// doesn't sort, it is NULL on live rows.
+var (
+	createdAt = listing.Field{Kind: listing.Time, Filter: true, Sort: true}
+	updatedAt = listing.Field{Kind: listing.Time, Filter: true, Sort: true}
+	deletedAt = listing.Field{Kind: listing.Time, Filter: true}
+	eqString  = listing.Field{Kind: listing.String, Filter: true}
+	eqBool    = listing.Field{Kind: listing.Bool, Filter: true}
+	sortName  = listing.Field{Kind: listing.String, Sort: true}
+
+	adminList = &listing.Spec{Key: "admin_id", SoftDelete: true, Fields: map[string]listing.Field{
+		"admin_department": eqString,
+		"admin_email":      eqString,
+		"admin_first_name": sortName,
+		"admin_last_name":  sortName,
+		"created_at":       createdAt,
+		"deleted_at":       deletedAt,
+		"deleted_by":       eqString,
+	}}
+	researcherList = &listing.Spec{Key: "researcher_id", SoftDelete: true, Fields: map[string]listing.Field{
+		"researcher_department": eqString,
+		"researcher_email":      eqString,
+		"admin_id":              eqString,
+		"researcher_first_name": sortName,
+		"researcher_last_name":  sortName,
+		"created_at":            createdAt,
+		"updated_at":            updatedAt,
+		"deleted_at":            deletedAt,
+		"deleted_by":            eqString,
+	}}
+	coordinatorList = &listing.Spec{Key: "coordinator_email", SoftDelete: true, Fields: map[string]listing.Field{
+		"case_id":          eqString,
+		"department":       eqString,
+		"coordinator_name": sortName,
+		"created_at":       createdAt,
+		"deleted_at":       deletedAt,
+		"deleted_by":       eqString,
+	}}
+	supporterList = &listing.Spec{Key: "supporter_id", SoftDelete: true, Fields: map[string]listing.Field{
+		"case_id":                            eqString,
+		"support_research":                   eqBool,
+		"support_vdc":                        eqBool,
+		"support_sieic":                      eqBool,
+		"need_protect_intellectual_property": eqBool,
+		"need_co_developers":                 eqBool,
+		"need_activities":                    eqBool,
+		"need_test":                          eqBool,
+		"need_capital":                       eqBool,
+		"need_partners":                      eqBool,
+		"need_guidelines":                    eqBool,
+		"need_certification":                 eqBool,
+		"need_account":                       eqBool,
+		"created_at":                         createdAt,
+		"deleted_at":                         deletedAt,
+		"deleted_by":                         eqString,
+	}}
+	appointmentList = &listing.Spec{Key: "appointment_id", SoftDelete: true, Fields: map[string]listing.Field{
+		"case_id":    eqString,
+		"status":     eqString,
+		"date":       {Kind: listing.Time, Filter: true, Sort: true},
+		"created_at": createdAt,
+		"deleted_at": deletedAt,
+		"deleted_by": eqString,
+	}}
+	caseList = &listing.Spec{Key: "case_id", SoftDelete: true, Fields: map[string]listing.Field{
+		"status":            eqString,
+		"is_urgent":         eqBool,
+		"case_type":         eqString,
+		"case_type_id":      eqString,
+		"coordinator_email": eqString,
+		"researcher_id":     eqString,
+		"case_title":        sortName,
+		"created_at":        createdAt,
+		"updated_at":        updatedAt,
+		"deleted_at":        deletedAt,
+		"deleted_by":        eqString,
+	}}
+	ipList = &listing.Spec{Key: "id", SoftDelete: true, Fields: map[string]listing.Field{
+		"case_id":              eqString,
+		"ip_types":             eqString,
+		"ip_protection_status": eqString,
+		"created_at":           createdAt,
+		"updated_at":           updatedAt,
+		"deleted_at":           deletedAt,
+		"deleted_by":           eqString,
+	}}
+	assessmentTrlList = &listing.Spec{Key: "id", SoftDelete: true, Fields: map[string]listing.Field{
+		"case_id":               eqString,
+		"status":                eqString,
+		"questionnaire_version": {Kind: listing.Int, Filter: true},
+		"trl_level_result":      {Kind: listing.Int, Filter: true, Sort: true},
+		"created_at":            createdAt,
+		"deleted_at":            deletedAt,
+		"deleted_by":            eqString,
+	}}
+	auditList = &listing.Spec{Key: "id", DefaultSort: "-created_at", Fields: map[string]listing.Field{
+		"request_id":  eqString,
+		"actor_id":    eqString,
+		"actor_email": eqString,
+		"actor_role":  eqString,
+		"entity":      eqString,
+		"entity_id":   eqString,
+		"action":      eqString,
+		"method":      eqString,
+		"status":      {Kind: listing.Int, Filter: true},
+		"created_at":  createdAt,
+	}}
+)
+
+// legacyListLimit - the most records a request without list parameters gets
+const legacyListLimit = 1000
+
+// listItems answers a GET collection endpoint. A request with list parameters (limit,
+// page_token, sort, a filter or deleted) gets a listing.Page. One without gets the bare array it
+// got before pagination existed, deprecated: at most legacyListLimit records in the default
+// sort, the headers Deprecation: true and, when there are more, X-Next-Page-Token.
+func listItems[T any](c *gin.Context, spec *listing.Spec, list func(listing.Query) (*listing.Page[T], error)) {
+	q, err := listing.Parse(c.Request.URL.Query(), spec)
+	var bad *listing.Error
+	if errors.As(err, &bad) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": bad.Error(), "param": bad.Param})
+		return
+	}
+
+	if q.Deleted != listing.HideDeleted && c.GetString("role") != auth.RoleAdmin {
+		c.JSON(http.StatusForbidden, gin.H{"error": "only admins can list deleted records", "param": "deleted"})
+		return
+	}
+
+	if !q.Paged {
+		q.Limit = legacyListLimit
+	}
+	page, err := list(q)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !q.Paged {
+		c.Header("Deprecation", "true")
+		if page.NextPageToken != "" {
+			c.Header("X-Next-Page-Token", page.NextPageToken)
+		}
+		c.JSON(http.StatusOK, page.Items)
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"trl-research-backend/internal/models"
+	"trl-research-backend/internal/patch"
+
+	"github.com/gin-gonic/gin"
+)
+
+// The writable fields of the PATCH endpoints; see internal/patch. Fields left out here are
+// listed in the Rules next to each DTO or are unknown.
+
+// CasePatch - PATCH /case/:id. status moves through POST /case/:id/transition and the TRL
+// fields follow the assessments.
+type CasePatch struct {
+	CoordinatorEmail *string   `json:"coordinator_email"`
+	IsUrgent         *bool     `json:"is_urgent"`
+	UrgentReason     *string   `json:"urgent_reason"`
+	UrgentFeedback   *string   `json:"urgent_feedback"`
+	CaseTitle        *string   `json:"case_title"`
+	CaseType         *string   `json:"case_type"`
+	CaseDescription  *string   `json:"case_description"`
+	CaseKeywords     *string   `json:"case_keywords"`
+	DomainIDs        *[]string `json:"domain_ids"`
+	CaseTypeID       *string   `json:"case_type_id"`
+	KeywordIDs       *[]string `json:"keyword_ids"`
+	ResearcherID     *string   `json:"researcher_id"`
+}
+
+var casePatchRules = patch.Rules{
+	Immutable: []string{"case_id", "created_at"},
+	Managed:   append(append(append([]string{"updated_at", "revision"}, caseStatusFields...), caseDerivedFields...), deletionFields...),
+}
+
+func (p *CasePatch) Validate(v *patch.Problems) {
+	v.Email("coordinator_email", p.CoordinatorEmail, true)
+	v.Length("urgent_reason", p.UrgentReason, 0, 2000)
+	v.Length("urgent_feedback", p.UrgentFeedback, 0, 2000)
+	v.Length("case_title", p.CaseTitle, 1, 300)
+	v.Length("case_type", p.CaseType, 0, 200)
+	v.Length("case_description", p.CaseDescription, 0, 10000)
+	v.Length("case_keywords", p.CaseKeywords, 0, 1000)
+	v.IDs("domain_ids", p.DomainIDs, 20)
+	v.Length("case_type_id", p.CaseTypeID, 0, 50)
+	v.IDs("keyword_ids", p.KeywordIDs, 50)
+	v.Length("researcher_id", p.ResearcherID, 1, 50)
+}
+
+// AppointmentPatch - PATCH /appointment/:id
+type AppointmentPatch struct {
+	CaseID   *string    `json:"case_id"`
+	Date     *time.Time `json:"date"`
+	Status   *string    `json:"status"`
+	Location *string    `json:"location"`
+	Note     *string    `json:"note"`
+	Summary  *string    `json:"summary"`
+}
+
+var appointmentPatchRules = patch.Rules{
+	Immutable: []string{"appointment_id", "created_at"},
+	Managed:   append([]string{"updated_at"}, deletionFields...),
+}
+
+func (p *AppointmentPatch) Validate(v *patch.Problems) {
+	v.Length("case_id", p.CaseID, 1, 50)
+	v.Time("date", p.Date, 10)
+	v.Enum("status", p.Status, models.AppointmentStatuses...)
+	v.Length("location", p.Location, 0, 300)
+	v.Length("note", p.Note, 0, 2000)
+	v.Length("summary", p.Summary, 0, 5000)
+}
+
+// IPPatch - PATCH /ip/:id
+type IPPatch struct {
+	CaseID             *string `json:"case_id"`
+	IPTypes            *string `json:"ip_types"`
+	IPProtectionStatus *string `json:"ip_protection_status"`
+	IPRequestNumber    *string `json:"ip_request_number"`
+}
+
+var ipPatchRules = patch.Rules{
+	Immutable: []string{"id", "created_at"},
+	Managed:   append([]string{"updated_at"}, deletionFields...),
+}
+
+func (p *IPPatch) Validate(v *patch.Problems) {
+	v.Length("case_id", p.CaseID, 1, 50)
+	v.Enum("ip_types", p.IPTypes, models.IPTypes...)
+	v.Length("ip_protection_status", p.IPProtectionStatus, 0, 100)
+	v.Length("ip_request_number", p.IPRequestNumber, 0, 100)
+}
+
+// AssessmentTrlPatch - PATCH /assessment_trl/:id. The answers are checked against the
+// questionnaire when the merged assessment is scored; the level itself is always scored.
+// questionnaire_version and case_id are immutable here, only a new assessment sets them.
+type AssessmentTrlPatch struct {
+	QuestionnaireVersion *int                 `json:"questionnaire_version"`
+	ReadinessAnswers     *map[string]bool     `json:"readiness_answers"`
+	CriteriaAnswers      *map[string][]string `json:"criteria_answers"`
+	Rq1Answer            *bool                `json:"rq1_answer"`
+	Rq2Answer            *bool                `json:"rq2_answer"`
+	Rq3Answer            *bool                `json:"rq3_answer"`
+	Rq4Answer            *bool                `json:"rq4_answer"`
+	Rq5Answer            *bool                `json:"rq5_answer"`
+	Rq6Answer            *bool                `json:"rq6_answer"`
+	Rq7Answer            *bool                `json:"rq7_answer"`
+	Cq1Answer            *[]string            `json:"cq1_answer"`
+	Cq2Answer            *[]string            `json:"cq2_answer"`
+	Cq3Answer            *[]string            `json:"cq3_answer"`
+	Cq4Answer            *[]string            `json:"cq4_answer"`
+	Cq5Answer            *[]string            `json:"cq5_answer"`
+	Cq6Answer            *[]string            `json:"cq6_answer"`
+	Cq7Answer            *[]string            `json:"cq7_answer"`
+	Cq8Answer            *[]string            `json:"cq8_answer"`
+	Cq9Answer            *[]string            `json:"cq9_answer"`
+}
+
+var assessmentTrlPatchRules = patch.Rules{
+	Immutable: []string{"id", "case_id", "created_at", "questionnaire_version"},
+	// evidence is part of the create / update response, not of the record
+	Managed: append(append([]string{"updated_at", "revision", "trl_level_result", "evidence"}, assessmentReviewFields...), deletionFields...),
+}
+
+func (p *AssessmentTrlPatch) Validate(v *patch.Problems) {
+	if p.QuestionnaireVersion != nil && *p.QuestionnaireVersion < 0 {
+		v.Add("questionnaire_version", "format", "must be a questionnaire version, or 0 for the active one")
+	}
+}
+
+// bindPatch reads a PATCH body into dto and returns the fields to update. stored is the record
+// as it is now. 400 for a body that isn't a JSON object, 422 listing every problem otherwise.
+func bindPatch(c *gin.Context, entity string, dto any, stored any, rules patch.Rules) (map[string]interface{}, bool) {
+	body, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	data, err := patch.Decode(body, dto, stored, rules)
+	var perr *patch.Error
+	if errors.As(err, &perr) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "invalid " + entity, "fields": perr.Problems})
+		return nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return data, true
+}
@@ -0,0 +1,256 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"trl-research-backend/internal/auth"
+	"trl-research-backend/internal/models"
+	"trl-research-backend/internal/repository"
+	"trl-research-backend/internal/trl"
+
+	"github.com/gin-gonic/gin"
+)
+
+// QuestionnaireHandler - versions of the TRL questionnaire. Admins write a draft, publish it
+// (it can't change after that) and activate it for new assessments. Version 1 is built in.
+type QuestionnaireHandler struct {
+	Repo repository.QuestionnaireRepository
+}
+
+// errQuestionnaireNotAnswerable - the version exists but is still a draft
+var errQuestionnaireNotAnswerable = errors.New("questionnaire is not published")
+
+// loadQuestionnaire returns a published version, the built-in one without a lookup
+func loadQuestionnaire(repo repository.QuestionnaireRepository, version int) (*models.QuestionnaireTemplate, error) {
+	if version == trl.BuiltInVersion {
+		return trl.BuiltInTemplate(), nil
+	}
+	t, err := repo.GetQuestionnaireTemplate(version)
+	if err != nil {
+		return nil, err
+	}
+	if !t.Published {
+		return nil, fmt.Errorf("questionnaire %d: %w", version, errQuestionnaireNotAnswerable)
+	}
+	return t, nil
+}
+
+// activeQuestionnaire - the version new assessments use, the built-in one while none is activated
+func activeQuestionnaire(repo repository.QuestionnaireRepository) (*models.QuestionnaireTemplate, error) {
+	t, err := repo.GetActiveQuestionnaireTemplate()
+	if errors.Is(err, repository.ErrNotFound) {
+		t, err = trl.BuiltInTemplate(), nil
+		t.Active = true
+	}
+	return t, err
+}
+
+// questionnaireError answers 404 / 409 / 422 / 500 for a repository error
+func questionnaireError(c *gin.Context, where string, err error) {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Questionnaire not found"})
+	case errors.Is(err, repository.ErrConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": "questionnaire is published and can't be changed"})
+	case errors.Is(err, errQuestionnaireNotAnswerable):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		log.Printf("❌ [%s] %v", where, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// versionParam parses :version; writes 400 and returns false when it isn't a number
+func versionParam(c *gin.Context) (int, bool) {
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid version"})
+		return 0, false
+	}
+	return version, true
+}
+
+// builtInReadOnly answers 409 for a write to the built-in version
+func builtInReadOnly(c *gin.Context, version int) bool {
+	if version != trl.BuiltInVersion {
+		return false
+	}
+	c.JSON(http.StatusConflict, gin.H{"error": "the built-in questionnaire can't be changed"})
+	return true
+}
+
+// QuestionnaireReq - the editable part of a template
+type QuestionnaireReq struct {
+	Title       models.LocalizedText `json:"title"`
+	Description models.LocalizedText `json:"description"`
+	Questions   []models.Question    `json:"questions"`
+}
+
+// bindQuestionnaire reads and validates a template body; 422 lists every problem
+func bindQuestionnaire(c *gin.Context) (*models.QuestionnaireTemplate, bool) {
+	var req QuestionnaireReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	t := &models.QuestionnaireTemplate{Title: req.Title, Description: req.Description, Questions: req.Questions}
+	var verr *trl.ValidationError
+	if err := trl.ValidateTemplate(t); errors.As(err, &verr) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "invalid questionnaire", "problems": verr.Problems})
+		return nil, false
+	}
+	return t, true
+}
+
+// 🟢 GET /questionnaires - every version; drafts only for admins
+func (h *QuestionnaireHandler) GetQuestionnaires(c *gin.Context) {
+	stored, err := h.Repo.GetQuestionnaireTemplates()
+	if err != nil {
+		questionnaireError(c, "GetQuestionnaires", err)
+		return
+	}
+
+	builtIn := trl.BuiltInTemplate()
+	builtIn.Active = true
+	templates := []models.QuestionnaireTemplate{}
+	for _, t := range stored {
+		if t.Active {
+			builtIn.Active = false
+		}
+		if t.Published || c.GetString("role") == auth.RoleAdmin {
+			templates = append(templates, t)
+		}
+	}
+	c.JSON(http.StatusOK, append([]models.QuestionnaireTemplate{*builtIn}, templates...))
+}
+
+// 🟢 GET /questionnaire/active - the form new assessments are answered on
+func (h *QuestionnaireHandler) GetActiveQuestionnaire(c *gin.Context) {
+	t, err := activeQuestionnaire(h.Repo)
+	if err != nil {
+		questionnaireError(c, "GetActiveQuestionnaire", err)
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
+
+// 🟢 GET /questionnaire/:version
+func (h *QuestionnaireHandler) GetQuestionnaire(c *gin.Context) {
+	version, ok := versionParam(c)
+	if !ok {
+		return
+	}
+	if version == trl.BuiltInVersion {
+		t, err := activeQuestionnaire(h.Repo)
+		if err != nil {
+			questionnaireError(c, "GetQuestionnaire", err)
+			return
+		}
+		builtIn := trl.BuiltInTemplate()
+		builtIn.Active = t.BuiltIn
+		c.JSON(http.StatusOK, builtIn)
+		return
+	}
+
+	t, err := h.Repo.GetQuestionnaireTemplate(version)
+	if err == nil && !t.Published && c.GetString("role") != auth.RoleAdmin {
+		err = repository.ErrNotFound
+	}
+	if err != nil {
+		questionnaireError(c, "GetQuestionnaire", err)
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
+
+// 🟢 POST /questionnaire - new draft with the next version number (admin)
+func (h *QuestionnaireHandler) CreateQuestionnaire(c *gin.Context) {
+	t, ok := bindQuestionnaire(c)
+	if !ok {
+		return
+	}
+	t.CreatedBy = c.GetString("userID")
+	if err := h.Repo.CreateQuestionnaireTemplate(t, trl.BuiltInVersion+1); err != nil {
+		questionnaireError(c, "CreateQuestionnaire", err)
+		return
+	}
+	log.Printf("📝 questionnaire v%d drafted by %s", t.Version, c.GetString("userEmail"))
+	c.JSON(http.StatusOK, t)
+}
+
+// 🟢 PUT /questionnaire/:version - replace a draft (admin)
+func (h *QuestionnaireHandler) UpdateQuestionnaire(c *gin.Context) {
+	version, ok := versionParam(c)
+	if !ok || builtInReadOnly(c, version) {
+		return
+	}
+	t, ok := bindQuestionnaire(c)
+	if !ok {
+		return
+	}
+	t.Version = version
+	if err := h.Repo.UpdateQuestionnaireTemplate(t); err != nil {
+		questionnaireError(c, "UpdateQuestionnaire", err)
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
+
+// 🟢 DELETE /questionnaire/:version - drop a draft (admin)
+func (h *QuestionnaireHandler) DeleteQuestionnaire(c *gin.Context) {
+	version, ok := versionParam(c)
+	if !ok || builtInReadOnly(c, version) {
+		return
+	}
+	if err := h.Repo.DeleteQuestionnaireTemplate(version); err != nil {
+		questionnaireError(c, "DeleteQuestionnaire", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Questionnaire deleted successfully"})
+}
+
+// 🟢 POST /questionnaire/:version/publish - freeze a draft so it can be answered (admin)
+func (h *QuestionnaireHandler) PublishQuestionnaire(c *gin.Context) {
+	version, ok := versionParam(c)
+	if !ok || builtInReadOnly(c, version) {
+		return
+	}
+	if err := h.Repo.PublishQuestionnaireTemplate(version); err != nil {
+		questionnaireError(c, "PublishQuestionnaire", err)
+		return
+	}
+	log.Printf("📢 questionnaire v%d published by %s", version, c.GetString("userEmail"))
+	t, err := h.Repo.GetQuestionnaireTemplate(version)
+	if err != nil {
+		questionnaireError(c, "PublishQuestionnaire", err)
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
+
+// 🟢 POST /questionnaire/:version/activate - new assessments use this version from now on (admin)
+func (h *QuestionnaireHandler) ActivateQuestionnaire(c *gin.Context) {
+	version, ok := versionParam(c)
+	if !ok {
+		return
+	}
+	stored := version
+	if version == trl.BuiltInVersion {
+		stored = 0 // back to the built-in form: no stored version is active
+	}
+	err := h.Repo.SetActiveQuestionnaireTemplate(stored)
+	if errors.Is(err, repository.ErrConflict) {
+		c.JSON(http.StatusConflict, gin.H{"error": "publish the questionnaire before activating it"})
+		return
+	}
+	if err != nil {
+		questionnaireError(c, "ActivateQuestionnaire", err)
+		return
+	}
+	log.Printf("✅ questionnaire v%d activated by %s", version, c.GetString("userEmail"))
+	c.JSON(http.StatusOK, gin.H{"message": "Questionnaire activated successfully", "version": version})
+}
@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"trl-research-backend/internal/auth"
+	"trl-research-backend/internal/models"
+	"trl-research-backend/internal/repository/memory"
+	"trl-research-backend/internal/trl"
+
+	"github.com/gin-gonic/gin"
+)
+
+// adminEngine - a bare router whose requests are made by an admin
+func adminEngine() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set("role", auth.RoleAdmin)
+		c.Set("userID", "AD-00001")
+	})
+	return r
+}
+
+// call sends body as JSON and decodes the JSON answer into out (when not nil)
+func call(t *testing.T, r *gin.Engine, method, path string, body, out interface{}) int {
+	t.Helper()
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(method, path, &buf))
+	if out != nil {
+		if err := json.Unmarshal(w.Body.Bytes(), out); err != nil {
+			t.Fatalf("%s %s: %d %s", method, path, w.Code, w.Body)
+		}
+	}
+	return w.Code
+}
+
+// oneLevel - a questionnaire of TRL 1 only, with its own criteria
+func oneLevel(title string) QuestionnaireReq {
+	txt := models.LocalizedText{TH: title, EN: title}
+	return QuestionnaireReq{
+		Title: txt,
+		Questions: []models.Question{
+			{Key: "rq1", Type: models.QuestionReadiness, Text: txt, Levels: []int{1}},
+			{Key: "cq1", Type: models.QuestionCriteria, Text: txt, Levels: []int{1}, Options: []models.AnswerOption{
+				{Value: "a", Text: txt}, {Value: "b", Text: txt},
+			}},
+		},
+	}
+}
+
+func questionnaireEngine() (*gin.Engine, *AssessmentTrlHandler) {
+	repos := memory.NewRepositories()
+	q := &QuestionnaireHandler{Repo: repos.Questionnaire}
+	a := &AssessmentTrlHandler{
+		Repo:      repos.AssessmentTrl,
+		Templates: repos.Questionnaire,
+		Trl:       &CaseTrl{Assessments: repos.AssessmentTrl, Templates: repos.Questionnaire, Cases: repos.Case, Supporters: repos.Supporter},
+	}
+	r := adminEngine()
+	r.GET("/questionnaire/active", q.GetActiveQuestionnaire)
+	r.POST("/questionnaire", q.CreateQuestionnaire)
+	r.PUT("/questionnaire/:version", q.UpdateQuestionnaire)
+	r.DELETE("/questionnaire/:version", q.DeleteQuestionnaire)
+	r.POST("/questionnaire/:version/publish", q.PublishQuestionnaire)
+	r.POST("/questionnaire/:version/activate", q.ActivateQuestionnaire)
+	r.POST("/assessment_trl", a.CreateAssessmentTrl)
+	r.PATCH("/assessment_trl/:id", a.UpdateAssessmentTrlByID)
+	return r, a
+}
+
+func TestQuestionnaireLifecycle(t *testing.T) {
+	r, _ := questionnaireEngine()
+
+	var draft models.QuestionnaireTemplate
+	if code := call(t, r, "POST", "/questionnaire", oneLevel("v2"), &draft); code != http.StatusOK || draft.Version != 2 || draft.Published {
+		t.Fatalf("create: %d, %+v", code, draft)
+	}
+	// a draft can't be activated, but it can be changed or dropped
+	if code := call(t, r, "POST", "/questionnaire/2/activate", nil, nil); code != http.StatusConflict {
+		t.Errorf("activate a draft: %d, want 409", code)
+	}
+	if code := call(t, r, "PUT", "/questionnaire/2", oneLevel("v2, edited"), nil); code != http.StatusOK {
+		t.Errorf("update the draft: %d", code)
+	}
+	invalid := oneLevel("v2")
+	invalid.Questions = invalid.Questions[:1]
+	if code := call(t, r, "PUT", "/questionnaire/2", invalid, nil); code != http.StatusUnprocessableEntity {
+		t.Errorf("update with a level without criteria: %d, want 422", code)
+	}
+
+	if code := call(t, r, "POST", "/questionnaire/2/publish", nil, nil); code != http.StatusOK {
+		t.Fatalf("publish: %d", code)
+	}
+	// published, it is frozen
+	tests := []struct {
+		method, path string
+		body         interface{}
+	}{
+		{"PUT", "/questionnaire/2", oneLevel("v2, too late")},
+		{"DELETE", "/questionnaire/2", nil},
+		{"POST", "/questionnaire/2/publish", nil},
+		{"PUT", "/questionnaire/1", oneLevel("v1")},
+		{"DELETE", "/questionnaire/1", nil},
+	}
+	for _, tt := range tests {
+		if code := call(t, r, tt.method, tt.path, tt.body, nil); code != http.StatusConflict {
+			t.Errorf("%s %s: %d, want 409", tt.method, tt.path, code)
+		}
+	}
+
+	var active models.QuestionnaireTemplate
+	call(t, r, "GET", "/questionnaire/active", nil, &active)
+	if active.Version != trl.BuiltInVersion {
+		t.Errorf("active before activating: v%d", active.Version)
+	}
+	if code := call(t, r, "POST", "/questionnaire/2/activate", nil, nil); code != http.StatusOK {
+		t.Fatalf("activate: %d", code)
+	}
+	call(t, r, "GET", "/questionnaire/active", nil, &active)
+	if active.Version != 2 || active.Title.EN != "v2, edited" {
+		t.Errorf("active: v%d %q", active.Version, active.Title.EN)
+	}
+	if code := call(t, r, "DELETE", "/questionnaire/3", nil, nil); code != http.StatusNotFound {
+		t.Errorf("delete a version that doesn't exist: %d, want 404", code)
+	}
+}
+
+// an assessment is scored against the version it was answered on, whatever is active now
+func TestAssessmentKeepsItsQuestionnaire(t *testing.T) {
+	r, h := questionnaireEngine()
+	v1 := trl.BuiltInTemplate()
+	criteria := func(key string) []string {
+		var values []string
+		for _, o := range v1.Question(key).Options {
+			values = append(values, o.Value)
+		}
+		return values
+	}
+
+	var old assessmentResponse
+	body := map[string]interface{}{"case_id": "CS-00001", "rq1_answer": true, "cq1_answer": criteria("cq1")}
+	if code := call(t, r, "POST", "/assessment_trl", body, &old); code != http.StatusOK {
+		t.Fatalf("create on v1: %d", code)
+	}
+	if old.QuestionnaireVersion != 1 || old.TrlLevelResult != 1 {
+		t.Fatalf("created on v%d at TRL %d", old.QuestionnaireVersion, old.TrlLevelResult)
+	}
+
+	call(t, r, "POST", "/questionnaire", oneLevel("v2"), nil)
+	call(t, r, "POST", "/questionnaire/2/publish", nil, nil)
+	if code := call(t, r, "POST", "/questionnaire/2/activate", nil, nil); code != http.StatusOK {
+		t.Fatalf("activate v2: %d", code)
+	}
+
+	// new assessments use v2, where the v1 criteria are unknown
+	var created assessmentResponse
+	body = map[string]interface{}{"case_id": "CS-00002", "readiness_answers": map[string]bool{"rq1": true}, "criteria_answers": map[string][]string{"cq1": {"a", "b"}}}
+	if code := call(t, r, "POST", "/assessment_trl", body, &created); code != http.StatusOK || created.QuestionnaireVersion != 2 || created.TrlLevelResult != 1 {
+		t.Errorf("create on v2: %d, v%d TRL %d", code, created.QuestionnaireVersion, created.TrlLevelResult)
+	}
+	body = map[string]interface{}{"case_id": "CS-00002", "rq1_answer": true, "cq1_answer": criteria("cq1")}
+	if code := call(t, r, "POST", "/assessment_trl", body, nil); code != http.StatusUnprocessableEntity {
+		t.Errorf("v1 answers on v2: %d, want 422", code)
+	}
+
+	// the v1 assessment still takes v1 answers: TRL 2 doesn't exist in v2
+	var res struct {
+		Version int `json:"questionnaire_version"`
+		Level   int `json:"trl_level_result"`
+	}
+	body = map[string]interface{}{"rq2_answer": true, "cq2_answer": criteria("cq2")}
+	if code := call(t, r, "PATCH", "/assessment_trl/"+old.ID, body, &res); code != http.StatusOK || res.Version != 1 || res.Level != 2 {
+		t.Errorf("update on v1: %d, %+v", code, res)
+	}
+	if code := call(t, r, "PATCH", "/assessment_trl/"+old.ID, map[string]int{"questionnaire_version": 2}, nil); code != http.StatusUnprocessableEntity {
+		t.Errorf("move to v2: %d, want 422", code)
+	}
+	stored, err := h.Repo.GetAssessmentTrlByID(old.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Version() != 1 || stored.TrlLevelResult != 2 || len(stored.CriteriaAnswers) != trl.MaxLevel {
+		t.Errorf("stored: v%d TRL %d, %d criteria answers", stored.Version(), stored.TrlLevelResult, len(stored.CriteriaAnswers))
+	}
+}
@@ -10,24 +10,21 @@ import (
 	"trl-research-backend/internal/entity"
 	"trl-research-backend/internal/models"
 	"trl-research-backend/internal/repository"
+	"trl-research-backend/internal/search"
 	"trl-research-backend/internal/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
 type ResearcherHandler struct {
-	Repo *repository.ResearcherRepo
+	Repo     repository.ResearcherRepository
+	Sessions repository.SessionRepository
+	Search   *SearchIndex
 }
 
 // 🟢 GET /researchers
 func (h *ResearcherHandler) GetResearcherAll(c *gin.Context) {
-	researchers, err := h.Repo.GetResearcherAll()
-	if err != nil {
-		log.Println("Get Researcher All error:", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-	c.JSON(http.StatusOK, researchers)
+	listItems(c, researcherList, h.Repo.ListResearchers)
 }
 
 // 🟢 GET /researcher/:id
@@ -64,7 +61,7 @@ func (h *ResearcherHandler) GetResearcherProfile(c *gin.Context) {
 
 	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 	// Validate and decode JWT
-	kp, err := utils.NewEnvKeyProvider()
+	kp, err := utils.SharedKeyProvider()
 	if err != nil {
 		log.Printf("❌ [GetResearcherProfile] Key provider error: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Key provider error"})
@@ -103,12 +100,14 @@ func (h *ResearcherHandler) CreateResearcher(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	req.Deletion = models.Deletion{} // only DELETE puts a record in the trash
 
 	if err := h.Repo.CreateResearcher(&req); err != nil {
 		log.Println("Create Researcher error:", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	h.Search.putResearcher(req)
 
 	c.JSON(http.StatusOK, req)
 }
@@ -187,9 +186,34 @@ func (h *ResearcherHandler) UpdateResearcherProfileByID(c *gin.Context) {
 
 	// Return updated profile in response (using entity.ResearcherResponse format)
 	if updatedResearcher != nil {
+		h.Search.putResearcher(*updatedResearcher)
 		response := updatedResearcher.ToResponse()
 		c.JSON(http.StatusOK, response)
 	} else {
 		c.JSON(http.StatusOK, gin.H{"message": "Researcher updated successfully"})
 	}
 }
+
+// 🟢 DELETE /researcher/:id - soft delete, the researcher is signed out everywhere. Their cases
+// stay; the purge keeps a researcher who still has cases.
+func (h *ResearcherHandler) DeleteResearcher(c *gin.Context) {
+	id := c.Param("id")
+	if !trashed(c, "DeleteResearcher", "Researcher", h.Repo.DeleteResearcher(id, deletedBy(c))) {
+		return
+	}
+	if _, err := h.Sessions.RevokeSessionsByUserID(id); err != nil {
+		log.Printf("❌ [DeleteResearcher] revoking sessions of %s: %v", id, err)
+	}
+	h.Search.remove(search.TypeResearcher, id)
+	c.JSON(http.StatusOK, gin.H{"message": "Researcher deleted successfully"})
+}
+
+// 🟢 POST /researcher/:id/restore
+func (h *ResearcherHandler) RestoreResearcher(c *gin.Context) {
+	id := c.Param("id")
+	if !trashed(c, "RestoreResearcher", "Deleted researcher", h.Repo.RestoreResearcher(id)) {
+		return
+	}
+	h.Search.syncResearcher(id)
+	c.JSON(http.StatusOK, gin.H{"message": "Researcher restored successfully"})
+}
@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"trl-research-backend/internal/listing"
+	"trl-research-backend/internal/models"
+	"trl-research-backend/internal/repository"
+	"trl-research-backend/internal/search"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	searchDefaultLimit = 20
+	searchMaxLimit     = 100
+)
+
+// SearchIndex keeps the full-text index in step with the repositories: the case / researcher /
+// IP handlers index what they write, and Sync catches up on what changed since the last sync,
+// written by this server or another one.
+type SearchIndex struct {
+	Index       *search.Index
+	Cases       repository.CaseRepository
+	Researchers repository.ResearcherRepository
+	IPs         repository.IntellectualPropertyRepository
+}
+
+// searchSyncOverlap - how far before the last sync the next one looks again, for writes that
+// committed late or came from a server whose clock is a bit behind
+const searchSyncOverlap = time.Minute
+
+// Sync indexes the cases, researchers and IPs updated since the last sync (a delete or restore
+// updates them too), every one of them when the index is new. Deleted records leave the index.
+func (s *SearchIndex) Sync() error {
+	started := time.Now()
+	since, err := s.Index.Synced()
+	if err != nil {
+		return err
+	}
+	values := url.Values{"deleted": {string(listing.IncludeDeleted)}}
+	if !since.IsZero() {
+		// sorted by updated_at, Firestore reads only the changed documents
+		values.Set("sort", "updated_at")
+		values.Set("updated_at_from", since.Add(-searchSyncOverlap).Format(time.RFC3339Nano))
+	}
+
+	cases, err := changed(values, caseList, s.Cases.ListCases)
+	if err != nil {
+		return err
+	}
+	for _, cs := range cases {
+		s.index(cs.IsDeleted(), search.CaseDocument(cs))
+	}
+	researchers, err := changed(values, researcherList, s.Researchers.ListResearchers)
+	if err != nil {
+		return err
+	}
+	for _, r := range researchers {
+		s.index(r.IsDeleted(), search.ResearcherDocument(r))
+	}
+	ips, err := changed(values, ipList, s.IPs.ListIPs)
+	if err != nil {
+		return err
+	}
+	for _, ip := range ips {
+		s.index(ip.IsDeleted(), search.IPDocument(ip))
+	}
+	return s.Index.SetSynced(started)
+}
+
+// changed - every record of a list request, not a page
+func changed[T any](values url.Values, spec *listing.Spec, list func(listing.Query) (*listing.Page[T], error)) ([]T, error) {
+	q, err := listing.Parse(values, spec)
+	if err != nil {
+		return nil, err
+	}
+	q.Limit = 0
+	page, err := list(q)
+	if err != nil {
+		return nil, err
+	}
+	return page.Items, nil
+}
+
+// Run syncs every interval until ctx is done
+func (s *SearchIndex) Run(ctx context.Context, every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Sync(); err != nil {
+				log.Printf("❌ [SearchIndex] sync: %v", err)
+			}
+		}
+	}
+}
+
+// The put / remove / sync methods index a record after a write that already succeeded, sync
+// reads it back first. A record that isn't there (anymore, or in the trash) leaves the index;
+// any other failure is only logged, the next Sync indexes the record again.
+
+func (s *SearchIndex) index(deleted bool, d search.Document) {
+	if deleted {
+		s.remove(d.Type, d.ID)
+		return
+	}
+	if err := s.Index.Put(d); err != nil {
+		log.Printf("❌ [SearchIndex] %s %s: %v", d.Type, d.ID, err)
+	}
+}
+
+func (s *SearchIndex) remove(typ, id string) {
+	if err := s.Index.Remove(typ, id); err != nil {
+		log.Printf("❌ [SearchIndex] %s %s: %v", typ, id, err)
+	}
+}
+
+func (s *SearchIndex) putCase(cs models.CaseInfo) { s.index(false, search.CaseDocument(cs)) }
+
+func (s *SearchIndex) putResearcher(r models.ResearcherInfo) {
+	s.index(false, search.ResearcherDocument(r))
+}
+
+func (s *SearchIndex) putIP(ip models.IntellectualProperty) { s.index(false, search.IPDocument(ip)) }
+
+func (s *SearchIndex) syncCase(id string) {
+	cs, err := s.Cases.GetCaseByID(id)
+	if errors.Is(err, repository.ErrNotFound) {
+		s.remove(search.TypeCase, id)
+		return
+	}
+	if err != nil {
+		log.Printf("❌ [SearchIndex] case %s: %v", id, err)
+		return
+	}
+	s.putCase(*cs)
+}
+
+func (s *SearchIndex) syncResearcher(id string) {
+	r, err := s.Researchers.GetResearcherByID(id)
+	if errors.Is(err, repository.ErrNotFound) {
+		s.remove(search.TypeResearcher, id)
+		return
+	}
+	if err != nil {
+		log.Printf("❌ [SearchIndex] researcher %s: %v", id, err)
+		return
+	}
+	s.putResearcher(*r)
+}
+
+func (s *SearchIndex) syncIP(id string) {
+	ip, err := s.IPs.GetIPByID(id)
+	if errors.Is(err, repository.ErrNotFound) {
+		s.remove(search.TypeIP, id)
+		return
+	}
+	if err != nil {
+		log.Printf("❌ [SearchIndex] ip %s: %v", id, err)
+		return
+	}
+	s.putIP(*ip)
+}
+
+// syncCaseIPs re-indexes the IPs of a case after it was deleted or restored with them
+func (s *SearchIndex) syncCaseIPs(caseID string) {
+	q, err := listing.Parse(url.Values{"case_id": {caseID}, "deleted": {string(listing.IncludeDeleted)}}, ipList)
+	if err != nil {
+		log.Printf("❌ [SearchIndex] ips of case %s: %v", caseID, err)
+		return
+	}
+	q.Limit = 0 // every IP of the case, not a page
+	page, err := s.IPs.ListIPs(q)
+	if err != nil {
+		log.Printf("❌ [SearchIndex] ips of case %s: %v", caseID, err)
+		return
+	}
+	for _, ip := range page.Items {
+		s.index(ip.IsDeleted(), search.IPDocument(ip))
+	}
+}
+
+// 🟢 GET /search?q=...&type=case,researcher,ip&limit=20 - full-text search (staff)
+func (s *SearchIndex) Search(c *gin.Context) {
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q is required"})
+		return
+	}
+
+	var types []string
+	if t := c.Query("type"); t != "" {
+		for _, typ := range strings.Split(t, ",") {
+			if !search.IsType(typ) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "type must be one of " + strings.Join(search.Types, ", ")})
+				return
+			}
+			types = append(types, typ)
+		}
+	}
+
+	limit := searchDefaultLimit
+	if l := c.Query("limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n < 1 || n > searchMaxLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be 1 to " + strconv.Itoa(searchMaxLimit)})
+			return
+		}
+		limit = n
+	}
+
+	hits, total, err := s.Index.Search(q, types, limit)
+	if err != nil {
+		log.Printf("❌ [Search] %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"query": q, "hits": hits, "total": total})
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+
+	"trl-research-backend/internal/models"
+	"trl-research-backend/internal/repository/memory"
+	"trl-research-backend/internal/search"
+)
+
+// the writes of another server instance reach this one's index with the next Sync
+func TestSearchIndexSync(t *testing.T) {
+	repos := memory.NewRepositories()
+	index, err := search.Open("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer index.Close()
+	s := &SearchIndex{Index: index, Cases: repos.Case, Researchers: repos.Researcher, IPs: repos.IntellectualProperty}
+	sync := func() {
+		t.Helper()
+		if err := s.Sync(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	find := func(q string) string {
+		t.Helper()
+		hits, _, err := index.Search(q, nil, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []string
+		for _, h := range hits {
+			ids = append(ids, h.ID)
+		}
+		return strings.Join(ids, ",")
+	}
+
+	cs := models.CaseInfo{CaseTitle: "เครื่องวัดความชื้นในดิน"}
+	if err := repos.Case.CreateCase(&cs); err != nil {
+		t.Fatal(err)
+	}
+	sync()
+	if got := find("ชื้น"); got != cs.CaseID {
+		t.Fatalf("first sync: got %q", got)
+	}
+
+	ip := models.IntellectualProperty{CaseID: cs.CaseID, IPRequestNumber: "2301001234"}
+	if err := repos.IntellectualProperty.CreateIP(&ip); err != nil {
+		t.Fatal(err)
+	}
+	if err := repos.Case.UpdateCaseByID(cs.CaseID, map[string]interface{}{"case_title": "Soil sensor"}); err != nil {
+		t.Fatal(err)
+	}
+	sync()
+	if find("sensor") != cs.CaseID || find("ชื้น") != "" || find("2301001234") != ip.ID {
+		t.Errorf("after update: sensor %q, ชื้น %q, ip %q", find("sensor"), find("ชื้น"), find("2301001234"))
+	}
+
+	// the case goes to the trash with its IP, and comes back with it
+	if err := repos.Case.DeleteCase(cs.CaseID, "AD-00001"); err != nil {
+		t.Fatal(err)
+	}
+	sync()
+	if find("sensor") != "" || find("2301001234") != "" {
+		t.Errorf("after delete: sensor %q, ip %q", find("sensor"), find("2301001234"))
+	}
+	if err := repos.Case.RestoreCase(cs.CaseID); err != nil {
+		t.Fatal(err)
+	}
+	sync()
+	if find("sensor") != cs.CaseID || find("2301001234") != ip.ID {
+		t.Errorf("after restore: sensor %q, ip %q", find("sensor"), find("2301001234"))
+	}
+}
@@ -0,0 +1,349 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"trl-research-backend/internal/audit"
+	"trl-research-backend/internal/auth"
+	"trl-research-backend/internal/models"
+	"trl-research-backend/internal/patch"
+	"trl-research-backend/internal/repository"
+	"trl-research-backend/internal/taxonomy"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// POST /submissions creates a case with its supporter, IPs, assessment and uploaded files in one
+// transaction, instead of a POST per record that can fail halfway. Each record is read through
+// the DTO of its PATCH endpoint (patch.go), so it accepts the same fields.
+
+const (
+	// IdempotencyKeyHeader - a retry with the same key and body gets the first answer again
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader - set to "true" on such an answer
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	idempotencyTTL    = 24 * time.Hour
+	maxSubmissionList = 20 // ips and files
+)
+
+// SupporterSubmission - the supporter of a submission
+type SupporterSubmission struct {
+	SupportResearch                 *bool   `json:"support_research"`
+	SupportVDC                      *bool   `json:"support_vdc"`
+	SupportSiEIC                    *bool   `json:"support_sieic"`
+	NeedProtectIntellectualProperty *bool   `json:"need_protect_intellectual_property"`
+	NeedCoDevelopers                *bool   `json:"need_co_developers"`
+	NeedActivities                  *bool   `json:"need_activities"`
+	NeedTest                        *bool   `json:"need_test"`
+	NeedCapital                     *bool   `json:"need_capital"`
+	NeedPartners                    *bool   `json:"need_partners"`
+	NeedGuidelines                  *bool   `json:"need_guidelines"`
+	NeedCertification               *bool   `json:"need_certification"`
+	NeedAccount                     *bool   `json:"need_account"`
+	Need                            *string `json:"need"`
+	AdditionalDocuments             *string `json:"additional_documents"`
+}
+
+func (p *SupporterSubmission) Validate(v *patch.Problems) {
+	v.Length("need", p.Need, 0, 5000)
+	v.Length("additional_documents", p.AdditionalDocuments, 0, 5000)
+}
+
+// FileSubmission - a file already uploaded through POST /presign/upload
+type FileSubmission struct {
+	FileName    *string `json:"file_name"`
+	ObjectPath  *string `json:"object_path"`
+	ContentType *string `json:"content_type"`
+}
+
+func (p *FileSubmission) Validate(v *patch.Problems) {
+	v.Length("file_name", p.FileName, 1, 300)
+	v.Length("object_path", p.ObjectPath, 1, 1000)
+	v.Length("content_type", p.ContentType, 0, 100)
+}
+
+// The records of a submission are new: their IDs, case_id and timestamps are given on create
+var (
+	submissionCaseRules = patch.Rules{
+		Managed: append([]string{"case_id", "created_at"}, casePatchRules.Managed...),
+	}
+	submissionSupporterRules = patch.Rules{
+		Managed: append([]string{"supporter_id", "case_id", "created_at", "updated_at"}, deletionFields...),
+	}
+	submissionIPRules = patch.Rules{
+		Managed: append([]string{"id", "case_id", "created_at"}, ipPatchRules.Managed...),
+	}
+	submissionAssessmentRules = patch.Rules{
+		Managed: append([]string{"id", "case_id", "created_at"}, assessmentTrlPatchRules.Managed...),
+	}
+	submissionFileRules = patch.Rules{
+		Managed: []string{"id", "bucket", "uploaded_by", "uploaded_at", "belongs_to_case_id"},
+	}
+)
+
+// submissionBody - the body of POST /submissions; only case is required
+type submissionBody struct {
+	Case       json.RawMessage   `json:"case"`
+	Supporter  json.RawMessage   `json:"supporter"`
+	IPs        []json.RawMessage `json:"ips"`
+	Assessment json.RawMessage   `json:"assessment"`
+	Files      []json.RawMessage `json:"files"`
+}
+
+// submissionResponse - the submission as stored, with the new case ID at the top for clients
+// (and the audit log) that only need that
+type submissionResponse struct {
+	CaseID string `json:"case_id"`
+	models.Submission
+}
+
+type SubmissionHandler struct {
+	Repo      repository.SubmissionRepository
+	Terms     repository.TaxonomyRepository
+	Templates repository.QuestionnaireRepository
+	Trl       *CaseTrl
+	Search    *SearchIndex
+	Audit     *audit.Logger // the route logs the case, the records created with it are logged here
+}
+
+// decodeSubmission reads a submission body into its records, every problem of every record
+// listed with its path (case.case_title, ips[0].ip_types). Records left out stay nil. A body
+// that isn't a JSON object is a plain error.
+func decodeSubmission(body []byte) (*models.Submission, []patch.Problem, error) {
+	var req submissionBody
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		return nil, nil, fmt.Errorf("invalid submission: %w", err)
+	}
+
+	s := &models.Submission{}
+	var problems []patch.Problem
+	var caseDTO CasePatch
+	if isNull(req.Case) {
+		problems = append(problems, patch.Problem{Field: "case", Code: "required", Message: "is required"})
+	} else if decodeRecord(&problems, "case", req.Case, &caseDTO, submissionCaseRules, &s.Case) && caseDTO.CaseTitle == nil {
+		problems = append(problems, patch.Problem{Field: "case.case_title", Code: "required", Message: "is required"})
+	}
+	if !isNull(req.Supporter) {
+		s.Supporter = &models.Supporter{}
+		decodeRecord(&problems, "supporter", req.Supporter, &SupporterSubmission{}, submissionSupporterRules, s.Supporter)
+	}
+	if !isNull(req.Assessment) {
+		s.Assessment = &models.AssessmentTrl{}
+		decodeRecord(&problems, "assessment", req.Assessment, &AssessmentTrlPatch{}, submissionAssessmentRules, s.Assessment)
+	}
+
+	if len(req.IPs) > maxSubmissionList {
+		problems = append(problems, patch.Problem{Field: "ips", Code: "length", Message: fmt.Sprintf("at most %d items", maxSubmissionList)})
+	}
+	s.IPs = make([]models.IntellectualProperty, len(req.IPs))
+	for i, raw := range req.IPs {
+		var dto IPPatch
+		prefix := fmt.Sprintf("ips[%d]", i)
+		if decodeRecord(&problems, prefix, raw, &dto, submissionIPRules, &s.IPs[i]) && dto.IPTypes == nil {
+			problems = append(problems, patch.Problem{Field: prefix + ".ip_types", Code: "required", Message: "is required"})
+		}
+	}
+
+	if len(req.Files) > maxSubmissionList {
+		problems = append(problems, patch.Problem{Field: "files", Code: "length", Message: fmt.Sprintf("at most %d items", maxSubmissionList)})
+	}
+	s.Files = make([]models.FileMetadata, len(req.Files))
+	for i, raw := range req.Files {
+		var dto FileSubmission
+		prefix := fmt.Sprintf("files[%d]", i)
+		if !decodeRecord(&problems, prefix, raw, &dto, submissionFileRules, &s.Files[i]) {
+			continue
+		}
+		if dto.FileName == nil {
+			problems = append(problems, patch.Problem{Field: prefix + ".file_name", Code: "required", Message: "is required"})
+		}
+		if dto.ObjectPath == nil {
+			problems = append(problems, patch.Problem{Field: prefix + ".object_path", Code: "required", Message: "is required"})
+		}
+	}
+	return s, problems, nil
+}
+
+// decodeRecord reads one record through its DTO into rec (the model), adding the problems under
+// prefix; false when there were any
+func decodeRecord(problems *[]patch.Problem, prefix string, raw json.RawMessage, dto any, rules patch.Rules, rec any) bool {
+	data, err := patch.Decode(raw, dto, nil, rules)
+	var perr *patch.Error
+	if errors.As(err, &perr) {
+		for _, p := range perr.Problems {
+			p.Field = prefix + "." + p.Field
+			*problems = append(*problems, p)
+		}
+		return false
+	}
+	if err != nil {
+		*problems = append(*problems, patch.Problem{Field: prefix, Code: "type", Message: "expected an object"})
+		return false
+	}
+	// only the fields of the DTO reach the model
+	b, err := json.Marshal(data)
+	if err == nil {
+		err = json.Unmarshal(b, rec)
+	}
+	if err != nil {
+		*problems = append(*problems, patch.Problem{Field: prefix, Code: "type", Message: err.Error()})
+		return false
+	}
+	return true
+}
+
+func isNull(raw json.RawMessage) bool {
+	t := bytes.TrimSpace(raw)
+	return len(t) == 0 || bytes.Equal(t, []byte("null"))
+}
+
+// prepare fills in what the server decides for a new submission of userID, like the CreateX
+// handlers of each record do. Writes the error response; false then.
+func (h *SubmissionHandler) prepare(c *gin.Context, s *models.Submission, userID string) bool {
+	s.Case.Deletion = models.Deletion{}
+	initCaseStatus(&s.Case)
+	s.Case.TrlScore, s.Case.TrlSuggestion, s.Case.TrlRecommendation = "", "", nil
+	err := taxonomy.NewResolver(h.Terms).ResolveCase(&s.Case)
+	var terr *taxonomy.Error
+	if errors.As(err, &terr) {
+		terr.Field = "case." + terr.Field
+	}
+	if !taxonomyOK(c, err) {
+		return false
+	}
+
+	if a := s.Assessment; a != nil {
+		a.Status = models.AssessmentPending
+		t, ok := questionnaireFor(c, h.Templates, a.QuestionnaireVersion)
+		if !ok {
+			return false
+		}
+		if _, ok := scoreAssessment(c, t, nil, a); !ok {
+			return false
+		}
+	}
+
+	now := time.Now()
+	for i := range s.Files {
+		s.Files[i].ID = uuid.NewString()
+		s.Files[i].Bucket = "trl-pdf-storage"
+		s.Files[i].UploadedBy = userID
+		s.Files[i].UploadedAt = now
+	}
+	return true
+}
+
+// create stores a prepared submission and answers it; key.Key "" is no idempotency key. false
+// when it answered an error.
+func (h *SubmissionHandler) create(c *gin.Context, where string, key models.SubmissionKey, s *models.Submission) bool {
+	replayed, err := h.Repo.CreateSubmission(key, s)
+	if errors.Is(err, repository.ErrConflict) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": IdempotencyKeyHeader + " was used for a different submission"})
+		return false
+	}
+	if err != nil {
+		log.Printf("❌ [%s] %v", where, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+
+	if replayed {
+		c.Header(IdempotentReplayedHeader, "true")
+	} else {
+		h.Search.putCase(s.Case)
+		for _, ip := range s.IPs {
+			h.Search.putIP(ip)
+		}
+		h.record(c, s)
+		if s.Assessment != nil {
+			h.Trl.refreshAfter(c, where, s.Case.CaseID)
+		}
+	}
+	c.JSON(http.StatusOK, submissionResponse{CaseID: s.Case.CaseID, Submission: *s})
+	return true
+}
+
+// record logs the records created with the case, each under its own entity
+func (h *SubmissionHandler) record(c *gin.Context, s *models.Submission) {
+	if s.Supporter != nil {
+		h.Audit.Record(c, "supporter", s.Supporter.SupporterID, "create", nil, s.Supporter)
+	}
+	for i := range s.IPs {
+		h.Audit.Record(c, "ip", s.IPs[i].ID, "create", nil, &s.IPs[i])
+	}
+	if s.Assessment != nil {
+		h.Audit.Record(c, "assessment_trl", s.Assessment.ID, "create", nil, s.Assessment)
+	}
+	for i := range s.Files {
+		h.Audit.Record(c, "file", s.Files[i].ID, "create", nil, &s.Files[i])
+	}
+}
+
+// 🟢 POST /submissions - body {case, supporter, ips, assessment, files}, every record but the case
+// optional. All records are created or none; 422 lists the problems of every record at once.
+// With an Idempotency-Key header a retry (same key and body, within 24h) answers the same
+// submission again instead of creating a second case.
+func (h *SubmissionHandler) CreateSubmission(c *gin.Context) {
+	userID := c.GetString("userID")
+	key := models.SubmissionKey{UserID: userID, Key: c.GetHeader(IdempotencyKeyHeader)}
+	if len(key.Key) > 255 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": IdempotencyKeyHeader + " must be at most 255 characters"})
+		return
+	}
+
+	body, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	s, problems, err := decodeSubmission(body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	// researchers submit their own cases (checked by the route), admins name the researcher
+	if c.GetString("role") == auth.RoleResearcher {
+		s.Case.ResearcherID = userID
+	} else if s.Case.ResearcherID == "" {
+		problems = append(problems, patch.Problem{Field: "case.researcher_id", Code: "required", Message: "is required"})
+	}
+	if len(problems) > 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "invalid submission", "fields": problems})
+		return
+	}
+	if !h.prepare(c, s, userID) {
+		return
+	}
+
+	if key.Key != "" {
+		key = keyFor(key.UserID, key.Key, body)
+	}
+	h.create(c, "CreateSubmission", key, s)
+}
+
+// keyFor - the idempotency key of a submission body, valid for idempotencyTTL
+func keyFor(userID, key string, body []byte) models.SubmissionKey {
+	var compact bytes.Buffer
+	json.Compact(&compact, body)
+	sum := sha256.Sum256(compact.Bytes())
+	now := time.Now()
+	return models.SubmissionKey{
+		UserID:      userID,
+		Key:         key,
+		RequestHash: hex.EncodeToString(sum[:]),
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(idempotencyTTL),
+	}
+}
@@ -9,17 +9,13 @@ import (
 )
 
 type SupporterHandler struct {
-	Repo *repository.SupporterRepo
+	Repo repository.SupporterRepository
+	Trl  *CaseTrl // the needs feed the case's TRL suggestions
 }
 
 // 🟢 GET /supporters
 func (h *SupporterHandler) GetSupporterAll(c *gin.Context) {
-	supporters, err := h.Repo.GetSupporterAll()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-	c.JSON(http.StatusOK, supporters)
+	listItems(c, supporterList, h.Repo.ListSupporters)
 }
 
 // 🟢 GET /supporter/:id
@@ -51,11 +47,13 @@ func (h *SupporterHandler) CreateSupporter(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	req.Deletion = models.Deletion{} // only DELETE puts a record in the trash
 
 	if err := h.Repo.CreateSupporter(&req); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	h.Trl.refreshAfter(c, "CreateSupporter", req.CaseID)
 
 	c.JSON(http.StatusOK, req)
 }
@@ -70,10 +68,44 @@ func (h *SupporterHandler) UpdateSupporterByID(c *gin.Context) {
 		return
 	}
 
-	if err := h.Repo.UpdateSupporterByID(id, updateData); err != nil {
+	if err := h.Repo.UpdateSupporterByID(id, withoutDeletion(updateData)); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	if supporter, err := h.Repo.GetSupporterByID(id); err == nil {
+		h.Trl.refreshAfter(c, "UpdateSupporterByID", supporter.CaseID)
+	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Supporter updated successfully"})
 }
+
+// 🟢 DELETE /supporter/:id - soft delete; the case's TRL suggestions lose the supporter needs
+func (h *SupporterHandler) DeleteSupporter(c *gin.Context) {
+	id := c.Param("id")
+	supporter, err := h.Repo.GetSupporterByID(id)
+	if !trashed(c, "DeleteSupporter", "Supporter", err) {
+		return
+	}
+	if !trashed(c, "DeleteSupporter", "Supporter", h.Repo.DeleteSupporter(id, deletedBy(c))) {
+		return
+	}
+	h.Trl.refreshAfter(c, "DeleteSupporter", supporter.CaseID)
+	c.JSON(http.StatusOK, gin.H{"message": "Supporter deleted successfully"})
+}
+
+// 🟢 POST /supporter/:id/restore - 409 while its case is deleted
+func (h *SupporterHandler) RestoreSupporter(c *gin.Context) {
+	id := c.Param("id")
+	supporter, err := findDeleted(supporterList, id, h.Repo.ListSupporters)
+	if !trashed(c, "RestoreSupporter", "Deleted supporter", err) {
+		return
+	}
+	if !restorableIn(c, h.Trl.Cases, supporter.CaseID, "supporter") {
+		return
+	}
+	if !trashed(c, "RestoreSupporter", "Deleted supporter", h.Repo.RestoreSupporter(id)) {
+		return
+	}
+	h.Trl.refreshAfter(c, "RestoreSupporter", supporter.CaseID)
+	c.JSON(http.StatusOK, gin.H{"message": "Supporter restored successfully"})
+}
@@ -0,0 +1,367 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+
+	"trl-research-backend/internal/audit"
+	"trl-research-backend/internal/listing"
+	"trl-research-backend/internal/models"
+	"trl-research-backend/internal/patch"
+	"trl-research-backend/internal/repository"
+	"trl-research-backend/internal/taxonomy"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TaxonomyHandler struct {
+	Repo   repository.TaxonomyRepository
+	Cases  repository.CaseRepository
+	Search *SearchIndex  // case_keywords change with renames and merges
+	Audit  *audit.Logger // so do the cases, each gets an entry of its own
+}
+
+// termRequest - body of POST /taxonomy and PATCH /taxonomy/:id; PATCH leaves out what stays
+type termRequest struct {
+	Kind     string                `json:"kind"`
+	Label    *models.LocalizedText `json:"label"`
+	Synonyms *[]string             `json:"synonyms"`
+}
+
+// 🟢 GET /taxonomy?kind=domain&include_merged=true - terms to pick from, merged ones only on request
+func (h *TaxonomyHandler) GetTerms(c *gin.Context) {
+	kind := c.Query("kind")
+	if kind != "" && !slices.Contains(models.TermKinds, kind) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unknown kind " + kind})
+		return
+	}
+	terms, err := h.Repo.GetTaxonomyTerms(kind)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if c.Query("include_merged") != "true" {
+		active := []models.TaxonomyTerm{}
+		for _, t := range terms {
+			if t.MergedInto == "" {
+				active = append(active, t)
+			}
+		}
+		terms = active
+	}
+	c.JSON(http.StatusOK, terms)
+}
+
+// 🟢 GET /taxonomy/:id
+func (h *TaxonomyHandler) GetTerm(c *gin.Context) {
+	t, err := h.Repo.GetTaxonomyTermByID(c.Param("id"))
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Term not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
+
+// 🟢 POST /taxonomy - new term (admin)
+func (h *TaxonomyHandler) CreateTerm(c *gin.Context) {
+	var req termRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !slices.Contains(models.TermKinds, req.Kind) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "kind must be domain, case_type or keyword"})
+		return
+	}
+	t := models.TaxonomyTerm{Kind: req.Kind, Synonyms: []string{}}
+	if req.Label != nil {
+		t.Label = *req.Label
+	}
+	if req.Synonyms != nil {
+		t.Synonyms = *req.Synonyms
+	}
+	if !h.checkNames(c, &t) {
+		return
+	}
+
+	if err := h.Repo.CreateTaxonomyTerm(&t); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, t)
+}
+
+// 🟢 PATCH /taxonomy/:id - rename a term or change its synonyms (admin); cases using it get the new label
+func (h *TaxonomyHandler) UpdateTerm(c *gin.Context) {
+	var req termRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	t, ok := h.term(c, c.Param("id"))
+	if !ok {
+		return
+	}
+	if t.MergedInto != "" {
+		c.JSON(http.StatusConflict, gin.H{"error": "term was merged into " + t.MergedInto})
+		return
+	}
+	renamed := req.Label != nil && *req.Label != t.Label
+	if req.Label != nil {
+		t.Label = *req.Label
+	}
+	if req.Synonyms != nil {
+		t.Synonyms = *req.Synonyms
+	}
+	if !h.checkNames(c, t) {
+		return
+	}
+
+	if !renamed {
+		if err := h.Repo.UpdateTaxonomyTerm(t); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"term": t, "cases_updated": 0})
+		return
+	}
+	changes := []models.TaxonomyTerm{*t}
+	updated, err := h.changeTerms(c, changes, t, t)
+	if err != nil {
+		log.Printf("❌ [UpdateTerm] relabel cases of %s: %v", t.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"term": changes[0], "cases_updated": updated})
+}
+
+// 🟢 POST /taxonomy/:id/merge {"into": "TX-00002"} - fold a duplicate into another term (admin).
+// Its cases move to the other term in the same transaction as the terms.
+func (h *TaxonomyHandler) MergeTerm(c *gin.Context) {
+	var req struct {
+		Into string `json:"into" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	from, ok := h.term(c, c.Param("id"))
+	if !ok {
+		return
+	}
+	into, ok := h.term(c, req.Into)
+	if !ok {
+		return
+	}
+	var terr *taxonomy.Error
+	if err := taxonomy.Merge(from, into); errors.As(err, &terr) {
+		c.JSON(http.StatusConflict, gin.H{"error": terr.Error()})
+		return
+	}
+
+	// terms merged into from earlier point at into directly
+	terms, err := h.Repo.GetTaxonomyTerms(from.Kind)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	changes := []models.TaxonomyTerm{*into, *from}
+	for _, t := range terms {
+		if t.MergedInto == from.ID {
+			t.MergedInto = into.ID
+			changes = append(changes, t)
+		}
+	}
+
+	updated, err := h.changeTerms(c, changes, from, into)
+	if err != nil {
+		log.Printf("❌ [MergeTerm] %s into %s: %v", from.ID, into.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"merged": changes[1], "into": changes[0], "cases_updated": updated})
+}
+
+// 🟢 GET /taxonomy/report?kind=domain - number of cases per term (staff)
+func (h *TaxonomyHandler) GetReport(c *gin.Context) {
+	kind := c.DefaultQuery("kind", models.TermDomain)
+	if !slices.Contains(models.TermKinds, kind) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unknown kind " + kind})
+		return
+	}
+	terms, err := h.Repo.GetTaxonomyTerms(kind)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	cases, err := h.Cases.GetCaseAll()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// references to merged terms count for the term they were merged into
+	canonical := map[string]string{}
+	for _, t := range terms {
+		canonical[t.ID] = t.ID
+		if t.MergedInto != "" {
+			canonical[t.ID] = t.MergedInto
+		}
+	}
+	counts := map[string]int{}
+	unclassified := 0
+	for _, cs := range cases {
+		seen := map[string]bool{}
+		for _, id := range caseTermIDs(cs, kind) {
+			if id = canonical[id]; id != "" && !seen[id] {
+				seen[id] = true
+				counts[id]++
+			}
+		}
+		if len(seen) == 0 {
+			unclassified++
+		}
+	}
+
+	type row struct {
+		Term  models.TaxonomyTerm `json:"term"`
+		Cases int                 `json:"cases"`
+	}
+	rows := []row{}
+	for _, t := range terms {
+		if t.MergedInto == "" {
+			rows = append(rows, row{t, counts[t.ID]})
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"kind": kind, "terms": rows, "unclassified": unclassified, "total_cases": len(cases)})
+}
+
+// term loads a term or answers 404 / 500
+func (h *TaxonomyHandler) term(c *gin.Context, id string) (*models.TaxonomyTerm, bool) {
+	t, err := h.Repo.GetTaxonomyTermByID(id)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Term not found: " + id})
+		return nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return t, true
+}
+
+// checkNames answers 422 for a term without label and 409 when another term of the kind is
+// already found by one of its names
+func (h *TaxonomyHandler) checkNames(c *gin.Context, t *models.TaxonomyTerm) bool {
+	if t.Label.TH == "" && t.Label.EN == "" {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "label.th or label.en is required"})
+		return false
+	}
+	resolver := taxonomy.NewResolver(h.Repo)
+	for _, name := range t.Names() {
+		other, err := resolver.ByName(t.Kind, name, false)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return false
+		}
+		if other != nil && other.ID != t.ID {
+			c.JSON(http.StatusConflict, gin.H{"error": "\"" + name + "\" is already " + other.ID + ", merge instead", "term": other})
+			return false
+		}
+	}
+	return true
+}
+
+// termChangeAttempts - how often changeTerms starts over when cases were edited in between
+const termChangeAttempts = 3
+
+// changeTerms writes terms together with every case using from, moved to into (deleted cases
+// too, so a restored one doesn't come back with the old term), and returns how many changed
+func (h *TaxonomyHandler) changeTerms(c *gin.Context, terms []models.TaxonomyTerm, from, into *models.TaxonomyTerm) (int, error) {
+	all := url.Values{"deleted": {string(listing.IncludeDeleted)}}
+	for attempt := 1; ; attempt++ {
+		cases, err := changed(all, caseList, h.Cases.ListCases)
+		if err != nil {
+			return 0, err
+		}
+		// the cases get the labels the terms will have, not the stored ones
+		resolver := taxonomy.NewResolver(h.Repo)
+		resolver.Pending(terms...)
+		before := map[string]models.CaseInfo{}
+		patches := []repository.CasePatch{}
+		for _, cs := range cases {
+			data, err := resolver.Repoint(cs, from, into)
+			if err != nil {
+				return 0, err
+			}
+			if data != nil {
+				before[cs.CaseID] = cs
+				patches = append(patches, repository.CasePatch{CaseID: cs.CaseID, Revision: cs.Revision, Data: data})
+			}
+		}
+
+		err = h.Repo.ApplyTermChange(terms, patches)
+		if errors.Is(err, repository.ErrStale) && attempt < termChangeAttempts {
+			log.Printf("⚠️ [changeTerms] %v, starting over", err)
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		if len(patches) > 0 {
+			if cases, err = changed(all, caseList, h.Cases.ListCases); err != nil {
+				log.Printf("❌ [changeTerms] reload cases for the audit log: %v", err)
+				cases = nil
+			}
+			for i := range cases {
+				if cs, ok := before[cases[i].CaseID]; ok {
+					h.Audit.Record(c, "case", cs.CaseID, "repoint_taxonomy", &cs, &cases[i])
+				}
+			}
+			for id := range before {
+				h.Search.syncCase(id)
+			}
+		}
+		return len(patches), nil
+	}
+}
+
+// taxonomyOK answers 422 for a reference the taxonomy can't resolve and 500 for other errors
+func taxonomyOK(c *gin.Context, err error) bool {
+	var terr *taxonomy.Error
+	switch {
+	case errors.As(err, &terr):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":  terr.Error(),
+			"fields": []patch.Problem{{Field: terr.Field, Code: "reference", Message: terr.Msg}},
+		})
+		return false
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+	return true
+}
+
+// caseTermIDs - the references of a case to terms of kind
+func caseTermIDs(cs models.CaseInfo, kind string) []string {
+	switch kind {
+	case models.TermDomain:
+		return cs.DomainIDs
+	case models.TermCaseType:
+		if cs.CaseTypeID != "" {
+			return []string{cs.CaseTypeID}
+		}
+	case models.TermKeyword:
+		return cs.KeywordIDs
+	}
+	return nil
+}
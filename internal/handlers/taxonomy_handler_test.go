@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"testing"
+
+	"trl-research-backend/internal/audit"
+	"trl-research-backend/internal/listing"
+	"trl-research-backend/internal/models"
+	"trl-research-backend/internal/repository/memory"
+	"trl-research-backend/internal/search"
+)
+
+// a merge moves the deleted cases along with the live ones, so a restored case doesn't come back
+// with the merged term
+func TestMergeTermMovesDeletedCases(t *testing.T) {
+	repos := memory.NewRepositories()
+	index, err := search.Open("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := &TaxonomyHandler{
+		Repo:   repos.Taxonomy,
+		Cases:  repos.Case,
+		Search: &SearchIndex{Index: index, Cases: repos.Case, Researchers: repos.Researcher, IPs: repos.IntellectualProperty},
+		Audit:  &audit.Logger{Repo: repos.Audit},
+	}
+	r := adminEngine()
+	r.POST("/taxonomy/:id/merge", h.MergeTerm)
+
+	var terms []*models.TaxonomyTerm
+	for _, label := range []string{"ยาง", "ยางพารา"} {
+		term := &models.TaxonomyTerm{Kind: models.TermKeyword, Label: models.LocalizedText{TH: label}, Synonyms: []string{}}
+		if err := repos.Taxonomy.CreateTaxonomyTerm(term); err != nil {
+			t.Fatal(err)
+		}
+		terms = append(terms, term)
+	}
+	var ids []string
+	for range 2 {
+		cs := &models.CaseInfo{CaseTitle: "case", KeywordIDs: []string{terms[0].ID}, CaseKeywords: "ยาง"}
+		if err := repos.Case.CreateCase(cs); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, cs.CaseID)
+	}
+	if err := repos.Case.DeleteCase(ids[1], "AD-00001"); err != nil {
+		t.Fatal(err)
+	}
+
+	var out struct {
+		CasesUpdated int `json:"cases_updated"`
+	}
+	if code := call(t, r, "POST", "/taxonomy/"+terms[0].ID+"/merge", map[string]string{"into": terms[1].ID}, &out); code != http.StatusOK || out.CasesUpdated != 2 {
+		t.Fatalf("merge: %d, %d cases", code, out.CasesUpdated)
+	}
+	cases, err := changed(url.Values{"deleted": {string(listing.IncludeDeleted)}}, caseList, repos.Case.ListCases)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, cs := range cases {
+		if !slices.Equal(cs.KeywordIDs, []string{terms[1].ID}) || cs.CaseKeywords != "ยางพารา" || cs.Revision != 2 {
+			t.Errorf("case %s: %q %q revision %d", cs.CaseID, cs.KeywordIDs, cs.CaseKeywords, cs.Revision)
+		}
+	}
+	entries, err := changed(url.Values{"action": {"repoint_taxonomy"}}, auditList, repos.Audit.ListAuditEntries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var logged []string
+	for _, e := range entries {
+		logged = append(logged, e.EntityID)
+	}
+	slices.Sort(logged)
+	if !slices.Equal(logged, ids) {
+		t.Errorf("audited %q, want %q", logged, ids)
+	}
+}
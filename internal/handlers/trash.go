@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+
+	"trl-research-backend/internal/listing"
+	"trl-research-backend/internal/repository"
+	"trl-research-backend/internal/trash"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Soft delete: DELETE /x/:id moves a record to the trash, where admins see it with
+// ?deleted=only on GET /xs and bring it back with POST /x/:id/restore. The purge job
+// (internal/trash) removes it for good after the retention period.
+
+// deletionFields - written by DELETE and restore only
+var deletionFields = []string{"deleted_at", "deleted_by"}
+
+// deletedBy - who a delete is recorded for
+func deletedBy(c *gin.Context) string {
+	return c.GetString("userID")
+}
+
+// trashed answers the error of a DeleteX / RestoreX, 404 when there was no live / deleted record;
+// false when it wrote one
+func trashed(c *gin.Context, where, entity string, err error) bool {
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": entity + " not found"})
+		return false
+	}
+	if err != nil {
+		log.Printf("❌ [%s] %v", where, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+	return true
+}
+
+// findDeleted - the deleted record with the key id, read through the trash listing of spec
+func findDeleted[T any](spec *listing.Spec, id string, list func(listing.Query) (*listing.Page[T], error)) (*T, error) {
+	q, err := listing.Parse(url.Values{"deleted": {string(listing.OnlyDeleted)}}, spec)
+	if err != nil {
+		return nil, err
+	}
+	page, err := list(q.Where(spec.Key, id))
+	if err != nil {
+		return nil, err
+	}
+	if len(page.Items) == 0 {
+		return nil, repository.ErrNotFound
+	}
+	return &page.Items[0], nil
+}
+
+// restorableIn checks that the case of a record being restored is live: the record would be
+// unreachable otherwise. 409 when the case is deleted (restore the case, it brings back what
+// was deleted with it); false when it wrote an answer.
+func restorableIn(c *gin.Context, cases repository.CaseRepository, caseID, entity string) bool {
+	if caseID == "" {
+		return true
+	}
+	_, err := cases.GetCaseByID(caseID)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "the case of this " + entity + " is deleted, restore case " + caseID + " first",
+			"case_id": caseID,
+		})
+		return false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+	return true
+}
+
+// withoutDeletion drops the trash fields from the body of a map update; only DELETE and restore
+// write them
+func withoutDeletion(data map[string]interface{}) map[string]interface{} {
+	for _, field := range deletionFields {
+		delete(data, field)
+	}
+	return data
+}
+
+type TrashHandler struct {
+	Purger *trash.Purger
+}
+
+// 🟢 POST /trash/purge - run the purge now instead of waiting for the background one
+func (h *TrashHandler) PurgeTrash(c *gin.Context) {
+	counts, err := h.Purger.Purge(c)
+	if err != nil {
+		log.Printf("❌ [PurgeTrash] %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "purged": counts})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Trash purged successfully", "purged": counts})
+}
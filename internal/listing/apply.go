@@ -0,0 +1,173 @@
+package listing
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Apply filters, sorts and pages items in memory
+func Apply[T any](items []T, q Query) *Page[T] {
+	var matched []T
+	for _, item := range items {
+		if q.Matches(item) {
+			matched = append(matched, item)
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		return q.compare(matched[i], matched[j]) < 0
+	})
+
+	page := &Page[T]{Items: []T{}, TotalEstimate: len(matched)}
+	start := 0
+	if q.After != nil {
+		start = sort.Search(len(matched), func(i int) bool {
+			return q.compareCursor(matched[i]) > 0
+		})
+	}
+	rest := matched[start:]
+	if q.Limit > 0 && len(rest) > q.Limit {
+		rest = rest[:q.Limit]
+		page.NextPageToken = q.NextToken(rest[len(rest)-1])
+	}
+	page.Items = append(page.Items, rest...)
+	return page
+}
+
+// Matches - item passes every filter of the query
+func (q Query) Matches(item any) bool {
+	if !q.MatchesDeleted(item) {
+		return false
+	}
+	for _, f := range q.Filters {
+		c := compareValues(FieldValue(item, f.Field), f.Value)
+		switch {
+		case f.Op == Eq && c != 0, f.Op == From && c < 0, f.Op == To && c >= 0:
+			return false
+		}
+	}
+	return true
+}
+
+// MatchesDeleted - item is live or deleted as the query's Deleted asks; always true for specs
+// without SoftDelete
+func (q Query) MatchesDeleted(item any) bool {
+	if q.Spec == nil || !q.Spec.SoftDelete {
+		return true
+	}
+	at, _ := FieldValue(item, "deleted_at").(time.Time)
+	switch q.Deleted {
+	case IncludeDeleted:
+		return true
+	case OnlyDeleted:
+		return !at.IsZero()
+	}
+	return at.IsZero()
+}
+
+// compare orders two items by the sort field, then the key
+func (q Query) compare(a, b any) int {
+	c := compareValues(FieldValue(a, q.Sort), FieldValue(b, q.Sort))
+	if c == 0 {
+		c = compareValues(FieldValue(a, q.Spec.Key), FieldValue(b, q.Spec.Key))
+	}
+	if q.Desc {
+		return -c
+	}
+	return c
+}
+
+// compareCursor orders an item against the end of the previous page
+func (q Query) compareCursor(item any) int {
+	c := compareValues(FieldValue(item, q.Sort), q.After.Value)
+	if c == 0 {
+		c = compareValues(FieldValue(item, q.Spec.Key), q.After.Key)
+	}
+	if q.Desc {
+		return -c
+	}
+	return c
+}
+
+func compareValues(a, b any) int {
+	switch x := a.(type) {
+	case string:
+		y, _ := b.(string)
+		return strings.Compare(x, y)
+	case int:
+		y, _ := b.(int)
+		return x - y
+	case bool:
+		y, _ := b.(bool)
+		switch {
+		case x == y:
+			return 0
+		case y:
+			return -1
+		}
+		return 1
+	case time.Time:
+		y, _ := b.(time.Time)
+		return x.Compare(y)
+	}
+	return 0
+}
+
+// fieldIndex caches the struct field behind each JSON name, per type
+var fieldIndex sync.Map // reflect.Type → map[string][]int
+
+// FieldValue - the field of a struct (or pointer to one) with the given JSON name, as a string,
+// bool, int or time.Time; nil when there is none
+func FieldValue(item any, name string) any {
+	v := reflect.Indirect(reflect.ValueOf(item))
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	index, ok := fieldsOf(v.Type())[name]
+	if !ok {
+		return nil
+	}
+	f := v.FieldByIndex(index)
+	switch f.Kind() {
+	case reflect.String:
+		return f.String()
+	case reflect.Bool:
+		return f.Bool()
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return int(f.Int())
+	}
+	if t, ok := f.Interface().(time.Time); ok {
+		return t
+	}
+	return nil
+}
+
+func fieldsOf(t reflect.Type) map[string][]int {
+	if m, ok := fieldIndex.Load(t); ok {
+		return m.(map[string][]int)
+	}
+	m := map[string][]int{}
+	for _, f := range reflect.VisibleFields(t) {
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name != "" && name != "-" && f.IsExported() {
+			m[name] = f.Index
+		}
+	}
+	fieldIndex.Store(t, m)
+	return m
+}
+
+func sortedFields(spec *Spec) []string {
+	return sortedKeys(spec.Fields)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
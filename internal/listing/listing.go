@@ -0,0 +1,293 @@
+// Package listing reads the list parameters of the GET collection endpoints (limit, page_token,
+// sort and field filters) and pages a slice in memory for the backends that can't do it in
+// their query.
+//
+// Pagination is keyset based: the page token is an opaque cursor holding the sort value and
+// the key of the last item, so pages stay stable while records are added.
+package listing
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Kind - the type of a listed field, decides how query values are parsed and compared
+type Kind int
+
+const (
+	String Kind = iota
+	Bool
+	Int
+	Time
+)
+
+// Field - one field of a listed model, by its JSON name
+type Field struct {
+	Kind   Kind
+	Filter bool // ?field=value; Int and Time also take ?field_from= (inclusive) and ?field_to= (exclusive)
+	Sort   bool // ?sort=field or ?sort=-field
+}
+
+// Spec - what an endpoint can filter and sort on. Key is the unique field every sort falls back
+// on and the default sort, unless DefaultSort names another one ("-created_at" for newest first).
+// SoftDelete: the records have a deleted_at (models.Deletion), deleted ones are left out unless
+// ?deleted= asks for them.
+type Spec struct {
+	Key         string
+	Fields      map[string]Field
+	DefaultSort string
+	SoftDelete  bool
+}
+
+const (
+	DefaultLimit = 50
+	MaxLimit     = 200
+)
+
+// Op - how a filter compares
+type Op string
+
+const (
+	Eq   Op = "eq"
+	From Op = "from" // >=
+	To   Op = "to"   // <
+)
+
+// Filter - field Op Value; Value is a string, bool, int or time.Time by the field's Kind
+type Filter struct {
+	Field string
+	Op    Op
+	Value any
+}
+
+// Deleted - which records of a SoftDelete spec a query lists
+type Deleted string
+
+const (
+	HideDeleted    Deleted = ""        // live records only, the default
+	IncludeDeleted Deleted = "include" // live and deleted
+	OnlyDeleted    Deleted = "only"    // the trash
+)
+
+// Cursor - where the previous page ended
+type Cursor struct {
+	Value any // sort field of the last item
+	Key   string
+}
+
+// Query - a parsed list request
+type Query struct {
+	Spec    *Spec
+	Filters []Filter
+	Sort    string // a field of Spec, Spec.Key when the request has none
+	Desc    bool
+	Limit   int     // 0 = everything, for requests without list parameters
+	After   *Cursor // nil on the first page
+	Paged   bool    // the request had a list parameter, answer with a Page
+	Deleted Deleted // SoftDelete specs only
+
+	signature string // sort and filters the page token belongs to
+}
+
+// Page - the common response of a list endpoint
+type Page[T any] struct {
+	Items         []T    `json:"items"`
+	NextPageToken string `json:"next_page_token"` // "" on the last page
+	TotalEstimate int    `json:"total_estimate"`  // items matching the filters, over all pages
+}
+
+// Error - a bad list parameter
+type Error struct {
+	Param string
+	Msg   string
+}
+
+func (e *Error) Error() string {
+	return e.Param + ": " + e.Msg
+}
+
+// Parse reads the list parameters of a request for spec. Parameters it doesn't know are ignored.
+func Parse(values url.Values, spec *Spec) (Query, error) {
+	q := Query{Spec: spec, Sort: spec.Key}
+
+	s := values.Get("sort")
+	if s != "" {
+		q.Paged = true
+	} else {
+		s = spec.DefaultSort
+	}
+	if s != "" {
+		q.Desc = strings.HasPrefix(s, "-")
+		q.Sort = strings.TrimPrefix(s, "-")
+		if f, ok := spec.Fields[q.Sort]; q.Sort != spec.Key && (!ok || !f.Sort) {
+			return q, &Error{"sort", fmt.Sprintf("can't sort by %q", q.Sort)}
+		}
+	}
+
+	var sig []string
+	for _, name := range sortedFields(spec) {
+		f := spec.Fields[name]
+		if !f.Filter {
+			continue
+		}
+		params := map[string]Op{name: Eq}
+		if f.Kind == Int || f.Kind == Time {
+			params = map[string]Op{name + "_from": From, name + "_to": To}
+			if f.Kind == Int {
+				params[name] = Eq
+			}
+		}
+		for _, param := range sortedKeys(params) {
+			raw, ok := values[param]
+			if !ok {
+				continue
+			}
+			v, err := parseValue(f.Kind, raw[0])
+			if err != nil {
+				return q, &Error{param, err.Error()}
+			}
+			q.Paged = true
+			q.Filters = append(q.Filters, Filter{Field: name, Op: params[param], Value: v})
+			sig = append(sig, param+"="+raw[0])
+		}
+	}
+	if d := values.Get("deleted"); d != "" {
+		if !spec.SoftDelete {
+			return q, &Error{"deleted", "these records can't be deleted"}
+		}
+		q.Deleted = Deleted(d)
+		if q.Deleted != IncludeDeleted && q.Deleted != OnlyDeleted {
+			return q, &Error{"deleted", "expected include or only"}
+		}
+		q.Paged = true
+		sig = append(sig, "deleted="+d)
+	}
+	q.signature = fmt.Sprintf("%s:%t:%s", q.Sort, q.Desc, strings.Join(sig, "&"))
+
+	if s := values.Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 || n > MaxLimit {
+			return q, &Error{"limit", fmt.Sprintf("must be 1 to %d", MaxLimit)}
+		}
+		q.Paged = true
+		q.Limit = n
+	}
+	if token := values.Get("page_token"); token != "" {
+		q.Paged = true
+		after, err := q.decodeToken(token)
+		if err != nil {
+			return q, &Error{"page_token", err.Error()}
+		}
+		q.After = after
+	}
+	if q.Paged && q.Limit == 0 {
+		q.Limit = DefaultLimit
+	}
+	return q, nil
+}
+
+// Where adds a filter the client can't choose, e.g. the researcher of "my cases". It isn't part
+// of the page token, which is fine as long as it's the same on every page.
+func (q Query) Where(field string, value any) Query {
+	q.Filters = append(append([]Filter{}, q.Filters...), Filter{Field: field, Op: Eq, Value: value})
+	return q
+}
+
+// KindOf - the kind of a field of the query's spec, the key is a String
+func (q Query) KindOf(field string) Kind {
+	return q.Spec.Fields[field].Kind
+}
+
+func parseValue(kind Kind, s string) (any, error) {
+	switch kind {
+	case Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("expected true or false")
+		}
+		return b, nil
+	case Int:
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, fmt.Errorf("expected a number")
+		}
+		return n, nil
+	case Time:
+		if t, err := time.Parse(time.RFC3339, s); err == nil {
+			return t, nil
+		}
+		t, err := time.Parse(time.DateOnly, s)
+		if err != nil {
+			return nil, fmt.Errorf("expected an RFC 3339 time or YYYY-MM-DD")
+		}
+		return t, nil
+	}
+	return s, nil
+}
+
+// token - what a page token encodes
+type token struct {
+	Signature string `json:"s"`
+	Value     any    `json:"v"`
+	Key       string `json:"k"`
+}
+
+// NextToken - the page token of the page after item, the last item of the current page
+func (q Query) NextToken(item any) string {
+	v := FieldValue(item, q.Sort)
+	if t, ok := v.(time.Time); ok {
+		v = t.Format(time.RFC3339Nano)
+	}
+	key, _ := FieldValue(item, q.Spec.Key).(string)
+	b, _ := json.Marshal(token{Signature: q.signature, Value: v, Key: key})
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func (q Query) decodeToken(s string) (*Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token")
+	}
+	var t token
+	if err := json.Unmarshal(b, &t); err != nil {
+		return nil, fmt.Errorf("invalid token")
+	}
+	if t.Signature != q.signature {
+		return nil, fmt.Errorf("token belongs to a different sort or filter")
+	}
+
+	c := &Cursor{Key: t.Key, Value: t.Value}
+	if q.Sort == q.Spec.Key {
+		return c, nil
+	}
+	switch q.KindOf(q.Sort) {
+	case Time:
+		s, _ := t.Value.(string)
+		c.Value, err = time.Parse(time.RFC3339Nano, s)
+	case Int:
+		n, ok := t.Value.(float64)
+		c.Value, err = int(n), boolErr(ok)
+	case Bool:
+		_, ok := t.Value.(bool)
+		err = boolErr(ok)
+	case String:
+		_, ok := t.Value.(string)
+		err = boolErr(ok)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid token")
+	}
+	return c, nil
+}
+
+func boolErr(ok bool) error {
+	if ok {
+		return nil
+	}
+	return fmt.Errorf("wrong type")
+}
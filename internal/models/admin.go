@@ -18,6 +18,8 @@ type AdminInfo struct {
 	CaseID                string    `json:"case_id" firestore:"case_id"`
 	CreatedAt             time.Time `json:"created_at" firestore:"created_at"`
 	UpdatedAt             time.Time `json:"updated_at" firestore:"updated_at"`
+
+	Deletion
 }
 
 func (r *AdminInfo) ToResponse() entity.AdminResponse {
@@ -12,4 +12,15 @@ type Appointment struct {
 	Summary       string    `json:"summary" firestore:"summary"`
 	CreatedAt     time.Time `json:"created_at" firestore:"created_at"`
 	UpdatedAt     time.Time `json:"updated_at" firestore:"updated_at"`
+
+	Deletion
 }
+
+// Appointment statuses
+const (
+	AppointmentPending  = "pending"
+	AppointmentAttended = "attended"
+	AppointmentAbsent   = "absent"
+)
+
+var AppointmentStatuses = []string{AppointmentPending, AppointmentAttended, AppointmentAbsent}
@@ -2,26 +2,171 @@ package models
 
 import "time"
 
+// Review states of an assessment. Answers can be changed while pending; a reviewed assessment
+// is an immutable record and a re-assessment is a new one.
+const (
+	AssessmentPending  = "pending"
+	AssessmentApproved = "approved"
+	AssessmentRejected = "rejected"
+)
+
+// AssessmentTrl - the answers of one TRL questionnaire. QuestionnaireVersion is the template
+// the answers belong to (0 in old records = version 1). ReadinessAnswers / CriteriaAnswers hold
+// the answers by question key; for the keys rq1..rq7 / cq1..cq9 the fixed fields below mirror
+// them so older clients keep working.
 type AssessmentTrl struct {
-	ID              string    `json:"id" firestore:"id"`
-	CaseID          string    `json:"case_id" firestore:"case_id"`
-	TrlLevelResult  int       `json:"trl_level_result" firestore:"trl_level_result"`
-	Rq1Answer       bool      `json:"rq1_answer" firestore:"rq1_answer"`
-	Rq2Answer       bool      `json:"rq2_answer" firestore:"rq2_answer"`
-	Rq3Answer       bool      `json:"rq3_answer" firestore:"rq3_answer"`
-	Rq4Answer       bool      `json:"rq4_answer" firestore:"rq4_answer"`
-	Rq5Answer       bool      `json:"rq5_answer" firestore:"rq5_answer"`
-	Rq6Answer       bool      `json:"rq6_answer" firestore:"rq6_answer"`
-	Rq7Answer       bool      `json:"rq7_answer" firestore:"rq7_answer"`
-	Cq1Answer       []string    `json:"cq1_answer" firestore:"cq1_answer"`
-	Cq2Answer       []string    `json:"cq2_answer" firestore:"cq2_answer"`
-	Cq3Answer       []string    `json:"cq3_answer" firestore:"cq3_answer"`
-	Cq4Answer       []string    `json:"cq4_answer" firestore:"cq4_answer"`
-	Cq5Answer       []string    `json:"cq5_answer" firestore:"cq5_answer"`
-	Cq6Answer       []string    `json:"cq6_answer" firestore:"cq6_answer"`
-	Cq7Answer       []string    `json:"cq7_answer" firestore:"cq7_answer"`
-	Cq8Answer       []string    `json:"cq8_answer" firestore:"cq8_answer"`
-	Cq9Answer       []string    `json:"cq9_answer" firestore:"cq9_answer"`
-	CreatedAt       time.Time `json:"created_at" firestore:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at" firestore:"updated_at"`
+	ID                   string              `json:"id" firestore:"id"`
+	CaseID               string              `json:"case_id" firestore:"case_id"`
+	QuestionnaireVersion int                 `json:"questionnaire_version" firestore:"questionnaire_version"`
+	TrlLevelResult       int                 `json:"trl_level_result" firestore:"trl_level_result"`
+	Status               string              `json:"status" firestore:"status"`
+	ReviewedBy           string              `json:"reviewed_by" firestore:"reviewed_by"`
+	ReviewedAt           time.Time           `json:"reviewed_at" firestore:"reviewed_at"`
+	ReviewNote           string              `json:"review_note" firestore:"review_note"`
+	ReadinessAnswers     map[string]bool     `json:"readiness_answers" firestore:"readiness_answers"`
+	CriteriaAnswers      map[string][]string `json:"criteria_answers" firestore:"criteria_answers"`
+	Rq1Answer            bool                `json:"rq1_answer" firestore:"rq1_answer"`
+	Rq2Answer            bool                `json:"rq2_answer" firestore:"rq2_answer"`
+	Rq3Answer            bool                `json:"rq3_answer" firestore:"rq3_answer"`
+	Rq4Answer            bool                `json:"rq4_answer" firestore:"rq4_answer"`
+	Rq5Answer            bool                `json:"rq5_answer" firestore:"rq5_answer"`
+	Rq6Answer            bool                `json:"rq6_answer" firestore:"rq6_answer"`
+	Rq7Answer            bool                `json:"rq7_answer" firestore:"rq7_answer"`
+	Cq1Answer            []string            `json:"cq1_answer" firestore:"cq1_answer"`
+	Cq2Answer            []string            `json:"cq2_answer" firestore:"cq2_answer"`
+	Cq3Answer            []string            `json:"cq3_answer" firestore:"cq3_answer"`
+	Cq4Answer            []string            `json:"cq4_answer" firestore:"cq4_answer"`
+	Cq5Answer            []string            `json:"cq5_answer" firestore:"cq5_answer"`
+	Cq6Answer            []string            `json:"cq6_answer" firestore:"cq6_answer"`
+	Cq7Answer            []string            `json:"cq7_answer" firestore:"cq7_answer"`
+	Cq8Answer            []string            `json:"cq8_answer" firestore:"cq8_answer"`
+	Cq9Answer            []string            `json:"cq9_answer" firestore:"cq9_answer"`
+	CreatedAt            time.Time           `json:"created_at" firestore:"created_at"`
+	UpdatedAt            time.Time           `json:"updated_at" firestore:"updated_at"`
+	// Revision - 1 on create, +1 on every write; the ETag of the assessment
+	Revision int64 `json:"revision" firestore:"revision"`
+
+	Deletion
+}
+
+// legacyReadiness - the fixed field behind a readiness key, nil for keys without one
+func (a *AssessmentTrl) legacyReadiness(key string) *bool {
+	switch key {
+	case "rq1":
+		return &a.Rq1Answer
+	case "rq2":
+		return &a.Rq2Answer
+	case "rq3":
+		return &a.Rq3Answer
+	case "rq4":
+		return &a.Rq4Answer
+	case "rq5":
+		return &a.Rq5Answer
+	case "rq6":
+		return &a.Rq6Answer
+	case "rq7":
+		return &a.Rq7Answer
+	}
+	return nil
+}
+
+// legacyCriteria - the fixed field behind a criteria key, nil for keys without one
+func (a *AssessmentTrl) legacyCriteria(key string) *[]string {
+	switch key {
+	case "cq1":
+		return &a.Cq1Answer
+	case "cq2":
+		return &a.Cq2Answer
+	case "cq3":
+		return &a.Cq3Answer
+	case "cq4":
+		return &a.Cq4Answer
+	case "cq5":
+		return &a.Cq5Answer
+	case "cq6":
+		return &a.Cq6Answer
+	case "cq7":
+		return &a.Cq7Answer
+	case "cq8":
+		return &a.Cq8Answer
+	case "cq9":
+		return &a.Cq9Answer
+	}
+	return nil
+}
+
+// LegacyAnswerKey maps a fixed JSON field ("rq3_answer") to its question key ("rq3")
+func LegacyAnswerKey(field string) (string, bool) {
+	if len(field) != len("rq1_answer") || field[3:] != "_answer" {
+		return "", false
+	}
+	key := field[:3]
+	var a AssessmentTrl
+	if a.legacyReadiness(key) == nil && a.legacyCriteria(key) == nil {
+		return "", false
+	}
+	return key, true
+}
+
+// Readiness - the yes/no answer of a readiness question, from the map or the fixed field
+func (a *AssessmentTrl) Readiness(key string) bool {
+	if v, ok := a.ReadinessAnswers[key]; ok {
+		return v
+	}
+	if f := a.legacyReadiness(key); f != nil {
+		return *f
+	}
+	return false
+}
+
+// Criteria - the ticked options of a criteria question, from the map or the fixed field
+func (a *AssessmentTrl) Criteria(key string) []string {
+	if v, ok := a.CriteriaAnswers[key]; ok {
+		return v
+	}
+	if f := a.legacyCriteria(key); f != nil {
+		return *f
+	}
+	return nil
+}
+
+// SetReadiness stores an answer in the map and, for rq1..rq7, in the fixed field
+func (a *AssessmentTrl) SetReadiness(key string, v bool) {
+	if a.ReadinessAnswers == nil {
+		a.ReadinessAnswers = map[string]bool{}
+	}
+	a.ReadinessAnswers[key] = v
+	if f := a.legacyReadiness(key); f != nil {
+		*f = v
+	}
+}
+
+// SetCriteria stores an answer in the map and, for cq1..cq9, in the fixed field
+func (a *AssessmentTrl) SetCriteria(key string, v []string) {
+	if v == nil {
+		v = []string{}
+	}
+	if a.CriteriaAnswers == nil {
+		a.CriteriaAnswers = map[string][]string{}
+	}
+	a.CriteriaAnswers[key] = v
+	if f := a.legacyCriteria(key); f != nil {
+		*f = v
+	}
+}
+
+// Version - the questionnaire version of the answers, old records without one are version 1
+func (a *AssessmentTrl) Version() int {
+	if a.QuestionnaireVersion == 0 {
+		return 1
+	}
+	return a.QuestionnaireVersion
+}
+
+// ReviewStatus - records from before reviews existed have no status and are pending
+func (a *AssessmentTrl) ReviewStatus() string {
+	if a.Status == "" {
+		return AssessmentPending
+	}
+	return a.Status
 }
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+)
+
+// AuditEntry is one mutating API call: who made it, on which record and what it changed
+type AuditEntry struct {
+	ID         string        `json:"id" firestore:"id"`
+	RequestID  string        `json:"request_id" firestore:"request_id"` // X-Request-ID of the call
+	ActorID    string        `json:"actor_id" firestore:"actor_id"`     // "" for public calls (POST /admin), "system" for the scheduled purge
+	ActorRole  string        `json:"actor_role" firestore:"actor_role"`
+	ActorEmail string        `json:"actor_email" firestore:"actor_email"`
+	Method     string        `json:"method" firestore:"method"`
+	Route      string        `json:"route" firestore:"route"`   // e.g. /trl/case/:id
+	Path       string        `json:"path" firestore:"path"`     // e.g. /trl/case/CS-00001
+	Entity     string        `json:"entity" firestore:"entity"` // "case", "researcher", ...
+	EntityID   string        `json:"entity_id" firestore:"entity_id"`
+	Action     string        `json:"action" firestore:"action"` // "create", "update", "delete", "transition", "purge", ...
+	Status     int           `json:"status" firestore:"status"` // HTTP status of the response, 0 without one
+	Changes    []AuditChange `json:"changes" firestore:"changes"`
+	CreatedAt  time.Time     `json:"created_at" firestore:"created_at"`
+}
+
+// AuditChange - one field of the record before and after the call, by its JSON name. Before is
+// nil on a create and After on a delete; secrets show as "[redacted]".
+type AuditChange struct {
+	Field  string `json:"field" firestore:"field"`
+	Before any    `json:"before" firestore:"before"`
+	After  any    `json:"after" firestore:"after"`
+}
@@ -5,20 +5,36 @@ import (
 )
 
 type CaseInfo struct {
-	CaseID           string    `json:"case_id" firestore:"case_id"`
-	CoordinatorEmail string    `json:"coordinator_email" firestore:"coordinator_email"`
-	TrlScore         string    `json:"trl_score" firestore:"tr_score"`
-	TrlSuggestion    string    `json:"trl_suggestion" firestore:"trl_suggestion"`
-	Status           bool      `json:"status" firestore:"status"`
-	IsUrgent         bool      `json:"is_urgent" firestore:"is_urgent"`
-	UrgentReason     string    `json:"urgent_reason" firestore:"urgent_reason"`
-	UrgentFeedback   string    `json:"urgent_feedback" firestore:"urgent_feedback"`
-	CaseTitle        string    `json:"case_title" firestore:"case_title"`
-	CaseType         string    `json:"case_type" firestore:"case_type"`
-	CaseDescription  string    `json:"case_description" firestore:"case_description"`
-	CaseKeywords     string    `json:"case_keywords" firestore:"case_keywords"`
-	CreatedAt        time.Time `json:"created_at" firestore:"created_at"`
-	UpdatedAt        time.Time `json:"updated_at" firestore:"updated_at"`
+	CaseID           string     `json:"case_id" firestore:"case_id"`
+	CoordinatorEmail string     `json:"coordinator_email" firestore:"coordinator_email"`
+	TrlScore         string     `json:"trl_score" firestore:"tr_score"`
+	TrlSuggestion    string     `json:"trl_suggestion" firestore:"trl_suggestion"`
+	Status           CaseStatus `json:"status" firestore:"status"`
+	StatusReason     string     `json:"status_reason" firestore:"status_reason"`
+	StatusChangedAt  time.Time  `json:"status_changed_at" firestore:"status_changed_at"`
+	// StatusTimestamps - when the case last entered each state, keyed by CaseStatus
+	StatusTimestamps map[string]time.Time `json:"status_timestamps" firestore:"status_timestamps"`
+	IsUrgent         bool                 `json:"is_urgent" firestore:"is_urgent"`
+	UrgentReason     string               `json:"urgent_reason" firestore:"urgent_reason"`
+	UrgentFeedback   string               `json:"urgent_feedback" firestore:"urgent_feedback"`
+	CaseTitle        string               `json:"case_title" firestore:"case_title"`
+	CaseType         string               `json:"case_type" firestore:"case_type"`
+	CaseDescription  string               `json:"case_description" firestore:"case_description"`
+	CaseKeywords     string               `json:"case_keywords" firestore:"case_keywords"`
+	CreatedAt        time.Time            `json:"created_at" firestore:"created_at"`
+	UpdatedAt        time.Time            `json:"updated_at" firestore:"updated_at"`
+	// Revision - 1 on create, +1 on every write; the ETag of the case
+	Revision int64 `json:"revision" firestore:"revision"`
 
-	ResearcherID     string    `json:"researcher_id" firestore:"researcher_id"`
+	// TrlRecommendation - the generated next steps towards the next level, TrlSuggestion holds them as text
+	TrlRecommendation *TrlRecommendation `json:"trl_recommendation" firestore:"trl_recommendation"`
+
+	// references into the taxonomy; CaseType and CaseKeywords hold their labels for display
+	DomainIDs  []string `json:"domain_ids" firestore:"domain_ids"`
+	CaseTypeID string   `json:"case_type_id" firestore:"case_type_id"`
+	KeywordIDs []string `json:"keyword_ids" firestore:"keyword_ids"`
+
+	ResearcherID string `json:"researcher_id" firestore:"researcher_id"`
+
+	Deletion
 }
@@ -0,0 +1,109 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// CaseStatus is the workflow state of a case. Moves between states go through
+// POST /trl/case/:id/transition, never through a plain PATCH.
+type CaseStatus string
+
+const (
+	CaseDraft             CaseStatus = "draft"
+	CaseSubmitted         CaseStatus = "submitted"
+	CaseUnderReview       CaseStatus = "under_review"
+	CaseRevisionRequested CaseStatus = "revision_requested"
+	CaseAssessment        CaseStatus = "assessment"
+	CaseApproved          CaseStatus = "approved"
+	CaseRejected          CaseStatus = "rejected"
+	CaseClosed            CaseStatus = "closed"
+)
+
+// CaseStatuses - every state, in workflow order
+var CaseStatuses = []CaseStatus{
+	CaseDraft, CaseSubmitted, CaseUnderReview, CaseRevisionRequested,
+	CaseAssessment, CaseApproved, CaseRejected, CaseClosed,
+}
+
+func (s CaseStatus) Valid() bool {
+	for _, known := range CaseStatuses {
+		if s == known {
+			return true
+		}
+	}
+	return false
+}
+
+// legacyCaseStatuses - free-text values written by the old PATCH /case/update-status/:id?status=...
+var legacyCaseStatuses = map[string]CaseStatus{
+	"":            CaseSubmitted,
+	"new":         CaseSubmitted,
+	"pending":     CaseSubmitted,
+	"waiting":     CaseSubmitted,
+	"in_progress": CaseUnderReview,
+	"in_process":  CaseUnderReview,
+	"processing":  CaseUnderReview,
+	"review":      CaseUnderReview,
+	"reviewing":   CaseUnderReview,
+	"assessing":   CaseAssessment,
+	"evaluating":  CaseAssessment,
+	"accept":      CaseApproved,
+	"accepted":    CaseApproved,
+	"complete":    CaseApproved,
+	"completed":   CaseApproved,
+	"done":        CaseApproved,
+	"passed":      CaseApproved,
+	"reject":      CaseRejected,
+	"declined":    CaseRejected,
+	"failed":      CaseRejected,
+	"close":       CaseClosed,
+	"archived":    CaseClosed,
+	"canceled":    CaseClosed,
+	"cancelled":   CaseClosed,
+}
+
+// ParseLegacyCaseStatus maps a stored status of any age to a workflow state. Before the
+// workflow, status was a bool (true = approved, false = still in process) or whatever string
+// the status endpoint received. ok is false when the value was unknown and had to be guessed.
+func ParseLegacyCaseStatus(v interface{}) (CaseStatus, bool) {
+	switch s := v.(type) {
+	case nil:
+		return CaseSubmitted, true
+	case bool:
+		if s {
+			return CaseApproved, true
+		}
+		return CaseUnderReview, true
+	case CaseStatus:
+		return ParseLegacyCaseStatus(string(s))
+	case string:
+		key := strings.ToLower(strings.TrimSpace(s))
+		key = strings.NewReplacer(" ", "_", "-", "_").Replace(key)
+		switch key {
+		case "true":
+			return CaseApproved, true
+		case "false":
+			return CaseUnderReview, true
+		}
+		if status := CaseStatus(key); status.Valid() {
+			return status, true
+		}
+		if status, ok := legacyCaseStatuses[key]; ok {
+			return status, true
+		}
+	}
+	return CaseUnderReview, false
+}
+
+// CaseStatusTransition is one entry of a case's status history
+type CaseStatusTransition struct {
+	ID        string     `json:"id" firestore:"id"`
+	CaseID    string     `json:"case_id" firestore:"case_id"`
+	From      CaseStatus `json:"from" firestore:"from"`
+	To        CaseStatus `json:"to" firestore:"to"`
+	Reason    string     `json:"reason" firestore:"reason"`
+	ActorID   string     `json:"actor_id" firestore:"actor_id"`
+	ActorRole string     `json:"actor_role" firestore:"actor_role"`
+	CreatedAt time.Time  `json:"created_at" firestore:"created_at"`
+}
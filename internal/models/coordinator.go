@@ -14,4 +14,9 @@ type CoordinatorInfo struct {
 	UpdatedAt        time.Time `json:"updated_at" firestore:"updated_at"`
 
 	CaseID           string    `json:"case_id" firestore:"case_id"`
+
+	// bcrypt hash; never in the API, a coordinator sets it through /auth/forgot-password
+	CoordinatorPassword string `json:"-" firestore:"coordinator_password"`
+
+	Deletion
 }
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Deletion - embedded in the records that can be soft-deleted. Zero while the record is live;
+// a deleted record is hidden from every read except the trash listing (?deleted=) until it is
+// restored or purged.
+type Deletion struct {
+	DeletedAt time.Time `json:"deleted_at,omitzero" firestore:"deleted_at,omitempty"`
+	DeletedBy string    `json:"deleted_by,omitempty" firestore:"deleted_by,omitempty"` // user ID
+}
+
+// IsDeleted - the record is in the trash
+func (d Deletion) IsDeleted() bool {
+	return !d.DeletedAt.IsZero()
+}
+
+// DeletedBefore - the record went to the trash before t, purging it is due
+func (d Deletion) DeletedBefore(t time.Time) bool {
+	return d.IsDeleted() && d.DeletedAt.Before(t)
+}
+
+// DeletedWith - the record went to the trash in the same delete as d, e.g. with its case
+func (d Deletion) DeletedWith(other Deletion) bool {
+	return d.IsDeleted() && d.DeletedAt.Equal(other.DeletedAt)
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// DraftContent - the parts of a submission as the researcher's form last saved them, in the
+// body format of POST /submissions. Parts may be incomplete; they are only checked fully when
+// the draft is submitted.
+type DraftContent struct {
+	Case       map[string]interface{}   `json:"case" firestore:"case"`
+	Supporter  map[string]interface{}   `json:"supporter" firestore:"supporter"`
+	IPs        []map[string]interface{} `json:"ips" firestore:"ips"`
+	Assessment map[string]interface{}   `json:"assessment" firestore:"assessment"`
+	Files      []map[string]interface{} `json:"files" firestore:"files"`
+}
+
+// Draft - a submission a researcher is still filling in (DR-00001). Every save moves ExpiresAt;
+// expired drafts are gone for reads and removed by the purge job.
+type Draft struct {
+	ID           string    `json:"id" firestore:"id"`
+	ResearcherID string    `json:"researcher_id" firestore:"researcher_id"`
+	CreatedAt    time.Time `json:"created_at" firestore:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at" firestore:"updated_at"`
+	ExpiresAt    time.Time `json:"expires_at" firestore:"expires_at"`
+
+	DraftContent
+}
+
+// Expired - the draft is past its ExpiresAt
+func (d *Draft) Expired(now time.Time) bool {
+	return !now.Before(d.ExpiresAt)
+}
@@ -12,4 +12,16 @@ type IntellectualProperty struct {
 	IPRequestNumber    string    `json:"ip_request_number" firestore:"ip_request_number"`
 	CreatedAt          time.Time `json:"created_at" firestore:"created_at"`
 	UpdatedAt          time.Time `json:"updated_at" firestore:"updated_at"`
+
+	Deletion
+}
+
+// IPTypes - the kinds of intellectual property a case can have (ip_types)
+var IPTypes = []string{
+	"สิทธิบัตร",                // patent
+	"อนุสิทธิบัตร",             // petty patent
+	"สิทธิบัตรออกแบบผลิตภัณฑ์", // design patent
+	"ลิขสิทธิ์",                // copyright
+	"เครื่องหมายการค้า",        // trademark
+	"ความลับทางการค้า",         // trade secret
 }
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+)
+
+// LoginThrottle counts recent failed logins for one key ("account:<email>" or "ip:<address>").
+// Backoff and lockout are derived from Failures and LastFailureAt.
+type LoginThrottle struct {
+	Key           string    `json:"key" firestore:"key"`
+	Failures      int       `json:"failures" firestore:"failures"`
+	LastFailureAt time.Time `json:"last_failure_at" firestore:"last_failure_at"`
+}
+
+// LoginAudit is one login attempt, successful or not
+type LoginAudit struct {
+	ID        string    `json:"id" firestore:"id"`
+	Email     string    `json:"email" firestore:"email"`
+	UserID    string    `json:"user_id" firestore:"user_id"`
+	Role      string    `json:"role" firestore:"role"`
+	IPAddress string    `json:"ip_address" firestore:"ip_address"`
+	UserAgent string    `json:"user_agent" firestore:"user_agent"`
+	Action    string    `json:"action" firestore:"action"` // "login", "change_password"
+	Success   bool      `json:"success" firestore:"success"`
+	Reason    string    `json:"reason" firestore:"reason"` // "", "invalid_credentials", "locked", "throttled"
+	CreatedAt time.Time `json:"created_at" firestore:"created_at"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+)
+
+// MFAEnrollment is the TOTP second factor of one user. Enrollment starts with Enabled=false
+// and is switched on once the user proves the authenticator app works. Recovery codes are
+// stored as SHA-256 hashes and each one works once.
+type MFAEnrollment struct {
+	UserID             string    `json:"user_id" firestore:"user_id"`
+	UserEmail          string    `json:"user_email" firestore:"user_email"`
+	Secret             string    `json:"-" firestore:"secret"` // base32 TOTP key
+	Enabled            bool      `json:"enabled" firestore:"enabled"`
+	RecoveryCodeHashes []string  `json:"-" firestore:"recovery_code_hashes"`
+	LastUsedStep       int64     `json:"-" firestore:"last_used_step"` // a TOTP code is accepted once
+	EnabledAt          time.Time `json:"enabled_at" firestore:"enabled_at"`
+	CreatedAt          time.Time `json:"created_at" firestore:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at" firestore:"updated_at"`
+}
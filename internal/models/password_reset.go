@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+)
+
+// PasswordReset is one emailed reset link. The document key is the SHA-256 of the token,
+// the token itself is never stored.
+type PasswordReset struct {
+	TokenHash string    `json:"-" firestore:"token_hash"`
+	UserID    string    `json:"user_id" firestore:"user_id"`
+	UserEmail string    `json:"user_email" firestore:"user_email"`
+	Role      string    `json:"role" firestore:"role"` // "admin" or "researcher"
+	Used      bool      `json:"used" firestore:"used"`
+	UsedAt    time.Time `json:"used_at" firestore:"used_at"`
+	ExpiresAt time.Time `json:"expires_at" firestore:"expires_at"`
+	CreatedAt time.Time `json:"created_at" firestore:"created_at"`
+}
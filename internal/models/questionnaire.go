@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+// Question types of a questionnaire template
+const (
+	QuestionReadiness = "readiness" // yes/no stage gate, answered in readiness_answers
+	QuestionCriteria  = "criteria"  // checklist of one level, answered in criteria_answers
+)
+
+// LocalizedText - the same text in Thai and English
+type LocalizedText struct {
+	TH string `json:"th" firestore:"th"`
+	EN string `json:"en" firestore:"en"`
+}
+
+// AnswerOption - one tickable criterion. Value is what the form sends back in criteria_answers.
+type AnswerOption struct {
+	Value string        `json:"value" firestore:"value"`
+	Text  LocalizedText `json:"text" firestore:"text"`
+}
+
+// Question - one question of the TRL form. Levels is the level mapping: the TRL levels a
+// readiness question gates, or the single level a criteria checklist belongs to.
+type Question struct {
+	Key     string         `json:"key" firestore:"key"` // "rq1", "cq3", ...
+	Type    string         `json:"type" firestore:"type"`
+	Text    LocalizedText  `json:"text" firestore:"text"`
+	Levels  []int          `json:"levels" firestore:"levels"`
+	Options []AnswerOption `json:"options,omitempty" firestore:"options"`
+}
+
+// QuestionnaireTemplate is one version of the TRL assessment form. A template is edited as a
+// draft and frozen once published, so every assessment answered against it keeps its meaning.
+// The active template is the one new assessments use; version 1 is built into the server.
+type QuestionnaireTemplate struct {
+	Version     int           `json:"version" firestore:"version"`
+	Title       LocalizedText `json:"title" firestore:"title"`
+	Description LocalizedText `json:"description" firestore:"description"`
+	Questions   []Question    `json:"questions" firestore:"questions"`
+	Published   bool          `json:"published" firestore:"published"`
+	PublishedAt time.Time     `json:"published_at" firestore:"published_at"`
+	Active      bool          `json:"active" firestore:"active"`
+	BuiltIn     bool          `json:"built_in" firestore:"-"`
+	CreatedBy   string        `json:"created_by" firestore:"created_by"`
+	CreatedAt   time.Time     `json:"created_at" firestore:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at" firestore:"updated_at"`
+}
+
+// Question returns the question with the given key, nil when the template has none
+func (t *QuestionnaireTemplate) Question(key string) *Question {
+	for i := range t.Questions {
+		if t.Questions[i].Key == key {
+			return &t.Questions[i]
+		}
+	}
+	return nil
+}
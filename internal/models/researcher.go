@@ -18,6 +18,8 @@ type ResearcherInfo struct {
 	ResearcherPassword         string    `json:"researcher_password" firestore:"researcher_password"`
 	CreatedAt                  time.Time `json:"created_at" firestore:"created_at"`
 	UpdatedAt                  time.Time `json:"updated_at" firestore:"updated_at"`
+
+	Deletion
 }
 
 func (r *ResearcherInfo) ToResponse() entity.ResearcherResponse {
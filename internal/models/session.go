@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+)
+
+// Session is one login. The refresh token handed to the client is "<session_id>.<secret>";
+// only the SHA-256 of the secret is stored and it changes on every refresh.
+type Session struct {
+	SessionID        string    `json:"session_id" firestore:"session_id"`
+	UserID           string    `json:"user_id" firestore:"user_id"`
+	UserEmail        string    `json:"user_email" firestore:"user_email"`
+	Role             string    `json:"role" firestore:"role"`
+	RefreshTokenHash string    `json:"-" firestore:"refresh_token_hash"`
+	UserAgent        string    `json:"user_agent" firestore:"user_agent"`
+	IPAddress        string    `json:"ip_address" firestore:"ip_address"`
+	Revoked          bool      `json:"revoked" firestore:"revoked"`
+	RevokedAt        time.Time `json:"revoked_at" firestore:"revoked_at"`
+	ExpiresAt        time.Time `json:"expires_at" firestore:"expires_at"`
+	LastUsedAt       time.Time `json:"last_used_at" firestore:"last_used_at"`
+	CreatedAt        time.Time `json:"created_at" firestore:"created_at"`
+}
+
+// Active - not revoked and the refresh token has not expired yet
+func (s *Session) Active(now time.Time) bool {
+	return !s.Revoked && now.Before(s.ExpiresAt)
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// Submission - a case with its first records, created together by POST /submissions. The
+// records get their IDs and the case ID when it is stored.
+type Submission struct {
+	Case       CaseInfo               `json:"case" firestore:"case"`
+	Supporter  *Supporter             `json:"supporter" firestore:"supporter"`
+	IPs        []IntellectualProperty `json:"ips" firestore:"ips"`
+	Assessment *AssessmentTrl         `json:"assessment" firestore:"assessment"`
+	Files      []FileMetadata         `json:"files" firestore:"files"`
+}
+
+// SubmissionKey - the Idempotency-Key a user sent with a submission. A retry with the same key
+// and body gets the stored submission instead of a second case until ExpiresAt.
+type SubmissionKey struct {
+	UserID      string    `json:"user_id" firestore:"user_id"`
+	Key         string    `json:"key" firestore:"key"`
+	RequestHash string    `json:"request_hash" firestore:"request_hash"` // sha256 of the body
+	CreatedAt   time.Time `json:"created_at" firestore:"created_at"`
+	ExpiresAt   time.Time `json:"expires_at" firestore:"expires_at"`
+}
+
+// Expired - the key can be used for a new submission
+func (k *SubmissionKey) Expired(now time.Time) bool {
+	return !now.Before(k.ExpiresAt)
+}
+
+// SubmissionRecord - a used key and what its submission created
+type SubmissionRecord struct {
+	SubmissionKey
+	Submission Submission `json:"submission" firestore:"submission"`
+}
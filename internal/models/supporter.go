@@ -21,4 +21,6 @@ type Supporter struct {
 	AdditionalDocuments            string    `json:"additional_documents" firestore:"additional_documents"`
 	CreatedAt                      time.Time `json:"created_at" firestore:"created_at"`
 	UpdatedAt                      time.Time `json:"updated_at" firestore:"updated_at"`
+
+	Deletion
 }
@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// Kinds of taxonomy terms
+const (
+	TermDomain   = "domain"    // technology domain, a case has any number
+	TermCaseType = "case_type" // a case has one
+	TermKeyword  = "keyword"
+)
+
+// TermKinds - every kind, in the order the admin screens list them
+var TermKinds = []string{TermDomain, TermCaseType, TermKeyword}
+
+// TaxonomyTerm - one curated term cases refer to by ID. Synonyms are other spellings that
+// resolve to the term when a case is saved with free text. A merged term is kept so old
+// references still resolve: MergedInto is the term that replaced it.
+type TaxonomyTerm struct {
+	ID         string        `json:"id" firestore:"id"` // TX-00001
+	Kind       string        `json:"kind" firestore:"kind"`
+	Label      LocalizedText `json:"label" firestore:"label"`
+	Synonyms   []string      `json:"synonyms" firestore:"synonyms"`
+	MergedInto string        `json:"merged_into,omitempty" firestore:"merged_into"`
+	CreatedAt  time.Time     `json:"created_at" firestore:"created_at"`
+	UpdatedAt  time.Time     `json:"updated_at" firestore:"updated_at"`
+}
+
+// DisplayLabel - the Thai label, the English one when there is no Thai
+func (t *TaxonomyTerm) DisplayLabel() string {
+	if t.Label.TH != "" {
+		return t.Label.TH
+	}
+	return t.Label.EN
+}
+
+// Names - the labels and synonyms of the term, the texts it is found by
+func (t *TaxonomyTerm) Names() []string {
+	var names []string
+	for _, s := range append([]string{t.Label.TH, t.Label.EN}, t.Synonyms...) {
+		if s != "" {
+			names = append(names, s)
+		}
+	}
+	return names
+}
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// Priorities of a TRL next step, most urgent first
+const (
+	StepPriorityHigh   = "high"   // required by the questionnaire for the next level
+	StepPriorityMedium = "medium" // the usual work at this stage / a need that fits it
+	StepPriorityLow    = "low"    // a need the researcher listed that matters at a later stage
+)
+
+// TrlNextStep - one thing to do on the way to the next TRL level
+type TrlNextStep struct {
+	Code     string        `json:"code" firestore:"code"` // "prototype_testing", "ip_filing", ...
+	Priority string        `json:"priority" firestore:"priority"`
+	Title    LocalizedText `json:"title" firestore:"title"`
+	Detail   LocalizedText `json:"detail" firestore:"detail"`
+	// Criteria - the checklist options still open, for steps that come from the questionnaire
+	Criteria []string `json:"criteria,omitempty" firestore:"criteria"`
+	// Sources - the rules that produced the step: "criteria:cq4", "stage:4", "need:need_test"
+	Sources []string `json:"sources" firestore:"sources"`
+}
+
+// TrlRecommendation - the next steps of a case, generated from its latest assessment and the
+// supporter needs. TargetLevel is 0 when the assessment already reached the top level.
+type TrlRecommendation struct {
+	AssessmentID         string        `json:"assessment_id" firestore:"assessment_id"`
+	QuestionnaireVersion int           `json:"questionnaire_version" firestore:"questionnaire_version"`
+	CurrentLevel         int           `json:"current_level" firestore:"current_level"`
+	TargetLevel          int           `json:"target_level" firestore:"target_level"`
+	Steps                []TrlNextStep `json:"steps" firestore:"steps"`
+	GeneratedAt          time.Time     `json:"generated_at" firestore:"generated_at"`
+}
@@ -0,0 +1,267 @@
+// Package patch reads the body of a PATCH endpoint into a typed DTO instead of merging whatever
+// JSON the client sent. A DTO is a struct of pointer fields, one per writable field by its JSON
+// name; nil means "not in the body".
+//
+// Every other field of the body is checked against Rules: a server-managed field is dropped (so
+// a client may send back the record it read), an immutable one may only repeat the stored value,
+// and anything else is an unknown field. All problems of a body are collected into one Error.
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/mail"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Problem - what is wrong with one field of the body
+type Problem struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"` // "unknown", "immutable", "type", "required", "length", "enum", "format", "reference"
+	Message string `json:"message"`
+}
+
+// Error - every problem of a body, answered as 422
+type Error struct {
+	Problems []Problem
+}
+
+func (e *Error) Error() string {
+	msgs := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		msgs[i] = p.Field + ": " + p.Message
+	}
+	return "invalid patch: " + strings.Join(msgs, "; ")
+}
+
+// Rules - the fields of the record a client can't write, by JSON name
+type Rules struct {
+	Immutable []string // IDs and the like: only the stored value is accepted
+	Managed   []string // written by the server (timestamps, workflow, derived values): dropped
+}
+
+// Validator is implemented by DTOs with rules beyond the type of their fields
+type Validator interface {
+	Validate(v *Problems)
+}
+
+// Decode reads body into dto (a pointer to a DTO struct) and returns the fields it set, as the
+// data of a repository UpdateXByID. stored is the record as it is now, for the immutable fields.
+// A body that isn't a JSON object is a plain error; problems with its fields are an *Error.
+func Decode(body []byte, dto any, stored any, rules Rules) (map[string]interface{}, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, errors.New("body must be a JSON object")
+	}
+	current := fieldsOf(stored)
+	dv := reflect.ValueOf(dto).Elem()
+	byName := fieldIndex(dv.Type())
+
+	v := &Problems{}
+	for _, name := range sortedKeys(raw) {
+		switch {
+		case contains(rules.Managed, name):
+		case contains(rules.Immutable, name):
+			if !sameValue(current[name], raw[name]) {
+				v.Add(name, "immutable", "can't be changed")
+			}
+		default:
+			i, ok := byName[name]
+			if !ok {
+				v.Add(name, "unknown", "unknown field")
+				continue
+			}
+			f := dv.Field(i)
+			ptr := reflect.New(f.Type().Elem())
+			if bytes.Equal(bytes.TrimSpace(raw[name]), []byte("null")) {
+				// lists and maps come back as null from records that never had one
+				switch t := f.Type().Elem(); t.Kind() {
+				case reflect.Slice:
+					ptr.Elem().Set(reflect.MakeSlice(t, 0, 0))
+				case reflect.Map:
+					ptr.Elem().Set(reflect.MakeMap(t))
+				default:
+					v.Add(name, "type", "can't be null")
+					continue
+				}
+				f.Set(ptr)
+				continue
+			}
+			if err := json.Unmarshal(raw[name], ptr.Interface()); err != nil {
+				v.Add(name, "type", "expected "+describe(f.Type().Elem()))
+				continue
+			}
+			f.Set(ptr)
+		}
+	}
+	if val, ok := dto.(Validator); ok {
+		val.Validate(v)
+	}
+	if len(v.list) > 0 {
+		sort.SliceStable(v.list, func(i, j int) bool { return v.list[i].Field < v.list[j].Field })
+		return nil, &Error{Problems: v.list}
+	}
+
+	data := map[string]interface{}{}
+	for name, i := range byName {
+		if f := dv.Field(i); !f.IsNil() {
+			data[name] = f.Elem().Interface()
+		}
+	}
+	return data, nil
+}
+
+// Problems collects the problems of a body; the checks below add one when a set field breaks
+// their rule and do nothing for nil
+type Problems struct {
+	list []Problem
+}
+
+func (v *Problems) Add(field, code, msg string) {
+	v.list = append(v.list, Problem{Field: field, Code: code, Message: msg})
+}
+
+// Length - between min and max letters; min 1 also rejects blank text
+func (v *Problems) Length(field string, s *string, min, max int) {
+	if s == nil {
+		return
+	}
+	n := utf8.RuneCountInString(*s)
+	switch {
+	case min > 0 && strings.TrimSpace(*s) == "":
+		v.Add(field, "required", "can't be empty")
+	case n < min || n > max:
+		v.Add(field, "length", fmt.Sprintf("must be %d to %d characters", min, max))
+	}
+}
+
+// Enum - one of allowed
+func (v *Problems) Enum(field string, s *string, allowed ...string) {
+	if s != nil && !contains(allowed, *s) {
+		v.Add(field, "enum", "must be one of "+strings.Join(allowed, ", "))
+	}
+}
+
+// Email - a plain address, or "" when optional
+func (v *Problems) Email(field string, s *string, optional bool) {
+	if s == nil || (optional && *s == "") {
+		return
+	}
+	addr, err := mail.ParseAddress(*s)
+	if err != nil || addr.Address != *s {
+		v.Add(field, "format", "must be an email address")
+	}
+}
+
+// Time - not the zero time and within years of now
+func (v *Problems) Time(field string, t *time.Time, years int) {
+	if t == nil {
+		return
+	}
+	now := time.Now()
+	if t.IsZero() || t.Before(now.AddDate(-years, 0, 0)) || t.After(now.AddDate(years, 0, 0)) {
+		v.Add(field, "format", fmt.Sprintf("must be a date within %d years from today", years))
+	}
+}
+
+// IDs - at most max non-empty IDs
+func (v *Problems) IDs(field string, ids *[]string, max int) {
+	if ids == nil {
+		return
+	}
+	if len(*ids) > max {
+		v.Add(field, "length", fmt.Sprintf("at most %d items", max))
+	}
+	for _, id := range *ids {
+		if strings.TrimSpace(id) == "" {
+			v.Add(field, "required", "items can't be empty")
+			return
+		}
+	}
+}
+
+// fieldIndex - the struct field behind each JSON name of a DTO
+func fieldIndex(t reflect.Type) map[string]int {
+	byName := map[string]int{}
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			byName[name] = i
+		}
+	}
+	return byName
+}
+
+// fieldsOf - a stored record by JSON name
+func fieldsOf(v any) map[string]json.RawMessage {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var m map[string]json.RawMessage
+	json.Unmarshal(b, &m)
+	return m
+}
+
+// sameValue - the body repeats the stored value; times compare as instants, so a client may
+// send them in another time zone
+func sameValue(stored, sent json.RawMessage) bool {
+	var a, b any
+	if json.Unmarshal(stored, &a) != nil || json.Unmarshal(sent, &b) != nil {
+		return false
+	}
+	if as, ok := a.(string); ok {
+		if bs, ok := b.(string); ok {
+			ta, errA := time.Parse(time.RFC3339Nano, as)
+			tb, errB := time.Parse(time.RFC3339Nano, bs)
+			if errA == nil && errB == nil {
+				return ta.Equal(tb)
+			}
+		}
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func describe(t reflect.Type) string {
+	switch {
+	case t == reflect.TypeOf(time.Time{}):
+		return "an RFC 3339 time"
+	case t.Kind() == reflect.String:
+		return "a string"
+	case t.Kind() == reflect.Bool:
+		return "true or false"
+	case t.Kind() == reflect.Int:
+		return "a whole number"
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.String:
+		return "an array of strings"
+	case t.Kind() == reflect.Slice:
+		return "an array"
+	case t.Kind() == reflect.Map:
+		return "an object"
+	}
+	return t.String()
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
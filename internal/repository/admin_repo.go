@@ -3,10 +3,9 @@ package repository
 import (
 	"context"
 	"fmt"
-	"strconv"
-	"strings"
 	"time"
 
+	"trl-research-backend/internal/listing"
 	"trl-research-backend/internal/models"
 
 	"cloud.google.com/go/firestore"
@@ -15,10 +14,11 @@ import (
 
 type AdminRepo struct {
 	Client *firestore.Client
+	Seq    SequenceGenerator
 }
 
 func NewAdminRepo(client *firestore.Client) *AdminRepo {
-	return &AdminRepo{Client: client}
+	return &AdminRepo{Client: client, Seq: NewFirestoreSequence(client)}
 }
 
 // 🟢 Get all admins
@@ -35,7 +35,12 @@ func (r *AdminRepo) GetAdminAll() ([]models.AdminInfo, error) {
 		doc.DataTo(&admin)
 		admins = append(admins, admin)
 	}
-	return admins, nil
+	return live(admins), nil
+}
+
+// 🟢 ListAdmins - one page of admin_info for GET with list parameters
+func (r *AdminRepo) ListAdmins(q listing.Query) (*listing.Page[models.AdminInfo], error) {
+	return listDocs(r.Client.Collection("admin_info"), q, dataTo[models.AdminInfo])
 }
 
 // 🟢 Get admin by ID
@@ -43,17 +48,18 @@ func (r *AdminRepo) GetAdminByID(adminID string) (*models.AdminInfo, error) {
 	ctx := context.Background()
 
 	// Query by admin_id field instead of document ID
-	docs, err := r.Client.Collection("admin_info").Where("admin_id", "==", adminID).Limit(1).Documents(ctx).GetAll()
+	docs, err := r.Client.Collection("admin_info").Where("admin_id", "==", adminID).Documents(ctx).GetAll()
 	if err != nil {
 		return nil, err
 	}
 
-	if len(docs) == 0 {
+	admin, ok, err := firstLive(docs, dataTo[models.AdminInfo])
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
 		return nil, fmt.Errorf("admin not found")
 	}
-
-	var admin models.AdminInfo
-	docs[0].DataTo(&admin)
 	return &admin, nil
 }
 
@@ -67,6 +73,9 @@ func (r *AdminRepo) GetAdminByEmail(email string) (*models.AdminInfo, error) {
 
 	var admin models.AdminInfo
 	doc.DataTo(&admin)
+	if admin.IsDeleted() {
+		return nil, fmt.Errorf("admin %s: %w", email, ErrNotFound)
+	}
 	return &admin, nil
 }
 
@@ -74,15 +83,9 @@ func (r *AdminRepo) GetAdminByEmail(email string) (*models.AdminInfo, error) {
 func (r *AdminRepo) CreateAdmin(admin *models.AdminInfo) error {
 	ctx := context.Background()
 
-	// find last ID to generate next
-	docs, err := r.Client.Collection("admin_info").OrderBy("admin_id", firestore.Desc).Limit(1).Documents(ctx).GetAll()
-	nextID := "AD-00001"
-	if err == nil && len(docs) > 0 {
-		lastID := docs[0].Data()["admin_id"].(string)
-		numStr := strings.TrimPrefix(lastID, "SI-")
-		if n, err := strconv.Atoi(numStr); err == nil {
-			nextID = fmt.Sprintf("AD-%05d", n+1)
-		}
+	nextID, err := r.Seq.Next("AD")
+	if err != nil {
+		return err
 	}
 
 	// assign values
@@ -100,18 +103,19 @@ func (r *AdminRepo) Login(email string, password string) (*models.AdminInfo, err
 	ctx := context.Background()
 
 	// Query by email field instead of using email as document ID
-	docs, err := r.Client.Collection("admin_info").Where("admin_email", "==", email).Limit(1).Documents(ctx).GetAll()
+	docs, err := r.Client.Collection("admin_info").Where("admin_email", "==", email).Documents(ctx).GetAll()
 	if err != nil {
 		return nil, err
 	}
 
-	if len(docs) == 0 {
+	admin, ok, err := firstLive(docs, dataTo[models.AdminInfo])
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
 		return nil, fmt.Errorf("admin not found")
 	}
 
-	var admin models.AdminInfo
-	docs[0].DataTo(&admin)
-
 	// Verify password
 	err = bcrypt.CompareHashAndPassword([]byte(admin.AdminPassword), []byte(password))
 	if err != nil {
@@ -157,9 +161,37 @@ func (r *AdminRepo) UpdateAdminByID(id string, data *models.AdminInfo) error {
 	return err
 }
 
-// 🟢 Delete admin
-func (r *AdminRepo) DeleteAdmin(email string) error {
-	ctx := context.Background()
-	_, err := r.Client.Collection("admin_info").Doc(email).Delete(ctx)
-	return err
+// 🟢 Delete admin - soft delete
+func (r *AdminRepo) DeleteAdmin(adminID, deletedBy string) error {
+	ref, err := r.refOf(adminID)
+	if err != nil {
+		return err
+	}
+	return setDeletion(r.Client, ref, "admin", models.Deletion{DeletedAt: time.Now(), DeletedBy: deletedBy})
+}
+
+// 🟢 RestoreAdmin
+func (r *AdminRepo) RestoreAdmin(adminID string) error {
+	ref, err := r.refOf(adminID)
+	if err != nil {
+		return err
+	}
+	return setDeletion(r.Client, ref, "admin", models.Deletion{})
+}
+
+// 🟢 PurgeAdmins
+func (r *AdminRepo) PurgeAdmins(cutoff time.Time) ([]string, error) {
+	return purge(r.Client.Collection("admin_info"), "admin_id", cutoff, nil)
+}
+
+// refOf - the document of an admin (keyed by email), deleted or not
+func (r *AdminRepo) refOf(adminID string) (*firestore.DocumentRef, error) {
+	docs, err := r.Client.Collection("admin_info").Where("admin_id", "==", adminID).Limit(1).Documents(context.Background()).GetAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) == 0 {
+		return nil, fmt.Errorf("admin %s: %w", adminID, ErrNotFound)
+	}
+	return docs[0].Ref, nil
 }
@@ -3,20 +3,20 @@ package repository
 import (
 	"context"
 	"fmt"
-	"strconv"
-	"strings"
 	"time"
 
 	"cloud.google.com/go/firestore"
+	"trl-research-backend/internal/listing"
 	"trl-research-backend/internal/models"
 )
 
 type AppointmentRepo struct {
 	Client *firestore.Client
+	Seq    SequenceGenerator
 }
 
 func NewAppointmentRepo(client *firestore.Client) *AppointmentRepo {
-	return &AppointmentRepo{Client: client}
+	return &AppointmentRepo{Client: client, Seq: NewFirestoreSequence(client)}
 }
 
 // 🟢 GetAppointmentAll
@@ -33,7 +33,12 @@ func (r *AppointmentRepo) GetAppointmentAll() ([]models.Appointment, error) {
 		doc.DataTo(&ap)
 		appointments = append(appointments, ap)
 	}
-	return appointments, nil
+	return live(appointments), nil
+}
+
+// 🟢 ListAppointments - one page of appointments for GET with list parameters
+func (r *AppointmentRepo) ListAppointments(q listing.Query) (*listing.Page[models.Appointment], error) {
+	return listDocs(r.Client.Collection("appointments"), q, dataTo[models.Appointment])
 }
 
 // 🟢 GetAppointmentByID
@@ -46,6 +51,9 @@ func (r *AppointmentRepo) GetAppointmentByID(appointmentID string) (*models.Appo
 
 	var ap models.Appointment
 	doc.DataTo(&ap)
+	if ap.IsDeleted() {
+		return nil, fmt.Errorf("appointment %s: %w", appointmentID, ErrNotFound)
+	}
 	return &ap, nil
 }
 
@@ -63,21 +71,16 @@ func (r *AppointmentRepo) GetAppointmentByCaseID(caseID string) ([]models.Appoin
         doc.DataTo(&ap)
         appointments = append(appointments, ap)
     }
-    return appointments, nil
+    return live(appointments), nil
 }
 
 // 🟢 CreateAppointment - auto generate ID AP-00001
 func (r *AppointmentRepo) CreateAppointment(ap *models.Appointment) error {
 	ctx := context.Background()
 
-	docs, err := r.Client.Collection("appointments").OrderBy("appointment_id", firestore.Desc).Limit(1).Documents(ctx).GetAll()
-	nextID := "AP-00001"
-	if err == nil && len(docs) > 0 {
-		lastID := docs[0].Data()["appointment_id"].(string)
-		numStr := strings.TrimPrefix(lastID, "AP-")
-		if n, err := strconv.Atoi(numStr); err == nil {
-			nextID = fmt.Sprintf("AP-%05d", n+1)
-		}
+	nextID, err := r.Seq.Next("AP")
+	if err != nil {
+		return err
 	}
 
 	ap.AppointmentID = nextID
@@ -85,7 +88,8 @@ func (r *AppointmentRepo) CreateAppointment(ap *models.Appointment) error {
 	ap.CreatedAt = now
 	ap.UpdatedAt = now
 
-	_, err = r.Client.Collection("appointments").Doc(ap.AppointmentID).Set(ctx, ap)
+	// Create (not Set) fails instead of silently overwriting an existing document
+	_, err = r.Client.Collection("appointments").Doc(ap.AppointmentID).Create(ctx, ap)
 	return err
 }
 
@@ -96,3 +100,18 @@ func (r *AppointmentRepo) UpdateAppointmentByID(appointmentID string, data map[s
 	_, err := r.Client.Collection("appointments").Doc(appointmentID).Set(ctx, data, firestore.MergeAll)
 	return err
 }
+
+// 🟢 DeleteAppointment - soft delete
+func (r *AppointmentRepo) DeleteAppointment(appointmentID, deletedBy string) error {
+	return trash(r.Client, "appointments", "appointment", appointmentID, deletedBy)
+}
+
+// 🟢 RestoreAppointment
+func (r *AppointmentRepo) RestoreAppointment(appointmentID string) error {
+	return restore(r.Client, "appointments", "appointment", appointmentID)
+}
+
+// 🟢 PurgeAppointments
+func (r *AppointmentRepo) PurgeAppointments(cutoff time.Time) ([]string, error) {
+	return purge(r.Client.Collection("appointments"), "", cutoff, nil)
+}
@@ -3,20 +3,23 @@ package repository
 import (
 	"context"
 	"fmt"
-	"strconv"
-	"strings"
+	"sort"
 	"time"
 
 	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"trl-research-backend/internal/listing"
 	"trl-research-backend/internal/models"
 )
 
 type AssessmentTrlRepo struct {
 	Client *firestore.Client
+	Seq    SequenceGenerator
 }
 
 func NewAssessmentTrlRepo(client *firestore.Client) *AssessmentTrlRepo {
-	return &AssessmentTrlRepo{Client: client}
+	return &AssessmentTrlRepo{Client: client, Seq: NewFirestoreSequence(client)}
 }
 
 // 🟢 GetAssessmentTrlAll
@@ -33,7 +36,12 @@ func (r *AssessmentTrlRepo) GetAssessmentTrlAll() ([]models.AssessmentTrl, error
 		doc.DataTo(&a)
 		assessments = append(assessments, a)
 	}
-	return assessments, nil
+	return live(assessments), nil
+}
+
+// 🟢 ListAssessmentTrls - one page of assessment_trl for GET with list parameters
+func (r *AssessmentTrlRepo) ListAssessmentTrls(q listing.Query) (*listing.Page[models.AssessmentTrl], error) {
+	return listDocs(r.Client.Collection("assessment_trl"), q, dataTo[models.AssessmentTrl])
 }
 
 // 🟢 GetAssessmentTrlByID
@@ -46,49 +54,154 @@ func (r *AssessmentTrlRepo) GetAssessmentTrlByID(id string) (*models.AssessmentT
 
 	var a models.AssessmentTrl
 	doc.DataTo(&a)
+	if a.IsDeleted() {
+		return nil, fmt.Errorf("assessment_trl %s: %w", id, ErrNotFound)
+	}
 	return &a, nil
 }
 
-// 🟢 GetAssessmentTrlByCaseID
+// 🟢 GetAssessmentTrlByCaseID - the latest one
 func (r *AssessmentTrlRepo) GetAssessmentTrlByCaseID(caseID string) (*models.AssessmentTrl, error) {
+	all, err := r.GetAssessmentTrlsByCaseID(caseID)
+	if err != nil {
+		return nil, err
+	}
+	if len(all) == 0 {
+		return nil, fmt.Errorf("assessment_trl for case %s: %w", caseID, ErrNotFound)
+	}
+	return &all[len(all)-1], nil
+}
+
+// 🟢 GetAssessmentTrlsByCaseID - oldest first
+func (r *AssessmentTrlRepo) GetAssessmentTrlsByCaseID(caseID string) ([]models.AssessmentTrl, error) {
 	ctx := context.Background()
-	doc, err := r.Client.Collection("assessment_trl").Where("case_id", "==", caseID).Documents(ctx).GetAll()
+	docs, err := r.Client.Collection("assessment_trl").Where("case_id", "==", caseID).Documents(ctx).GetAll()
 	if err != nil {
 		return nil, err
 	}
 
-	var a models.AssessmentTrl
-	doc[0].DataTo(&a)
-	return &a, nil
+	var assessments []models.AssessmentTrl
+	for _, doc := range docs {
+		var a models.AssessmentTrl
+		if err := doc.DataTo(&a); err != nil {
+			return nil, err
+		}
+		if !a.IsDeleted() {
+			assessments = append(assessments, a)
+		}
+	}
+	// sorted here instead of OrderBy so no composite index is needed; IDs break ties
+	sort.Slice(assessments, func(i, j int) bool {
+		if !assessments[i].CreatedAt.Equal(assessments[j].CreatedAt) {
+			return assessments[i].CreatedAt.Before(assessments[j].CreatedAt)
+		}
+		return assessments[i].ID < assessments[j].ID
+	})
+	return assessments, nil
 }
 
 // 🟢 CreateAssessmentTrl - auto generate ID AS-00001
 func (r *AssessmentTrlRepo) CreateAssessmentTrl(a *models.AssessmentTrl) error {
 	ctx := context.Background()
 
-	docs, err := r.Client.Collection("assessment_trl").OrderBy("id", firestore.Desc).Limit(1).Documents(ctx).GetAll()
-	nextID := "AS-00001"
-	if err == nil && len(docs) > 0 {
-		lastID := docs[0].Data()["id"].(string)
-		numStr := strings.TrimPrefix(lastID, "AS-")
-		if n, err := strconv.Atoi(numStr); err == nil {
-			nextID = fmt.Sprintf("AS-%05d", n+1)
-		}
+	nextID, err := r.Seq.Next("AS")
+	if err != nil {
+		return err
 	}
 
 	a.ID = nextID
 	now := time.Now()
 	a.CreatedAt = now
 	a.UpdatedAt = now
+	a.Revision = 1
 
-	_, err = r.Client.Collection("assessment_trl").Doc(a.ID).Set(ctx, a)
+	// Create (not Set) fails instead of silently overwriting an existing document
+	_, err = r.Client.Collection("assessment_trl").Doc(a.ID).Create(ctx, a)
 	return err
 }
 
-// 🟢 UpdateAssessmentTrlByID
+// pendingIn reads an assessment inside a transaction; ErrConflict once it is reviewed
+func (r *AssessmentTrlRepo) pendingIn(tx *firestore.Transaction, id string) (*models.AssessmentTrl, error) {
+	doc, err := tx.Get(r.Client.Collection("assessment_trl").Doc(id))
+	if status.Code(err) == codes.NotFound {
+		return nil, fmt.Errorf("assessment_trl %s: %w", id, ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var a models.AssessmentTrl
+	if err := doc.DataTo(&a); err != nil {
+		return nil, err
+	}
+	if a.IsDeleted() {
+		return nil, fmt.Errorf("assessment_trl %s: %w", id, ErrNotFound)
+	}
+	if a.ReviewStatus() != models.AssessmentPending {
+		return nil, fmt.Errorf("assessment_trl %s is %s: %w", id, a.Status, ErrConflict)
+	}
+	return &a, nil
+}
+
+// 🟢 UpdateAssessmentTrlByID - only while pending, checked inside a transaction
 func (r *AssessmentTrlRepo) UpdateAssessmentTrlByID(id string, data map[string]interface{}) error {
+	return r.update(id, nil, data)
+}
+
+// 🟢 UpdateAssessmentTrlIfRevision - only while pending and still at revision
+func (r *AssessmentTrlRepo) UpdateAssessmentTrlIfRevision(id string, revision int64, data map[string]interface{}) error {
+	return r.update(id, &revision, data)
+}
+
+// update - revision nil writes whatever revision the assessment is at
+func (r *AssessmentTrlRepo) update(id string, revision *int64, data map[string]interface{}) error {
 	ctx := context.Background()
-	data["updated_at"] = time.Now()
-	_, err := r.Client.Collection("assessment_trl").Doc(id).Set(ctx, data, firestore.MergeAll)
-	return err
+	ref := r.Client.Collection("assessment_trl").Doc(id)
+	return r.Client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		a, err := r.pendingIn(tx, id)
+		if err != nil {
+			return err
+		}
+		if revision != nil && a.Revision != *revision {
+			return fmt.Errorf("assessment_trl %s is at revision %d: %w", id, a.Revision, ErrStale)
+		}
+		data["updated_at"] = time.Now()
+		data["revision"] = a.Revision + 1
+		return tx.Set(ref, data, firestore.MergeAll)
+	})
+}
+
+// 🟢 ReviewAssessmentTrl
+func (r *AssessmentTrlRepo) ReviewAssessmentTrl(id, reviewStatus, reviewerID, note string) error {
+	ctx := context.Background()
+	ref := r.Client.Collection("assessment_trl").Doc(id)
+	return r.Client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		a, err := r.pendingIn(tx, id)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		return tx.Update(ref, []firestore.Update{
+			{Path: "status", Value: reviewStatus},
+			{Path: "reviewed_by", Value: reviewerID},
+			{Path: "reviewed_at", Value: now},
+			{Path: "review_note", Value: note},
+			{Path: "updated_at", Value: now},
+			{Path: "revision", Value: a.Revision + 1},
+		})
+	})
+}
+
+// 🟢 DeleteAssessmentTrl - soft delete
+func (r *AssessmentTrlRepo) DeleteAssessmentTrl(id, deletedBy string) error {
+	return trash(r.Client, "assessment_trl", "assessment_trl", id, deletedBy)
+}
+
+// 🟢 RestoreAssessmentTrl
+func (r *AssessmentTrlRepo) RestoreAssessmentTrl(id string) error {
+	return restore(r.Client, "assessment_trl", "assessment_trl", id)
+}
+
+// 🟢 PurgeAssessmentTrls
+func (r *AssessmentTrlRepo) PurgeAssessmentTrls(cutoff time.Time) ([]string, error) {
+	return purge(r.Client.Collection("assessment_trl"), "", cutoff, nil)
 }
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/google/uuid"
+	"trl-research-backend/internal/listing"
+	"trl-research-backend/internal/models"
+)
+
+type AuditRepo struct {
+	Client *firestore.Client
+}
+
+func NewAuditRepo(client *firestore.Client) *AuditRepo {
+	return &AuditRepo{Client: client}
+}
+
+func (r *AuditRepo) col() *firestore.CollectionRef {
+	return r.Client.Collection("audit_log")
+}
+
+// 🟢 CreateAuditEntry
+func (r *AuditRepo) CreateAuditEntry(e *models.AuditEntry) error {
+	e.ID = uuid.NewString()
+	e.CreatedAt = time.Now()
+	if e.Changes == nil {
+		e.Changes = []models.AuditChange{}
+	}
+	_, err := r.col().Doc(e.ID).Create(context.Background(), e)
+	return err
+}
+
+// 🟢 ListAuditEntries
+func (r *AuditRepo) ListAuditEntries(q listing.Query) (*listing.Page[models.AuditEntry], error) {
+	return listDocs(r.col(), q, dataTo[models.AuditEntry])
+}
@@ -3,20 +3,24 @@ package repository
 import (
 	"context"
 	"fmt"
-	"strconv"
-	"strings"
+	"sort"
 	"time"
 
 	"cloud.google.com/go/firestore"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"trl-research-backend/internal/listing"
 	"trl-research-backend/internal/models"
 )
 
 type CaseRepo struct {
 	Client *firestore.Client
+	Seq    SequenceGenerator
 }
 
 func NewCaseRepo(client *firestore.Client) *CaseRepo {
-	return &CaseRepo{Client: client}
+	return &CaseRepo{Client: client, Seq: NewFirestoreSequence(client)}
 }
 
 // 🟢 GetCaseAll - fetch all cases
@@ -34,12 +38,16 @@ func (r *CaseRepo) GetCaseAll() ([]models.CaseInfo, error) {
 
 	var cases []models.CaseInfo
 	for _, doc := range docs {
-		var cs models.CaseInfo
-		doc.DataTo(&cs)
-		cases = append(cases, cs)
+		cs, _ := caseFromDoc(doc)
+		cases = append(cases, *cs)
 	}
 	fmt.Println(cases)
-	return cases, nil
+	return live(cases), nil
+}
+
+// 🟢 ListCases - one page of cases for GET with list parameters
+func (r *CaseRepo) ListCases(q listing.Query) (*listing.Page[models.CaseInfo], error) {
+	return listDocs(r.Client.Collection("cases"), q, caseValue, "status") // legacy true/false statuses
 }
 
 // 🟢 GetCaseAllByResearcher_id - fetch all cases for a researcher
@@ -52,60 +60,268 @@ func (r *CaseRepo) GetCaseAllByResearcher_id(researcher_id string) ([]models.Cas
 
 	var cases []models.CaseInfo
 	for _, doc := range docs {
-		var cs models.CaseInfo
-		doc.DataTo(&cs)
-		cases = append(cases, cs)
+		cs, _ := caseFromDoc(doc)
+		cases = append(cases, *cs)
 	}
-	return cases, nil
+	return live(cases), nil
 }
 
 // 🟢 GetCaseByID
 func (r *CaseRepo) GetCaseByID(caseID string) (*models.CaseInfo, error) {
 	ctx := context.Background()
 	doc, err := r.Client.Collection("cases").Doc(caseID).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return nil, fmt.Errorf("case %s: %w", caseID, ErrNotFound)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	var cs models.CaseInfo
-	doc.DataTo(&cs)
-	return &cs, nil
+	cs, _ := caseFromDoc(doc)
+	if cs.IsDeleted() {
+		return nil, fmt.Errorf("case %s: %w", caseID, ErrNotFound)
+	}
+	return cs, nil
 }
 
 // 🟢 CreateCase - auto generate CaseID (CS-00001)
 func (r *CaseRepo) CreateCase(cs *models.CaseInfo) error {
 	ctx := context.Background()
 
-	docs, err := r.Client.Collection("cases").OrderBy("case_id", firestore.Desc).Limit(1).Documents(ctx).GetAll()
-	nextID := "CS-00001"
-	if err == nil && len(docs) > 0 {
-		lastID := docs[0].Data()["case_id"].(string)
-		numStr := strings.TrimPrefix(lastID, "CS-")
-		if n, err := strconv.Atoi(numStr); err == nil {
-			nextID = fmt.Sprintf("CS-%05d", n+1)
-		}
+	nextID, err := r.Seq.Next("CS")
+	if err != nil {
+		return err
 	}
 
 	cs.CaseID = nextID
 	now := time.Now()
 	cs.CreatedAt = now
 	cs.UpdatedAt = now
+	cs.Revision = 1
 
-	_, err = r.Client.Collection("cases").Doc(cs.CaseID).Set(ctx, cs)
+	// Create (not Set) fails instead of silently overwriting an existing document
+	_, err = r.Client.Collection("cases").Doc(cs.CaseID).Create(ctx, cs)
 	return err
 }
 
 // 🟢 UpdateCaseByID
 func (r *CaseRepo) UpdateCaseByID(caseID string, data map[string]interface{}) error {
 	ctx := context.Background()
-	data["updated_at"] = time.Now()
+	caseData(data)
+	data["revision"] = firestore.Increment(1)
 	_, err := r.Client.Collection("cases").Doc(caseID).Set(ctx, data, firestore.MergeAll)
 	return err
 }
 
-// 🟢 UpdateCaseStatusByID
-func (r *CaseRepo) UpdateCaseStatusByID(caseID string, status string) error {
+// 🟢 UpdateCaseIfRevision - the revision is compared inside a transaction
+func (r *CaseRepo) UpdateCaseIfRevision(caseID string, revision int64, data map[string]interface{}) error {
 	ctx := context.Background()
-	_, err := r.Client.Collection("cases").Doc(caseID).Set(ctx, map[string]interface{}{"status": status}, firestore.MergeAll)
-	return err
-}
\ No newline at end of file
+	ref := r.Client.Collection("cases").Doc(caseID)
+	caseData(data)
+	return r.Client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(ref)
+		if status.Code(err) == codes.NotFound {
+			return fmt.Errorf("case %s: %w", caseID, ErrNotFound)
+		}
+		if err != nil {
+			return err
+		}
+		cs, err := caseFromDoc(doc)
+		if err != nil {
+			return err
+		}
+		if cs.IsDeleted() {
+			return fmt.Errorf("case %s: %w", caseID, ErrNotFound)
+		}
+		if cs.Revision != revision {
+			return fmt.Errorf("case %s is at revision %d: %w", caseID, cs.Revision, ErrStale)
+		}
+		data["revision"] = revision + 1
+		return tx.Set(ref, data, firestore.MergeAll)
+	})
+}
+
+// caseData prepares the data of an update
+func caseData(data map[string]interface{}) {
+	// trl_score is stored as "tr_score" (see models.CaseInfo)
+	if v, ok := data["trl_score"]; ok {
+		delete(data, "trl_score")
+		data["tr_score"] = v
+	}
+	data["updated_at"] = time.Now()
+}
+
+// storedCase reads "status" untyped: documents written before the case workflow hold a bool
+// or a free-text string there (see internal/script/migrate_case_status.go)
+type storedCase struct {
+	models.CaseInfo
+	Status interface{} `firestore:"status"`
+}
+
+// caseValue is caseFromDoc for listDocs
+func caseValue(doc *firestore.DocumentSnapshot) (models.CaseInfo, error) {
+	cs, err := caseFromDoc(doc)
+	if err != nil {
+		return models.CaseInfo{}, err
+	}
+	return *cs, nil
+}
+
+func caseFromDoc(doc *firestore.DocumentSnapshot) (*models.CaseInfo, error) {
+	var sc storedCase
+	err := doc.DataTo(&sc)
+	cs := sc.CaseInfo
+	cs.Status, _ = models.ParseLegacyCaseStatus(sc.Status)
+	return &cs, err
+}
+
+// 🟢 TransitionCaseStatus - compare-and-swap of the status inside a transaction
+func (r *CaseRepo) TransitionCaseStatus(t *models.CaseStatusTransition) error {
+	ctx := context.Background()
+	ref := r.Client.Collection("cases").Doc(t.CaseID)
+
+	return r.Client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(ref)
+		if status.Code(err) == codes.NotFound {
+			return fmt.Errorf("case %s: %w", t.CaseID, ErrNotFound)
+		}
+		if err != nil {
+			return err
+		}
+		cs, err := caseFromDoc(doc)
+		if err != nil {
+			return err
+		}
+		if cs.IsDeleted() {
+			return fmt.Errorf("case %s: %w", t.CaseID, ErrNotFound)
+		}
+		if cs.Status != t.From {
+			return fmt.Errorf("case %s is %s: %w", t.CaseID, cs.Status, ErrConflict)
+		}
+
+		t.ID = uuid.NewString()
+		t.CreatedAt = time.Now()
+		if err := tx.Update(ref, []firestore.Update{
+			{Path: "status", Value: t.To},
+			{Path: "status_reason", Value: t.Reason},
+			{Path: "status_changed_at", Value: t.CreatedAt},
+			{FieldPath: firestore.FieldPath{"status_timestamps", string(t.To)}, Value: t.CreatedAt},
+			{Path: "updated_at", Value: t.CreatedAt},
+			{Path: "revision", Value: firestore.Increment(1)},
+		}); err != nil {
+			return err
+		}
+		return tx.Create(r.Client.Collection("case_status_history").Doc(t.ID), t)
+	})
+}
+
+// 🟢 GetCaseStatusHistory
+func (r *CaseRepo) GetCaseStatusHistory(caseID string) ([]models.CaseStatusTransition, error) {
+	ctx := context.Background()
+	docs, err := r.Client.Collection("case_status_history").
+		Where("case_id", "==", caseID).
+		Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var history []models.CaseStatusTransition
+	for _, doc := range docs {
+		var t models.CaseStatusTransition
+		doc.DataTo(&t)
+		history = append(history, t)
+	}
+	// sorted here instead of OrderBy so no composite index is needed
+	sort.Slice(history, func(i, j int) bool { return history[i].CreatedAt.Before(history[j].CreatedAt) })
+	return history, nil
+}
+
+// caseRecords - the collections whose documents belong to a case by case_id and go to the
+// trash with it
+var caseRecords = []string{"appointments", "intellectual_properties", "supporters", "assessment_trl"}
+
+// 🟢 DeleteCase - soft delete, with the records of the case in the same transaction
+func (r *CaseRepo) DeleteCase(caseID, deletedBy string) error {
+	d := models.Deletion{DeletedAt: time.Now(), DeletedBy: deletedBy}
+	return r.setCaseDeletion(caseID, d, func(_, record models.Deletion) bool { return !record.IsDeleted() })
+}
+
+// 🟢 RestoreCase - with the records deleted together with it
+func (r *CaseRepo) RestoreCase(caseID string) error {
+	return r.setCaseDeletion(caseID, models.Deletion{}, func(cs, record models.Deletion) bool { return record.DeletedWith(cs) })
+}
+
+// setCaseDeletion sets d on the case and on those of its records that match, given the
+// deletion the case had before
+func (r *CaseRepo) setCaseDeletion(caseID string, d models.Deletion, match func(cs, record models.Deletion) bool) error {
+	ctx := context.Background()
+	ref := r.Client.Collection("cases").Doc(caseID)
+	return r.Client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(ref)
+		if status.Code(err) == codes.NotFound {
+			return notFoundIn("case", caseID, d)
+		}
+		if err != nil {
+			return err
+		}
+		was, err := deletionOf(doc)
+		if err != nil {
+			return err
+		}
+		if was.IsDeleted() == d.IsDeleted() {
+			return notFoundIn("case", caseID, d)
+		}
+
+		// a transaction reads everything before it writes
+		refs := []*firestore.DocumentRef{ref}
+		for _, col := range caseRecords {
+			docs, err := tx.Documents(r.Client.Collection(col).Where("case_id", "==", caseID)).GetAll()
+			if err != nil {
+				return err
+			}
+			for _, doc := range docs {
+				record, err := deletionOf(doc)
+				if err != nil {
+					return err
+				}
+				if match(was, record) {
+					refs = append(refs, doc.Ref)
+				}
+			}
+		}
+		for _, ref := range refs {
+			if err := tx.Update(ref, deletionUpdates(d)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// 🟢 PurgeCases - with whatever still refers to them, like ON DELETE CASCADE in PostgreSQL
+func (r *CaseRepo) PurgeCases(cutoff time.Time) ([]string, error) {
+	ctx := context.Background()
+	docs, err := deletedBefore(r.Client.Collection("cases"), cutoff)
+	if err != nil {
+		return nil, err
+	}
+	ids := []string{}
+	for _, doc := range docs {
+		for _, col := range append([]string{"case_status_history"}, caseRecords...) {
+			records, err := r.Client.Collection(col).Where("case_id", "==", doc.Ref.ID).Documents(ctx).GetAll()
+			if err != nil {
+				return ids, err
+			}
+			for _, record := range records {
+				if _, err := record.Ref.Delete(ctx); err != nil {
+					return ids, err
+				}
+			}
+		}
+		if _, err := doc.Ref.Delete(ctx); err != nil {
+			return ids, err
+		}
+		ids = append(ids, doc.Ref.ID)
+	}
+	return ids, nil
+}
@@ -2,9 +2,14 @@ package repository
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"cloud.google.com/go/firestore"
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"trl-research-backend/internal/listing"
 	"trl-research-backend/internal/models"
 )
 
@@ -16,6 +21,18 @@ func NewCoordinatorRepo(client *firestore.Client) *CoordinatorRepo {
 	return &CoordinatorRepo{Client: client}
 }
 
+// 🟢 Login with password verification; a coordinator without a password can't sign in
+func (r *CoordinatorRepo) Login(email string, password string) (*models.CoordinatorInfo, error) {
+	coordinator, err := r.GetCoordinatorByEmail(email)
+	if err != nil {
+		return nil, fmt.Errorf("coordinator not found")
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(coordinator.CoordinatorPassword), []byte(password)); err != nil {
+		return nil, fmt.Errorf("invalid password")
+	}
+	return coordinator, nil
+}
+
 // 🟢 GetCoordinatorAll - fetch all coordinators
 func (r *CoordinatorRepo) GetCoordinatorAll() ([]models.CoordinatorInfo, error) {
 	ctx := context.Background()
@@ -30,7 +47,12 @@ func (r *CoordinatorRepo) GetCoordinatorAll() ([]models.CoordinatorInfo, error)
 		doc.DataTo(&coordinator)
 		coordinators = append(coordinators, coordinator)
 	}
-	return coordinators, nil
+	return live(coordinators), nil
+}
+
+// 🟢 ListCoordinators - one page of coordinators for GET with list parameters
+func (r *CoordinatorRepo) ListCoordinators(q listing.Query) (*listing.Page[models.CoordinatorInfo], error) {
+	return listDocs(r.Client.Collection("coordinators"), q, dataTo[models.CoordinatorInfo])
 }
 
 // 🟢 GetCoordinatorByEmail - use email as document ID
@@ -43,19 +65,27 @@ func (r *CoordinatorRepo) GetCoordinatorByEmail(email string) (*models.Coordinat
 
 	var coordinator models.CoordinatorInfo
 	doc.DataTo(&coordinator)
+	if coordinator.IsDeleted() {
+		return nil, fmt.Errorf("coordinator %s: %w", email, ErrNotFound)
+	}
 	return &coordinator, nil
 }
 
 // 🟢 GetCoordinatorByCaseID
 func (r *CoordinatorRepo) GetCoordinatorByCaseID(caseID string) (*models.CoordinatorInfo, error) {
 	ctx := context.Background()
-	doc, err := r.Client.Collection("coordinators").Where("case_id", "==", caseID).Documents(ctx).GetAll()
+	docs, err := r.Client.Collection("coordinators").Where("case_id", "==", caseID).Documents(ctx).GetAll()
 	if err != nil {
 		return nil, err
 	}
 
-	var coordinator models.CoordinatorInfo
-	doc[0].DataTo(&coordinator)
+	coordinator, ok, err := firstLive(docs, dataTo[models.CoordinatorInfo])
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("coordinator for case %s: %w", caseID, ErrNotFound)
+	}
 	return &coordinator, nil
 }
 
@@ -77,3 +107,31 @@ func (r *CoordinatorRepo) UpdateCoordinatorByEmail(email string, data map[string
 	_, err := r.Client.Collection("coordinators").Doc(email).Set(ctx, data, firestore.MergeAll)
 	return err
 }
+
+// 🟢 UpdateCoordinatorPasswordByEmail - password must already be a bcrypt hash
+func (r *CoordinatorRepo) UpdateCoordinatorPasswordByEmail(email string, password string) error {
+	ctx := context.Background()
+	_, err := r.Client.Collection("coordinators").Doc(email).Update(ctx, []firestore.Update{
+		{Path: "coordinator_password", Value: password},
+		{Path: "updated_at", Value: time.Now()},
+	})
+	if status.Code(err) == codes.NotFound {
+		return fmt.Errorf("coordinator %s: %w", email, ErrNotFound)
+	}
+	return err
+}
+
+// 🟢 DeleteCoordinator - soft delete
+func (r *CoordinatorRepo) DeleteCoordinator(email, deletedBy string) error {
+	return trash(r.Client, "coordinators", "coordinator", email, deletedBy)
+}
+
+// 🟢 RestoreCoordinator
+func (r *CoordinatorRepo) RestoreCoordinator(email string) error {
+	return restore(r.Client, "coordinators", "coordinator", email)
+}
+
+// 🟢 PurgeCoordinators
+func (r *CoordinatorRepo) PurgeCoordinators(cutoff time.Time) ([]string, error) {
+	return purge(r.Client.Collection("coordinators"), "", cutoff, nil)
+}
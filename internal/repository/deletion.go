@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"trl-research-backend/internal/models"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// deletable - the models embedding models.Deletion
+type deletable interface {
+	IsDeleted() bool
+	DeletedWith(d models.Deletion) bool
+}
+
+// live - the records that aren't deleted
+func live[T deletable](items []T) []T {
+	out := items[:0]
+	for _, item := range items {
+		if !item.IsDeleted() {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// firstLive - the first of docs that isn't deleted; false when there is none
+func firstLive[T deletable](docs []*firestore.DocumentSnapshot, decode func(*firestore.DocumentSnapshot) (T, error)) (T, bool, error) {
+	for _, doc := range docs {
+		item, err := decode(doc)
+		if err != nil {
+			return item, false, err
+		}
+		if !item.IsDeleted() {
+			return item, true, nil
+		}
+	}
+	var zero T
+	return zero, false, nil
+}
+
+// deletionOf reads the deletion of a document, without decoding the rest
+func deletionOf(doc *firestore.DocumentSnapshot) (models.Deletion, error) {
+	var d struct{ models.Deletion }
+	err := doc.DataTo(&d)
+	return d.Deletion, err
+}
+
+// deletionUpdates - the fields a delete (d set) or restore (d zero) writes. A restore removes
+// them, live documents don't have them, so "deleted_at < cutoff" only finds deleted ones. Both
+// set updated_at, for what reads the changes since a time (the search index sync).
+func deletionUpdates(d models.Deletion) []firestore.Update {
+	if !d.IsDeleted() {
+		return []firestore.Update{
+			{Path: "deleted_at", Value: firestore.Delete},
+			{Path: "deleted_by", Value: firestore.Delete},
+			{Path: "updated_at", Value: time.Now()},
+		}
+	}
+	return []firestore.Update{
+		{Path: "deleted_at", Value: d.DeletedAt},
+		{Path: "deleted_by", Value: d.DeletedBy},
+		{Path: "updated_at", Value: d.DeletedAt},
+	}
+}
+
+// setDeletion moves a document to the trash (d set) or back (d zero) inside a transaction;
+// ErrNotFound unless it is live / deleted to begin with
+func setDeletion(client *firestore.Client, ref *firestore.DocumentRef, kind string, d models.Deletion) error {
+	return client.RunTransaction(context.Background(), func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(ref)
+		if status.Code(err) == codes.NotFound {
+			return notFoundIn(kind, ref.ID, d)
+		}
+		if err != nil {
+			return err
+		}
+		current, err := deletionOf(doc)
+		if err != nil {
+			return err
+		}
+		if current.IsDeleted() == d.IsDeleted() {
+			return notFoundIn(kind, ref.ID, d)
+		}
+		return tx.Update(ref, deletionUpdates(d))
+	})
+}
+
+// trash / restore - DeleteX and RestoreX of a collection keyed by the record ID
+func trash(client *firestore.Client, col, kind, id, deletedBy string) error {
+	d := models.Deletion{DeletedAt: time.Now(), DeletedBy: deletedBy}
+	return setDeletion(client, client.Collection(col).Doc(id), kind, d)
+}
+
+func restore(client *firestore.Client, col, kind, id string) error {
+	return setDeletion(client, client.Collection(col).Doc(id), kind, models.Deletion{})
+}
+
+func notFoundIn(kind, id string, d models.Deletion) error {
+	if !d.IsDeleted() {
+		kind = "deleted " + kind
+	}
+	return fmt.Errorf("%s %s: %w", kind, id, ErrNotFound)
+}
+
+// deletedBefore - the documents of col deleted before cutoff
+func deletedBefore(col *firestore.CollectionRef, cutoff time.Time) ([]*firestore.DocumentSnapshot, error) {
+	return col.Where("deleted_at", "<", cutoff).Documents(context.Background()).GetAll()
+}
+
+// purge removes the documents of col deleted before cutoff that keep (if any) doesn't hold on to.
+// The IDs returned are the document IDs, or the idField of the documents when it is given.
+func purge(col *firestore.CollectionRef, idField string, cutoff time.Time, keep func(*firestore.DocumentSnapshot) (bool, error)) ([]string, error) {
+	docs, err := deletedBefore(col, cutoff)
+	if err != nil {
+		return nil, err
+	}
+	ids := []string{}
+	for _, doc := range docs {
+		if keep != nil {
+			kept, err := keep(doc)
+			if err != nil {
+				return ids, err
+			}
+			if kept {
+				continue
+			}
+		}
+		if _, err := doc.Ref.Delete(context.Background()); err != nil {
+			return ids, err
+		}
+		ids = append(ids, docID(doc, idField))
+	}
+	return ids, nil
+}
+
+// docID - the idField of a document, its document ID without one
+func docID(doc *firestore.DocumentSnapshot, idField string) string {
+	if id, ok := doc.Data()[idField].(string); ok && id != "" {
+		return id
+	}
+	return doc.Ref.ID
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"trl-research-backend/internal/models"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type DraftRepo struct {
+	Client *firestore.Client
+	Seq    SequenceGenerator
+}
+
+func NewDraftRepo(client *firestore.Client) *DraftRepo {
+	return &DraftRepo{Client: client, Seq: NewFirestoreSequence(client)}
+}
+
+func (r *DraftRepo) col() *firestore.CollectionRef {
+	return r.Client.Collection("drafts")
+}
+
+// liveDraft reads a draft document; ErrNotFound when it is missing or expired
+func liveDraft(doc *firestore.DocumentSnapshot, err error, draftID string) (*models.Draft, error) {
+	if status.Code(err) == codes.NotFound {
+		return nil, fmt.Errorf("draft %s: %w", draftID, ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var d models.Draft
+	if err := doc.DataTo(&d); err != nil {
+		return nil, err
+	}
+	if d.Expired(time.Now()) {
+		return nil, fmt.Errorf("draft %s: %w", draftID, ErrNotFound)
+	}
+	return &d, nil
+}
+
+// 🟢 CreateDraft - auto generate ID DR-00001
+func (r *DraftRepo) CreateDraft(d *models.Draft) error {
+	id, err := r.Seq.Next("DR")
+	if err != nil {
+		return err
+	}
+	d.ID = id
+	now := time.Now()
+	d.CreatedAt = now
+	d.UpdatedAt = now
+	_, err = r.col().Doc(d.ID).Create(context.Background(), d)
+	return err
+}
+
+// 🟢 GetDraftByID
+func (r *DraftRepo) GetDraftByID(draftID string) (*models.Draft, error) {
+	doc, err := r.col().Doc(draftID).Get(context.Background())
+	return liveDraft(doc, err, draftID)
+}
+
+// 🟢 GetDraftsByResearcherID - expired drafts are dropped and the rest sorted here, so the
+// query needs no composite index
+func (r *DraftRepo) GetDraftsByResearcherID(researcherID string) ([]models.Draft, error) {
+	docs, err := r.col().Where("researcher_id", "==", researcherID).Documents(context.Background()).GetAll()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	drafts := []models.Draft{}
+	for _, doc := range docs {
+		var d models.Draft
+		if err := doc.DataTo(&d); err != nil {
+			return nil, err
+		}
+		if !d.Expired(now) {
+			drafts = append(drafts, d)
+		}
+	}
+	sort.SliceStable(drafts, func(i, j int) bool { return drafts[i].UpdatedAt.After(drafts[j].UpdatedAt) })
+	return drafts, nil
+}
+
+// 🟢 SaveDraft
+func (r *DraftRepo) SaveDraft(d *models.Draft) error {
+	ref := r.col().Doc(d.ID)
+	return r.Client.RunTransaction(context.Background(), func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(ref)
+		stored, err := liveDraft(doc, err, d.ID)
+		if err != nil {
+			return err
+		}
+		d.ResearcherID, d.CreatedAt = stored.ResearcherID, stored.CreatedAt
+		d.UpdatedAt = time.Now()
+		return tx.Set(ref, d)
+	})
+}
+
+// 🟢 DeleteDraft
+func (r *DraftRepo) DeleteDraft(draftID string) error {
+	if _, err := r.GetDraftByID(draftID); err != nil {
+		return err
+	}
+	_, err := r.col().Doc(draftID).Delete(context.Background())
+	return err
+}
+
+// 🟢 PurgeDrafts
+func (r *DraftRepo) PurgeDrafts(now time.Time) ([]string, error) {
+	docs, err := r.col().Where("expires_at", "<=", now).Documents(context.Background()).GetAll()
+	if err != nil {
+		return nil, err
+	}
+	ids := []string{}
+	for _, doc := range docs {
+		if _, err := doc.Ref.Delete(context.Background()); err != nil {
+			return ids, err
+		}
+		ids = append(ids, doc.Ref.ID)
+	}
+	return ids, nil
+}
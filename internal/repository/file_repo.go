@@ -2,6 +2,7 @@ package repository
 
 import (
     "context"
+    "sort"
     "trl-research-backend/internal/models"
 
     "cloud.google.com/go/firestore"
@@ -36,3 +37,21 @@ func (r *FileRepo) GetFileByID(ctx context.Context, fileID string) (*models.File
 
 	return &file, nil
 }
+
+// 🟢 GetFilesByCaseID - sorted here, an order on uploaded_at would need a composite index
+func (r *FileRepo) GetFilesByCaseID(ctx context.Context, caseID string) ([]models.FileMetadata, error) {
+	docs, err := r.client.Collection("files").Where("belongs_to_case_id", "==", caseID).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+	files := make([]models.FileMetadata, 0, len(docs))
+	for _, doc := range docs {
+		var file models.FileMetadata
+		if err := doc.DataTo(&file); err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	sort.SliceStable(files, func(i, j int) bool { return files[i].UploadedAt.Before(files[j].UploadedAt) })
+	return files, nil
+}
@@ -3,20 +3,20 @@ package repository
 import (
 	"context"
 	"fmt"
-	"strconv"
-	"strings"
 	"time"
 
 	"cloud.google.com/go/firestore"
+	"trl-research-backend/internal/listing"
 	"trl-research-backend/internal/models"
 )
 
 type IntellectualPropertyRepo struct {
 	Client *firestore.Client
+	Seq    SequenceGenerator
 }
 
 func NewIntellectualPropertyRepo(client *firestore.Client) *IntellectualPropertyRepo {
-	return &IntellectualPropertyRepo{Client: client}
+	return &IntellectualPropertyRepo{Client: client, Seq: NewFirestoreSequence(client)}
 }
 
 // 🟢 GetIPAll - fetch all intellectual property records
@@ -33,7 +33,12 @@ func (r *IntellectualPropertyRepo) GetIPAll() ([]models.IntellectualProperty, er
 		doc.DataTo(&ip)
 		ips = append(ips, ip)
 	}
-	return ips, nil
+	return live(ips), nil
+}
+
+// 🟢 ListIPs - one page of intellectual_properties for GET with list parameters
+func (r *IntellectualPropertyRepo) ListIPs(q listing.Query) (*listing.Page[models.IntellectualProperty], error) {
+	return listDocs(r.Client.Collection("intellectual_properties"), q, dataTo[models.IntellectualProperty])
 }
 
 // 🟢 GetIPByID
@@ -46,19 +51,27 @@ func (r *IntellectualPropertyRepo) GetIPByID(ipID string) (*models.IntellectualP
 
 	var ip models.IntellectualProperty
 	doc.DataTo(&ip)
+	if ip.IsDeleted() {
+		return nil, fmt.Errorf("intellectual property %s: %w", ipID, ErrNotFound)
+	}
 	return &ip, nil
 }
 
 // 🟢 GetIPByCaseID
 func (r *IntellectualPropertyRepo) GetIPByCaseID(caseID string) (*models.IntellectualProperty, error) {
 	ctx := context.Background()
-	doc, err := r.Client.Collection("intellectual_properties").Where("case_id", "==", caseID).Documents(ctx).GetAll()
+	docs, err := r.Client.Collection("intellectual_properties").Where("case_id", "==", caseID).Documents(ctx).GetAll()
 	if err != nil {
 		return nil, err
 	}
 
-	var ip models.IntellectualProperty
-	doc[0].DataTo(&ip)
+	ip, ok, err := firstLive(docs, dataTo[models.IntellectualProperty])
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("intellectual property for case %s: %w", caseID, ErrNotFound)
+	}
 	return &ip, nil
 }
 
@@ -66,14 +79,9 @@ func (r *IntellectualPropertyRepo) GetIPByCaseID(caseID string) (*models.Intelle
 func (r *IntellectualPropertyRepo) CreateIP(ip *models.IntellectualProperty) error {
 	ctx := context.Background()
 
-	docs, err := r.Client.Collection("intellectual_properties").OrderBy("id", firestore.Desc).Limit(1).Documents(ctx).GetAll()
-	nextID := "IP-00001"
-	if err == nil && len(docs) > 0 {
-		lastID := docs[0].Data()["id"].(string)
-		numStr := strings.TrimPrefix(lastID, "IP-")
-		if n, err := strconv.Atoi(numStr); err == nil {
-			nextID = fmt.Sprintf("IP-%05d", n+1)
-		}
+	nextID, err := r.Seq.Next("IP")
+	if err != nil {
+		return err
 	}
 
 	ip.ID = nextID
@@ -81,7 +89,8 @@ func (r *IntellectualPropertyRepo) CreateIP(ip *models.IntellectualProperty) err
 	ip.CreatedAt = now
 	ip.UpdatedAt = now
 
-	_, err = r.Client.Collection("intellectual_properties").Doc(ip.ID).Set(ctx, ip)
+	// Create (not Set) fails instead of silently overwriting an existing document
+	_, err = r.Client.Collection("intellectual_properties").Doc(ip.ID).Create(ctx, ip)
 	return err
 }
 
@@ -92,3 +101,18 @@ func (r *IntellectualPropertyRepo) UpdateIPByID(ipID string, data map[string]int
 	_, err := r.Client.Collection("intellectual_properties").Doc(ipID).Set(ctx, data, firestore.MergeAll)
 	return err
 }
+
+// 🟢 DeleteIP - soft delete
+func (r *IntellectualPropertyRepo) DeleteIP(ipID, deletedBy string) error {
+	return trash(r.Client, "intellectual_properties", "intellectual property", ipID, deletedBy)
+}
+
+// 🟢 RestoreIP
+func (r *IntellectualPropertyRepo) RestoreIP(ipID string) error {
+	return restore(r.Client, "intellectual_properties", "intellectual property", ipID)
+}
+
+// 🟢 PurgeIPs
+func (r *IntellectualPropertyRepo) PurgeIPs(cutoff time.Time) ([]string, error) {
+	return purge(r.Client.Collection("intellectual_properties"), "", cutoff, nil)
+}
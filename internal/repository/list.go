@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"slices"
+
+	"cloud.google.com/go/firestore"
+	pb "cloud.google.com/go/firestore/apiv1/firestorepb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"trl-research-backend/internal/listing"
+)
+
+// listDocs pages a collection for a list request in Firestore: the query orders by the sort
+// field and the key, starts after the cursor and reads one item more than the page to know
+// whether there is a next one. total_estimate is an aggregation count of the filters Firestore
+// runs.
+//
+// What Firestore can't answer from the stored fields is checked in Go on every document read
+// (listing.Query.Matches), reading on in batches until the page is full:
+//   - equality on false / "" / 0, Firestore doesn't match documents that lack the field while
+//     the decoded model has the zero value
+//   - ranges on another field than the sort, they would need an index per pair of fields
+//   - deleted_at, live documents don't have it
+//   - inGo, the fields whose stored value the decoder rewrites (e.g. legacy formats)
+//
+// The total then counts a bit more than the filters match (deleted records too), it is an
+// estimate. Equality filters together with a sort need a composite index; until it exists
+// Firestore answers FailedPrecondition with a link to create it, and the page is made in Go
+// from the whole collection like before.
+func listDocs[T any](col *firestore.CollectionRef, q listing.Query, decode func(*firestore.DocumentSnapshot) (T, error), inGo ...string) (*listing.Page[T], error) {
+	ctx := context.Background()
+
+	filtered := col.Query
+	residual := q.Spec.SoftDelete && q.Deleted != listing.IncludeDeleted
+	for _, f := range q.Filters {
+		switch {
+		case slices.Contains(inGo, f.Field), f.Value == false, f.Value == "", f.Value == 0:
+			residual = true
+		case f.Op == listing.Eq:
+			filtered = filtered.Where(f.Field, "==", f.Value)
+		case f.Field != q.Sort:
+			residual = true
+		case f.Op == listing.From:
+			filtered = filtered.Where(f.Field, ">=", f.Value)
+		case f.Op == listing.To:
+			filtered = filtered.Where(f.Field, "<", f.Value)
+		}
+	}
+
+	dir := firestore.Asc
+	if q.Desc {
+		dir = firestore.Desc
+	}
+	ordered := filtered.OrderBy(q.Sort, dir)
+	if q.Sort != q.Spec.Key {
+		ordered = ordered.OrderBy(q.Spec.Key, dir)
+	}
+	if q.After != nil {
+		if q.Sort == q.Spec.Key {
+			ordered = ordered.StartAfter(q.After.Key)
+		} else {
+			ordered = ordered.StartAfter(q.After.Value, q.After.Key)
+		}
+	}
+
+	// one more than the page; more per read when some documents are dropped in Go
+	batch := q.Limit + 1
+	if q.Limit == 0 || residual {
+		batch = max(batch, 200)
+	}
+
+	page := &listing.Page[T]{Items: []T{}}
+	for query := ordered; ; {
+		docs, err := query.Limit(batch).Documents(ctx).GetAll()
+		if status.Code(err) == codes.FailedPrecondition {
+			log.Printf("❌ [listDocs] %s: %v, paging in Go until the index exists", col.ID, err)
+			return listAllDocs(ctx, col, q, decode)
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, doc := range docs {
+			item, err := decode(doc)
+			if err != nil {
+				return nil, err
+			}
+			if !q.Matches(item) {
+				continue
+			}
+			if q.Limit > 0 && len(page.Items) == q.Limit {
+				page.NextPageToken = q.NextToken(page.Items[len(page.Items)-1])
+				break
+			}
+			page.Items = append(page.Items, item)
+		}
+		if page.NextPageToken != "" || len(docs) < batch {
+			break
+		}
+		query = ordered.StartAfter(docs[len(docs)-1])
+	}
+
+	n, err := countDocs(ctx, filtered)
+	if err != nil {
+		return nil, err
+	}
+	page.TotalEstimate = max(n, len(page.Items))
+	return page, nil
+}
+
+// countDocs - the documents of query, by an aggregation that doesn't read them
+func countDocs(ctx context.Context, query firestore.Query) (int, error) {
+	res, err := query.NewAggregationQuery().WithCount("count").Get(ctx)
+	if err != nil {
+		return 0, err
+	}
+	v, _ := res["count"].(*pb.Value)
+	return int(v.GetIntegerValue()), nil
+}
+
+// listAllDocs pages the whole collection in Go, for queries that wait for their index
+func listAllDocs[T any](ctx context.Context, col *firestore.CollectionRef, q listing.Query, decode func(*firestore.DocumentSnapshot) (T, error)) (*listing.Page[T], error) {
+	docs, err := col.Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+	items := make([]T, 0, len(docs))
+	for _, doc := range docs {
+		item, err := decode(doc)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return listing.Apply(items, q), nil
+}
+
+// dataTo decodes a document with its firestore struct tags
+func dataTo[T any](doc *firestore.DocumentSnapshot) (T, error) {
+	var item T
+	err := doc.DataTo(&item)
+	return item, err
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	"trl-research-backend/internal/listing"
+)
+
+var testCaseList = &listing.Spec{Key: "case_id", SoftDelete: true, Fields: map[string]listing.Field{
+	"is_urgent":  {Kind: listing.Bool, Filter: true},
+	"case_type":  {Kind: listing.String, Filter: true},
+	"created_at": {Kind: listing.Time, Filter: true, Sort: true},
+}}
+
+// pages walks every page of the request and returns the case IDs in order
+func pages(t *testing.T, repo *CaseRepo, params string) ([]string, int) {
+	t.Helper()
+	values, _ := url.ParseQuery(params)
+	var ids []string
+	total := -1
+	for {
+		q, err := listing.Parse(values, testCaseList)
+		if err != nil {
+			t.Fatal(err)
+		}
+		page, err := repo.ListCases(q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total == -1 {
+			total = page.TotalEstimate
+		}
+		for _, cs := range page.Items {
+			ids = append(ids, cs.CaseID)
+		}
+		if page.NextPageToken == "" {
+			return ids, total
+		}
+		values.Set("page_token", page.NextPageToken)
+	}
+}
+
+func TestListDocsPagesInFirestore(t *testing.T) {
+	client := emulatorClient(t)
+	ctx := context.Background()
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 1; i <= 7; i++ {
+		data := map[string]interface{}{
+			"case_id":    fmt.Sprintf("CS-%05d", i),
+			"case_type":  "Software",
+			"created_at": start.Add(time.Duration(8-i) * time.Hour), // newest first is CS-00001 first
+		}
+		if i%2 == 0 {
+			data["is_urgent"] = true
+		}
+		if i == 5 {
+			data["deleted_at"] = start
+		}
+		if _, err := client.Collection("cases").Doc(data["case_id"].(string)).Set(ctx, data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	repo := &CaseRepo{Client: client}
+
+	tests := []struct {
+		params string
+		want   []string
+	}{
+		{"limit=2", []string{"CS-00001", "CS-00002", "CS-00003", "CS-00004", "CS-00006", "CS-00007"}},
+		{"limit=2&sort=-created_at", []string{"CS-00001", "CS-00002", "CS-00003", "CS-00004", "CS-00006", "CS-00007"}},
+		{"limit=2&sort=created_at", []string{"CS-00007", "CS-00006", "CS-00004", "CS-00003", "CS-00002", "CS-00001"}},
+		{"limit=2&is_urgent=true", []string{"CS-00002", "CS-00004", "CS-00006"}},
+		{"limit=2&is_urgent=false", []string{"CS-00001", "CS-00003", "CS-00007"}}, // no is_urgent field at all
+		{"limit=3&deleted=only", []string{"CS-00005"}},
+		{"limit=1&sort=created_at&created_at_from=2025-01-01T03:00:00Z&case_type=Software", []string{"CS-00004", "CS-00003", "CS-00002", "CS-00001"}},
+	}
+	for _, tt := range tests {
+		got, total := pages(t, repo, tt.params)
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.params, got, tt.want)
+		}
+		if total < len(tt.want) {
+			t.Errorf("%s: total_estimate %d below the %d items", tt.params, total, len(tt.want))
+		}
+	}
+}
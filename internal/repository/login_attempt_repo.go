@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"trl-research-backend/internal/models"
+)
+
+type LoginAttemptRepo struct {
+	Client *firestore.Client
+}
+
+func NewLoginAttemptRepo(client *firestore.Client) *LoginAttemptRepo {
+	return &LoginAttemptRepo{Client: client}
+}
+
+// 🟢 GetLoginThrottle - the throttle key is the document ID
+func (r *LoginAttemptRepo) GetLoginThrottle(key string) (*models.LoginThrottle, error) {
+	ctx := context.Background()
+	doc, err := r.Client.Collection("login_throttles").Doc(key).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return nil, fmt.Errorf("login throttle %s: %w", key, ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var t models.LoginThrottle
+	if err := doc.DataTo(&t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// 🟢 RecordLoginFailure - read-increment-write in a transaction so parallel attempts all count
+func (r *LoginAttemptRepo) RecordLoginFailure(key string, resetAfter time.Duration) (*models.LoginThrottle, error) {
+	ctx := context.Background()
+	ref := r.Client.Collection("login_throttles").Doc(key)
+
+	var t models.LoginThrottle
+	err := r.Client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		now := time.Now()
+		t = models.LoginThrottle{Key: key}
+		doc, err := tx.Get(ref)
+		switch {
+		case status.Code(err) == codes.NotFound:
+		case err != nil:
+			return err
+		default:
+			if err := doc.DataTo(&t); err != nil {
+				return err
+			}
+		}
+		if now.Sub(t.LastFailureAt) > resetAfter {
+			t.Failures = 0
+		}
+		t.Failures++
+		t.LastFailureAt = now
+		return tx.Set(ref, t)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// 🟢 ClearLoginFailures
+func (r *LoginAttemptRepo) ClearLoginFailures(key string) error {
+	ctx := context.Background()
+	_, err := r.Client.Collection("login_throttles").Doc(key).Delete(ctx)
+	return err
+}
+
+// 🟢 CreateLoginAudit
+func (r *LoginAttemptRepo) CreateLoginAudit(audit *models.LoginAudit) error {
+	ctx := context.Background()
+	audit.ID = uuid.NewString()
+	audit.CreatedAt = time.Now()
+	_, err := r.Client.Collection("login_audit").Doc(audit.ID).Create(ctx, audit)
+	return err
+}
+
+// 🟢 GetLoginAuditsByEmail - sorted here so no composite index is needed
+func (r *LoginAttemptRepo) GetLoginAuditsByEmail(email string, limit int) ([]models.LoginAudit, error) {
+	ctx := context.Background()
+	docs, err := r.Client.Collection("login_audit").Where("email", "==", email).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var audits []models.LoginAudit
+	for _, doc := range docs {
+		var a models.LoginAudit
+		doc.DataTo(&a)
+		audits = append(audits, a)
+	}
+	sort.Slice(audits, func(i, j int) bool { return audits[i].CreatedAt.After(audits[j].CreatedAt) })
+	if len(audits) > limit {
+		audits = audits[:limit]
+	}
+	return audits, nil
+}
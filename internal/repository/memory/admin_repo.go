@@ -0,0 +1,130 @@
+package memory
+
+import (
+	"fmt"
+	"time"
+
+	"trl-research-backend/internal/listing"
+	"trl-research-backend/internal/models"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+type AdminRepo struct {
+	store *Store
+}
+
+// 🟢 Get all admins
+func (r *AdminRepo) GetAdminAll() ([]models.AdminInfo, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	return live(r.store.admins), nil
+}
+
+// 🟢 ListAdmins
+func (r *AdminRepo) ListAdmins(q listing.Query) (*listing.Page[models.AdminInfo], error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	return listing.Apply(sortedValues(r.store.admins), q), nil
+}
+
+// 🟢 Get admin by ID
+func (r *AdminRepo) GetAdminByID(adminID string) (*models.AdminInfo, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	for _, admin := range r.store.admins {
+		if admin.AdminID == adminID && !admin.IsDeleted() {
+			return &admin, nil
+		}
+	}
+	return nil, notFound("admin", adminID)
+}
+
+// 🟢 Get admin by email
+func (r *AdminRepo) GetAdminByEmail(email string) (*models.AdminInfo, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	admin, ok := liveByKey(r.store.admins, email)
+	if !ok {
+		return nil, notFound("admin", email)
+	}
+	return &admin, nil
+}
+
+// 🟢 Create admin (auto-generate AdminID)
+func (r *AdminRepo) CreateAdmin(admin *models.AdminInfo) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	id, err := r.store.seq.Next("AD")
+	if err != nil {
+		return err
+	}
+	admin.AdminID = id
+	admin.CreatedAt = time.Now()
+	r.store.admins[admin.AdminEmail] = *admin
+	return nil
+}
+
+// 🟢 Login with password verification
+func (r *AdminRepo) Login(email string, password string) (*models.AdminInfo, error) {
+	r.store.mu.RLock()
+	admin, ok := liveByKey(r.store.admins, email)
+	r.store.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("admin not found")
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(admin.AdminPassword), []byte(password)); err != nil {
+		return nil, fmt.Errorf("invalid password")
+	}
+	return &admin, nil
+}
+
+// 🟢 Update password
+func (r *AdminRepo) UpdatePasswordByEmail(email string, password string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	admin := r.store.admins[email]
+	admin.AdminPassword = password
+	r.store.admins[email] = admin
+	return nil
+}
+
+// 🟢 Update admin by ID (keyed by data.AdminEmail like the Firestore repo)
+func (r *AdminRepo) UpdateAdminByID(id string, data *models.AdminInfo) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	data.UpdatedAt = time.Now()
+	r.store.admins[data.AdminEmail] = *data
+	return nil
+}
+
+// 🟢 Delete admin - soft delete
+func (r *AdminRepo) DeleteAdmin(adminID, deletedBy string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	return trash(r.store.admins, "admin", r.emailOf(adminID), deletedBy)
+}
+
+// 🟢 RestoreAdmin
+func (r *AdminRepo) RestoreAdmin(adminID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	return restore(r.store.admins, "admin", r.emailOf(adminID))
+}
+
+// 🟢 PurgeAdmins
+func (r *AdminRepo) PurgeAdmins(cutoff time.Time) ([]string, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	return purge(r.store.admins, cutoff, nil, func(admin models.AdminInfo) string { return admin.AdminID }), nil
+}
+
+// emailOf - the key of an admin, adminID itself when there is none so the error names it
+func (r *AdminRepo) emailOf(adminID string) string {
+	for email, admin := range r.store.admins {
+		if admin.AdminID == adminID {
+			return email
+		}
+	}
+	return adminID
+}
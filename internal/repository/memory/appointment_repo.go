@@ -0,0 +1,103 @@
+package memory
+
+import (
+	"time"
+
+	"trl-research-backend/internal/listing"
+	"trl-research-backend/internal/models"
+)
+
+type AppointmentRepo struct {
+	store *Store
+}
+
+// 🟢 GetAppointmentAll
+func (r *AppointmentRepo) GetAppointmentAll() ([]models.Appointment, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	return live(r.store.appointments), nil
+}
+
+// 🟢 ListAppointments
+func (r *AppointmentRepo) ListAppointments(q listing.Query) (*listing.Page[models.Appointment], error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	return listing.Apply(sortedValues(r.store.appointments), q), nil
+}
+
+// 🟢 GetAppointmentByID
+func (r *AppointmentRepo) GetAppointmentByID(appointmentID string) (*models.Appointment, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	ap, ok := liveByKey(r.store.appointments, appointmentID)
+	if !ok {
+		return nil, notFound("appointment", appointmentID)
+	}
+	return &ap, nil
+}
+
+// 🟢 GetAppointmentByCaseID
+func (r *AppointmentRepo) GetAppointmentByCaseID(caseID string) ([]models.Appointment, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	var appointments []models.Appointment
+	for _, ap := range live(r.store.appointments) {
+		if ap.CaseID == caseID {
+			appointments = append(appointments, ap)
+		}
+	}
+	return appointments, nil
+}
+
+// 🟢 CreateAppointment - auto generate ID AP-00001
+func (r *AppointmentRepo) CreateAppointment(ap *models.Appointment) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	id, err := r.store.seq.Next("AP")
+	if err != nil {
+		return err
+	}
+	ap.AppointmentID = id
+	now := time.Now()
+	ap.CreatedAt = now
+	ap.UpdatedAt = now
+	r.store.appointments[ap.AppointmentID] = *ap
+	return nil
+}
+
+// 🟢 UpdateAppointmentByID
+func (r *AppointmentRepo) UpdateAppointmentByID(appointmentID string, data map[string]interface{}) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	ap, ok := liveByKey(r.store.appointments, appointmentID)
+	if !ok {
+		return notFound("appointment", appointmentID)
+	}
+	data["updated_at"] = time.Now()
+	if err := merge(&ap, data); err != nil {
+		return err
+	}
+	r.store.appointments[appointmentID] = ap
+	return nil
+}
+
+// 🟢 DeleteAppointment - soft delete
+func (r *AppointmentRepo) DeleteAppointment(appointmentID, deletedBy string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	return trash(r.store.appointments, "appointment", appointmentID, deletedBy)
+}
+
+// 🟢 RestoreAppointment
+func (r *AppointmentRepo) RestoreAppointment(appointmentID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	return restore(r.store.appointments, "appointment", appointmentID)
+}
+
+// 🟢 PurgeAppointments
+func (r *AppointmentRepo) PurgeAppointments(cutoff time.Time) ([]string, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	return purge(r.store.appointments, cutoff, nil, nil), nil
+}
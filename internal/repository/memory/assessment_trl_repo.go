@@ -0,0 +1,151 @@
+package memory
+
+import (
+	"time"
+
+	"trl-research-backend/internal/listing"
+	"trl-research-backend/internal/models"
+)
+
+type AssessmentTrlRepo struct {
+	store *Store
+}
+
+// 🟢 GetAssessmentTrlAll
+func (r *AssessmentTrlRepo) GetAssessmentTrlAll() ([]models.AssessmentTrl, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	return live(r.store.assessments), nil
+}
+
+// 🟢 ListAssessmentTrls
+func (r *AssessmentTrlRepo) ListAssessmentTrls(q listing.Query) (*listing.Page[models.AssessmentTrl], error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	return listing.Apply(sortedValues(r.store.assessments), q), nil
+}
+
+// 🟢 GetAssessmentTrlByID
+func (r *AssessmentTrlRepo) GetAssessmentTrlByID(id string) (*models.AssessmentTrl, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	a, ok := liveByKey(r.store.assessments, id)
+	if !ok {
+		return nil, notFound("assessment_trl", id)
+	}
+	return &a, nil
+}
+
+// 🟢 GetAssessmentTrlByCaseID - the latest one (IDs grow with every create)
+func (r *AssessmentTrlRepo) GetAssessmentTrlByCaseID(caseID string) (*models.AssessmentTrl, error) {
+	all, _ := r.GetAssessmentTrlsByCaseID(caseID)
+	if len(all) == 0 {
+		return nil, notFound("assessment_trl for case", caseID)
+	}
+	return &all[len(all)-1], nil
+}
+
+// 🟢 GetAssessmentTrlsByCaseID - oldest first
+func (r *AssessmentTrlRepo) GetAssessmentTrlsByCaseID(caseID string) ([]models.AssessmentTrl, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	var out []models.AssessmentTrl
+	for _, a := range live(r.store.assessments) {
+		if a.CaseID == caseID {
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
+
+// 🟢 CreateAssessmentTrl - auto generate ID AS-00001
+func (r *AssessmentTrlRepo) CreateAssessmentTrl(a *models.AssessmentTrl) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	id, err := r.store.seq.Next("AS")
+	if err != nil {
+		return err
+	}
+	a.ID = id
+	now := time.Now()
+	a.CreatedAt = now
+	a.UpdatedAt = now
+	a.Revision = 1
+	r.store.assessments[a.ID] = *a
+	return nil
+}
+
+// 🟢 UpdateAssessmentTrlByID
+func (r *AssessmentTrlRepo) UpdateAssessmentTrlByID(id string, data map[string]interface{}) error {
+	return r.update(id, nil, data)
+}
+
+// 🟢 UpdateAssessmentTrlIfRevision
+func (r *AssessmentTrlRepo) UpdateAssessmentTrlIfRevision(id string, revision int64, data map[string]interface{}) error {
+	return r.update(id, &revision, data)
+}
+
+func (r *AssessmentTrlRepo) update(id string, revision *int64, data map[string]interface{}) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	a, ok := liveByKey(r.store.assessments, id)
+	if !ok {
+		return notFound("assessment_trl", id)
+	}
+	if a.ReviewStatus() != models.AssessmentPending {
+		return conflict("assessment_trl", id)
+	}
+	if revision != nil && a.Revision != *revision {
+		return stale("assessment_trl", id)
+	}
+	data["updated_at"] = time.Now()
+	data["revision"] = a.Revision + 1
+	if err := merge(&a, data); err != nil {
+		return err
+	}
+	r.store.assessments[id] = a
+	return nil
+}
+
+// 🟢 ReviewAssessmentTrl
+func (r *AssessmentTrlRepo) ReviewAssessmentTrl(id, status, reviewerID, note string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	a, ok := liveByKey(r.store.assessments, id)
+	if !ok {
+		return notFound("assessment_trl", id)
+	}
+	if a.ReviewStatus() != models.AssessmentPending {
+		return conflict("assessment_trl", id)
+	}
+	now := time.Now()
+	a.Status = status
+	a.ReviewedBy = reviewerID
+	a.ReviewedAt = now
+	a.ReviewNote = note
+	a.UpdatedAt = now
+	a.Revision++
+	r.store.assessments[id] = a
+	return nil
+}
+
+// 🟢 DeleteAssessmentTrl - soft delete
+func (r *AssessmentTrlRepo) DeleteAssessmentTrl(id, deletedBy string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	return trash(r.store.assessments, "assessment_trl", id, deletedBy)
+}
+
+// 🟢 RestoreAssessmentTrl
+func (r *AssessmentTrlRepo) RestoreAssessmentTrl(id string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	return restore(r.store.assessments, "assessment_trl", id)
+}
+
+// 🟢 PurgeAssessmentTrls
+func (r *AssessmentTrlRepo) PurgeAssessmentTrls(cutoff time.Time) ([]string, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	return purge(r.store.assessments, cutoff, nil, nil), nil
+}
@@ -0,0 +1,34 @@
+package memory
+
+import (
+	"time"
+
+	"trl-research-backend/internal/listing"
+	"trl-research-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type AuditRepo struct {
+	store *Store
+}
+
+// 🟢 CreateAuditEntry
+func (r *AuditRepo) CreateAuditEntry(e *models.AuditEntry) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	e.ID = uuid.NewString()
+	e.CreatedAt = time.Now()
+	if e.Changes == nil {
+		e.Changes = []models.AuditChange{}
+	}
+	r.store.audit = append(r.store.audit, *e)
+	return nil
+}
+
+// 🟢 ListAuditEntries
+func (r *AuditRepo) ListAuditEntries(q listing.Query) (*listing.Page[models.AuditEntry], error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	return listing.Apply(r.store.audit, q), nil
+}
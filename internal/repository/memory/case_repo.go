@@ -0,0 +1,239 @@
+package memory
+
+import (
+	"time"
+
+	"trl-research-backend/internal/listing"
+	"trl-research-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type CaseRepo struct {
+	store *Store
+}
+
+// 🟢 GetCaseAll - fetch all cases
+func (r *CaseRepo) GetCaseAll() ([]models.CaseInfo, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	return live(r.store.cases), nil
+}
+
+// 🟢 ListCases
+func (r *CaseRepo) ListCases(q listing.Query) (*listing.Page[models.CaseInfo], error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	return listing.Apply(sortedValues(r.store.cases), q), nil
+}
+
+// 🟢 GetCaseAllByResearcher_id - fetch all cases for a researcher
+func (r *CaseRepo) GetCaseAllByResearcher_id(researcher_id string) ([]models.CaseInfo, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	var cases []models.CaseInfo
+	for _, cs := range live(r.store.cases) {
+		if cs.ResearcherID == researcher_id {
+			cases = append(cases, cs)
+		}
+	}
+	return cases, nil
+}
+
+// 🟢 GetCaseByID
+func (r *CaseRepo) GetCaseByID(caseID string) (*models.CaseInfo, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	cs, ok := liveByKey(r.store.cases, caseID)
+	if !ok {
+		return nil, notFound("case", caseID)
+	}
+	return &cs, nil
+}
+
+// 🟢 CreateCase - auto generate CaseID (CS-00001)
+func (r *CaseRepo) CreateCase(cs *models.CaseInfo) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	id, err := r.store.seq.Next("CS")
+	if err != nil {
+		return err
+	}
+	cs.CaseID = id
+	now := time.Now()
+	cs.CreatedAt = now
+	cs.UpdatedAt = now
+	cs.Revision = 1
+	r.store.cases[cs.CaseID] = *cs
+	return nil
+}
+
+// 🟢 UpdateCaseByID
+func (r *CaseRepo) UpdateCaseByID(caseID string, data map[string]interface{}) error {
+	return r.update(caseID, nil, data)
+}
+
+// 🟢 UpdateCaseIfRevision
+func (r *CaseRepo) UpdateCaseIfRevision(caseID string, revision int64, data map[string]interface{}) error {
+	return r.update(caseID, &revision, data)
+}
+
+func (r *CaseRepo) update(caseID string, revision *int64, data map[string]interface{}) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	cs, ok := liveByKey(r.store.cases, caseID)
+	if !ok {
+		return notFound("case", caseID)
+	}
+	if revision != nil && cs.Revision != *revision {
+		return stale("case", caseID)
+	}
+	data["updated_at"] = time.Now()
+	data["revision"] = cs.Revision + 1
+	if err := merge(&cs, data); err != nil {
+		return err
+	}
+	r.store.cases[caseID] = cs
+	return nil
+}
+
+// 🟢 TransitionCaseStatus
+func (r *CaseRepo) TransitionCaseStatus(t *models.CaseStatusTransition) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	cs, ok := liveByKey(r.store.cases, t.CaseID)
+	if !ok {
+		return notFound("case", t.CaseID)
+	}
+	if cs.Status != t.From {
+		return conflict("case", t.CaseID)
+	}
+
+	t.ID = uuid.NewString()
+	t.CreatedAt = time.Now()
+	timestamps := make(map[string]time.Time, len(cs.StatusTimestamps)+1)
+	for k, v := range cs.StatusTimestamps {
+		timestamps[k] = v
+	}
+	timestamps[string(t.To)] = t.CreatedAt
+	cs.Status = t.To
+	cs.StatusReason = t.Reason
+	cs.StatusChangedAt = t.CreatedAt
+	cs.StatusTimestamps = timestamps
+	cs.UpdatedAt = t.CreatedAt
+	cs.Revision++
+	r.store.cases[t.CaseID] = cs
+	r.store.caseHistory = append(r.store.caseHistory, *t)
+	return nil
+}
+
+// 🟢 GetCaseStatusHistory
+func (r *CaseRepo) GetCaseStatusHistory(caseID string) ([]models.CaseStatusTransition, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	var history []models.CaseStatusTransition
+	for _, t := range r.store.caseHistory {
+		if t.CaseID == caseID {
+			history = append(history, t)
+		}
+	}
+	return history, nil
+}
+
+// 🟢 DeleteCase - soft delete, with the records of the case
+func (r *CaseRepo) DeleteCase(caseID, deletedBy string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	d := models.Deletion{DeletedAt: time.Now(), DeletedBy: deletedBy}
+	ok, err := setDeletion(r.store.cases, caseID, d)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return notFound("case", caseID)
+	}
+	return r.cascade(caseID, d, func(item deletable) bool { return !item.IsDeleted() })
+}
+
+// 🟢 RestoreCase - with the records deleted together with it
+func (r *CaseRepo) RestoreCase(caseID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	cs, ok := r.store.cases[caseID]
+	if !ok || !cs.IsDeleted() {
+		return notFound("deleted case", caseID)
+	}
+	if _, err := setDeletion(r.store.cases, caseID, models.Deletion{}); err != nil {
+		return err
+	}
+	return r.cascade(caseID, models.Deletion{}, func(item deletable) bool { return item.DeletedWith(cs.Deletion) })
+}
+
+// cascade sets d on the records of the case that match
+func (r *CaseRepo) cascade(caseID string, d models.Deletion, match func(deletable) bool) error {
+	s := r.store
+	for _, err := range []error{
+		setCaseDeletion(s.appointments, caseID, d, match, func(ap models.Appointment) string { return ap.CaseID }),
+		setCaseDeletion(s.ips, caseID, d, match, func(ip models.IntellectualProperty) string { return ip.CaseID }),
+		setCaseDeletion(s.supporters, caseID, d, match, func(sp models.Supporter) string { return sp.CaseID }),
+		setCaseDeletion(s.assessments, caseID, d, match, func(a models.AssessmentTrl) string { return a.CaseID }),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func setCaseDeletion[T deletable](m map[string]T, caseID string, d models.Deletion, match func(deletable) bool, caseOf func(T) string) error {
+	for key, item := range m {
+		if caseOf(item) == caseID && match(item) {
+			if _, err := setDeletion(m, key, d); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// 🟢 PurgeCases - with whatever still refers to them, like ON DELETE CASCADE in PostgreSQL
+func (r *CaseRepo) PurgeCases(cutoff time.Time) ([]string, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	s := r.store
+	purged := map[string]bool{}
+	for id, cs := range s.cases {
+		if cs.DeletedBefore(cutoff) {
+			purged[id] = true
+		}
+	}
+	gone := func(caseID string) bool { return purged[caseID] }
+	for id, ap := range s.appointments {
+		if gone(ap.CaseID) {
+			delete(s.appointments, id)
+		}
+	}
+	for id, ip := range s.ips {
+		if gone(ip.CaseID) {
+			delete(s.ips, id)
+		}
+	}
+	for id, sp := range s.supporters {
+		if gone(sp.CaseID) {
+			delete(s.supporters, id)
+		}
+	}
+	for id, a := range s.assessments {
+		if gone(a.CaseID) {
+			delete(s.assessments, id)
+		}
+	}
+	history := s.caseHistory[:0]
+	for _, t := range s.caseHistory {
+		if !gone(t.CaseID) {
+			history = append(history, t)
+		}
+	}
+	s.caseHistory = history
+	return purge(s.cases, cutoff, nil, nil), nil
+}
@@ -0,0 +1,128 @@
+package memory
+
+import (
+	"fmt"
+	"time"
+
+	"trl-research-backend/internal/listing"
+	"trl-research-backend/internal/models"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+type CoordinatorRepo struct {
+	store *Store
+}
+
+// 🟢 Login with password verification; a coordinator without a password can't sign in
+func (r *CoordinatorRepo) Login(email string, password string) (*models.CoordinatorInfo, error) {
+	r.store.mu.RLock()
+	coordinator, ok := liveByKey(r.store.coordinators, email)
+	r.store.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("coordinator not found")
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(coordinator.CoordinatorPassword), []byte(password)); err != nil {
+		return nil, fmt.Errorf("invalid password")
+	}
+	return &coordinator, nil
+}
+
+// 🟢 GetCoordinatorAll - fetch all coordinators
+func (r *CoordinatorRepo) GetCoordinatorAll() ([]models.CoordinatorInfo, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	return live(r.store.coordinators), nil
+}
+
+// 🟢 ListCoordinators
+func (r *CoordinatorRepo) ListCoordinators(q listing.Query) (*listing.Page[models.CoordinatorInfo], error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	return listing.Apply(sortedValues(r.store.coordinators), q), nil
+}
+
+// 🟢 GetCoordinatorByEmail
+func (r *CoordinatorRepo) GetCoordinatorByEmail(email string) (*models.CoordinatorInfo, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	coordinator, ok := liveByKey(r.store.coordinators, email)
+	if !ok {
+		return nil, notFound("coordinator", email)
+	}
+	return &coordinator, nil
+}
+
+// 🟢 GetCoordinatorByCaseID
+func (r *CoordinatorRepo) GetCoordinatorByCaseID(caseID string) (*models.CoordinatorInfo, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	for _, coordinator := range live(r.store.coordinators) {
+		if coordinator.CaseID == caseID {
+			return &coordinator, nil
+		}
+	}
+	return nil, notFound("coordinator for case", caseID)
+}
+
+// 🟢 CreateCoordinator
+func (r *CoordinatorRepo) CreateCoordinator(coordinator *models.CoordinatorInfo) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	now := time.Now()
+	coordinator.CreatedAt = now
+	coordinator.UpdatedAt = now
+	r.store.coordinators[coordinator.CoordinatorEmail] = *coordinator
+	return nil
+}
+
+// 🟢 UpdateCoordinatorByEmail
+func (r *CoordinatorRepo) UpdateCoordinatorByEmail(email string, data map[string]interface{}) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	coordinator, ok := liveByKey(r.store.coordinators, email)
+	if !ok {
+		return notFound("coordinator", email)
+	}
+	data["updated_at"] = time.Now()
+	if err := merge(&coordinator, data); err != nil {
+		return err
+	}
+	r.store.coordinators[email] = coordinator
+	return nil
+}
+
+// 🟢 UpdateCoordinatorPasswordByEmail
+func (r *CoordinatorRepo) UpdateCoordinatorPasswordByEmail(email string, password string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	coordinator, ok := liveByKey(r.store.coordinators, email)
+	if !ok {
+		return notFound("coordinator", email)
+	}
+	coordinator.CoordinatorPassword = password
+	coordinator.UpdatedAt = time.Now()
+	r.store.coordinators[email] = coordinator
+	return nil
+}
+
+// 🟢 DeleteCoordinator - soft delete
+func (r *CoordinatorRepo) DeleteCoordinator(email, deletedBy string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	return trash(r.store.coordinators, "coordinator", email, deletedBy)
+}
+
+// 🟢 RestoreCoordinator
+func (r *CoordinatorRepo) RestoreCoordinator(email string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	return restore(r.store.coordinators, "coordinator", email)
+}
+
+// 🟢 PurgeCoordinators
+func (r *CoordinatorRepo) PurgeCoordinators(cutoff time.Time) ([]string, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	return purge(r.store.coordinators, cutoff, nil, nil), nil
+}
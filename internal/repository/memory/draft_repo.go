@@ -0,0 +1,105 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"trl-research-backend/internal/models"
+)
+
+type DraftRepo struct {
+	store *Store
+}
+
+// liveDraft - the draft with the ID unless it is missing or expired; the caller holds the lock
+func (r *DraftRepo) liveDraft(draftID string) (models.Draft, bool) {
+	d, ok := r.store.drafts[draftID]
+	return d, ok && !d.Expired(time.Now())
+}
+
+// 🟢 CreateDraft - auto generate ID DR-00001
+func (r *DraftRepo) CreateDraft(d *models.Draft) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	id, err := r.store.seq.Next("DR")
+	if err != nil {
+		return err
+	}
+	d.ID = id
+	now := time.Now()
+	d.CreatedAt = now
+	d.UpdatedAt = now
+	r.store.drafts[d.ID] = *d
+	return nil
+}
+
+// 🟢 GetDraftByID
+func (r *DraftRepo) GetDraftByID(draftID string) (*models.Draft, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	d, ok := r.liveDraft(draftID)
+	if !ok {
+		return nil, notFound("draft", draftID)
+	}
+	return &d, nil
+}
+
+// 🟢 GetDraftsByResearcherID
+func (r *DraftRepo) GetDraftsByResearcherID(researcherID string) ([]models.Draft, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	now := time.Now()
+	drafts := []models.Draft{}
+	for _, d := range r.store.drafts {
+		if d.ResearcherID == researcherID && !d.Expired(now) {
+			drafts = append(drafts, d)
+		}
+	}
+	sort.Slice(drafts, func(i, j int) bool {
+		if !drafts[i].UpdatedAt.Equal(drafts[j].UpdatedAt) {
+			return drafts[i].UpdatedAt.After(drafts[j].UpdatedAt)
+		}
+		return drafts[i].ID > drafts[j].ID
+	})
+	return drafts, nil
+}
+
+// 🟢 SaveDraft
+func (r *DraftRepo) SaveDraft(d *models.Draft) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	stored, ok := r.liveDraft(d.ID)
+	if !ok {
+		return notFound("draft", d.ID)
+	}
+	d.ResearcherID, d.CreatedAt = stored.ResearcherID, stored.CreatedAt
+	d.UpdatedAt = time.Now()
+	r.store.drafts[d.ID] = *d
+	return nil
+}
+
+// 🟢 DeleteDraft
+func (r *DraftRepo) DeleteDraft(draftID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if _, ok := r.liveDraft(draftID); !ok {
+		return notFound("draft", draftID)
+	}
+	delete(r.store.drafts, draftID)
+	return nil
+}
+
+// 🟢 PurgeDrafts
+func (r *DraftRepo) PurgeDrafts(now time.Time) ([]string, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	ids := []string{}
+	for id, d := range r.store.drafts {
+		if d.Expired(now) {
+			delete(r.store.drafts, id)
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
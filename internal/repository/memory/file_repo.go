@@ -0,0 +1,47 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"trl-research-backend/internal/models"
+)
+
+type FileRepo struct {
+	store *Store
+}
+
+func (r *FileRepo) SaveFile(ctx context.Context, file *models.FileMetadata) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	r.store.files[file.ID] = *file
+	return nil
+}
+
+func (r *FileRepo) GetFileByID(ctx context.Context, fileID string) (*models.FileMetadata, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	file, ok := r.store.files[fileID]
+	if !ok {
+		return nil, notFound("file", fileID)
+	}
+	return &file, nil
+}
+
+func (r *FileRepo) GetFilesByCaseID(ctx context.Context, caseID string) ([]models.FileMetadata, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	files := []models.FileMetadata{}
+	for _, file := range r.store.files {
+		if file.BelongsToCaseID == caseID {
+			files = append(files, file)
+		}
+	}
+	sort.Slice(files, func(i, j int) bool {
+		if !files[i].UploadedAt.Equal(files[j].UploadedAt) {
+			return files[i].UploadedAt.Before(files[j].UploadedAt)
+		}
+		return files[i].ID < files[j].ID
+	})
+	return files, nil
+}
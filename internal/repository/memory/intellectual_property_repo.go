@@ -0,0 +1,102 @@
+package memory
+
+import (
+	"time"
+
+	"trl-research-backend/internal/listing"
+	"trl-research-backend/internal/models"
+)
+
+type IntellectualPropertyRepo struct {
+	store *Store
+}
+
+// 🟢 GetIPAll - fetch all intellectual property records
+func (r *IntellectualPropertyRepo) GetIPAll() ([]models.IntellectualProperty, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	return live(r.store.ips), nil
+}
+
+// 🟢 ListIPs
+func (r *IntellectualPropertyRepo) ListIPs(q listing.Query) (*listing.Page[models.IntellectualProperty], error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	return listing.Apply(sortedValues(r.store.ips), q), nil
+}
+
+// 🟢 GetIPByID
+func (r *IntellectualPropertyRepo) GetIPByID(ipID string) (*models.IntellectualProperty, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	ip, ok := liveByKey(r.store.ips, ipID)
+	if !ok {
+		return nil, notFound("intellectual property", ipID)
+	}
+	return &ip, nil
+}
+
+// 🟢 GetIPByCaseID
+func (r *IntellectualPropertyRepo) GetIPByCaseID(caseID string) (*models.IntellectualProperty, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	for _, ip := range live(r.store.ips) {
+		if ip.CaseID == caseID {
+			return &ip, nil
+		}
+	}
+	return nil, notFound("intellectual property for case", caseID)
+}
+
+// 🟢 CreateIP - auto generate ID IP-00001
+func (r *IntellectualPropertyRepo) CreateIP(ip *models.IntellectualProperty) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	id, err := r.store.seq.Next("IP")
+	if err != nil {
+		return err
+	}
+	ip.ID = id
+	now := time.Now()
+	ip.CreatedAt = now
+	ip.UpdatedAt = now
+	r.store.ips[ip.ID] = *ip
+	return nil
+}
+
+// 🟢 UpdateIPByID
+func (r *IntellectualPropertyRepo) UpdateIPByID(ipID string, data map[string]interface{}) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	ip, ok := liveByKey(r.store.ips, ipID)
+	if !ok {
+		return notFound("intellectual property", ipID)
+	}
+	data["updated_at"] = time.Now()
+	if err := merge(&ip, data); err != nil {
+		return err
+	}
+	r.store.ips[ipID] = ip
+	return nil
+}
+
+// 🟢 DeleteIP - soft delete
+func (r *IntellectualPropertyRepo) DeleteIP(ipID, deletedBy string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	return trash(r.store.ips, "intellectual property", ipID, deletedBy)
+}
+
+// 🟢 RestoreIP
+func (r *IntellectualPropertyRepo) RestoreIP(ipID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	return restore(r.store.ips, "intellectual property", ipID)
+}
+
+// 🟢 PurgeIPs
+func (r *IntellectualPropertyRepo) PurgeIPs(cutoff time.Time) ([]string, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	return purge(r.store.ips, cutoff, nil, nil), nil
+}
@@ -0,0 +1,70 @@
+package memory
+
+import (
+	"time"
+
+	"trl-research-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type LoginAttemptRepo struct {
+	store *Store
+}
+
+// 🟢 GetLoginThrottle
+func (r *LoginAttemptRepo) GetLoginThrottle(key string) (*models.LoginThrottle, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	t, ok := r.store.throttles[key]
+	if !ok {
+		return nil, notFound("login throttle", key)
+	}
+	return &t, nil
+}
+
+// 🟢 RecordLoginFailure
+func (r *LoginAttemptRepo) RecordLoginFailure(key string, resetAfter time.Duration) (*models.LoginThrottle, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	now := time.Now()
+	t, ok := r.store.throttles[key]
+	if !ok || now.Sub(t.LastFailureAt) > resetAfter {
+		t = models.LoginThrottle{Key: key}
+	}
+	t.Failures++
+	t.LastFailureAt = now
+	r.store.throttles[key] = t
+	return &t, nil
+}
+
+// 🟢 ClearLoginFailures
+func (r *LoginAttemptRepo) ClearLoginFailures(key string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	delete(r.store.throttles, key)
+	return nil
+}
+
+// 🟢 CreateLoginAudit
+func (r *LoginAttemptRepo) CreateLoginAudit(audit *models.LoginAudit) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	audit.ID = uuid.NewString()
+	audit.CreatedAt = time.Now()
+	r.store.loginAudits = append(r.store.loginAudits, *audit)
+	return nil
+}
+
+// 🟢 GetLoginAuditsByEmail - newest first
+func (r *LoginAttemptRepo) GetLoginAuditsByEmail(email string, limit int) ([]models.LoginAudit, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	var audits []models.LoginAudit
+	for i := len(r.store.loginAudits) - 1; i >= 0 && len(audits) < limit; i-- {
+		if r.store.loginAudits[i].Email == email {
+			audits = append(audits, r.store.loginAudits[i])
+		}
+	}
+	return audits, nil
+}
@@ -0,0 +1,87 @@
+package memory
+
+import (
+	"time"
+
+	"trl-research-backend/internal/models"
+)
+
+type MFARepo struct {
+	store *Store
+}
+
+// 🟢 GetMFA
+func (r *MFARepo) GetMFA(userID string) (*models.MFAEnrollment, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	m, ok := r.store.mfa[userID]
+	if !ok {
+		return nil, notFound("mfa", userID)
+	}
+	m.RecoveryCodeHashes = append([]string(nil), m.RecoveryCodeHashes...)
+	return &m, nil
+}
+
+// 🟢 SaveMFA
+func (r *MFARepo) SaveMFA(m *models.MFAEnrollment) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	now := time.Now()
+	if old, ok := r.store.mfa[m.UserID]; ok {
+		m.CreatedAt = old.CreatedAt
+	} else {
+		m.CreatedAt = now
+	}
+	m.UpdatedAt = now
+	saved := *m
+	saved.RecoveryCodeHashes = append([]string(nil), m.RecoveryCodeHashes...)
+	r.store.mfa[m.UserID] = saved
+	return nil
+}
+
+// 🟢 DeleteMFA
+func (r *MFARepo) DeleteMFA(userID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if _, ok := r.store.mfa[userID]; !ok {
+		return notFound("mfa", userID)
+	}
+	delete(r.store.mfa, userID)
+	return nil
+}
+
+// 🟢 UseTOTPStep
+func (r *MFARepo) UseTOTPStep(userID string, step int64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	m, ok := r.store.mfa[userID]
+	if !ok {
+		return notFound("mfa", userID)
+	}
+	if step <= m.LastUsedStep {
+		return conflict("mfa", userID)
+	}
+	m.LastUsedStep = step
+	r.store.mfa[userID] = m
+	return nil
+}
+
+// 🟢 UseRecoveryCode
+func (r *MFARepo) UseRecoveryCode(userID, codeHash string) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	m, ok := r.store.mfa[userID]
+	if !ok {
+		return 0, notFound("mfa", userID)
+	}
+	for i, h := range m.RecoveryCodeHashes {
+		if h == codeHash {
+			left := append(append([]string(nil), m.RecoveryCodeHashes[:i]...), m.RecoveryCodeHashes[i+1:]...)
+			m.RecoveryCodeHashes = left
+			m.UpdatedAt = time.Now()
+			r.store.mfa[userID] = m
+			return len(left), nil
+		}
+	}
+	return 0, notFound("recovery code", userID)
+}
@@ -0,0 +1,54 @@
+package memory
+
+import (
+	"time"
+
+	"trl-research-backend/internal/models"
+)
+
+type PasswordResetRepo struct {
+	store *Store
+}
+
+// 🟢 CreatePasswordReset
+func (r *PasswordResetRepo) CreatePasswordReset(reset *models.PasswordReset) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	reset.CreatedAt = time.Now()
+	r.store.resets[reset.TokenHash] = *reset
+	return nil
+}
+
+// 🟢 ConsumePasswordReset
+func (r *PasswordResetRepo) ConsumePasswordReset(tokenHash string) (*models.PasswordReset, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	reset, ok := r.store.resets[tokenHash]
+	if !ok {
+		return nil, notFound("password reset", "token")
+	}
+	now := time.Now()
+	if reset.Used || now.After(reset.ExpiresAt) {
+		return nil, conflict("password reset", "token")
+	}
+	reset.Used = true
+	reset.UsedAt = now
+	r.store.resets[tokenHash] = reset
+	return &reset, nil
+}
+
+// 🟢 InvalidatePasswordResetsByUserID
+func (r *PasswordResetRepo) InvalidatePasswordResetsByUserID(userID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	now := time.Now()
+	for hash, reset := range r.store.resets {
+		if reset.UserID != userID || reset.Used {
+			continue
+		}
+		reset.Used = true
+		reset.UsedAt = now
+		r.store.resets[hash] = reset
+	}
+	return nil
+}
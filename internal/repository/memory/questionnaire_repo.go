@@ -0,0 +1,161 @@
+package memory
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"trl-research-backend/internal/models"
+)
+
+type QuestionnaireRepo struct {
+	store *Store
+}
+
+// cloneTemplate copies the nested slices so callers can't change the stored template
+func cloneTemplate(t models.QuestionnaireTemplate) models.QuestionnaireTemplate {
+	questions := make([]models.Question, len(t.Questions))
+	for i, q := range t.Questions {
+		q.Levels = append([]int(nil), q.Levels...)
+		q.Options = append([]models.AnswerOption(nil), q.Options...)
+		questions[i] = q
+	}
+	t.Questions = questions
+	return t
+}
+
+// 🟢 GetQuestionnaireTemplates - ordered by version
+func (r *QuestionnaireRepo) GetQuestionnaireTemplates() ([]models.QuestionnaireTemplate, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	var out []models.QuestionnaireTemplate
+	for _, t := range r.store.templates {
+		out = append(out, cloneTemplate(t))
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	return out, nil
+}
+
+// 🟢 GetQuestionnaireTemplate
+func (r *QuestionnaireRepo) GetQuestionnaireTemplate(version int) (*models.QuestionnaireTemplate, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	t, ok := r.store.templates[version]
+	if !ok {
+		return nil, notFound("questionnaire", strconv.Itoa(version))
+	}
+	t = cloneTemplate(t)
+	return &t, nil
+}
+
+// 🟢 GetActiveQuestionnaireTemplate
+func (r *QuestionnaireRepo) GetActiveQuestionnaireTemplate() (*models.QuestionnaireTemplate, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	for _, t := range r.store.templates {
+		if t.Active {
+			t = cloneTemplate(t)
+			return &t, nil
+		}
+	}
+	return nil, notFound("questionnaire", "active")
+}
+
+// 🟢 CreateQuestionnaireTemplate
+func (r *QuestionnaireRepo) CreateQuestionnaireTemplate(t *models.QuestionnaireTemplate, minVersion int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	version := minVersion
+	for v := range r.store.templates {
+		if v >= version {
+			version = v + 1
+		}
+	}
+	now := time.Now()
+	t.Version = version
+	t.Published, t.Active = false, false
+	t.PublishedAt = time.Time{}
+	t.CreatedAt = now
+	t.UpdatedAt = now
+	r.store.templates[version] = cloneTemplate(*t)
+	return nil
+}
+
+// 🟢 UpdateQuestionnaireTemplate
+func (r *QuestionnaireRepo) UpdateQuestionnaireTemplate(t *models.QuestionnaireTemplate) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	old, ok := r.store.templates[t.Version]
+	if !ok {
+		return notFound("questionnaire", strconv.Itoa(t.Version))
+	}
+	if old.Published {
+		return conflict("questionnaire", strconv.Itoa(t.Version))
+	}
+	old.Title = t.Title
+	old.Description = t.Description
+	old.Questions = t.Questions
+	old.UpdatedAt = time.Now()
+	old = cloneTemplate(old)
+	r.store.templates[t.Version] = old
+	*t = old
+	return nil
+}
+
+// 🟢 PublishQuestionnaireTemplate
+func (r *QuestionnaireRepo) PublishQuestionnaireTemplate(version int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	t, ok := r.store.templates[version]
+	if !ok {
+		return notFound("questionnaire", strconv.Itoa(version))
+	}
+	if t.Published {
+		return conflict("questionnaire", strconv.Itoa(version))
+	}
+	now := time.Now()
+	t.Published = true
+	t.PublishedAt = now
+	t.UpdatedAt = now
+	r.store.templates[version] = t
+	return nil
+}
+
+// 🟢 SetActiveQuestionnaireTemplate
+func (r *QuestionnaireRepo) SetActiveQuestionnaireTemplate(version int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if version != 0 {
+		t, ok := r.store.templates[version]
+		if !ok {
+			return notFound("questionnaire", strconv.Itoa(version))
+		}
+		if !t.Published {
+			return conflict("questionnaire", strconv.Itoa(version))
+		}
+	}
+	now := time.Now()
+	for v, t := range r.store.templates {
+		if t.Active != (v == version) {
+			t.Active = v == version
+			t.UpdatedAt = now
+			r.store.templates[v] = t
+		}
+	}
+	return nil
+}
+
+// 🟢 DeleteQuestionnaireTemplate
+func (r *QuestionnaireRepo) DeleteQuestionnaireTemplate(version int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	t, ok := r.store.templates[version]
+	if !ok {
+		return notFound("questionnaire", strconv.Itoa(version))
+	}
+	if t.Published {
+		return conflict("questionnaire", strconv.Itoa(version))
+	}
+	delete(r.store.templates, version)
+	return nil
+}
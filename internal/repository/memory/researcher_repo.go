@@ -0,0 +1,157 @@
+package memory
+
+import (
+	"fmt"
+	"time"
+
+	"trl-research-backend/internal/listing"
+	"trl-research-backend/internal/models"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+type ResearcherRepo struct {
+	store *Store
+}
+
+// 🟢 Login with password verification
+func (r *ResearcherRepo) Login(email string, password string) (*models.ResearcherInfo, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	for _, researcher := range r.store.researchers {
+		if researcher.ResearcherEmail != email || researcher.IsDeleted() {
+			continue
+		}
+		if err := bcrypt.CompareHashAndPassword([]byte(researcher.ResearcherPassword), []byte(password)); err != nil {
+			return nil, fmt.Errorf("invalid password")
+		}
+		return &researcher, nil
+	}
+	return nil, fmt.Errorf("researcher not found")
+}
+
+// 🟢 GetResearcherAll - fetch all researchers
+func (r *ResearcherRepo) GetResearcherAll() ([]models.ResearcherInfo, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	return live(r.store.researchers), nil
+}
+
+// 🟢 ListResearchers
+func (r *ResearcherRepo) ListResearchers(q listing.Query) (*listing.Page[models.ResearcherInfo], error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	return listing.Apply(sortedValues(r.store.researchers), q), nil
+}
+
+// 🟢 GetResearcherByID
+func (r *ResearcherRepo) GetResearcherByID(researcherID string) (*models.ResearcherInfo, error) {
+	return r.GetResearcherByIDDirect(researcherID)
+}
+
+// 🟢 GetResearcherByIDDirect - memory has no eventual consistency, so both lookups are the same
+func (r *ResearcherRepo) GetResearcherByIDDirect(researcherID string) (*models.ResearcherInfo, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	researcher, ok := liveByKey(r.store.researchers, researcherID)
+	if !ok {
+		return nil, notFound("researcher", researcherID)
+	}
+	return &researcher, nil
+}
+
+// 🟢 GetResearcherByCaseID - resolve through the case's researcher_id
+func (r *ResearcherRepo) GetResearcherByCaseID(caseID string) (*models.ResearcherInfo, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	cs, ok := liveByKey(r.store.cases, caseID)
+	if !ok {
+		return nil, notFound("case", caseID)
+	}
+	researcher, ok := liveByKey(r.store.researchers, cs.ResearcherID)
+	if !ok {
+		return nil, notFound("researcher", cs.ResearcherID)
+	}
+	return &researcher, nil
+}
+
+// 🟢 GetResearcherByEmail
+func (r *ResearcherRepo) GetResearcherByEmail(email string) (*models.ResearcherInfo, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	for _, researcher := range live(r.store.researchers) {
+		if researcher.ResearcherEmail == email {
+			return &researcher, nil
+		}
+	}
+	return nil, notFound("researcher", email)
+}
+
+// 🟢 CreateResearcher - auto-generate ResearcherID and create new record
+func (r *ResearcherRepo) CreateResearcher(researcher *models.ResearcherInfo) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	id, err := r.store.seq.Next("RS")
+	if err != nil {
+		return err
+	}
+	researcher.ResearcherID = id
+	now := time.Now()
+	researcher.CreatedAt = now
+	researcher.UpdatedAt = now
+	r.store.researchers[researcher.ResearcherID] = *researcher
+	return nil
+}
+
+// 🟢 UpdateResearcherByID - replace with the merged record built by the handler
+func (r *ResearcherRepo) UpdateResearcherByID(researcherID string, data *models.ResearcherInfo) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if _, ok := r.store.researchers[researcherID]; !ok {
+		return notFound("researcher", researcherID)
+	}
+	data.UpdatedAt = time.Now()
+	r.store.researchers[researcherID] = *data
+	return nil
+}
+
+// 🟢 UpdateResearcherPasswordByID
+func (r *ResearcherRepo) UpdateResearcherPasswordByID(researcherID string, password string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	researcher, ok := liveByKey(r.store.researchers, researcherID)
+	if !ok {
+		return notFound("researcher", researcherID)
+	}
+	researcher.ResearcherPassword = password
+	researcher.UpdatedAt = time.Now()
+	r.store.researchers[researcherID] = researcher
+	return nil
+}
+
+// 🟢 DeleteResearcher - soft delete
+func (r *ResearcherRepo) DeleteResearcher(researcherID, deletedBy string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	return trash(r.store.researchers, "researcher", researcherID, deletedBy)
+}
+
+// 🟢 RestoreResearcher
+func (r *ResearcherRepo) RestoreResearcher(researcherID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	return restore(r.store.researchers, "researcher", researcherID)
+}
+
+// 🟢 PurgeResearchers - not while they still have cases
+func (r *ResearcherRepo) PurgeResearchers(cutoff time.Time) ([]string, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	owners := map[string]bool{}
+	for _, cs := range r.store.cases {
+		owners[cs.ResearcherID] = true
+	}
+	return purge(r.store.researchers, cutoff, func(researcher models.ResearcherInfo) bool {
+		return owners[researcher.ResearcherID]
+	}, nil), nil
+}
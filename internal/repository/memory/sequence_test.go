@@ -0,0 +1,42 @@
+package memory
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"trl-research-backend/internal/models"
+)
+
+func TestCreateCaseParallelIDsAreUnique(t *testing.T) {
+	const n = 200
+	repos := NewRepositories()
+
+	var wg sync.WaitGroup
+	ids := make([]string, n)
+	errs := make([]error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			cs := &models.CaseInfo{CaseTitle: fmt.Sprintf("sequence test %d", i)}
+			errs[i] = repos.Case.CreateCase(cs)
+			ids[i] = cs.CaseID
+		}(i)
+	}
+	wg.Wait()
+
+	seen := map[string]bool{}
+	for i, id := range ids {
+		if errs[i] != nil {
+			t.Fatalf("CreateCase: %v", errs[i])
+		}
+		if seen[id] {
+			t.Fatalf("duplicate ID %s", id)
+		}
+		seen[id] = true
+	}
+	if !seen["CS-00001"] || !seen[fmt.Sprintf("CS-%05d", n)] {
+		t.Errorf("IDs are not CS-00001 to CS-%05d", n)
+	}
+}
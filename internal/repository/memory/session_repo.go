@@ -0,0 +1,98 @@
+package memory
+
+import (
+	"time"
+
+	"trl-research-backend/internal/models"
+)
+
+type SessionRepo struct {
+	store *Store
+}
+
+// 🟢 CreateSession
+func (r *SessionRepo) CreateSession(s *models.Session) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	now := time.Now()
+	s.CreatedAt = now
+	s.LastUsedAt = now
+	r.store.sessions[s.SessionID] = *s
+	return nil
+}
+
+// 🟢 GetSessionByID
+func (r *SessionRepo) GetSessionByID(sessionID string) (*models.Session, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	s, ok := r.store.sessions[sessionID]
+	if !ok {
+		return nil, notFound("session", sessionID)
+	}
+	return &s, nil
+}
+
+// 🟢 GetSessionsByUserID
+func (r *SessionRepo) GetSessionsByUserID(userID string) ([]models.Session, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	var sessions []models.Session
+	for _, s := range sortedValues(r.store.sessions) {
+		if s.UserID == userID {
+			sessions = append(sessions, s)
+		}
+	}
+	return sessions, nil
+}
+
+// 🟢 RotateSession
+func (r *SessionRepo) RotateSession(sessionID, oldHash, newHash string, expiresAt time.Time) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	s, ok := r.store.sessions[sessionID]
+	if !ok {
+		return notFound("session", sessionID)
+	}
+	if s.Revoked || s.RefreshTokenHash != oldHash {
+		return conflict("session", sessionID)
+	}
+	s.RefreshTokenHash = newHash
+	s.ExpiresAt = expiresAt
+	s.LastUsedAt = time.Now()
+	r.store.sessions[sessionID] = s
+	return nil
+}
+
+// 🟢 RevokeSession
+func (r *SessionRepo) RevokeSession(sessionID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	s, ok := r.store.sessions[sessionID]
+	if !ok {
+		return notFound("session", sessionID)
+	}
+	if !s.Revoked {
+		s.Revoked = true
+		s.RevokedAt = time.Now()
+		r.store.sessions[sessionID] = s
+	}
+	return nil
+}
+
+// 🟢 RevokeSessionsByUserID
+func (r *SessionRepo) RevokeSessionsByUserID(userID string) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	now := time.Now()
+	revoked := 0
+	for id, s := range r.store.sessions {
+		if s.UserID != userID || s.Revoked {
+			continue
+		}
+		s.Revoked = true
+		s.RevokedAt = now
+		r.store.sessions[id] = s
+		revoked++
+	}
+	return revoked, nil
+}
@@ -0,0 +1,255 @@
+package memory
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"trl-research-backend/internal/models"
+	"trl-research-backend/internal/repository"
+)
+
+// Store keeps every collection in process memory. It is safe for concurrent use and is
+// meant for local runs and unit tests where no Firestore project is available.
+type Store struct {
+	mu sync.RWMutex
+
+	admins       map[string]models.AdminInfo            // key: admin_email (same as Firestore doc ID)
+	researchers  map[string]models.ResearcherInfo       // key: researcher_id
+	coordinators map[string]models.CoordinatorInfo      // key: coordinator_email
+	supporters   map[string]models.Supporter            // key: supporter_id
+	appointments map[string]models.Appointment          // key: appointment_id
+	cases        map[string]models.CaseInfo             // key: case_id
+	caseHistory  []models.CaseStatusTransition          // append-only
+	ips          map[string]models.IntellectualProperty // key: id
+	assessments  map[string]models.AssessmentTrl        // key: id
+	files        map[string]models.FileMetadata         // key: id
+	sessions     map[string]models.Session              // key: session_id
+	resets       map[string]models.PasswordReset        // key: token_hash
+	throttles    map[string]models.LoginThrottle        // key: throttle key
+	loginAudits  []models.LoginAudit                    // append-only
+	mfa          map[string]models.MFAEnrollment        // key: user_id
+	templates    map[int]models.QuestionnaireTemplate   // key: version
+	terms        map[string]models.TaxonomyTerm         // key: id
+	audit        []models.AuditEntry                    // append-only
+	submissions  map[string]models.SubmissionRecord     // key: user_id + "\x00" + Idempotency-Key
+	drafts       map[string]models.Draft                // key: id
+
+	seq *Sequence
+}
+
+func NewStore() *Store {
+	return &Store{
+		admins:       map[string]models.AdminInfo{},
+		researchers:  map[string]models.ResearcherInfo{},
+		coordinators: map[string]models.CoordinatorInfo{},
+		supporters:   map[string]models.Supporter{},
+		appointments: map[string]models.Appointment{},
+		cases:        map[string]models.CaseInfo{},
+		ips:          map[string]models.IntellectualProperty{},
+		assessments:  map[string]models.AssessmentTrl{},
+		files:        map[string]models.FileMetadata{},
+		sessions:     map[string]models.Session{},
+		resets:       map[string]models.PasswordReset{},
+		throttles:    map[string]models.LoginThrottle{},
+		mfa:          map[string]models.MFAEnrollment{},
+		templates:    map[int]models.QuestionnaireTemplate{},
+		terms:        map[string]models.TaxonomyTerm{},
+		submissions:  map[string]models.SubmissionRecord{},
+		drafts:       map[string]models.Draft{},
+		seq:          NewSequence(),
+	}
+}
+
+// NewRepositories - every repository backed by one shared in-memory store
+func NewRepositories() *repository.Repositories {
+	return NewStore().Repositories()
+}
+
+// Repositories wires all repositories to this store
+func (s *Store) Repositories() *repository.Repositories {
+	return &repository.Repositories{
+		Admin:                &AdminRepo{store: s},
+		Researcher:           &ResearcherRepo{store: s},
+		Coordinator:          &CoordinatorRepo{store: s},
+		Supporter:            &SupporterRepo{store: s},
+		Appointment:          &AppointmentRepo{store: s},
+		Case:                 &CaseRepo{store: s},
+		IntellectualProperty: &IntellectualPropertyRepo{store: s},
+		AssessmentTrl:        &AssessmentTrlRepo{store: s},
+		File:                 &FileRepo{store: s},
+		Session:              &SessionRepo{store: s},
+		PasswordReset:        &PasswordResetRepo{store: s},
+		LoginAttempt:         &LoginAttemptRepo{store: s},
+		MFA:                  &MFARepo{store: s},
+		Questionnaire:        &QuestionnaireRepo{store: s},
+		Taxonomy:             &TaxonomyRepo{store: s},
+		Audit:                &AuditRepo{store: s},
+		Submission:           &SubmissionRepo{store: s},
+		Draft:                &DraftRepo{store: s},
+		Sequence:             s.seq,
+	}
+}
+
+// Sequence is the in-memory counterpart of repository.FirestoreSequence
+type Sequence struct {
+	mu     sync.Mutex
+	values map[string]int64
+}
+
+func NewSequence() *Sequence {
+	return &Sequence{values: map[string]int64{}}
+}
+
+func (s *Sequence) Next(prefix string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[prefix]++
+	return repository.FormatID(prefix, s.values[prefix]), nil
+}
+
+func conflict(kind, id string) error {
+	return fmt.Errorf("%s %s: %w", kind, id, repository.ErrConflict)
+}
+
+func stale(kind, id string) error {
+	return fmt.Errorf("%s %s: %w", kind, id, repository.ErrStale)
+}
+
+// sortedValues returns map values ordered by key so list endpoints are deterministic
+func sortedValues[T any](m map[string]T) []T {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var out []T
+	for _, k := range keys {
+		out = append(out, m[k])
+	}
+	return out
+}
+
+// merge applies a partial update the same way Firestore MergeAll does: keys are matched
+// against the JSON field names of dst and unknown keys are ignored.
+func merge(dst interface{}, data map[string]interface{}) error {
+	current, err := json.Marshal(dst)
+	if err != nil {
+		return err
+	}
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(current, &fields); err != nil {
+		return err
+	}
+	for k, v := range data {
+		fields[k] = v
+	}
+	merged, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(merged, dst)
+}
+
+// deletable - the models embedding models.Deletion
+type deletable interface {
+	IsDeleted() bool
+	DeletedBefore(t time.Time) bool
+	DeletedWith(d models.Deletion) bool
+}
+
+// live - the records of m that aren't deleted, ordered by key
+func live[T deletable](m map[string]T) []T {
+	var out []T
+	for _, item := range sortedValues(m) {
+		if !item.IsDeleted() {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// liveByKey - m[key] unless it is missing or deleted
+func liveByKey[T deletable](m map[string]T, key string) (T, bool) {
+	item, ok := m[key]
+	return item, ok && !item.IsDeleted()
+}
+
+// setDeletion moves m[key] to the trash (d set) or back (d zero); false when it isn't live /
+// deleted to begin with
+func setDeletion[T deletable](m map[string]T, key string, d models.Deletion) (bool, error) {
+	item, ok := m[key]
+	if !ok || item.IsDeleted() == d.IsDeleted() {
+		return false, nil
+	}
+	updatedAt := d.DeletedAt
+	if !d.IsDeleted() {
+		updatedAt = time.Now()
+	}
+	if err := merge(&item, map[string]interface{}{"deleted_at": d.DeletedAt, "deleted_by": d.DeletedBy, "updated_at": updatedAt}); err != nil {
+		return false, err
+	}
+	m[key] = item
+	return true, nil
+}
+
+// trash / restore - DeleteX and RestoreX of a map keyed by the record ID
+func trash[T deletable](m map[string]T, kind, key, deletedBy string) error {
+	ok, err := setDeletion(m, key, models.Deletion{DeletedAt: time.Now(), DeletedBy: deletedBy})
+	if err == nil && !ok {
+		return notFound(kind, key)
+	}
+	return err
+}
+
+func restore[T deletable](m map[string]T, kind, key string) error {
+	ok, err := setDeletion(m, key, models.Deletion{})
+	if err == nil && !ok {
+		return notFound("deleted "+kind, key)
+	}
+	return err
+}
+
+// purge removes the records deleted before cutoff that keep (if any) doesn't hold on to and
+// returns their IDs, sorted: id of each record, or its key when id is nil
+func purge[T deletable](m map[string]T, cutoff time.Time, keep func(T) bool, id func(T) string) []string {
+	ids := []string{}
+	for key, item := range m {
+		if item.DeletedBefore(cutoff) && (keep == nil || !keep(item)) {
+			delete(m, key)
+			if id != nil {
+				key = id(item)
+			}
+			ids = append(ids, key)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func notFound(kind, id string) error {
+	return fmt.Errorf("%s %s: %w", kind, id, repository.ErrNotFound)
+}
+
+var (
+	_ repository.AdminRepository                = (*AdminRepo)(nil)
+	_ repository.ResearcherRepository           = (*ResearcherRepo)(nil)
+	_ repository.CoordinatorRepository          = (*CoordinatorRepo)(nil)
+	_ repository.SupporterRepository            = (*SupporterRepo)(nil)
+	_ repository.AppointmentRepository          = (*AppointmentRepo)(nil)
+	_ repository.CaseRepository                 = (*CaseRepo)(nil)
+	_ repository.IntellectualPropertyRepository = (*IntellectualPropertyRepo)(nil)
+	_ repository.AssessmentTrlRepository        = (*AssessmentTrlRepo)(nil)
+	_ repository.FileRepository                 = (*FileRepo)(nil)
+	_ repository.SessionRepository              = (*SessionRepo)(nil)
+	_ repository.PasswordResetRepository        = (*PasswordResetRepo)(nil)
+	_ repository.LoginAttemptRepository         = (*LoginAttemptRepo)(nil)
+	_ repository.MFARepository                  = (*MFARepo)(nil)
+	_ repository.QuestionnaireRepository        = (*QuestionnaireRepo)(nil)
+	_ repository.TaxonomyRepository             = (*TaxonomyRepo)(nil)
+	_ repository.AuditRepository                = (*AuditRepo)(nil)
+	_ repository.SequenceGenerator              = (*Sequence)(nil)
+)
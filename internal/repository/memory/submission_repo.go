@@ -0,0 +1,51 @@
+package memory
+
+import (
+	"time"
+
+	"trl-research-backend/internal/models"
+	"trl-research-backend/internal/repository"
+)
+
+type SubmissionRepo struct {
+	store *Store
+}
+
+// 🟢 CreateSubmission - one lock around the key and every record
+func (r *SubmissionRepo) CreateSubmission(key models.SubmissionKey, s *models.Submission) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	now := time.Now()
+	id := key.UserID + "\x00" + key.Key
+	if stored, ok := r.store.submissions[id]; ok && key.Key != "" {
+		replay, err := repository.ReplayOf(&stored, key, now)
+		if err != nil {
+			return false, err
+		}
+		if replay != nil {
+			*s = replay.Submission
+			return true, nil
+		}
+	}
+
+	if err := repository.PrepareSubmission(s, r.store.seq.Next, now); err != nil {
+		return false, err
+	}
+	r.store.cases[s.Case.CaseID] = s.Case
+	if s.Supporter != nil {
+		r.store.supporters[s.Supporter.SupporterID] = *s.Supporter
+	}
+	for _, ip := range s.IPs {
+		r.store.ips[ip.ID] = ip
+	}
+	if s.Assessment != nil {
+		r.store.assessments[s.Assessment.ID] = *s.Assessment
+	}
+	for _, file := range s.Files {
+		r.store.files[file.ID] = file
+	}
+	if key.Key != "" {
+		r.store.submissions[id] = models.SubmissionRecord{SubmissionKey: key, Submission: *s}
+	}
+	return false, nil
+}
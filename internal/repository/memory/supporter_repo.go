@@ -0,0 +1,102 @@
+package memory
+
+import (
+	"time"
+
+	"trl-research-backend/internal/listing"
+	"trl-research-backend/internal/models"
+)
+
+type SupporterRepo struct {
+	store *Store
+}
+
+// 🟢 GetSupporterAll
+func (r *SupporterRepo) GetSupporterAll() ([]models.Supporter, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	return live(r.store.supporters), nil
+}
+
+// 🟢 ListSupporters
+func (r *SupporterRepo) ListSupporters(q listing.Query) (*listing.Page[models.Supporter], error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	return listing.Apply(sortedValues(r.store.supporters), q), nil
+}
+
+// 🟢 GetSupporterByID
+func (r *SupporterRepo) GetSupporterByID(supporterID string) (*models.Supporter, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	supporter, ok := liveByKey(r.store.supporters, supporterID)
+	if !ok {
+		return nil, notFound("supporter", supporterID)
+	}
+	return &supporter, nil
+}
+
+// 🟢 GetSupporterByCaseID
+func (r *SupporterRepo) GetSupporterByCaseID(caseID string) (*models.Supporter, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	for _, supporter := range live(r.store.supporters) {
+		if supporter.CaseID == caseID {
+			return &supporter, nil
+		}
+	}
+	return nil, notFound("supporter for case", caseID)
+}
+
+// 🟢 CreateSupporter - auto-generate ID SP-00001
+func (r *SupporterRepo) CreateSupporter(supporter *models.Supporter) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	id, err := r.store.seq.Next("SP")
+	if err != nil {
+		return err
+	}
+	supporter.SupporterID = id
+	now := time.Now()
+	supporter.CreatedAt = now
+	supporter.UpdatedAt = now
+	r.store.supporters[supporter.SupporterID] = *supporter
+	return nil
+}
+
+// 🟢 UpdateSupporterByID
+func (r *SupporterRepo) UpdateSupporterByID(supporterID string, data map[string]interface{}) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	supporter, ok := liveByKey(r.store.supporters, supporterID)
+	if !ok {
+		return notFound("supporter", supporterID)
+	}
+	data["updated_at"] = time.Now()
+	if err := merge(&supporter, data); err != nil {
+		return err
+	}
+	r.store.supporters[supporterID] = supporter
+	return nil
+}
+
+// 🟢 DeleteSupporter - soft delete
+func (r *SupporterRepo) DeleteSupporter(supporterID, deletedBy string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	return trash(r.store.supporters, "supporter", supporterID, deletedBy)
+}
+
+// 🟢 RestoreSupporter
+func (r *SupporterRepo) RestoreSupporter(supporterID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	return restore(r.store.supporters, "supporter", supporterID)
+}
+
+// 🟢 PurgeSupporters
+func (r *SupporterRepo) PurgeSupporters(cutoff time.Time) ([]string, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	return purge(r.store.supporters, cutoff, nil, nil), nil
+}
@@ -0,0 +1,118 @@
+package memory
+
+import (
+	"time"
+
+	"trl-research-backend/internal/models"
+	"trl-research-backend/internal/repository"
+)
+
+type TaxonomyRepo struct {
+	store *Store
+}
+
+// cloneTerm copies the synonyms so callers can't change the stored term
+func cloneTerm(t models.TaxonomyTerm) models.TaxonomyTerm {
+	t.Synonyms = append([]string{}, t.Synonyms...)
+	return t
+}
+
+// 🟢 GetTaxonomyTerms
+func (r *TaxonomyRepo) GetTaxonomyTerms(kind string) ([]models.TaxonomyTerm, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	terms := []models.TaxonomyTerm{}
+	for _, t := range sortedValues(r.store.terms) {
+		if kind == "" || t.Kind == kind {
+			terms = append(terms, cloneTerm(t))
+		}
+	}
+	return terms, nil
+}
+
+// 🟢 GetTaxonomyTermByID
+func (r *TaxonomyRepo) GetTaxonomyTermByID(id string) (*models.TaxonomyTerm, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	t, ok := r.store.terms[id]
+	if !ok {
+		return nil, notFound("taxonomy term", id)
+	}
+	t = cloneTerm(t)
+	return &t, nil
+}
+
+// 🟢 CreateTaxonomyTerm - auto generate ID (TX-00001)
+func (r *TaxonomyRepo) CreateTaxonomyTerm(t *models.TaxonomyTerm) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	id, err := r.store.seq.Next("TX")
+	if err != nil {
+		return err
+	}
+	t.ID = id
+	now := time.Now()
+	t.CreatedAt = now
+	t.UpdatedAt = now
+	r.store.terms[t.ID] = cloneTerm(*t)
+	return nil
+}
+
+// 🟢 UpdateTaxonomyTerm
+func (r *TaxonomyRepo) UpdateTaxonomyTerm(t *models.TaxonomyTerm) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	stored, ok := r.store.terms[t.ID]
+	if !ok {
+		return notFound("taxonomy term", t.ID)
+	}
+	t.UpdatedAt = time.Now()
+	stored.Label = t.Label
+	stored.Synonyms = t.Synonyms
+	stored.MergedInto = t.MergedInto
+	stored.UpdatedAt = t.UpdatedAt
+	r.store.terms[t.ID] = cloneTerm(stored)
+	return nil
+}
+
+// 🟢 ApplyTermChange - everything is checked before the first write, under one lock
+func (r *TaxonomyRepo) ApplyTermChange(terms []models.TaxonomyTerm, cases []repository.CasePatch) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	for _, t := range terms {
+		if _, ok := r.store.terms[t.ID]; !ok {
+			return notFound("taxonomy term", t.ID)
+		}
+	}
+	updated := map[string]models.CaseInfo{}
+	for _, p := range cases {
+		cs, ok := r.store.cases[p.CaseID] // deleted ones too
+		if !ok {
+			return notFound("case", p.CaseID)
+		}
+		if cs.Revision != p.Revision {
+			return stale("case", p.CaseID)
+		}
+		p.Data["updated_at"] = time.Now()
+		p.Data["revision"] = cs.Revision + 1
+		if err := merge(&cs, p.Data); err != nil {
+			return err
+		}
+		updated[p.CaseID] = cs
+	}
+
+	now := time.Now()
+	for i := range terms {
+		terms[i].UpdatedAt = now
+		stored := r.store.terms[terms[i].ID]
+		stored.Label = terms[i].Label
+		stored.Synonyms = terms[i].Synonyms
+		stored.MergedInto = terms[i].MergedInto
+		stored.UpdatedAt = now
+		r.store.terms[terms[i].ID] = cloneTerm(stored)
+	}
+	for id, cs := range updated {
+		r.store.cases[id] = cs
+	}
+	return nil
+}
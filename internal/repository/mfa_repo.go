@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"trl-research-backend/internal/models"
+)
+
+type MFARepo struct {
+	Client *firestore.Client
+}
+
+func NewMFARepo(client *firestore.Client) *MFARepo {
+	return &MFARepo{Client: client}
+}
+
+func (r *MFARepo) doc(userID string) *firestore.DocumentRef {
+	return r.Client.Collection("mfa").Doc(userID)
+}
+
+// getIn reads the enrollment inside a transaction
+func (r *MFARepo) getIn(tx *firestore.Transaction, userID string) (*models.MFAEnrollment, error) {
+	doc, err := tx.Get(r.doc(userID))
+	if status.Code(err) == codes.NotFound {
+		return nil, fmt.Errorf("mfa %s: %w", userID, ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var m models.MFAEnrollment
+	if err := doc.DataTo(&m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// 🟢 GetMFA - user_id is the document ID
+func (r *MFARepo) GetMFA(userID string) (*models.MFAEnrollment, error) {
+	ctx := context.Background()
+	doc, err := r.doc(userID).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return nil, fmt.Errorf("mfa %s: %w", userID, ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var m models.MFAEnrollment
+	if err := doc.DataTo(&m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// 🟢 SaveMFA - replaces the whole document, keeping created_at
+func (r *MFARepo) SaveMFA(m *models.MFAEnrollment) error {
+	ctx := context.Background()
+	return r.Client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		now := time.Now()
+		m.CreatedAt = now
+		if old, err := r.getIn(tx, m.UserID); err == nil {
+			m.CreatedAt = old.CreatedAt
+		} else if !errors.Is(err, ErrNotFound) {
+			return err
+		}
+		m.UpdatedAt = now
+		return tx.Set(r.doc(m.UserID), m)
+	})
+}
+
+// 🟢 DeleteMFA
+func (r *MFARepo) DeleteMFA(userID string) error {
+	ctx := context.Background()
+	_, err := r.doc(userID).Delete(ctx, firestore.Exists)
+	if status.Code(err) == codes.NotFound {
+		return fmt.Errorf("mfa %s: %w", userID, ErrNotFound)
+	}
+	return err
+}
+
+// 🟢 UseTOTPStep - last_used_step only moves forward, checked inside a transaction
+func (r *MFARepo) UseTOTPStep(userID string, step int64) error {
+	ctx := context.Background()
+	return r.Client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		m, err := r.getIn(tx, userID)
+		if err != nil {
+			return err
+		}
+		if step <= m.LastUsedStep {
+			return fmt.Errorf("mfa %s: %w", userID, ErrConflict)
+		}
+		return tx.Update(r.doc(userID), []firestore.Update{
+			{Path: "last_used_step", Value: step},
+		})
+	})
+}
+
+// 🟢 UseRecoveryCode - removes the hash inside a transaction so a code works once
+func (r *MFARepo) UseRecoveryCode(userID, codeHash string) (int, error) {
+	ctx := context.Background()
+	left := 0
+	err := r.Client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		m, err := r.getIn(tx, userID)
+		if err != nil {
+			return err
+		}
+		var rest []string
+		for _, h := range m.RecoveryCodeHashes {
+			if h != codeHash {
+				rest = append(rest, h)
+			}
+		}
+		if len(rest) == len(m.RecoveryCodeHashes) {
+			return fmt.Errorf("recovery code of %s: %w", userID, ErrNotFound)
+		}
+		left = len(rest)
+		return tx.Update(r.doc(userID), []firestore.Update{
+			{Path: "recovery_code_hashes", Value: rest},
+			{Path: "updated_at", Value: time.Now()},
+		})
+	})
+	return left, err
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"trl-research-backend/internal/models"
+)
+
+type PasswordResetRepo struct {
+	Client *firestore.Client
+}
+
+func NewPasswordResetRepo(client *firestore.Client) *PasswordResetRepo {
+	return &PasswordResetRepo{Client: client}
+}
+
+// 🟢 CreatePasswordReset - token_hash is the document ID
+func (r *PasswordResetRepo) CreatePasswordReset(reset *models.PasswordReset) error {
+	ctx := context.Background()
+	reset.CreatedAt = time.Now()
+	_, err := r.Client.Collection("password_resets").Doc(reset.TokenHash).Create(ctx, reset)
+	return err
+}
+
+// 🟢 ConsumePasswordReset - check and mark used in one transaction so a token works once
+func (r *PasswordResetRepo) ConsumePasswordReset(tokenHash string) (*models.PasswordReset, error) {
+	ctx := context.Background()
+	ref := r.Client.Collection("password_resets").Doc(tokenHash)
+
+	var reset models.PasswordReset
+	err := r.Client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(ref)
+		if status.Code(err) == codes.NotFound {
+			return fmt.Errorf("password reset token: %w", ErrNotFound)
+		}
+		if err != nil {
+			return err
+		}
+		if err := doc.DataTo(&reset); err != nil {
+			return err
+		}
+		now := time.Now()
+		if reset.Used || now.After(reset.ExpiresAt) {
+			return fmt.Errorf("password reset token: %w", ErrConflict)
+		}
+		reset.Used = true
+		reset.UsedAt = now
+		return tx.Update(ref, []firestore.Update{
+			{Path: "used", Value: true},
+			{Path: "used_at", Value: now},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &reset, nil
+}
+
+// 🟢 InvalidatePasswordResetsByUserID
+func (r *PasswordResetRepo) InvalidatePasswordResetsByUserID(userID string) error {
+	ctx := context.Background()
+	docs, err := r.Client.Collection("password_resets").
+		Where("user_id", "==", userID).
+		Where("used", "==", false).
+		Documents(ctx).GetAll()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, doc := range docs {
+		if _, err := doc.Ref.Update(ctx, []firestore.Update{
+			{Path: "used", Value: true},
+			{Path: "used_at", Value: now},
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"trl-research-backend/internal/listing"
+	"trl-research-backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type AdminRepo struct {
+	pool *pgxpool.Pool
+}
+
+const adminSelect = `SELECT admin_id, admin_prefix, admin_academic_position, admin_first_name,
+	admin_last_name, admin_department, admin_phone_number, admin_email, admin_password, case_id,
+	created_at, updated_at, deleted_at, deleted_by FROM admins`
+
+func scanAdmin(row pgx.Row) (models.AdminInfo, error) {
+	var a models.AdminInfo
+	var deletedAt *time.Time
+	err := row.Scan(&a.AdminID, &a.AdminPrefix, &a.AdminAcademicPosition, &a.AdminFirstName,
+		&a.AdminLastName, &a.AdminDepartment, &a.AdminPhoneNumber, &a.AdminEmail, &a.AdminPassword,
+		&a.CaseID, &a.CreatedAt, &a.UpdatedAt, &deletedAt, &a.DeletedBy)
+	a.Deletion = deletion(deletedAt, a.DeletedBy)
+	return a, err
+}
+
+// 🟢 Get all admins
+func (r *AdminRepo) GetAdminAll() ([]models.AdminInfo, error) {
+	rows, err := r.pool.Query(context.Background(), adminSelect+" WHERE deleted_at IS NULL ORDER BY admin_email")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var admins []models.AdminInfo
+	for rows.Next() {
+		admin, err := scanAdmin(rows)
+		if err != nil {
+			return nil, err
+		}
+		admins = append(admins, admin)
+	}
+	return admins, rows.Err()
+}
+
+// 🟢 ListAdmins
+func (r *AdminRepo) ListAdmins(q listing.Query) (*listing.Page[models.AdminInfo], error) {
+	return listPage(context.Background(), r.pool, adminSelect, "admins", q, scanAdmin)
+}
+
+// 🟢 Get admin by ID
+func (r *AdminRepo) GetAdminByID(adminID string) (*models.AdminInfo, error) {
+	admin, err := scanAdmin(r.pool.QueryRow(context.Background(), adminSelect+" WHERE admin_id = $1 AND deleted_at IS NULL", adminID))
+	if err != nil {
+		return nil, wrapNoRows(err, "admin", adminID)
+	}
+	return &admin, nil
+}
+
+// 🟢 Get admin by email
+func (r *AdminRepo) GetAdminByEmail(email string) (*models.AdminInfo, error) {
+	admin, err := scanAdmin(r.pool.QueryRow(context.Background(), adminSelect+" WHERE admin_email = $1 AND deleted_at IS NULL", email))
+	if err != nil {
+		return nil, wrapNoRows(err, "admin", email)
+	}
+	return &admin, nil
+}
+
+// 🟢 Create admin (auto-generate AdminID)
+func (r *AdminRepo) CreateAdmin(admin *models.AdminInfo) error {
+	ctx := context.Background()
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		id, err := nextID(ctx, tx, "AD")
+		if err != nil {
+			return err
+		}
+		admin.AdminID = id
+		admin.CreatedAt = time.Now()
+
+		_, err = tx.Exec(ctx, `INSERT INTO admins (admin_email, admin_id, admin_prefix,
+			admin_academic_position, admin_first_name, admin_last_name, admin_department,
+			admin_phone_number, admin_password, case_id, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+			admin.AdminEmail, admin.AdminID, admin.AdminPrefix, admin.AdminAcademicPosition,
+			admin.AdminFirstName, admin.AdminLastName, admin.AdminDepartment, admin.AdminPhoneNumber,
+			admin.AdminPassword, admin.CaseID, admin.CreatedAt, admin.UpdatedAt)
+		return err
+	})
+}
+
+// 🟢 Login with password verification
+func (r *AdminRepo) Login(email string, password string) (*models.AdminInfo, error) {
+	admin, err := r.GetAdminByEmail(email)
+	if err != nil {
+		return nil, fmt.Errorf("admin not found")
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(admin.AdminPassword), []byte(password)); err != nil {
+		return nil, fmt.Errorf("invalid password")
+	}
+	return admin, nil
+}
+
+// 🟢 Update password
+func (r *AdminRepo) UpdatePasswordByEmail(email string, password string) error {
+	tag, err := r.pool.Exec(context.Background(),
+		"UPDATE admins SET admin_password = $1, updated_at = $2 WHERE admin_email = $3", password, time.Now(), email)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return notFound("admin", email)
+	}
+	return nil
+}
+
+// 🟢 Update admin by ID (row is keyed by data.AdminEmail like the Firestore repo)
+func (r *AdminRepo) UpdateAdminByID(id string, data *models.AdminInfo) error {
+	data.UpdatedAt = time.Now()
+	tag, err := r.pool.Exec(context.Background(), `UPDATE admins SET admin_id = $1, admin_prefix = $2,
+		admin_academic_position = $3, admin_first_name = $4, admin_last_name = $5, admin_department = $6,
+		admin_phone_number = $7, admin_password = $8, case_id = $9, created_at = $10, updated_at = $11
+		WHERE admin_email = $12`,
+		data.AdminID, data.AdminPrefix, data.AdminAcademicPosition, data.AdminFirstName, data.AdminLastName,
+		data.AdminDepartment, data.AdminPhoneNumber, data.AdminPassword, data.CaseID, data.CreatedAt,
+		data.UpdatedAt, data.AdminEmail)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return notFound("admin", data.AdminEmail)
+	}
+	return nil
+}
+
+// 🟢 Delete admin - soft delete
+func (r *AdminRepo) DeleteAdmin(adminID, deletedBy string) error {
+	return trash(context.Background(), r.pool, "admins", "admin_id", "admin", adminID, deletedBy)
+}
+
+// 🟢 RestoreAdmin
+func (r *AdminRepo) RestoreAdmin(adminID string) error {
+	return restore(context.Background(), r.pool, "admins", "admin_id", "admin", adminID)
+}
+
+// 🟢 PurgeAdmins
+func (r *AdminRepo) PurgeAdmins(cutoff time.Time) ([]string, error) {
+	return purge(context.Background(), r.pool, "admins", "admin_id", cutoff, "")
+}
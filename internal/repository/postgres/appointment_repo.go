@@ -0,0 +1,124 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"trl-research-backend/internal/listing"
+	"trl-research-backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AppointmentRepo struct {
+	pool *pgxpool.Pool
+}
+
+const appointmentSelect = `SELECT appointment_id, case_id, date, status, location, note, summary,
+	created_at, updated_at, deleted_at, deleted_by FROM appointments`
+
+var appointmentColumns = columns{
+	"case_id":    textColumn,
+	"date":       timeColumn,
+	"status":     textColumn,
+	"location":   textColumn,
+	"note":       textColumn,
+	"summary":    textColumn,
+	"created_at": timeColumn,
+	"updated_at": timeColumn,
+}
+
+func scanAppointment(row pgx.Row) (models.Appointment, error) {
+	var ap models.Appointment
+	var deletedAt *time.Time
+	err := row.Scan(&ap.AppointmentID, &ap.CaseID, &ap.Date, &ap.Status, &ap.Location, &ap.Note,
+		&ap.Summary, &ap.CreatedAt, &ap.UpdatedAt, &deletedAt, &ap.DeletedBy)
+	ap.Deletion = deletion(deletedAt, ap.DeletedBy)
+	return ap, err
+}
+
+func (r *AppointmentRepo) queryAppointments(sql string, args ...any) ([]models.Appointment, error) {
+	rows, err := r.pool.Query(context.Background(), sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var appointments []models.Appointment
+	for rows.Next() {
+		ap, err := scanAppointment(rows)
+		if err != nil {
+			return nil, err
+		}
+		appointments = append(appointments, ap)
+	}
+	return appointments, rows.Err()
+}
+
+// 🟢 GetAppointmentAll
+func (r *AppointmentRepo) GetAppointmentAll() ([]models.Appointment, error) {
+	return r.queryAppointments(appointmentSelect + " WHERE deleted_at IS NULL ORDER BY appointment_id")
+}
+
+// 🟢 ListAppointments
+func (r *AppointmentRepo) ListAppointments(q listing.Query) (*listing.Page[models.Appointment], error) {
+	return listPage(context.Background(), r.pool, appointmentSelect, "appointments", q, scanAppointment)
+}
+
+// 🟢 GetAppointmentByID
+func (r *AppointmentRepo) GetAppointmentByID(appointmentID string) (*models.Appointment, error) {
+	ap, err := scanAppointment(r.pool.QueryRow(context.Background(),
+		appointmentSelect+" WHERE appointment_id = $1 AND deleted_at IS NULL", appointmentID))
+	if err != nil {
+		return nil, wrapNoRows(err, "appointment", appointmentID)
+	}
+	return &ap, nil
+}
+
+// 🟢 GetAppointmentByCaseID
+func (r *AppointmentRepo) GetAppointmentByCaseID(caseID string) ([]models.Appointment, error) {
+	return r.queryAppointments(appointmentSelect+" WHERE case_id = $1 AND deleted_at IS NULL ORDER BY appointment_id", caseID)
+}
+
+// 🟢 CreateAppointment - auto generate ID AP-00001
+func (r *AppointmentRepo) CreateAppointment(ap *models.Appointment) error {
+	ctx := context.Background()
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		id, err := nextID(ctx, tx, "AP")
+		if err != nil {
+			return err
+		}
+		ap.AppointmentID = id
+		now := time.Now()
+		ap.CreatedAt = now
+		ap.UpdatedAt = now
+
+		_, err = tx.Exec(ctx, `INSERT INTO appointments (appointment_id, case_id, date, status, location,
+			note, summary, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+			ap.AppointmentID, ap.CaseID, ap.Date, ap.Status, ap.Location, ap.Note, ap.Summary,
+			ap.CreatedAt, ap.UpdatedAt)
+		return err
+	})
+}
+
+// 🟢 UpdateAppointmentByID
+func (r *AppointmentRepo) UpdateAppointmentByID(appointmentID string, data map[string]interface{}) error {
+	data["updated_at"] = time.Now()
+	return updateByKey(context.Background(), r.pool, "appointments", "appointment_id", appointmentID, appointmentColumns, data)
+}
+
+// 🟢 DeleteAppointment - soft delete
+func (r *AppointmentRepo) DeleteAppointment(appointmentID, deletedBy string) error {
+	return trash(context.Background(), r.pool, "appointments", "appointment_id", "appointment", appointmentID, deletedBy)
+}
+
+// 🟢 RestoreAppointment
+func (r *AppointmentRepo) RestoreAppointment(appointmentID string) error {
+	return restore(context.Background(), r.pool, "appointments", "appointment_id", "appointment", appointmentID)
+}
+
+// 🟢 PurgeAppointments
+func (r *AppointmentRepo) PurgeAppointments(cutoff time.Time) ([]string, error) {
+	return purge(context.Background(), r.pool, "appointments", "appointment_id", cutoff, "")
+}
@@ -0,0 +1,246 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"trl-research-backend/internal/listing"
+	"trl-research-backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AssessmentTrlRepo struct {
+	pool *pgxpool.Pool
+}
+
+const assessmentTrlSelect = `SELECT id, case_id, questionnaire_version, trl_level_result, status, reviewed_by,
+	reviewed_at, review_note, readiness_answers, criteria_answers, rq1_answer, rq2_answer, rq3_answer,
+	rq4_answer, rq5_answer, rq6_answer, rq7_answer, cq1_answer, cq2_answer, cq3_answer, cq4_answer,
+	cq5_answer, cq6_answer, cq7_answer, cq8_answer, cq9_answer, created_at, updated_at, revision, deleted_at,
+	deleted_by FROM assessment_trl`
+
+var assessmentTrlColumns = columns{
+	"case_id":               textColumn,
+	"questionnaire_version": intColumn,
+	"trl_level_result":      intColumn,
+	"readiness_answers":     jsonColumn,
+	"criteria_answers":      jsonColumn,
+	"rq1_answer":            boolColumn,
+	"rq2_answer":            boolColumn,
+	"rq3_answer":            boolColumn,
+	"rq4_answer":            boolColumn,
+	"rq5_answer":            boolColumn,
+	"rq6_answer":            boolColumn,
+	"rq7_answer":            boolColumn,
+	"cq1_answer":            textArrayColumn,
+	"cq2_answer":            textArrayColumn,
+	"cq3_answer":            textArrayColumn,
+	"cq4_answer":            textArrayColumn,
+	"cq5_answer":            textArrayColumn,
+	"cq6_answer":            textArrayColumn,
+	"cq7_answer":            textArrayColumn,
+	"cq8_answer":            textArrayColumn,
+	"cq9_answer":            textArrayColumn,
+	"created_at":            timeColumn,
+	"updated_at":            timeColumn,
+	"revision":              intColumn,
+}
+
+func scanAssessmentTrl(row pgx.Row) (models.AssessmentTrl, error) {
+	var a models.AssessmentTrl
+	var reviewedAt, deletedAt *time.Time
+	err := row.Scan(&a.ID, &a.CaseID, &a.QuestionnaireVersion, &a.TrlLevelResult, &a.Status, &a.ReviewedBy,
+		&reviewedAt, &a.ReviewNote, &a.ReadinessAnswers, &a.CriteriaAnswers, &a.Rq1Answer, &a.Rq2Answer, &a.Rq3Answer,
+		&a.Rq4Answer, &a.Rq5Answer, &a.Rq6Answer, &a.Rq7Answer, &a.Cq1Answer, &a.Cq2Answer, &a.Cq3Answer,
+		&a.Cq4Answer, &a.Cq5Answer, &a.Cq6Answer, &a.Cq7Answer, &a.Cq8Answer, &a.Cq9Answer,
+		&a.CreatedAt, &a.UpdatedAt, &a.Revision, &deletedAt, &a.DeletedBy)
+	if reviewedAt != nil {
+		a.ReviewedAt = *reviewedAt
+	}
+	a.Deletion = deletion(deletedAt, a.DeletedBy)
+	return a, err
+}
+
+// 🟢 GetAssessmentTrlAll
+func (r *AssessmentTrlRepo) GetAssessmentTrlAll() ([]models.AssessmentTrl, error) {
+	rows, err := r.pool.Query(context.Background(), assessmentTrlSelect+" WHERE deleted_at IS NULL ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var assessments []models.AssessmentTrl
+	for rows.Next() {
+		a, err := scanAssessmentTrl(rows)
+		if err != nil {
+			return nil, err
+		}
+		assessments = append(assessments, a)
+	}
+	return assessments, rows.Err()
+}
+
+// 🟢 ListAssessmentTrls
+func (r *AssessmentTrlRepo) ListAssessmentTrls(q listing.Query) (*listing.Page[models.AssessmentTrl], error) {
+	return listPage(context.Background(), r.pool, assessmentTrlSelect, "assessment_trl", q, scanAssessmentTrl)
+}
+
+// 🟢 GetAssessmentTrlByID
+func (r *AssessmentTrlRepo) GetAssessmentTrlByID(id string) (*models.AssessmentTrl, error) {
+	a, err := scanAssessmentTrl(r.pool.QueryRow(context.Background(), assessmentTrlSelect+" WHERE id = $1 AND deleted_at IS NULL", id))
+	if err != nil {
+		return nil, wrapNoRows(err, "assessment_trl", id)
+	}
+	return &a, nil
+}
+
+// 🟢 GetAssessmentTrlByCaseID - the latest one
+func (r *AssessmentTrlRepo) GetAssessmentTrlByCaseID(caseID string) (*models.AssessmentTrl, error) {
+	a, err := scanAssessmentTrl(r.pool.QueryRow(context.Background(),
+		assessmentTrlSelect+" WHERE case_id = $1 AND deleted_at IS NULL ORDER BY created_at DESC, id DESC LIMIT 1", caseID))
+	if err != nil {
+		return nil, wrapNoRows(err, "assessment_trl for case", caseID)
+	}
+	return &a, nil
+}
+
+// 🟢 GetAssessmentTrlsByCaseID - oldest first
+func (r *AssessmentTrlRepo) GetAssessmentTrlsByCaseID(caseID string) ([]models.AssessmentTrl, error) {
+	rows, err := r.pool.Query(context.Background(),
+		assessmentTrlSelect+" WHERE case_id = $1 AND deleted_at IS NULL ORDER BY created_at, id", caseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var assessments []models.AssessmentTrl
+	for rows.Next() {
+		a, err := scanAssessmentTrl(rows)
+		if err != nil {
+			return nil, err
+		}
+		assessments = append(assessments, a)
+	}
+	return assessments, rows.Err()
+}
+
+// 🟢 CreateAssessmentTrl - auto generate ID AS-00001
+func (r *AssessmentTrlRepo) CreateAssessmentTrl(a *models.AssessmentTrl) error {
+	ctx := context.Background()
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		id, err := nextID(ctx, tx, "AS")
+		if err != nil {
+			return err
+		}
+		a.ID = id
+		now := time.Now()
+		a.CreatedAt = now
+		a.UpdatedAt = now
+		a.Revision = 1
+		return insertAssessmentTrl(ctx, tx, a)
+	})
+}
+
+// insertAssessmentTrl - the INSERT of CreateAssessmentTrl, for callers with their own ID and transaction
+func insertAssessmentTrl(ctx context.Context, q querier, a *models.AssessmentTrl) error {
+	_, err := q.Exec(ctx, `INSERT INTO assessment_trl (id, case_id, questionnaire_version, trl_level_result,
+			status, readiness_answers, criteria_answers, rq1_answer,
+			rq2_answer, rq3_answer, rq4_answer, rq5_answer, rq6_answer, rq7_answer, cq1_answer, cq2_answer,
+			cq3_answer, cq4_answer, cq5_answer, cq6_answer, cq7_answer, cq8_answer, cq9_answer,
+			created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21,
+			$22, $23, $24, $25)`,
+		a.ID, a.CaseID, a.Version(), a.TrlLevelResult, a.ReviewStatus(), readinessAnswers(a.ReadinessAnswers),
+		criteriaAnswers(a.CriteriaAnswers), a.Rq1Answer, a.Rq2Answer, a.Rq3Answer, a.Rq4Answer,
+		a.Rq5Answer, a.Rq6Answer, a.Rq7Answer, emptyStrings(a.Cq1Answer), emptyStrings(a.Cq2Answer),
+		emptyStrings(a.Cq3Answer), emptyStrings(a.Cq4Answer), emptyStrings(a.Cq5Answer),
+		emptyStrings(a.Cq6Answer), emptyStrings(a.Cq7Answer), emptyStrings(a.Cq8Answer),
+		emptyStrings(a.Cq9Answer), a.CreatedAt, a.UpdatedAt)
+	return err
+}
+
+// pendingTx locks an assessment row and returns its revision; ErrConflict once it is reviewed
+func pendingTx(ctx context.Context, tx pgx.Tx, id string) (int64, error) {
+	var status string
+	var revision int64
+	err := tx.QueryRow(ctx, "SELECT status, revision FROM assessment_trl WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", id).
+		Scan(&status, &revision)
+	if err != nil {
+		return 0, wrapNoRows(err, "assessment_trl", id)
+	}
+	if status != models.AssessmentPending {
+		return 0, conflict("assessment_trl", id)
+	}
+	return revision, nil
+}
+
+// 🟢 UpdateAssessmentTrlByID - only while pending
+func (r *AssessmentTrlRepo) UpdateAssessmentTrlByID(id string, data map[string]interface{}) error {
+	return r.update(id, nil, data)
+}
+
+// 🟢 UpdateAssessmentTrlIfRevision - only while pending and still at revision
+func (r *AssessmentTrlRepo) UpdateAssessmentTrlIfRevision(id string, revision int64, data map[string]interface{}) error {
+	return r.update(id, &revision, data)
+}
+
+func (r *AssessmentTrlRepo) update(id string, revision *int64, data map[string]interface{}) error {
+	ctx := context.Background()
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		current, err := pendingTx(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if revision != nil && current != *revision {
+			return stale("assessment_trl", id)
+		}
+		data["updated_at"] = time.Now()
+		data["revision"] = current + 1
+		return updateByKey(ctx, tx, "assessment_trl", "id", id, assessmentTrlColumns, data)
+	})
+}
+
+// 🟢 ReviewAssessmentTrl
+func (r *AssessmentTrlRepo) ReviewAssessmentTrl(id, status, reviewerID, note string) error {
+	ctx := context.Background()
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		if _, err := pendingTx(ctx, tx, id); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, `UPDATE assessment_trl SET status = $1, reviewed_by = $2, reviewed_at = now(),
+			review_note = $3, updated_at = now(), revision = revision + 1 WHERE id = $4`, status, reviewerID, note, id)
+		return err
+	})
+}
+
+// 🟢 DeleteAssessmentTrl - soft delete
+func (r *AssessmentTrlRepo) DeleteAssessmentTrl(id, deletedBy string) error {
+	return trash(context.Background(), r.pool, "assessment_trl", "id", "assessment_trl", id, deletedBy)
+}
+
+// 🟢 RestoreAssessmentTrl
+func (r *AssessmentTrlRepo) RestoreAssessmentTrl(id string) error {
+	return restore(context.Background(), r.pool, "assessment_trl", "id", "assessment_trl", id)
+}
+
+// 🟢 PurgeAssessmentTrls
+func (r *AssessmentTrlRepo) PurgeAssessmentTrls(cutoff time.Time) ([]string, error) {
+	return purge(context.Background(), r.pool, "assessment_trl", "id", cutoff, "")
+}
+
+// readinessAnswers / criteriaAnswers keep the NOT NULL jsonb columns an object
+func readinessAnswers(m map[string]bool) map[string]bool {
+	if m == nil {
+		return map[string]bool{}
+	}
+	return m
+}
+
+func criteriaAnswers(m map[string][]string) map[string][]string {
+	if m == nil {
+		return map[string][]string{}
+	}
+	return m
+}
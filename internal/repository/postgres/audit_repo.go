@@ -0,0 +1,47 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"trl-research-backend/internal/listing"
+	"trl-research-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AuditRepo struct {
+	pool *pgxpool.Pool
+}
+
+const auditSelect = `SELECT id, request_id, actor_id, actor_role, actor_email, method, route, path, entity,
+	entity_id, action, status, changes, created_at FROM audit_log`
+
+func scanAuditEntry(row pgx.Row) (models.AuditEntry, error) {
+	var e models.AuditEntry
+	err := row.Scan(&e.ID, &e.RequestID, &e.ActorID, &e.ActorRole, &e.ActorEmail, &e.Method, &e.Route, &e.Path,
+		&e.Entity, &e.EntityID, &e.Action, &e.Status, &e.Changes, &e.CreatedAt)
+	return e, err
+}
+
+// 🟢 CreateAuditEntry
+func (r *AuditRepo) CreateAuditEntry(e *models.AuditEntry) error {
+	e.ID = uuid.NewString()
+	e.CreatedAt = time.Now()
+	if e.Changes == nil {
+		e.Changes = []models.AuditChange{}
+	}
+	_, err := r.pool.Exec(context.Background(), `INSERT INTO audit_log (id, request_id, actor_id, actor_role,
+		actor_email, method, route, path, entity, entity_id, action, status, changes, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`,
+		e.ID, e.RequestID, e.ActorID, e.ActorRole, e.ActorEmail, e.Method, e.Route, e.Path, e.Entity,
+		e.EntityID, e.Action, e.Status, e.Changes, e.CreatedAt)
+	return err
+}
+
+// 🟢 ListAuditEntries
+func (r *AuditRepo) ListAuditEntries(q listing.Query) (*listing.Page[models.AuditEntry], error) {
+	return listPage(context.Background(), r.pool, auditSelect, "audit_log", q, scanAuditEntry)
+}
@@ -0,0 +1,271 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"trl-research-backend/internal/listing"
+	"trl-research-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type CaseRepo struct {
+	pool *pgxpool.Pool
+}
+
+const caseSelect = `SELECT case_id, COALESCE(researcher_id, ''), coordinator_email, trl_score, trl_suggestion,
+	trl_recommendation, status, status_reason, status_changed_at, status_timestamps, is_urgent, urgent_reason,
+	urgent_feedback, case_title, case_type, case_description, case_keywords, domain_ids, case_type_id, keyword_ids,
+	created_at, updated_at, revision, deleted_at, deleted_by FROM cases`
+
+var caseColumns = columns{
+	"researcher_id":      nullableTextColumn,
+	"coordinator_email":  textColumn,
+	"trl_score":          textColumn,
+	"trl_suggestion":     textColumn,
+	"trl_recommendation": jsonColumn,
+	"is_urgent":          boolColumn,
+	"urgent_reason":      textColumn,
+	"urgent_feedback":    textColumn,
+	"case_title":         textColumn,
+	"case_type":          textColumn,
+	"case_description":   textColumn,
+	"case_keywords":      textColumn,
+	"domain_ids":         textArrayColumn,
+	"case_type_id":       textColumn,
+	"keyword_ids":        textArrayColumn,
+	"created_at":         timeColumn,
+	"updated_at":         timeColumn,
+	"revision":           intColumn,
+}
+
+func scanCase(row pgx.Row) (models.CaseInfo, error) {
+	var cs models.CaseInfo
+	var statusChangedAt, deletedAt *time.Time
+	err := row.Scan(&cs.CaseID, &cs.ResearcherID, &cs.CoordinatorEmail, &cs.TrlScore, &cs.TrlSuggestion,
+		&cs.TrlRecommendation, &cs.Status, &cs.StatusReason, &statusChangedAt, &cs.StatusTimestamps, &cs.IsUrgent,
+		&cs.UrgentReason, &cs.UrgentFeedback, &cs.CaseTitle, &cs.CaseType, &cs.CaseDescription, &cs.CaseKeywords,
+		&cs.DomainIDs, &cs.CaseTypeID, &cs.KeywordIDs, &cs.CreatedAt, &cs.UpdatedAt, &cs.Revision, &deletedAt,
+		&cs.DeletedBy)
+	if statusChangedAt != nil {
+		cs.StatusChangedAt = *statusChangedAt
+	}
+	cs.Deletion = deletion(deletedAt, cs.DeletedBy)
+	return cs, err
+}
+
+func (r *CaseRepo) queryCases(sql string, args ...any) ([]models.CaseInfo, error) {
+	rows, err := r.pool.Query(context.Background(), sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cases []models.CaseInfo
+	for rows.Next() {
+		cs, err := scanCase(rows)
+		if err != nil {
+			return nil, err
+		}
+		cases = append(cases, cs)
+	}
+	return cases, rows.Err()
+}
+
+// 🟢 GetCaseAll - fetch all cases
+func (r *CaseRepo) GetCaseAll() ([]models.CaseInfo, error) {
+	return r.queryCases(caseSelect + " WHERE deleted_at IS NULL ORDER BY case_id")
+}
+
+// 🟢 ListCases
+func (r *CaseRepo) ListCases(q listing.Query) (*listing.Page[models.CaseInfo], error) {
+	return listPage(context.Background(), r.pool, caseSelect, "cases", q, scanCase)
+}
+
+// 🟢 GetCaseAllByResearcher_id - fetch all cases for a researcher
+func (r *CaseRepo) GetCaseAllByResearcher_id(researcher_id string) ([]models.CaseInfo, error) {
+	return r.queryCases(caseSelect+" WHERE researcher_id = $1 AND deleted_at IS NULL ORDER BY case_id", researcher_id)
+}
+
+// 🟢 GetCaseByID
+func (r *CaseRepo) GetCaseByID(caseID string) (*models.CaseInfo, error) {
+	cs, err := scanCase(r.pool.QueryRow(context.Background(), caseSelect+" WHERE case_id = $1 AND deleted_at IS NULL", caseID))
+	if err != nil {
+		return nil, wrapNoRows(err, "case", caseID)
+	}
+	return &cs, nil
+}
+
+// 🟢 CreateCase - auto generate CaseID (CS-00001)
+func (r *CaseRepo) CreateCase(cs *models.CaseInfo) error {
+	ctx := context.Background()
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		id, err := nextID(ctx, tx, "CS")
+		if err != nil {
+			return err
+		}
+		cs.CaseID = id
+		now := time.Now()
+		cs.CreatedAt = now
+		cs.UpdatedAt = now
+		cs.Revision = 1
+		return insertCase(ctx, tx, cs)
+	})
+}
+
+// insertCase - the INSERT of CreateCase, for callers with their own ID and transaction
+func insertCase(ctx context.Context, q querier, cs *models.CaseInfo) error {
+	var statusChangedAt *time.Time
+	if !cs.StatusChangedAt.IsZero() {
+		statusChangedAt = &cs.StatusChangedAt
+	}
+	timestamps := cs.StatusTimestamps
+	if timestamps == nil {
+		timestamps = map[string]time.Time{}
+	}
+	_, err := q.Exec(ctx, `INSERT INTO cases (case_id, researcher_id, coordinator_email, trl_score,
+			trl_suggestion, trl_recommendation, status, status_reason, status_changed_at, status_timestamps,
+			is_urgent, urgent_reason, urgent_feedback, case_title, case_type, case_description, case_keywords,
+			domain_ids, case_type_id, keyword_ids, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21,
+			$22)`,
+		cs.CaseID, nullIfEmpty(cs.ResearcherID), cs.CoordinatorEmail, cs.TrlScore, cs.TrlSuggestion,
+		cs.TrlRecommendation, cs.Status, cs.StatusReason, statusChangedAt, timestamps, cs.IsUrgent,
+		cs.UrgentReason, cs.UrgentFeedback, cs.CaseTitle, cs.CaseType, cs.CaseDescription, cs.CaseKeywords,
+		emptyStrings(cs.DomainIDs), cs.CaseTypeID, emptyStrings(cs.KeywordIDs), cs.CreatedAt, cs.UpdatedAt)
+	return err
+}
+
+// 🟢 UpdateCaseByID
+func (r *CaseRepo) UpdateCaseByID(caseID string, data map[string]interface{}) error {
+	return r.update(caseID, nil, data)
+}
+
+// 🟢 UpdateCaseIfRevision
+func (r *CaseRepo) UpdateCaseIfRevision(caseID string, revision int64, data map[string]interface{}) error {
+	return r.update(caseID, &revision, data)
+}
+
+// update locks the row for the revision; revision nil writes whatever revision the case is at
+func (r *CaseRepo) update(caseID string, revision *int64, data map[string]interface{}) error {
+	ctx := context.Background()
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		var current int64
+		err := tx.QueryRow(ctx, "SELECT revision FROM cases WHERE case_id = $1 AND deleted_at IS NULL FOR UPDATE", caseID).Scan(&current)
+		if err != nil {
+			return wrapNoRows(err, "case", caseID)
+		}
+		if revision != nil && current != *revision {
+			return stale("case", caseID)
+		}
+		data["updated_at"] = time.Now()
+		data["revision"] = current + 1
+		return updateByKey(ctx, tx, "cases", "case_id", caseID, caseColumns, data)
+	})
+}
+
+// 🟢 TransitionCaseStatus - the WHERE clause is the compare-and-swap, history row in the same transaction
+func (r *CaseRepo) TransitionCaseStatus(t *models.CaseStatusTransition) error {
+	ctx := context.Background()
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		t.ID = uuid.NewString()
+		t.CreatedAt = time.Now()
+		tag, err := tx.Exec(ctx, `UPDATE cases SET status = $1, status_reason = $2, status_changed_at = $3,
+			status_timestamps = status_timestamps || jsonb_build_object($1::text, $3::timestamptz), updated_at = $3,
+			revision = revision + 1 WHERE case_id = $4 AND status = $5 AND deleted_at IS NULL`, t.To, t.Reason, t.CreatedAt, t.CaseID, t.From)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			var exists bool
+			if err := tx.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM cases WHERE case_id = $1 AND deleted_at IS NULL)", t.CaseID).Scan(&exists); err != nil {
+				return err
+			}
+			if !exists {
+				return notFound("case", t.CaseID)
+			}
+			return conflict("case", t.CaseID)
+		}
+
+		_, err = tx.Exec(ctx, `INSERT INTO case_status_history (id, case_id, from_status, to_status, reason,
+			actor_id, actor_role, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+			t.ID, t.CaseID, t.From, t.To, t.Reason, t.ActorID, t.ActorRole, t.CreatedAt)
+		return err
+	})
+}
+
+// 🟢 GetCaseStatusHistory
+func (r *CaseRepo) GetCaseStatusHistory(caseID string) ([]models.CaseStatusTransition, error) {
+	rows, err := r.pool.Query(context.Background(), `SELECT id, case_id, from_status, to_status, reason,
+		actor_id, actor_role, created_at FROM case_status_history WHERE case_id = $1 ORDER BY created_at, id`, caseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []models.CaseStatusTransition
+	for rows.Next() {
+		var t models.CaseStatusTransition
+		if err := rows.Scan(&t.ID, &t.CaseID, &t.From, &t.To, &t.Reason, &t.ActorID, &t.ActorRole, &t.CreatedAt); err != nil {
+			return nil, err
+		}
+		history = append(history, t)
+	}
+	return history, rows.Err()
+}
+
+// caseRecords - the tables whose rows belong to a case by case_id and go to the trash with it
+var caseRecords = []string{"appointments", "intellectual_properties", "supporters", "assessment_trl"}
+
+// 🟢 DeleteCase - soft delete, with the live records of the case in the same transaction
+func (r *CaseRepo) DeleteCase(caseID, deletedBy string) error {
+	ctx := context.Background()
+	d := models.Deletion{DeletedAt: time.Now(), DeletedBy: deletedBy}
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		if err := setDeletion(ctx, tx, "cases", "case_id", "case", caseID, d); err != nil {
+			return err
+		}
+		for _, table := range caseRecords {
+			_, err := tx.Exec(ctx, "UPDATE "+table+" SET deleted_at = $1, deleted_by = $2, updated_at = $1 WHERE case_id = $3 AND deleted_at IS NULL",
+				d.DeletedAt, d.DeletedBy, caseID)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// 🟢 RestoreCase - with the records deleted together with it, they have the same deleted_at
+func (r *CaseRepo) RestoreCase(caseID string) error {
+	ctx := context.Background()
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		var deletedAt *time.Time
+		err := tx.QueryRow(ctx, "SELECT deleted_at FROM cases WHERE case_id = $1 FOR UPDATE", caseID).Scan(&deletedAt)
+		if err != nil {
+			return wrapNoRows(err, "deleted case", caseID)
+		}
+		if deletedAt == nil {
+			return notFound("deleted case", caseID)
+		}
+		if err := setDeletion(ctx, tx, "cases", "case_id", "case", caseID, models.Deletion{}); err != nil {
+			return err
+		}
+		for _, table := range caseRecords {
+			_, err := tx.Exec(ctx, "UPDATE "+table+" SET deleted_at = NULL, deleted_by = '', updated_at = now() WHERE case_id = $1 AND deleted_at = $2",
+				caseID, *deletedAt)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// 🟢 PurgeCases - their records and status history go with them (ON DELETE CASCADE)
+func (r *CaseRepo) PurgeCases(cutoff time.Time) ([]string, error) {
+	return purge(context.Background(), r.pool, "cases", "case_id", cutoff, "")
+}
@@ -0,0 +1,153 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"trl-research-backend/internal/listing"
+	"trl-research-backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type CoordinatorRepo struct {
+	pool *pgxpool.Pool
+}
+
+const coordinatorSelect = `SELECT coordinator_id, coordinator_email, coordinator_name, coordinator_phone,
+	department, case_id, coordinator_password, created_at, updated_at, deleted_at, deleted_by FROM coordinators`
+
+var coordinatorColumns = columns{
+	"coordinator_id":    textColumn,
+	"coordinator_name":  textColumn,
+	"coordinator_phone": textColumn,
+	"department":        textColumn,
+	"case_id":           textColumn,
+	"created_at":        timeColumn,
+	"updated_at":        timeColumn,
+}
+
+func scanCoordinator(row pgx.Row) (models.CoordinatorInfo, error) {
+	var c models.CoordinatorInfo
+	var deletedAt *time.Time
+	err := row.Scan(&c.CoordinatorID, &c.CoordinatorEmail, &c.CoordinatorName, &c.CoordinatorPhone,
+		&c.Department, &c.CaseID, &c.CoordinatorPassword, &c.CreatedAt, &c.UpdatedAt, &deletedAt, &c.DeletedBy)
+	c.Deletion = deletion(deletedAt, c.DeletedBy)
+	return c, err
+}
+
+// 🟢 Login with password verification; a coordinator without a password can't sign in
+func (r *CoordinatorRepo) Login(email string, password string) (*models.CoordinatorInfo, error) {
+	coordinator, err := r.GetCoordinatorByEmail(email)
+	if err != nil {
+		return nil, fmt.Errorf("coordinator not found")
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(coordinator.CoordinatorPassword), []byte(password)); err != nil {
+		return nil, fmt.Errorf("invalid password")
+	}
+	return coordinator, nil
+}
+
+// 🟢 GetCoordinatorAll - fetch all coordinators
+func (r *CoordinatorRepo) GetCoordinatorAll() ([]models.CoordinatorInfo, error) {
+	rows, err := r.pool.Query(context.Background(), coordinatorSelect+" WHERE deleted_at IS NULL ORDER BY coordinator_email")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var coordinators []models.CoordinatorInfo
+	for rows.Next() {
+		coordinator, err := scanCoordinator(rows)
+		if err != nil {
+			return nil, err
+		}
+		coordinators = append(coordinators, coordinator)
+	}
+	return coordinators, rows.Err()
+}
+
+// 🟢 ListCoordinators
+func (r *CoordinatorRepo) ListCoordinators(q listing.Query) (*listing.Page[models.CoordinatorInfo], error) {
+	return listPage(context.Background(), r.pool, coordinatorSelect, "coordinators", q, scanCoordinator)
+}
+
+// 🟢 GetCoordinatorByEmail
+func (r *CoordinatorRepo) GetCoordinatorByEmail(email string) (*models.CoordinatorInfo, error) {
+	coordinator, err := scanCoordinator(r.pool.QueryRow(context.Background(),
+		coordinatorSelect+" WHERE coordinator_email = $1 AND deleted_at IS NULL", email))
+	if err != nil {
+		return nil, wrapNoRows(err, "coordinator", email)
+	}
+	return &coordinator, nil
+}
+
+// 🟢 GetCoordinatorByCaseID
+func (r *CoordinatorRepo) GetCoordinatorByCaseID(caseID string) (*models.CoordinatorInfo, error) {
+	coordinator, err := scanCoordinator(r.pool.QueryRow(context.Background(),
+		coordinatorSelect+" WHERE case_id = $1 AND deleted_at IS NULL ORDER BY coordinator_email LIMIT 1", caseID))
+	if err != nil {
+		return nil, wrapNoRows(err, "coordinator for case", caseID)
+	}
+	return &coordinator, nil
+}
+
+// 🟢 CreateCoordinator
+func (r *CoordinatorRepo) CreateCoordinator(coordinator *models.CoordinatorInfo) error {
+	now := time.Now()
+	coordinator.CreatedAt = now
+	coordinator.UpdatedAt = now
+
+	// email is the key, so creating an existing coordinator overwrites it like Firestore Set does,
+	// a deleted one included
+	_, err := r.pool.Exec(context.Background(), `INSERT INTO coordinators (coordinator_email, coordinator_id,
+		coordinator_name, coordinator_phone, department, case_id, coordinator_password, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (coordinator_email) DO UPDATE SET coordinator_id = EXCLUDED.coordinator_id,
+		coordinator_name = EXCLUDED.coordinator_name, coordinator_phone = EXCLUDED.coordinator_phone,
+		department = EXCLUDED.department, case_id = EXCLUDED.case_id,
+		coordinator_password = EXCLUDED.coordinator_password, updated_at = EXCLUDED.updated_at,
+		deleted_at = NULL, deleted_by = ''`,
+		coordinator.CoordinatorEmail, coordinator.CoordinatorID, coordinator.CoordinatorName,
+		coordinator.CoordinatorPhone, coordinator.Department, coordinator.CaseID,
+		coordinator.CoordinatorPassword, coordinator.CreatedAt, coordinator.UpdatedAt)
+	return err
+}
+
+// 🟢 UpdateCoordinatorByEmail
+func (r *CoordinatorRepo) UpdateCoordinatorByEmail(email string, data map[string]interface{}) error {
+	data["updated_at"] = time.Now()
+	return updateByKey(context.Background(), r.pool, "coordinators", "coordinator_email", email, coordinatorColumns, data)
+}
+
+// 🟢 UpdateCoordinatorPasswordByEmail
+func (r *CoordinatorRepo) UpdateCoordinatorPasswordByEmail(email string, password string) error {
+	tag, err := r.pool.Exec(context.Background(),
+		"UPDATE coordinators SET coordinator_password = $1, updated_at = $2 WHERE coordinator_email = $3 AND deleted_at IS NULL",
+		password, time.Now(), email)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return notFound("coordinator", email)
+	}
+	return nil
+}
+
+// 🟢 DeleteCoordinator - soft delete
+func (r *CoordinatorRepo) DeleteCoordinator(email, deletedBy string) error {
+	return trash(context.Background(), r.pool, "coordinators", "coordinator_email", "coordinator", email, deletedBy)
+}
+
+// 🟢 RestoreCoordinator
+func (r *CoordinatorRepo) RestoreCoordinator(email string) error {
+	return restore(context.Background(), r.pool, "coordinators", "coordinator_email", "coordinator", email)
+}
+
+// 🟢 PurgeCoordinators
+func (r *CoordinatorRepo) PurgeCoordinators(cutoff time.Time) ([]string, error) {
+	return purge(context.Background(), r.pool, "coordinators", "coordinator_email", cutoff, "")
+}
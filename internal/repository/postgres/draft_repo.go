@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"trl-research-backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type DraftRepo struct {
+	pool *pgxpool.Pool
+}
+
+const draftSelect = `SELECT id, researcher_id, content, created_at, updated_at, expires_at FROM drafts`
+
+func scanDraft(row pgx.Row) (models.Draft, error) {
+	var d models.Draft
+	err := row.Scan(&d.ID, &d.ResearcherID, &d.DraftContent, &d.CreatedAt, &d.UpdatedAt, &d.ExpiresAt)
+	return d, err
+}
+
+// 🟢 CreateDraft - auto generate ID DR-00001
+func (r *DraftRepo) CreateDraft(d *models.Draft) error {
+	ctx := context.Background()
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		id, err := nextID(ctx, tx, "DR")
+		if err != nil {
+			return err
+		}
+		d.ID = id
+		now := time.Now()
+		d.CreatedAt = now
+		d.UpdatedAt = now
+
+		_, err = tx.Exec(ctx, `INSERT INTO drafts (id, researcher_id, content, created_at, updated_at, expires_at)
+			VALUES ($1, $2, $3, $4, $5, $6)`,
+			d.ID, d.ResearcherID, d.DraftContent, d.CreatedAt, d.UpdatedAt, d.ExpiresAt)
+		return err
+	})
+}
+
+// 🟢 GetDraftByID
+func (r *DraftRepo) GetDraftByID(draftID string) (*models.Draft, error) {
+	d, err := scanDraft(r.pool.QueryRow(context.Background(), draftSelect+" WHERE id = $1 AND expires_at > now()", draftID))
+	if err != nil {
+		return nil, wrapNoRows(err, "draft", draftID)
+	}
+	return &d, nil
+}
+
+// 🟢 GetDraftsByResearcherID
+func (r *DraftRepo) GetDraftsByResearcherID(researcherID string) ([]models.Draft, error) {
+	rows, err := r.pool.Query(context.Background(),
+		draftSelect+" WHERE researcher_id = $1 AND expires_at > now() ORDER BY updated_at DESC, id DESC", researcherID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	drafts := []models.Draft{}
+	for rows.Next() {
+		d, err := scanDraft(rows)
+		if err != nil {
+			return nil, err
+		}
+		drafts = append(drafts, d)
+	}
+	return drafts, rows.Err()
+}
+
+// 🟢 SaveDraft
+func (r *DraftRepo) SaveDraft(d *models.Draft) error {
+	d.UpdatedAt = time.Now()
+	err := r.pool.QueryRow(context.Background(), `UPDATE drafts SET content = $2, updated_at = $3, expires_at = $4
+		WHERE id = $1 AND expires_at > now() RETURNING researcher_id, created_at`,
+		d.ID, d.DraftContent, d.UpdatedAt, d.ExpiresAt).Scan(&d.ResearcherID, &d.CreatedAt)
+	return wrapNoRows(err, "draft", d.ID)
+}
+
+// 🟢 DeleteDraft
+func (r *DraftRepo) DeleteDraft(draftID string) error {
+	tag, err := r.pool.Exec(context.Background(), "DELETE FROM drafts WHERE id = $1 AND expires_at > now()", draftID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return notFound("draft", draftID)
+	}
+	return nil
+}
+
+// 🟢 PurgeDrafts
+func (r *DraftRepo) PurgeDrafts(now time.Time) ([]string, error) {
+	return deleteReturning(context.Background(), r.pool, "DELETE FROM drafts WHERE expires_at <= $1 RETURNING id", now)
+}
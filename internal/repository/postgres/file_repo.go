@@ -0,0 +1,59 @@
+package postgres
+
+import (
+	"context"
+
+	"trl-research-backend/internal/models"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type FileRepo struct {
+	pool *pgxpool.Pool
+}
+
+func (r *FileRepo) SaveFile(ctx context.Context, file *models.FileMetadata) error {
+	return saveFile(ctx, r.pool, file)
+}
+
+// saveFile - the upsert of SaveFile, for callers with their own transaction
+func saveFile(ctx context.Context, q querier, file *models.FileMetadata) error {
+	_, err := q.Exec(ctx, `INSERT INTO files (id, file_name, object_path, bucket, uploaded_by,
+		uploaded_at, content_type, belongs_to_case_id) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (id) DO UPDATE SET file_name = EXCLUDED.file_name, object_path = EXCLUDED.object_path,
+		bucket = EXCLUDED.bucket, uploaded_by = EXCLUDED.uploaded_by, uploaded_at = EXCLUDED.uploaded_at,
+		content_type = EXCLUDED.content_type, belongs_to_case_id = EXCLUDED.belongs_to_case_id`,
+		file.ID, file.FileName, file.ObjectPath, file.Bucket, file.UploadedBy, file.UploadedAt,
+		file.ContentType, file.BelongsToCaseID)
+	return err
+}
+
+func (r *FileRepo) GetFileByID(ctx context.Context, fileID string) (*models.FileMetadata, error) {
+	var file models.FileMetadata
+	err := r.pool.QueryRow(ctx, `SELECT id, file_name, object_path, bucket, uploaded_by, uploaded_at,
+		content_type, belongs_to_case_id FROM files WHERE id = $1`, fileID).Scan(&file.ID, &file.FileName,
+		&file.ObjectPath, &file.Bucket, &file.UploadedBy, &file.UploadedAt, &file.ContentType, &file.BelongsToCaseID)
+	if err != nil {
+		return nil, wrapNoRows(err, "file", fileID)
+	}
+	return &file, nil
+}
+
+func (r *FileRepo) GetFilesByCaseID(ctx context.Context, caseID string) ([]models.FileMetadata, error) {
+	rows, err := r.pool.Query(ctx, `SELECT id, file_name, object_path, bucket, uploaded_by, uploaded_at,
+		content_type, belongs_to_case_id FROM files WHERE belongs_to_case_id = $1 ORDER BY uploaded_at, id`, caseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	files := []models.FileMetadata{}
+	for rows.Next() {
+		var file models.FileMetadata
+		if err := rows.Scan(&file.ID, &file.FileName, &file.ObjectPath, &file.Bucket, &file.UploadedBy,
+			&file.UploadedAt, &file.ContentType, &file.BelongsToCaseID); err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	return files, rows.Err()
+}
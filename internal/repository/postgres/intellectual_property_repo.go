@@ -0,0 +1,125 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"trl-research-backend/internal/listing"
+	"trl-research-backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type IntellectualPropertyRepo struct {
+	pool *pgxpool.Pool
+}
+
+const ipSelect = `SELECT id, case_id, ip_types, ip_protection_status, ip_request_number,
+	created_at, updated_at, deleted_at, deleted_by FROM intellectual_properties`
+
+var ipColumns = columns{
+	"case_id":              textColumn,
+	"ip_types":             textColumn,
+	"ip_protection_status": textColumn,
+	"ip_request_number":    textColumn,
+	"created_at":           timeColumn,
+	"updated_at":           timeColumn,
+}
+
+func scanIP(row pgx.Row) (models.IntellectualProperty, error) {
+	var ip models.IntellectualProperty
+	var deletedAt *time.Time
+	err := row.Scan(&ip.ID, &ip.CaseID, &ip.IPTypes, &ip.IPProtectionStatus, &ip.IPRequestNumber,
+		&ip.CreatedAt, &ip.UpdatedAt, &deletedAt, &ip.DeletedBy)
+	ip.Deletion = deletion(deletedAt, ip.DeletedBy)
+	return ip, err
+}
+
+// 🟢 GetIPAll - fetch all intellectual property records
+func (r *IntellectualPropertyRepo) GetIPAll() ([]models.IntellectualProperty, error) {
+	rows, err := r.pool.Query(context.Background(), ipSelect+" WHERE deleted_at IS NULL ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ips []models.IntellectualProperty
+	for rows.Next() {
+		ip, err := scanIP(rows)
+		if err != nil {
+			return nil, err
+		}
+		ips = append(ips, ip)
+	}
+	return ips, rows.Err()
+}
+
+// 🟢 ListIPs
+func (r *IntellectualPropertyRepo) ListIPs(q listing.Query) (*listing.Page[models.IntellectualProperty], error) {
+	return listPage(context.Background(), r.pool, ipSelect, "intellectual_properties", q, scanIP)
+}
+
+// 🟢 GetIPByID
+func (r *IntellectualPropertyRepo) GetIPByID(ipID string) (*models.IntellectualProperty, error) {
+	ip, err := scanIP(r.pool.QueryRow(context.Background(), ipSelect+" WHERE id = $1 AND deleted_at IS NULL", ipID))
+	if err != nil {
+		return nil, wrapNoRows(err, "intellectual property", ipID)
+	}
+	return &ip, nil
+}
+
+// 🟢 GetIPByCaseID
+func (r *IntellectualPropertyRepo) GetIPByCaseID(caseID string) (*models.IntellectualProperty, error) {
+	ip, err := scanIP(r.pool.QueryRow(context.Background(), ipSelect+" WHERE case_id = $1 AND deleted_at IS NULL ORDER BY id LIMIT 1", caseID))
+	if err != nil {
+		return nil, wrapNoRows(err, "intellectual property for case", caseID)
+	}
+	return &ip, nil
+}
+
+// 🟢 CreateIP - auto generate ID IP-00001
+func (r *IntellectualPropertyRepo) CreateIP(ip *models.IntellectualProperty) error {
+	ctx := context.Background()
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		id, err := nextID(ctx, tx, "IP")
+		if err != nil {
+			return err
+		}
+		ip.ID = id
+		now := time.Now()
+		ip.CreatedAt = now
+		ip.UpdatedAt = now
+		return insertIP(ctx, tx, ip)
+	})
+}
+
+// insertIP - the INSERT of CreateIP, for callers with their own ID and transaction
+func insertIP(ctx context.Context, q querier, ip *models.IntellectualProperty) error {
+	_, err := q.Exec(ctx, `INSERT INTO intellectual_properties (id, case_id, ip_types,
+			ip_protection_status, ip_request_number, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		ip.ID, ip.CaseID, ip.IPTypes, ip.IPProtectionStatus, ip.IPRequestNumber, ip.CreatedAt, ip.UpdatedAt)
+	return err
+}
+
+// 🟢 UpdateIPByID
+func (r *IntellectualPropertyRepo) UpdateIPByID(ipID string, data map[string]interface{}) error {
+	data["updated_at"] = time.Now()
+	return updateByKey(context.Background(), r.pool, "intellectual_properties", "id", ipID, ipColumns, data)
+}
+
+// 🟢 DeleteIP - soft delete
+func (r *IntellectualPropertyRepo) DeleteIP(ipID, deletedBy string) error {
+	return trash(context.Background(), r.pool, "intellectual_properties", "id", "intellectual property", ipID, deletedBy)
+}
+
+// 🟢 RestoreIP
+func (r *IntellectualPropertyRepo) RestoreIP(ipID string) error {
+	return restore(context.Background(), r.pool, "intellectual_properties", "id", "intellectual property", ipID)
+}
+
+// 🟢 PurgeIPs
+func (r *IntellectualPropertyRepo) PurgeIPs(cutoff time.Time) ([]string, error) {
+	return purge(context.Background(), r.pool, "intellectual_properties", "id", cutoff, "")
+}
@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"trl-research-backend/internal/listing"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// listOps - the SQL operator of each filter op
+var listOps = map[listing.Op]string{listing.Eq: "=", listing.From: ">=", listing.To: "<"}
+
+// listPage runs a list request in SQL: filters, keyset pagination on (sort field, key) and
+// LIMIT n+1 to know whether there is a next page. base is "SELECT ... FROM table". The field
+// names come from the endpoint's listing.Spec; they are the JSON names of the model, which are
+// also the column names.
+func listPage[T any](ctx context.Context, q querier, base, table string, lq listing.Query, scan func(pgx.Row) (T, error)) (*listing.Page[T], error) {
+	var where []string
+	var args []any
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	for _, f := range lq.Filters {
+		where = append(where, fmt.Sprintf("%s %s %s", f.Field, listOps[f.Op], arg(f.Value)))
+	}
+	if lq.Spec.SoftDelete {
+		switch lq.Deleted {
+		case listing.HideDeleted:
+			where = append(where, "deleted_at IS NULL")
+		case listing.OnlyDeleted:
+			where = append(where, "deleted_at IS NOT NULL")
+		}
+	}
+
+	page := &listing.Page[T]{Items: []T{}}
+	filtered := whereClause(where)
+	if err := q.QueryRow(ctx, "SELECT count(*) FROM "+table+filtered, args...).Scan(&page.TotalEstimate); err != nil {
+		return nil, err
+	}
+
+	key := lq.Spec.Key
+	dir, cmp := "ASC", ">"
+	if lq.Desc {
+		dir, cmp = "DESC", "<"
+	}
+	if lq.After != nil {
+		where = append(where, fmt.Sprintf("(%s, %s) %s (%s, %s)", lq.Sort, key, cmp, arg(lq.After.Value), arg(lq.After.Key)))
+	}
+	sql := fmt.Sprintf("%s%s ORDER BY %s %s, %s %s", base, whereClause(where), lq.Sort, dir, key, dir)
+	if lq.Limit > 0 {
+		sql += fmt.Sprintf(" LIMIT %d", lq.Limit+1)
+	}
+
+	rows, err := q.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		item, err := scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		page.Items = append(page.Items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if lq.Limit > 0 && len(page.Items) > lq.Limit {
+		page.Items = page.Items[:lq.Limit]
+		page.NextPageToken = lq.NextToken(page.Items[lq.Limit-1])
+	}
+	return page, nil
+}
+
+func whereClause(conds []string) string {
+	if len(conds) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(conds, " AND ")
+}
@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"trl-research-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type LoginAttemptRepo struct {
+	pool *pgxpool.Pool
+}
+
+// 🟢 GetLoginThrottle
+func (r *LoginAttemptRepo) GetLoginThrottle(key string) (*models.LoginThrottle, error) {
+	var t models.LoginThrottle
+	err := r.pool.QueryRow(context.Background(),
+		"SELECT key, failures, last_failure_at FROM login_throttles WHERE key = $1", key).
+		Scan(&t.Key, &t.Failures, &t.LastFailureAt)
+	if err != nil {
+		return nil, wrapNoRows(err, "login throttle", key)
+	}
+	return &t, nil
+}
+
+// 🟢 RecordLoginFailure - one upsert, so concurrent failures can't overwrite each other
+func (r *LoginAttemptRepo) RecordLoginFailure(key string, resetAfter time.Duration) (*models.LoginThrottle, error) {
+	var t models.LoginThrottle
+	err := r.pool.QueryRow(context.Background(), `INSERT INTO login_throttles (key, failures, last_failure_at)
+		VALUES ($1, 1, now())
+		ON CONFLICT (key) DO UPDATE SET
+			failures = CASE WHEN login_throttles.last_failure_at < $2 THEN 1 ELSE login_throttles.failures + 1 END,
+			last_failure_at = now()
+		RETURNING key, failures, last_failure_at`, key, time.Now().Add(-resetAfter)).
+		Scan(&t.Key, &t.Failures, &t.LastFailureAt)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// 🟢 ClearLoginFailures
+func (r *LoginAttemptRepo) ClearLoginFailures(key string) error {
+	_, err := r.pool.Exec(context.Background(), "DELETE FROM login_throttles WHERE key = $1", key)
+	return err
+}
+
+// 🟢 CreateLoginAudit
+func (r *LoginAttemptRepo) CreateLoginAudit(audit *models.LoginAudit) error {
+	audit.ID = uuid.NewString()
+	audit.CreatedAt = time.Now()
+	_, err := r.pool.Exec(context.Background(), `INSERT INTO login_audit (id, email, user_id, role, ip_address,
+		user_agent, action, success, reason, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		audit.ID, audit.Email, audit.UserID, audit.Role, audit.IPAddress, audit.UserAgent, audit.Action,
+		audit.Success, audit.Reason, audit.CreatedAt)
+	return err
+}
+
+// 🟢 GetLoginAuditsByEmail
+func (r *LoginAttemptRepo) GetLoginAuditsByEmail(email string, limit int) ([]models.LoginAudit, error) {
+	rows, err := r.pool.Query(context.Background(), `SELECT id, email, user_id, role, ip_address, user_agent,
+		action, success, reason, created_at FROM login_audit WHERE email = $1
+		ORDER BY created_at DESC LIMIT $2`, email, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var audits []models.LoginAudit
+	for rows.Next() {
+		var a models.LoginAudit
+		if err := rows.Scan(&a.ID, &a.Email, &a.UserID, &a.Role, &a.IPAddress, &a.UserAgent, &a.Action,
+			&a.Success, &a.Reason, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		audits = append(audits, a)
+	}
+	return audits, rows.Err()
+}
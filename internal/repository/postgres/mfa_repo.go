@@ -0,0 +1,93 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"trl-research-backend/internal/models"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type MFARepo struct {
+	pool *pgxpool.Pool
+}
+
+// 🟢 GetMFA
+func (r *MFARepo) GetMFA(userID string) (*models.MFAEnrollment, error) {
+	var m models.MFAEnrollment
+	var enabledAt *time.Time
+	err := r.pool.QueryRow(context.Background(), `SELECT user_id, user_email, secret, enabled, recovery_code_hashes,
+		last_used_step, enabled_at, created_at, updated_at FROM mfa WHERE user_id = $1`, userID).
+		Scan(&m.UserID, &m.UserEmail, &m.Secret, &m.Enabled, &m.RecoveryCodeHashes,
+			&m.LastUsedStep, &enabledAt, &m.CreatedAt, &m.UpdatedAt)
+	if err != nil {
+		return nil, wrapNoRows(err, "mfa", userID)
+	}
+	if enabledAt != nil {
+		m.EnabledAt = *enabledAt
+	}
+	return &m, nil
+}
+
+// 🟢 SaveMFA - upsert, created_at of an existing row is kept
+func (r *MFARepo) SaveMFA(m *models.MFAEnrollment) error {
+	var enabledAt *time.Time
+	if !m.EnabledAt.IsZero() {
+		enabledAt = &m.EnabledAt
+	}
+	hashes := m.RecoveryCodeHashes
+	if hashes == nil {
+		hashes = []string{}
+	}
+	return r.pool.QueryRow(context.Background(), `INSERT INTO mfa (user_id, user_email, secret, enabled,
+		recovery_code_hashes, last_used_step, enabled_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, now(), now())
+		ON CONFLICT (user_id) DO UPDATE SET user_email = EXCLUDED.user_email, secret = EXCLUDED.secret,
+			enabled = EXCLUDED.enabled, recovery_code_hashes = EXCLUDED.recovery_code_hashes,
+			last_used_step = EXCLUDED.last_used_step, enabled_at = EXCLUDED.enabled_at, updated_at = now()
+		RETURNING created_at, updated_at`,
+		m.UserID, m.UserEmail, m.Secret, m.Enabled, hashes, m.LastUsedStep, enabledAt).
+		Scan(&m.CreatedAt, &m.UpdatedAt)
+}
+
+// 🟢 DeleteMFA
+func (r *MFARepo) DeleteMFA(userID string) error {
+	tag, err := r.pool.Exec(context.Background(), "DELETE FROM mfa WHERE user_id = $1", userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return notFound("mfa", userID)
+	}
+	return nil
+}
+
+// 🟢 UseTOTPStep - the WHERE clause keeps last_used_step moving forward only
+func (r *MFARepo) UseTOTPStep(userID string, step int64) error {
+	tag, err := r.pool.Exec(context.Background(),
+		"UPDATE mfa SET last_used_step = $1 WHERE user_id = $2 AND last_used_step < $1", step, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		if _, err := r.GetMFA(userID); err != nil {
+			return err
+		}
+		return conflict("mfa", userID)
+	}
+	return nil
+}
+
+// 🟢 UseRecoveryCode
+func (r *MFARepo) UseRecoveryCode(userID, codeHash string) (int, error) {
+	var left int
+	err := r.pool.QueryRow(context.Background(), `UPDATE mfa
+		SET recovery_code_hashes = array_remove(recovery_code_hashes, $1), updated_at = now()
+		WHERE user_id = $2 AND $1 = ANY(recovery_code_hashes)
+		RETURNING cardinality(recovery_code_hashes)`, codeHash, userID).Scan(&left)
+	if err != nil {
+		return 0, wrapNoRows(err, "recovery code", userID)
+	}
+	return left, nil
+}
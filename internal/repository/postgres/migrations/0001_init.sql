@@ -0,0 +1,152 @@
+-- Initial relational schema. Column names follow the json/firestore tags of internal/models
+-- so PATCH payloads map 1:1 onto columns.
+
+CREATE TABLE IF NOT EXISTS id_sequences (
+    prefix TEXT PRIMARY KEY,
+    value  BIGINT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS admins (
+    admin_email             TEXT PRIMARY KEY,
+    admin_id                TEXT NOT NULL UNIQUE,
+    admin_prefix            TEXT NOT NULL DEFAULT '',
+    admin_academic_position TEXT NOT NULL DEFAULT '',
+    admin_first_name        TEXT NOT NULL DEFAULT '',
+    admin_last_name         TEXT NOT NULL DEFAULT '',
+    admin_department        TEXT NOT NULL DEFAULT '',
+    admin_phone_number      TEXT NOT NULL DEFAULT '',
+    admin_password          TEXT NOT NULL DEFAULT '',
+    case_id                 TEXT NOT NULL DEFAULT '',
+    created_at              TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at              TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS researchers (
+    researcher_id                TEXT PRIMARY KEY,
+    admin_id                     TEXT NOT NULL DEFAULT '',
+    researcher_prefix            TEXT NOT NULL DEFAULT '',
+    researcher_academic_position TEXT NOT NULL DEFAULT '',
+    researcher_first_name        TEXT NOT NULL DEFAULT '',
+    researcher_last_name         TEXT NOT NULL DEFAULT '',
+    researcher_department        TEXT NOT NULL DEFAULT '',
+    researcher_phone_number      TEXT NOT NULL DEFAULT '',
+    researcher_email             TEXT NOT NULL DEFAULT '',
+    researcher_password          TEXT NOT NULL DEFAULT '',
+    created_at                   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at                   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS researchers_email_idx ON researchers (researcher_email);
+
+CREATE TABLE IF NOT EXISTS cases (
+    case_id           TEXT PRIMARY KEY,
+    researcher_id     TEXT REFERENCES researchers (researcher_id) ON DELETE RESTRICT,
+    coordinator_email TEXT NOT NULL DEFAULT '',
+    trl_score         TEXT NOT NULL DEFAULT '',
+    trl_suggestion    TEXT NOT NULL DEFAULT '',
+    status            BOOLEAN NOT NULL DEFAULT FALSE,
+    is_urgent         BOOLEAN NOT NULL DEFAULT FALSE,
+    urgent_reason     TEXT NOT NULL DEFAULT '',
+    urgent_feedback   TEXT NOT NULL DEFAULT '',
+    case_title        TEXT NOT NULL DEFAULT '',
+    case_type         TEXT NOT NULL DEFAULT '',
+    case_description  TEXT NOT NULL DEFAULT '',
+    case_keywords     TEXT NOT NULL DEFAULT '',
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at        TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS cases_researcher_idx ON cases (researcher_id);
+
+-- coordinators are keyed by email like the Firestore collection; case_id is informational
+CREATE TABLE IF NOT EXISTS coordinators (
+    coordinator_email TEXT PRIMARY KEY,
+    coordinator_id    TEXT NOT NULL DEFAULT '',
+    coordinator_name  TEXT NOT NULL DEFAULT '',
+    coordinator_phone TEXT NOT NULL DEFAULT '',
+    department        TEXT NOT NULL DEFAULT '',
+    case_id           TEXT NOT NULL DEFAULT '',
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at        TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS coordinators_case_idx ON coordinators (case_id);
+
+CREATE TABLE IF NOT EXISTS appointments (
+    appointment_id TEXT PRIMARY KEY,
+    case_id        TEXT NOT NULL REFERENCES cases (case_id) ON DELETE CASCADE,
+    date           TIMESTAMPTZ NOT NULL DEFAULT '0001-01-01 00:00:00+00',
+    status         TEXT NOT NULL DEFAULT '',
+    location       TEXT NOT NULL DEFAULT '',
+    note           TEXT NOT NULL DEFAULT '',
+    summary        TEXT NOT NULL DEFAULT '',
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at     TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS appointments_case_idx ON appointments (case_id);
+
+CREATE TABLE IF NOT EXISTS intellectual_properties (
+    id                   TEXT PRIMARY KEY,
+    case_id              TEXT NOT NULL REFERENCES cases (case_id) ON DELETE CASCADE,
+    ip_types             TEXT NOT NULL DEFAULT '',
+    ip_protection_status TEXT NOT NULL DEFAULT '',
+    ip_request_number    TEXT NOT NULL DEFAULT '',
+    created_at           TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at           TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS intellectual_properties_case_idx ON intellectual_properties (case_id);
+
+CREATE TABLE IF NOT EXISTS supporters (
+    supporter_id                       TEXT PRIMARY KEY,
+    case_id                            TEXT NOT NULL REFERENCES cases (case_id) ON DELETE CASCADE,
+    support_research                   BOOLEAN NOT NULL DEFAULT FALSE,
+    support_vdc                        BOOLEAN NOT NULL DEFAULT FALSE,
+    support_sieic                      BOOLEAN NOT NULL DEFAULT FALSE,
+    need_protect_intellectual_property BOOLEAN NOT NULL DEFAULT FALSE,
+    need_co_developers                 BOOLEAN NOT NULL DEFAULT FALSE,
+    need_activities                    BOOLEAN NOT NULL DEFAULT FALSE,
+    need_test                          BOOLEAN NOT NULL DEFAULT FALSE,
+    need_capital                       BOOLEAN NOT NULL DEFAULT FALSE,
+    need_partners                      BOOLEAN NOT NULL DEFAULT FALSE,
+    need_guidelines                    BOOLEAN NOT NULL DEFAULT FALSE,
+    need_certification                 BOOLEAN NOT NULL DEFAULT FALSE,
+    need_account                       BOOLEAN NOT NULL DEFAULT FALSE,
+    need                               TEXT NOT NULL DEFAULT '',
+    additional_documents               TEXT NOT NULL DEFAULT '',
+    created_at                         TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at                         TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS supporters_case_idx ON supporters (case_id);
+
+CREATE TABLE IF NOT EXISTS assessment_trl (
+    id               TEXT PRIMARY KEY,
+    case_id          TEXT NOT NULL REFERENCES cases (case_id) ON DELETE CASCADE,
+    trl_level_result INTEGER NOT NULL DEFAULT 0,
+    rq1_answer       BOOLEAN NOT NULL DEFAULT FALSE,
+    rq2_answer       BOOLEAN NOT NULL DEFAULT FALSE,
+    rq3_answer       BOOLEAN NOT NULL DEFAULT FALSE,
+    rq4_answer       BOOLEAN NOT NULL DEFAULT FALSE,
+    rq5_answer       BOOLEAN NOT NULL DEFAULT FALSE,
+    rq6_answer       BOOLEAN NOT NULL DEFAULT FALSE,
+    rq7_answer       BOOLEAN NOT NULL DEFAULT FALSE,
+    cq1_answer       TEXT[] NOT NULL DEFAULT '{}',
+    cq2_answer       TEXT[] NOT NULL DEFAULT '{}',
+    cq3_answer       TEXT[] NOT NULL DEFAULT '{}',
+    cq4_answer       TEXT[] NOT NULL DEFAULT '{}',
+    cq5_answer       TEXT[] NOT NULL DEFAULT '{}',
+    cq6_answer       TEXT[] NOT NULL DEFAULT '{}',
+    cq7_answer       TEXT[] NOT NULL DEFAULT '{}',
+    cq8_answer       TEXT[] NOT NULL DEFAULT '{}',
+    cq9_answer       TEXT[] NOT NULL DEFAULT '{}',
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS assessment_trl_case_idx ON assessment_trl (case_id);
+
+CREATE TABLE IF NOT EXISTS files (
+    id                 TEXT PRIMARY KEY,
+    file_name          TEXT NOT NULL DEFAULT '',
+    object_path        TEXT NOT NULL DEFAULT '',
+    bucket             TEXT NOT NULL DEFAULT '',
+    uploaded_by        TEXT NOT NULL DEFAULT '',
+    uploaded_at        TIMESTAMPTZ NOT NULL DEFAULT now(),
+    content_type       TEXT NOT NULL DEFAULT '',
+    belongs_to_case_id TEXT NOT NULL DEFAULT ''
+);
@@ -0,0 +1,17 @@
+-- Login sessions backing refresh tokens. Only the SHA-256 of the refresh secret is stored.
+
+CREATE TABLE IF NOT EXISTS sessions (
+    session_id         TEXT PRIMARY KEY,
+    user_id            TEXT NOT NULL,
+    user_email         TEXT NOT NULL DEFAULT '',
+    role               TEXT NOT NULL DEFAULT '',
+    refresh_token_hash TEXT NOT NULL,
+    user_agent         TEXT NOT NULL DEFAULT '',
+    ip_address         TEXT NOT NULL DEFAULT '',
+    revoked            BOOLEAN NOT NULL DEFAULT FALSE,
+    revoked_at         TIMESTAMPTZ,
+    expires_at         TIMESTAMPTZ NOT NULL,
+    last_used_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS sessions_user_idx ON sessions (user_id);
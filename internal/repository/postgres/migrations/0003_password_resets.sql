@@ -0,0 +1,13 @@
+-- Single-use password reset tokens, keyed by the SHA-256 of the emailed token.
+
+CREATE TABLE IF NOT EXISTS password_resets (
+    token_hash TEXT PRIMARY KEY,
+    user_id    TEXT NOT NULL,
+    user_email TEXT NOT NULL DEFAULT '',
+    role       TEXT NOT NULL,
+    used       BOOLEAN NOT NULL DEFAULT FALSE,
+    used_at    TIMESTAMPTZ,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS password_resets_user_idx ON password_resets (user_id) WHERE NOT used;
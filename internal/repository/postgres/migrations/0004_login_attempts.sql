@@ -0,0 +1,21 @@
+-- Failed login counters (per account and per IP) and the login audit trail.
+
+CREATE TABLE IF NOT EXISTS login_throttles (
+    key             TEXT PRIMARY KEY,
+    failures        INTEGER NOT NULL DEFAULT 0,
+    last_failure_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS login_audit (
+    id         TEXT PRIMARY KEY,
+    email      TEXT NOT NULL DEFAULT '',
+    user_id    TEXT NOT NULL DEFAULT '',
+    role       TEXT NOT NULL DEFAULT '',
+    ip_address TEXT NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    action     TEXT NOT NULL DEFAULT '',
+    success    BOOLEAN NOT NULL DEFAULT FALSE,
+    reason     TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS login_audit_email_idx ON login_audit (email, created_at DESC);
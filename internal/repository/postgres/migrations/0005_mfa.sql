@@ -0,0 +1,13 @@
+-- TOTP second factor, one row per user. Recovery codes are stored as SHA-256 hashes.
+
+CREATE TABLE IF NOT EXISTS mfa (
+    user_id              TEXT PRIMARY KEY,
+    user_email           TEXT NOT NULL DEFAULT '',
+    secret               TEXT NOT NULL,
+    enabled              BOOLEAN NOT NULL DEFAULT FALSE,
+    recovery_code_hashes TEXT[] NOT NULL DEFAULT '{}',
+    last_used_step       BIGINT NOT NULL DEFAULT 0,
+    enabled_at           TIMESTAMPTZ,
+    created_at           TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at           TIMESTAMPTZ NOT NULL DEFAULT now()
+);
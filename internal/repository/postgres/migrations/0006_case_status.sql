@@ -0,0 +1,25 @@
+-- Case workflow: status becomes a state name instead of a boolean (true = approved,
+-- false = still in process), every state gets the time it was entered, and moves are logged.
+
+ALTER TABLE cases ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE cases ALTER COLUMN status TYPE TEXT
+    USING CASE WHEN status THEN 'approved' ELSE 'under_review' END;
+ALTER TABLE cases ALTER COLUMN status SET DEFAULT 'draft';
+
+ALTER TABLE cases ADD COLUMN IF NOT EXISTS status_reason TEXT NOT NULL DEFAULT '';
+ALTER TABLE cases ADD COLUMN IF NOT EXISTS status_changed_at TIMESTAMPTZ;
+ALTER TABLE cases ADD COLUMN IF NOT EXISTS status_timestamps JSONB NOT NULL DEFAULT '{}';
+UPDATE cases SET status_changed_at = updated_at, status_timestamps = jsonb_build_object(status, updated_at);
+CREATE INDEX IF NOT EXISTS cases_status_idx ON cases (status);
+
+CREATE TABLE IF NOT EXISTS case_status_history (
+    id          TEXT PRIMARY KEY,
+    case_id     TEXT NOT NULL REFERENCES cases (case_id) ON DELETE CASCADE,
+    from_status TEXT NOT NULL,
+    to_status   TEXT NOT NULL,
+    reason      TEXT NOT NULL DEFAULT '',
+    actor_id    TEXT NOT NULL DEFAULT '',
+    actor_role  TEXT NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS case_status_history_case_idx ON case_status_history (case_id, created_at);
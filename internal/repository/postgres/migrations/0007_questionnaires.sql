@@ -0,0 +1,28 @@
+-- Versioned TRL questionnaires. Version 1 is built into the server and not stored; every
+-- assessment records the version it was answered against and keeps its answers by question key.
+
+CREATE TABLE IF NOT EXISTS questionnaire_templates (
+    version      INTEGER PRIMARY KEY,
+    title        JSONB NOT NULL DEFAULT '{}',
+    description  JSONB NOT NULL DEFAULT '{}',
+    questions    JSONB NOT NULL DEFAULT '[]',
+    published    BOOLEAN NOT NULL DEFAULT FALSE,
+    published_at TIMESTAMPTZ,
+    active       BOOLEAN NOT NULL DEFAULT FALSE,
+    created_by   TEXT NOT NULL DEFAULT '',
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE UNIQUE INDEX IF NOT EXISTS questionnaire_templates_active_idx ON questionnaire_templates (active) WHERE active;
+
+ALTER TABLE assessment_trl ADD COLUMN IF NOT EXISTS questionnaire_version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE assessment_trl ADD COLUMN IF NOT EXISTS readiness_answers JSONB NOT NULL DEFAULT '{}';
+ALTER TABLE assessment_trl ADD COLUMN IF NOT EXISTS criteria_answers JSONB NOT NULL DEFAULT '{}';
+UPDATE assessment_trl SET
+    readiness_answers = jsonb_build_object('rq1', rq1_answer, 'rq2', rq2_answer, 'rq3', rq3_answer,
+        'rq4', rq4_answer, 'rq5', rq5_answer, 'rq6', rq6_answer, 'rq7', rq7_answer),
+    criteria_answers = jsonb_build_object('cq1', to_jsonb(cq1_answer), 'cq2', to_jsonb(cq2_answer),
+        'cq3', to_jsonb(cq3_answer), 'cq4', to_jsonb(cq4_answer), 'cq5', to_jsonb(cq5_answer),
+        'cq6', to_jsonb(cq6_answer), 'cq7', to_jsonb(cq7_answer), 'cq8', to_jsonb(cq8_answer),
+        'cq9', to_jsonb(cq9_answer))
+WHERE readiness_answers = '{}';
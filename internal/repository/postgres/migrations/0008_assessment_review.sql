@@ -0,0 +1,8 @@
+-- Assessment history: an assessment is reviewed once (approved / rejected) and never changes after
+-- that, a re-assessment is a new row. The case's trl_score follows the latest approved one.
+
+ALTER TABLE assessment_trl ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'pending';
+ALTER TABLE assessment_trl ADD COLUMN IF NOT EXISTS reviewed_by TEXT NOT NULL DEFAULT '';
+ALTER TABLE assessment_trl ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMPTZ;
+ALTER TABLE assessment_trl ADD COLUMN IF NOT EXISTS review_note TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS assessment_trl_case_created_idx ON assessment_trl (case_id, created_at, id);
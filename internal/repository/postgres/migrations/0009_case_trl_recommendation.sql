@@ -0,0 +1,4 @@
+-- Generated TRL next steps of a case (see trl.Suggest). NULL until the case has an assessment;
+-- trl_suggestion keeps the same steps as text.
+
+ALTER TABLE cases ADD COLUMN IF NOT EXISTS trl_recommendation JSONB;
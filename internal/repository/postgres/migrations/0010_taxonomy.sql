@@ -0,0 +1,20 @@
+-- Curated terms cases refer to (see models.TaxonomyTerm). case_type / case_keywords stay as the
+-- labels of the referenced terms; internal/script/migrate_case_taxonomy.go fills the references
+-- of existing cases from those texts.
+
+CREATE TABLE IF NOT EXISTS taxonomy_terms (
+    id          TEXT PRIMARY KEY,
+    kind        TEXT NOT NULL,
+    label_th    TEXT NOT NULL DEFAULT '',
+    label_en    TEXT NOT NULL DEFAULT '',
+    synonyms    TEXT[] NOT NULL DEFAULT '{}',
+    merged_into TEXT NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS taxonomy_terms_kind_idx ON taxonomy_terms (kind);
+
+ALTER TABLE cases ADD COLUMN IF NOT EXISTS domain_ids TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE cases ADD COLUMN IF NOT EXISTS case_type_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE cases ADD COLUMN IF NOT EXISTS keyword_ids TEXT[] NOT NULL DEFAULT '{}';
+CREATE INDEX IF NOT EXISTS cases_domain_ids_idx ON cases USING GIN (domain_ids);
@@ -0,0 +1,5 @@
+-- Revision of cases and assessments, +1 on every write: the ETag the PATCH endpoints compare
+-- If-Match with. Rows written before start at 1.
+
+ALTER TABLE cases ADD COLUMN IF NOT EXISTS revision BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE assessment_trl ADD COLUMN IF NOT EXISTS revision BIGINT NOT NULL DEFAULT 1;
@@ -0,0 +1,22 @@
+-- Soft delete: a deleted row keeps its data with deleted_at set until the purge job removes it
+-- for good; the repositories only read rows with deleted_at IS NULL unless asked for the trash.
+
+ALTER TABLE admins ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE admins ADD COLUMN IF NOT EXISTS deleted_by TEXT NOT NULL DEFAULT '';
+ALTER TABLE researchers ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE researchers ADD COLUMN IF NOT EXISTS deleted_by TEXT NOT NULL DEFAULT '';
+ALTER TABLE coordinators ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE coordinators ADD COLUMN IF NOT EXISTS deleted_by TEXT NOT NULL DEFAULT '';
+ALTER TABLE supporters ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE supporters ADD COLUMN IF NOT EXISTS deleted_by TEXT NOT NULL DEFAULT '';
+ALTER TABLE appointments ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE appointments ADD COLUMN IF NOT EXISTS deleted_by TEXT NOT NULL DEFAULT '';
+ALTER TABLE cases ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE cases ADD COLUMN IF NOT EXISTS deleted_by TEXT NOT NULL DEFAULT '';
+ALTER TABLE intellectual_properties ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE intellectual_properties ADD COLUMN IF NOT EXISTS deleted_by TEXT NOT NULL DEFAULT '';
+ALTER TABLE assessment_trl ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE assessment_trl ADD COLUMN IF NOT EXISTS deleted_by TEXT NOT NULL DEFAULT '';
+
+-- the purge job looks for rows deleted before its cutoff
+CREATE INDEX IF NOT EXISTS cases_deleted_at_idx ON cases (deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,3 @@
+-- GET /case/:id/full reads the files of a case
+
+CREATE INDEX IF NOT EXISTS files_case_idx ON files (belongs_to_case_id);
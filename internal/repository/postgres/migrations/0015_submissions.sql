@@ -0,0 +1,13 @@
+-- Idempotency keys of POST /submissions (see models.SubmissionKey). submission holds the
+-- models.Submission a key created, answered again to a retry until expires_at.
+
+CREATE TABLE IF NOT EXISTS submission_keys (
+    user_id      TEXT NOT NULL,
+    key          TEXT NOT NULL,
+    request_hash TEXT NOT NULL,
+    submission   JSONB,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at   TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (user_id, key)
+);
+CREATE INDEX IF NOT EXISTS submission_keys_expires_at_idx ON submission_keys (expires_at);
@@ -0,0 +1,13 @@
+-- Submission drafts of researchers (see models.Draft). content holds the models.DraftContent
+-- as the form saved it; expired drafts are removed by the purge job.
+
+CREATE TABLE IF NOT EXISTS drafts (
+    id            TEXT PRIMARY KEY,
+    researcher_id TEXT NOT NULL,
+    content       JSONB NOT NULL DEFAULT '{}',
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at    TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX IF NOT EXISTS drafts_researcher_idx ON drafts (researcher_id, updated_at DESC);
+CREATE INDEX IF NOT EXISTS drafts_expires_at_idx ON drafts (expires_at);
@@ -0,0 +1,3 @@
+-- coordinators sign in like admins and researchers (bcrypt hash, set through the password reset)
+
+ALTER TABLE coordinators ADD COLUMN IF NOT EXISTS coordinator_password TEXT NOT NULL DEFAULT '';
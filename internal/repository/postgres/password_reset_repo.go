@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"trl-research-backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PasswordResetRepo struct {
+	pool *pgxpool.Pool
+}
+
+// 🟢 CreatePasswordReset
+func (r *PasswordResetRepo) CreatePasswordReset(reset *models.PasswordReset) error {
+	reset.CreatedAt = time.Now()
+	_, err := r.pool.Exec(context.Background(), `INSERT INTO password_resets (token_hash, user_id, user_email,
+		role, expires_at, created_at) VALUES ($1, $2, $3, $4, $5, $6)`,
+		reset.TokenHash, reset.UserID, reset.UserEmail, reset.Role, reset.ExpiresAt, reset.CreatedAt)
+	return err
+}
+
+// 🟢 ConsumePasswordReset - the UPDATE only matches an unused, unexpired token
+func (r *PasswordResetRepo) ConsumePasswordReset(tokenHash string) (*models.PasswordReset, error) {
+	ctx := context.Background()
+	var reset models.PasswordReset
+	err := r.pool.QueryRow(ctx, `UPDATE password_resets SET used = TRUE, used_at = now()
+		WHERE token_hash = $1 AND NOT used AND expires_at > now()
+		RETURNING token_hash, user_id, user_email, role, used, used_at, expires_at, created_at`, tokenHash).
+		Scan(&reset.TokenHash, &reset.UserID, &reset.UserEmail, &reset.Role, &reset.Used, &reset.UsedAt,
+			&reset.ExpiresAt, &reset.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		var exists bool
+		if err := r.pool.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM password_resets WHERE token_hash = $1)",
+			tokenHash).Scan(&exists); err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, conflict("password reset", "token")
+		}
+		return nil, notFound("password reset", "token")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &reset, nil
+}
+
+// 🟢 InvalidatePasswordResetsByUserID
+func (r *PasswordResetRepo) InvalidatePasswordResetsByUserID(userID string) error {
+	_, err := r.pool.Exec(context.Background(),
+		"UPDATE password_resets SET used = TRUE, used_at = now() WHERE user_id = $1 AND NOT used", userID)
+	return err
+}
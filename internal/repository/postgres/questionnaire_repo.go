@@ -0,0 +1,187 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"trl-research-backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type QuestionnaireRepo struct {
+	pool *pgxpool.Pool
+}
+
+const questionnaireSelect = `SELECT version, title, description, questions, published, published_at, active,
+	created_by, created_at, updated_at FROM questionnaire_templates`
+
+func scanQuestionnaire(row pgx.Row) (models.QuestionnaireTemplate, error) {
+	var t models.QuestionnaireTemplate
+	var publishedAt *time.Time
+	err := row.Scan(&t.Version, &t.Title, &t.Description, &t.Questions, &t.Published, &publishedAt,
+		&t.Active, &t.CreatedBy, &t.CreatedAt, &t.UpdatedAt)
+	if publishedAt != nil {
+		t.PublishedAt = *publishedAt
+	}
+	return t, err
+}
+
+// questions keeps the NOT NULL jsonb column an array when there are none
+func questions(q []models.Question) []models.Question {
+	if q == nil {
+		return []models.Question{}
+	}
+	return q
+}
+
+// 🟢 GetQuestionnaireTemplates - ordered by version
+func (r *QuestionnaireRepo) GetQuestionnaireTemplates() ([]models.QuestionnaireTemplate, error) {
+	rows, err := r.pool.Query(context.Background(), questionnaireSelect+" ORDER BY version")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var templates []models.QuestionnaireTemplate
+	for rows.Next() {
+		t, err := scanQuestionnaire(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, t)
+	}
+	return templates, rows.Err()
+}
+
+// 🟢 GetQuestionnaireTemplate
+func (r *QuestionnaireRepo) GetQuestionnaireTemplate(version int) (*models.QuestionnaireTemplate, error) {
+	t, err := scanQuestionnaire(r.pool.QueryRow(context.Background(), questionnaireSelect+" WHERE version = $1", version))
+	if err != nil {
+		return nil, wrapNoRows(err, "questionnaire", strconv.Itoa(version))
+	}
+	return &t, nil
+}
+
+// 🟢 GetActiveQuestionnaireTemplate
+func (r *QuestionnaireRepo) GetActiveQuestionnaireTemplate() (*models.QuestionnaireTemplate, error) {
+	t, err := scanQuestionnaire(r.pool.QueryRow(context.Background(), questionnaireSelect+" WHERE active"))
+	if err != nil {
+		return nil, wrapNoRows(err, "questionnaire", "active")
+	}
+	return &t, nil
+}
+
+// 🟢 CreateQuestionnaireTemplate - the next version is taken under a table lock
+func (r *QuestionnaireRepo) CreateQuestionnaireTemplate(t *models.QuestionnaireTemplate, minVersion int) error {
+	ctx := context.Background()
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, "LOCK TABLE questionnaire_templates IN EXCLUSIVE MODE"); err != nil {
+			return err
+		}
+		var version int
+		err := tx.QueryRow(ctx, "SELECT GREATEST(COALESCE(MAX(version) + 1, 0), $1) FROM questionnaire_templates",
+			minVersion).Scan(&version)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		t.Version = version
+		t.Published, t.Active = false, false
+		t.PublishedAt = time.Time{}
+		t.CreatedAt = now
+		t.UpdatedAt = now
+		_, err = tx.Exec(ctx, `INSERT INTO questionnaire_templates (version, title, description, questions,
+			created_by, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+			t.Version, t.Title, t.Description, questions(t.Questions), t.CreatedBy, t.CreatedAt, t.UpdatedAt)
+		return err
+	})
+}
+
+// 🟢 UpdateQuestionnaireTemplate - the WHERE clause only matches drafts
+func (r *QuestionnaireRepo) UpdateQuestionnaireTemplate(t *models.QuestionnaireTemplate) error {
+	ctx := context.Background()
+	row := r.pool.QueryRow(ctx, `UPDATE questionnaire_templates SET title = $1, description = $2, questions = $3,
+		updated_at = now() WHERE version = $4 AND NOT published
+		RETURNING version, title, description, questions, published, published_at, active,
+		created_by, created_at, updated_at`,
+		t.Title, t.Description, questions(t.Questions), t.Version)
+	updated, err := scanQuestionnaire(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return r.draftConflict(ctx, t.Version)
+	}
+	if err != nil {
+		return err
+	}
+	*t = updated
+	return nil
+}
+
+// draftConflict tells a missing template (ErrNotFound) from a published one (ErrConflict)
+func (r *QuestionnaireRepo) draftConflict(ctx context.Context, version int) error {
+	var published bool
+	err := r.pool.QueryRow(ctx, "SELECT published FROM questionnaire_templates WHERE version = $1", version).Scan(&published)
+	if err != nil {
+		return wrapNoRows(err, "questionnaire", strconv.Itoa(version))
+	}
+	return conflict("questionnaire", strconv.Itoa(version))
+}
+
+// 🟢 PublishQuestionnaireTemplate
+func (r *QuestionnaireRepo) PublishQuestionnaireTemplate(version int) error {
+	ctx := context.Background()
+	tag, err := r.pool.Exec(ctx, `UPDATE questionnaire_templates SET published = TRUE, published_at = now(),
+		updated_at = now() WHERE version = $1 AND NOT published`, version)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return r.draftConflict(ctx, version)
+	}
+	return nil
+}
+
+// 🟢 SetActiveQuestionnaireTemplate - one transaction, the partial unique index allows one active row
+func (r *QuestionnaireRepo) SetActiveQuestionnaireTemplate(version int) error {
+	ctx := context.Background()
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		if version != 0 {
+			var published bool
+			err := tx.QueryRow(ctx, "SELECT published FROM questionnaire_templates WHERE version = $1 FOR UPDATE",
+				version).Scan(&published)
+			if err != nil {
+				return wrapNoRows(err, "questionnaire", strconv.Itoa(version))
+			}
+			if !published {
+				return conflict("questionnaire", strconv.Itoa(version))
+			}
+		}
+		if _, err := tx.Exec(ctx, `UPDATE questionnaire_templates SET active = FALSE, updated_at = now()
+			WHERE active AND version <> $1`, version); err != nil {
+			return err
+		}
+		if version == 0 {
+			return nil
+		}
+		_, err := tx.Exec(ctx, `UPDATE questionnaire_templates SET active = TRUE, updated_at = now()
+			WHERE version = $1 AND NOT active`, version)
+		return err
+	})
+}
+
+// 🟢 DeleteQuestionnaireTemplate - only drafts
+func (r *QuestionnaireRepo) DeleteQuestionnaireTemplate(version int) error {
+	ctx := context.Background()
+	tag, err := r.pool.Exec(ctx, "DELETE FROM questionnaire_templates WHERE version = $1 AND NOT published", version)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return r.draftConflict(ctx, version)
+	}
+	return nil
+}
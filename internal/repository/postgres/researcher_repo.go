@@ -0,0 +1,182 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"trl-research-backend/internal/listing"
+	"trl-research-backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type ResearcherRepo struct {
+	pool *pgxpool.Pool
+}
+
+const researcherColumns = `r.researcher_id, r.admin_id, r.researcher_prefix, r.researcher_academic_position,
+	r.researcher_first_name, r.researcher_last_name, r.researcher_department, r.researcher_phone_number,
+	r.researcher_email, r.researcher_password, r.created_at, r.updated_at, r.deleted_at, r.deleted_by`
+
+const researcherSelect = "SELECT " + researcherColumns + " FROM researchers r"
+
+func scanResearcher(row pgx.Row) (models.ResearcherInfo, error) {
+	var rs models.ResearcherInfo
+	var deletedAt *time.Time
+	err := row.Scan(&rs.ResearcherID, &rs.AdminID, &rs.ResearcherPrefix, &rs.ResearcherAcademicPosition,
+		&rs.ResearcherFirstName, &rs.ResearcherLastName, &rs.ResearcherDepartment, &rs.ResearcherPhoneNumber,
+		&rs.ResearcherEmail, &rs.ResearcherPassword, &rs.CreatedAt, &rs.UpdatedAt, &deletedAt, &rs.DeletedBy)
+	rs.Deletion = deletion(deletedAt, rs.DeletedBy)
+	return rs, err
+}
+
+// 🟢 Login with password verification
+func (r *ResearcherRepo) Login(email string, password string) (*models.ResearcherInfo, error) {
+	researcher, err := scanResearcher(r.pool.QueryRow(context.Background(),
+		researcherSelect+" WHERE r.researcher_email = $1 AND r.deleted_at IS NULL ORDER BY r.researcher_id LIMIT 1", email))
+	if err != nil {
+		return nil, fmt.Errorf("researcher not found")
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(researcher.ResearcherPassword), []byte(password)); err != nil {
+		return nil, fmt.Errorf("invalid password")
+	}
+	return &researcher, nil
+}
+
+// 🟢 GetResearcherAll - fetch all researchers
+func (r *ResearcherRepo) GetResearcherAll() ([]models.ResearcherInfo, error) {
+	rows, err := r.pool.Query(context.Background(), researcherSelect+" WHERE r.deleted_at IS NULL ORDER BY r.researcher_id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var researchers []models.ResearcherInfo
+	for rows.Next() {
+		researcher, err := scanResearcher(rows)
+		if err != nil {
+			return nil, err
+		}
+		researchers = append(researchers, researcher)
+	}
+	return researchers, rows.Err()
+}
+
+// 🟢 ListResearchers
+func (r *ResearcherRepo) ListResearchers(q listing.Query) (*listing.Page[models.ResearcherInfo], error) {
+	return listPage(context.Background(), r.pool, researcherSelect, "researchers r", q, scanResearcher)
+}
+
+// 🟢 GetResearcherByID
+func (r *ResearcherRepo) GetResearcherByID(researcherID string) (*models.ResearcherInfo, error) {
+	researcher, err := scanResearcher(r.pool.QueryRow(context.Background(),
+		researcherSelect+" WHERE r.researcher_id = $1 AND r.deleted_at IS NULL", researcherID))
+	if err != nil {
+		return nil, wrapNoRows(err, "researcher", researcherID)
+	}
+	return &researcher, nil
+}
+
+// 🟢 GetResearcherByIDDirect - a primary key lookup is already consistent in PostgreSQL
+func (r *ResearcherRepo) GetResearcherByIDDirect(researcherID string) (*models.ResearcherInfo, error) {
+	return r.GetResearcherByID(researcherID)
+}
+
+// 🟢 GetResearcherByCaseID - join through cases.researcher_id
+func (r *ResearcherRepo) GetResearcherByCaseID(caseID string) (*models.ResearcherInfo, error) {
+	researcher, err := scanResearcher(r.pool.QueryRow(context.Background(),
+		"SELECT "+researcherColumns+" FROM researchers r JOIN cases c ON c.researcher_id = r.researcher_id WHERE c.case_id = $1 AND c.deleted_at IS NULL AND r.deleted_at IS NULL",
+		caseID))
+	if err != nil {
+		return nil, wrapNoRows(err, "researcher for case", caseID)
+	}
+	return &researcher, nil
+}
+
+// 🟢 GetResearcherByEmail
+func (r *ResearcherRepo) GetResearcherByEmail(email string) (*models.ResearcherInfo, error) {
+	researcher, err := scanResearcher(r.pool.QueryRow(context.Background(),
+		researcherSelect+" WHERE r.researcher_email = $1 AND r.deleted_at IS NULL ORDER BY r.researcher_id LIMIT 1", email))
+	if err != nil {
+		return nil, wrapNoRows(err, "researcher", email)
+	}
+	return &researcher, nil
+}
+
+// 🟢 CreateResearcher - auto-generate ResearcherID and create new record
+func (r *ResearcherRepo) CreateResearcher(researcher *models.ResearcherInfo) error {
+	ctx := context.Background()
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		id, err := nextID(ctx, tx, "RS")
+		if err != nil {
+			return err
+		}
+		researcher.ResearcherID = id
+		now := time.Now()
+		researcher.CreatedAt = now
+		researcher.UpdatedAt = now
+
+		_, err = tx.Exec(ctx, `INSERT INTO researchers (researcher_id, admin_id, researcher_prefix,
+			researcher_academic_position, researcher_first_name, researcher_last_name, researcher_department,
+			researcher_phone_number, researcher_email, researcher_password, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+			researcher.ResearcherID, researcher.AdminID, researcher.ResearcherPrefix,
+			researcher.ResearcherAcademicPosition, researcher.ResearcherFirstName, researcher.ResearcherLastName,
+			researcher.ResearcherDepartment, researcher.ResearcherPhoneNumber, researcher.ResearcherEmail,
+			researcher.ResearcherPassword, researcher.CreatedAt, researcher.UpdatedAt)
+		return err
+	})
+}
+
+// 🟢 UpdateResearcherByID - write back the merged record built by the handler
+func (r *ResearcherRepo) UpdateResearcherByID(researcherID string, data *models.ResearcherInfo) error {
+	data.UpdatedAt = time.Now()
+	tag, err := r.pool.Exec(context.Background(), `UPDATE researchers SET admin_id = $1, researcher_prefix = $2,
+		researcher_academic_position = $3, researcher_first_name = $4, researcher_last_name = $5,
+		researcher_department = $6, researcher_phone_number = $7, researcher_email = $8,
+		researcher_password = $9, created_at = $10, updated_at = $11
+		WHERE researcher_id = $12`,
+		data.AdminID, data.ResearcherPrefix, data.ResearcherAcademicPosition, data.ResearcherFirstName,
+		data.ResearcherLastName, data.ResearcherDepartment, data.ResearcherPhoneNumber, data.ResearcherEmail,
+		data.ResearcherPassword, data.CreatedAt, data.UpdatedAt, researcherID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return notFound("researcher", researcherID)
+	}
+	return nil
+}
+
+// 🟢 UpdateResearcherPasswordByID
+func (r *ResearcherRepo) UpdateResearcherPasswordByID(researcherID string, password string) error {
+	tag, err := r.pool.Exec(context.Background(),
+		"UPDATE researchers SET researcher_password = $1, updated_at = now() WHERE researcher_id = $2",
+		password, researcherID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return notFound("researcher", researcherID)
+	}
+	return nil
+}
+
+// 🟢 DeleteResearcher - soft delete
+func (r *ResearcherRepo) DeleteResearcher(researcherID, deletedBy string) error {
+	return trash(context.Background(), r.pool, "researchers", "researcher_id", "researcher", researcherID, deletedBy)
+}
+
+// 🟢 RestoreResearcher
+func (r *ResearcherRepo) RestoreResearcher(researcherID string) error {
+	return restore(context.Background(), r.pool, "researchers", "researcher_id", "researcher", researcherID)
+}
+
+// 🟢 PurgeResearchers - cases.researcher_id is ON DELETE RESTRICT, researchers with cases stay
+func (r *ResearcherRepo) PurgeResearchers(cutoff time.Time) ([]string, error) {
+	return purge(context.Background(), r.pool, "researchers", "researcher_id", cutoff,
+		" AND NOT EXISTS (SELECT 1 FROM cases c WHERE c.researcher_id = researchers.researcher_id)")
+}
@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"trl-research-backend/internal/models"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// testPool connects to DB_URL with its own schema, migrated and dropped after the test,
+// so the counters of the database it points at are never touched. Skips without DB_URL.
+func testPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+	dbURL := os.Getenv("DB_URL")
+	if dbURL == "" {
+		t.Skip("DB_URL is not set")
+	}
+	ctx := context.Background()
+	schema := fmt.Sprintf("trl_test_%d", time.Now().UnixNano())
+
+	admin, err := pgxpool.New(ctx, dbURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(admin.Close)
+	if _, err := admin.Exec(ctx, "CREATE SCHEMA "+schema); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { admin.Exec(ctx, "DROP SCHEMA "+schema+" CASCADE") })
+
+	cfg, err := pgxpool.ParseConfig(dbURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.ConnConfig.RuntimeParams["search_path"] = schema
+	pool, err := pgxpool.NewWithConfig(ctx, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(pool.Close)
+	if err := Migrate(ctx, pool); err != nil {
+		t.Fatal(err)
+	}
+	return pool
+}
+
+func TestCreateCaseParallelIDsAreUnique(t *testing.T) {
+	const n = 100
+	repos := NewRepositories(testPool(t))
+
+	var wg sync.WaitGroup
+	ids := make([]string, n)
+	errs := make([]error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			cs := &models.CaseInfo{CaseTitle: fmt.Sprintf("sequence test %d", i)}
+			errs[i] = repos.Case.CreateCase(cs)
+			ids[i] = cs.CaseID
+		}(i)
+	}
+	wg.Wait()
+
+	seen := map[string]bool{}
+	for i, id := range ids {
+		if errs[i] != nil {
+			t.Fatalf("CreateCase: %v", errs[i])
+		}
+		if seen[id] {
+			t.Fatalf("duplicate ID %s", id)
+		}
+		seen[id] = true
+	}
+}
+
+func TestSequenceContinuesFromHighestStoredID(t *testing.T) {
+	pool := testPool(t)
+	ctx := context.Background()
+	// rows from before the counter existed, e.g. imported from Firestore
+	for _, id := range []string{"CS-00007", "CS-00041", "CS-abc", "XX-00099"} {
+		if _, err := pool.Exec(ctx, "INSERT INTO cases (case_id) VALUES ($1)", id); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	repos := NewRepositories(pool)
+	for _, want := range []string{"CS-00042", "CS-00043"} {
+		cs := &models.CaseInfo{CaseTitle: "after import"}
+		if err := repos.Case.CreateCase(cs); err != nil {
+			t.Fatalf("CreateCase: %v", err)
+		}
+		if cs.CaseID != want {
+			t.Errorf("got %s, want %s", cs.CaseID, want)
+		}
+	}
+
+	// a prefix without stored IDs starts at 1
+	ap := &models.Appointment{CaseID: "CS-00042", Date: time.Now()}
+	if err := repos.Appointment.CreateAppointment(ap); err != nil {
+		t.Fatalf("CreateAppointment: %v", err)
+	}
+	if ap.AppointmentID != "AP-00001" {
+		t.Errorf("got %s, want AP-00001", ap.AppointmentID)
+	}
+}
@@ -0,0 +1,111 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"trl-research-backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type SessionRepo struct {
+	pool *pgxpool.Pool
+}
+
+const sessionSelect = `SELECT session_id, user_id, user_email, role, refresh_token_hash, user_agent,
+	ip_address, revoked, revoked_at, expires_at, last_used_at, created_at FROM sessions`
+
+func scanSession(row pgx.Row) (models.Session, error) {
+	var s models.Session
+	var revokedAt *time.Time
+	err := row.Scan(&s.SessionID, &s.UserID, &s.UserEmail, &s.Role, &s.RefreshTokenHash, &s.UserAgent,
+		&s.IPAddress, &s.Revoked, &revokedAt, &s.ExpiresAt, &s.LastUsedAt, &s.CreatedAt)
+	if revokedAt != nil {
+		s.RevokedAt = *revokedAt
+	}
+	return s, err
+}
+
+// 🟢 CreateSession
+func (r *SessionRepo) CreateSession(s *models.Session) error {
+	now := time.Now()
+	s.CreatedAt = now
+	s.LastUsedAt = now
+
+	_, err := r.pool.Exec(context.Background(), `INSERT INTO sessions (session_id, user_id, user_email, role,
+		refresh_token_hash, user_agent, ip_address, expires_at, last_used_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		s.SessionID, s.UserID, s.UserEmail, s.Role, s.RefreshTokenHash, s.UserAgent, s.IPAddress,
+		s.ExpiresAt, s.LastUsedAt, s.CreatedAt)
+	return err
+}
+
+// 🟢 GetSessionByID
+func (r *SessionRepo) GetSessionByID(sessionID string) (*models.Session, error) {
+	s, err := scanSession(r.pool.QueryRow(context.Background(), sessionSelect+" WHERE session_id = $1", sessionID))
+	if err != nil {
+		return nil, wrapNoRows(err, "session", sessionID)
+	}
+	return &s, nil
+}
+
+// 🟢 GetSessionsByUserID
+func (r *SessionRepo) GetSessionsByUserID(userID string) ([]models.Session, error) {
+	rows, err := r.pool.Query(context.Background(), sessionSelect+" WHERE user_id = $1 ORDER BY session_id", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []models.Session
+	for rows.Next() {
+		s, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, s)
+	}
+	return sessions, rows.Err()
+}
+
+// 🟢 RotateSession - the WHERE clause is the compare-and-swap
+func (r *SessionRepo) RotateSession(sessionID, oldHash, newHash string, expiresAt time.Time) error {
+	ctx := context.Background()
+	tag, err := r.pool.Exec(ctx, `UPDATE sessions SET refresh_token_hash = $1, expires_at = $2, last_used_at = now()
+		WHERE session_id = $3 AND refresh_token_hash = $4 AND NOT revoked`, newHash, expiresAt, sessionID, oldHash)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		if _, err := r.GetSessionByID(sessionID); err != nil {
+			return err
+		}
+		return conflict("session", sessionID)
+	}
+	return nil
+}
+
+// 🟢 RevokeSession
+func (r *SessionRepo) RevokeSession(sessionID string) error {
+	tag, err := r.pool.Exec(context.Background(),
+		"UPDATE sessions SET revoked = TRUE, revoked_at = COALESCE(revoked_at, now()) WHERE session_id = $1", sessionID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return notFound("session", sessionID)
+	}
+	return nil
+}
+
+// 🟢 RevokeSessionsByUserID
+func (r *SessionRepo) RevokeSessionsByUserID(userID string) (int, error) {
+	tag, err := r.pool.Exec(context.Background(),
+		"UPDATE sessions SET revoked = TRUE, revoked_at = now() WHERE user_id = $1 AND NOT revoked", userID)
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
@@ -0,0 +1,410 @@
+package postgres
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"trl-research-backend/internal/models"
+	"trl-research-backend/internal/repository"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+//go:embed migrations/*.sql
+var migrationFS embed.FS
+
+// migrationLockID is the pg_advisory_lock key held while migrating, so two instances
+// starting at the same time don't apply the same file twice
+const migrationLockID = 7274_1001
+
+// querier is satisfied by both *pgxpool.Pool and pgx.Tx
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// NewRepositories - every repository backed by the given PostgreSQL pool
+func NewRepositories(pool *pgxpool.Pool) *repository.Repositories {
+	return &repository.Repositories{
+		Admin:                &AdminRepo{pool: pool},
+		Researcher:           &ResearcherRepo{pool: pool},
+		Coordinator:          &CoordinatorRepo{pool: pool},
+		Supporter:            &SupporterRepo{pool: pool},
+		Appointment:          &AppointmentRepo{pool: pool},
+		Case:                 &CaseRepo{pool: pool},
+		IntellectualProperty: &IntellectualPropertyRepo{pool: pool},
+		AssessmentTrl:        &AssessmentTrlRepo{pool: pool},
+		File:                 &FileRepo{pool: pool},
+		Session:              &SessionRepo{pool: pool},
+		PasswordReset:        &PasswordResetRepo{pool: pool},
+		LoginAttempt:         &LoginAttemptRepo{pool: pool},
+		MFA:                  &MFARepo{pool: pool},
+		Questionnaire:        &QuestionnaireRepo{pool: pool},
+		Taxonomy:             &TaxonomyRepo{pool: pool},
+		Audit:                &AuditRepo{pool: pool},
+		Submission:           &SubmissionRepo{pool: pool},
+		Draft:                &DraftRepo{pool: pool},
+		Sequence:             &Sequence{pool: pool},
+	}
+}
+
+// Migrate applies every embedded migrations/*.sql file that is not yet recorded in
+// schema_migrations, in file-name order, each inside its own transaction
+func Migrate(ctx context.Context, pool *pgxpool.Pool) error {
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "SELECT pg_advisory_lock($1)", migrationLockID); err != nil {
+		return err
+	}
+	defer conn.Exec(ctx, "SELECT pg_advisory_unlock($1)", migrationLockID)
+
+	if _, err := conn.Exec(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    TEXT PRIMARY KEY,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`); err != nil {
+		return err
+	}
+
+	entries, err := migrationFS.ReadDir("migrations")
+	if err != nil {
+		return err
+	}
+	var files []string
+	for _, e := range entries {
+		if strings.HasSuffix(e.Name(), ".sql") {
+			files = append(files, e.Name())
+		}
+	}
+	sort.Strings(files)
+
+	for _, name := range files {
+		version := strings.TrimSuffix(name, ".sql")
+		var applied bool
+		if err := conn.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)", version).Scan(&applied); err != nil {
+			return err
+		}
+		if applied {
+			continue
+		}
+
+		body, err := migrationFS.ReadFile("migrations/" + name)
+		if err != nil {
+			return err
+		}
+		err = pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+			if _, err := tx.Exec(ctx, string(body)); err != nil {
+				return err
+			}
+			_, err := tx.Exec(ctx, "INSERT INTO schema_migrations (version) VALUES ($1)", version)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("migration %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Sequence hands out IDs from the id_sequences table
+type Sequence struct {
+	pool *pgxpool.Pool
+}
+
+func (s *Sequence) Next(prefix string) (string, error) {
+	return nextID(context.Background(), s.pool, prefix)
+}
+
+// sequenceSources - the table and column holding each prefix's IDs, like
+// repository.sequenceSources: a missing counter continues from the highest stored ID
+var sequenceSources = map[string][2]string{
+	"AD": {"admins", "admin_id"},
+	"RS": {"researchers", "researcher_id"},
+	"CS": {"cases", "case_id"},
+	"AP": {"appointments", "appointment_id"},
+	"AS": {"assessment_trl", "id"},
+	"SP": {"supporters", "supporter_id"},
+	"IP": {"intellectual_properties", "id"},
+	"TX": {"taxonomy_terms", "id"},
+	"DR": {"drafts", "id"},
+}
+
+// nextID atomically bumps the counter row for prefix. Creates call it inside their own
+// transaction so a failed insert doesn't burn a number.
+func nextID(ctx context.Context, q querier, prefix string) (string, error) {
+	var n int64
+	err := q.QueryRow(ctx, `UPDATE id_sequences SET value = value + 1 WHERE prefix = $1
+		RETURNING value`, prefix).Scan(&n)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// first ID of the prefix: start after what is already stored. Two first calls
+		// at once both read the same highest ID, the loser of the insert bumps the winner's row.
+		var highest int64
+		highest, err = highestExistingID(ctx, q, prefix)
+		if err == nil {
+			err = q.QueryRow(ctx, `INSERT INTO id_sequences (prefix, value) VALUES ($1, $2 + 1)
+				ON CONFLICT (prefix) DO UPDATE SET value = id_sequences.value + 1
+				RETURNING value`, prefix, highest).Scan(&n)
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+	return repository.FormatID(prefix, n), nil
+}
+
+// highestExistingID - the largest numeric suffix of prefix's stored IDs, 0 when there are none
+func highestExistingID(ctx context.Context, q querier, prefix string) (int64, error) {
+	src, ok := sequenceSources[prefix]
+	if !ok {
+		return 0, nil
+	}
+	var n int64
+	err := q.QueryRow(ctx, fmt.Sprintf(`SELECT COALESCE(MAX(substring(%s FROM $1)::BIGINT), 0) FROM %s`,
+		src[1], src[0]), "^"+regexp.QuoteMeta(prefix)+"-([0-9]{1,18})$").Scan(&n)
+	return n, err
+}
+
+type columnKind int
+
+const (
+	textColumn         columnKind = iota
+	nullableTextColumn            // optional foreign key, "" is stored as NULL
+	boolColumn
+	intColumn
+	timeColumn
+	textArrayColumn
+	jsonColumn // JSONB value, PATCH replaces the whole value
+)
+
+// columns maps the JSON keys accepted by PATCH endpoints onto table columns.
+// Primary keys are deliberately left out so they can't be rewritten.
+type columns map[string]columnKind
+
+// updateByKey runs UPDATE table SET ... WHERE key = id for every known key in data
+func updateByKey(ctx context.Context, q querier, table, key, id string, cols columns, data map[string]interface{}) error {
+	var sets []string
+	var args []any
+	fields := make([]string, 0, len(data))
+	for field := range data {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		kind, ok := cols[field]
+		if !ok {
+			continue
+		}
+		value, err := convert(kind, data[field])
+		if err != nil {
+			return fmt.Errorf("%s: %w", field, err)
+		}
+		args = append(args, value)
+		sets = append(sets, fmt.Sprintf("%s = $%d", field, len(args)))
+	}
+	if len(sets) == 0 {
+		return nil
+	}
+
+	args = append(args, id)
+	sql := fmt.Sprintf("UPDATE %s SET %s WHERE %s = $%d", table, strings.Join(sets, ", "), key, len(args))
+	tag, err := q.Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return notFound(table, id)
+	}
+	return nil
+}
+
+// convert turns a decoded JSON value into the Go type pgx expects for the column
+func convert(kind columnKind, v interface{}) (interface{}, error) {
+	switch kind {
+	case textColumn:
+		if v == nil {
+			return "", nil
+		}
+		if s, ok := v.(string); ok {
+			return s, nil
+		}
+	case nullableTextColumn:
+		if v == nil {
+			return nil, nil
+		}
+		if s, ok := v.(string); ok {
+			return nullIfEmpty(s), nil
+		}
+	case boolColumn:
+		if b, ok := v.(bool); ok {
+			return b, nil
+		}
+	case intColumn:
+		switch n := v.(type) {
+		case int, int64:
+			return n, nil
+		case float64:
+			return int(n), nil
+		}
+	case timeColumn:
+		switch t := v.(type) {
+		case time.Time:
+			return t, nil
+		case string:
+			return time.Parse(time.RFC3339, t)
+		}
+	case jsonColumn:
+		switch m := v.(type) {
+		case nil:
+			return map[string]interface{}{}, nil
+		case map[string]interface{}:
+			return m, nil
+		case map[string]bool, map[string][]string, *models.TrlRecommendation:
+			return m, nil
+		}
+	case textArrayColumn:
+		switch a := v.(type) {
+		case nil:
+			return []string{}, nil
+		case []string:
+			return a, nil
+		case []interface{}:
+			out := make([]string, 0, len(a))
+			for _, item := range a {
+				s, ok := item.(string)
+				if !ok {
+					return nil, fmt.Errorf("expected array of strings")
+				}
+				out = append(out, s)
+			}
+			return out, nil
+		}
+	}
+	return nil, fmt.Errorf("unexpected value %v", v)
+}
+
+// setDeletion moves a row to the trash (d set) or back (d zero); notFound unless it is live /
+// deleted to begin with
+func setDeletion(ctx context.Context, q querier, table, key, kind, id string, d models.Deletion) error {
+	var tag pgconn.CommandTag
+	var err error
+	if d.IsDeleted() {
+		tag, err = q.Exec(ctx, fmt.Sprintf("UPDATE %s SET deleted_at = $1, deleted_by = $2, updated_at = $1 WHERE %s = $3 AND deleted_at IS NULL", table, key),
+			d.DeletedAt, d.DeletedBy, id)
+	} else {
+		kind = "deleted " + kind
+		tag, err = q.Exec(ctx, fmt.Sprintf("UPDATE %s SET deleted_at = NULL, deleted_by = '', updated_at = now() WHERE %s = $1 AND deleted_at IS NOT NULL", table, key), id)
+	}
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return notFound(kind, id)
+	}
+	return nil
+}
+
+// trash / restore - DeleteX and RestoreX of a table
+func trash(ctx context.Context, q querier, table, key, kind, id, deletedBy string) error {
+	return setDeletion(ctx, q, table, key, kind, id, models.Deletion{DeletedAt: time.Now(), DeletedBy: deletedBy})
+}
+
+func restore(ctx context.Context, q querier, table, key, kind, id string) error {
+	return setDeletion(ctx, q, table, key, kind, id, models.Deletion{})
+}
+
+// purge removes the rows of table deleted before cutoff and returns their key column, sorted;
+// and adds a condition of the table
+func purge(ctx context.Context, q querier, table, key string, cutoff time.Time, and string) ([]string, error) {
+	return deleteReturning(ctx, q, "DELETE FROM "+table+" WHERE deleted_at < $1"+and+" RETURNING "+key, cutoff)
+}
+
+// deleteReturning runs a DELETE ... RETURNING of one text column
+func deleteReturning(ctx context.Context, q querier, sql string, args ...any) ([]string, error) {
+	rows, err := q.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// deletion - a scanned deleted_at (NULL while live) and deleted_by
+func deletion(at *time.Time, by string) models.Deletion {
+	if at == nil {
+		return models.Deletion{}
+	}
+	return models.Deletion{DeletedAt: *at, DeletedBy: by}
+}
+
+func notFound(kind, id string) error {
+	return fmt.Errorf("%s %s: %w", kind, id, repository.ErrNotFound)
+}
+
+func conflict(kind, id string) error {
+	return fmt.Errorf("%s %s: %w", kind, id, repository.ErrConflict)
+}
+
+func stale(kind, id string) error {
+	return fmt.Errorf("%s %s: %w", kind, id, repository.ErrStale)
+}
+
+// wrapNoRows maps pgx.ErrNoRows to repository.ErrNotFound
+func wrapNoRows(err error, kind, id string) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return notFound(kind, id)
+	}
+	return err
+}
+
+// nullIfEmpty stores "" as NULL so optional foreign keys stay valid
+func nullIfEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// emptyStrings keeps NOT NULL text[] columns happy when the client sent nothing
+func emptyStrings(a []string) []string {
+	if a == nil {
+		return []string{}
+	}
+	return a
+}
+
+var (
+	_ repository.AdminRepository                = (*AdminRepo)(nil)
+	_ repository.ResearcherRepository           = (*ResearcherRepo)(nil)
+	_ repository.CoordinatorRepository          = (*CoordinatorRepo)(nil)
+	_ repository.SupporterRepository            = (*SupporterRepo)(nil)
+	_ repository.AppointmentRepository          = (*AppointmentRepo)(nil)
+	_ repository.CaseRepository                 = (*CaseRepo)(nil)
+	_ repository.IntellectualPropertyRepository = (*IntellectualPropertyRepo)(nil)
+	_ repository.AssessmentTrlRepository        = (*AssessmentTrlRepo)(nil)
+	_ repository.FileRepository                 = (*FileRepo)(nil)
+	_ repository.SessionRepository              = (*SessionRepo)(nil)
+	_ repository.PasswordResetRepository        = (*PasswordResetRepo)(nil)
+	_ repository.LoginAttemptRepository         = (*LoginAttemptRepo)(nil)
+	_ repository.MFARepository                  = (*MFARepo)(nil)
+	_ repository.QuestionnaireRepository        = (*QuestionnaireRepo)(nil)
+	_ repository.TaxonomyRepository             = (*TaxonomyRepo)(nil)
+	_ repository.AuditRepository                = (*AuditRepo)(nil)
+	_ repository.SequenceGenerator              = (*Sequence)(nil)
+)
@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"trl-research-backend/internal/models"
+	"trl-research-backend/internal/repository"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type SubmissionRepo struct {
+	pool *pgxpool.Pool
+}
+
+// 🟢 CreateSubmission - the key row is claimed first, so a concurrent retry waits on it and
+// then replays what this one created
+func (r *SubmissionRepo) CreateSubmission(key models.SubmissionKey, s *models.Submission) (bool, error) {
+	ctx := context.Background()
+	replayed := false
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		now := time.Now()
+		if key.Key != "" {
+			stored, err := claimKey(ctx, tx, key, now)
+			if err != nil {
+				return err
+			}
+			if stored != nil {
+				*s, replayed = stored.Submission, true
+				return nil
+			}
+		}
+
+		next := func(prefix string) (string, error) { return nextID(ctx, tx, prefix) }
+		if err := repository.PrepareSubmission(s, next, now); err != nil {
+			return err
+		}
+		if err := insertCase(ctx, tx, &s.Case); err != nil {
+			return err
+		}
+		if s.Supporter != nil {
+			if err := insertSupporter(ctx, tx, s.Supporter); err != nil {
+				return err
+			}
+		}
+		for i := range s.IPs {
+			if err := insertIP(ctx, tx, &s.IPs[i]); err != nil {
+				return err
+			}
+		}
+		if s.Assessment != nil {
+			if err := insertAssessmentTrl(ctx, tx, s.Assessment); err != nil {
+				return err
+			}
+		}
+		for i := range s.Files {
+			if err := saveFile(ctx, tx, &s.Files[i]); err != nil {
+				return err
+			}
+		}
+		if key.Key == "" {
+			return nil
+		}
+		_, err := tx.Exec(ctx, "UPDATE submission_keys SET submission = $3 WHERE user_id = $1 AND key = $2",
+			key.UserID, key.Key, s)
+		return err
+	})
+	return replayed, err
+}
+
+// claimKey inserts the key row, or locks the one already there: nil when this request may go
+// on (a new or expired key, now holding its hash), the stored record for a retry
+func claimKey(ctx context.Context, tx pgx.Tx, key models.SubmissionKey, now time.Time) (*models.SubmissionRecord, error) {
+	tag, err := tx.Exec(ctx, `INSERT INTO submission_keys (user_id, key, request_hash, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5) ON CONFLICT (user_id, key) DO NOTHING`,
+		key.UserID, key.Key, key.RequestHash, key.CreatedAt, key.ExpiresAt)
+	if err != nil || tag.RowsAffected() == 1 {
+		return nil, err
+	}
+
+	stored := models.SubmissionRecord{SubmissionKey: models.SubmissionKey{UserID: key.UserID, Key: key.Key}}
+	err = tx.QueryRow(ctx, `SELECT request_hash, submission, created_at, expires_at FROM submission_keys
+		WHERE user_id = $1 AND key = $2 FOR UPDATE`, key.UserID, key.Key).
+		Scan(&stored.RequestHash, &stored.Submission, &stored.CreatedAt, &stored.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	replay, err := repository.ReplayOf(&stored, key, now)
+	if err != nil || replay != nil {
+		return replay, err
+	}
+	_, err = tx.Exec(ctx, `UPDATE submission_keys SET request_hash = $3, submission = NULL, created_at = $4,
+		expires_at = $5 WHERE user_id = $1 AND key = $2`,
+		key.UserID, key.Key, key.RequestHash, key.CreatedAt, key.ExpiresAt)
+	return nil, err
+}
@@ -0,0 +1,147 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"trl-research-backend/internal/listing"
+	"trl-research-backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type SupporterRepo struct {
+	pool *pgxpool.Pool
+}
+
+const supporterSelect = `SELECT supporter_id, case_id, support_research, support_vdc, support_sieic,
+	need_protect_intellectual_property, need_co_developers, need_activities, need_test, need_capital,
+	need_partners, need_guidelines, need_certification, need_account, need, additional_documents,
+	created_at, updated_at, deleted_at, deleted_by FROM supporters`
+
+var supporterColumns = columns{
+	"case_id":                            textColumn,
+	"support_research":                   boolColumn,
+	"support_vdc":                        boolColumn,
+	"support_sieic":                      boolColumn,
+	"need_protect_intellectual_property": boolColumn,
+	"need_co_developers":                 boolColumn,
+	"need_activities":                    boolColumn,
+	"need_test":                          boolColumn,
+	"need_capital":                       boolColumn,
+	"need_partners":                      boolColumn,
+	"need_guidelines":                    boolColumn,
+	"need_certification":                 boolColumn,
+	"need_account":                       boolColumn,
+	"need":                               textColumn,
+	"additional_documents":               textColumn,
+	"created_at":                         timeColumn,
+	"updated_at":                         timeColumn,
+}
+
+func scanSupporter(row pgx.Row) (models.Supporter, error) {
+	var s models.Supporter
+	var deletedAt *time.Time
+	err := row.Scan(&s.SupporterID, &s.CaseID, &s.SupportResearch, &s.SupportVDC, &s.SupportSiEIC,
+		&s.NeedProtectIntellectualProperty, &s.NeedCoDevelopers, &s.NeedActivities, &s.NeedTest,
+		&s.NeedCapital, &s.NeedPartners, &s.NeedGuidelines, &s.NeedCertification, &s.NeedAccount,
+		&s.Need, &s.AdditionalDocuments, &s.CreatedAt, &s.UpdatedAt, &deletedAt, &s.DeletedBy)
+	s.Deletion = deletion(deletedAt, s.DeletedBy)
+	return s, err
+}
+
+// 🟢 GetSupporterAll
+func (r *SupporterRepo) GetSupporterAll() ([]models.Supporter, error) {
+	rows, err := r.pool.Query(context.Background(), supporterSelect+" WHERE deleted_at IS NULL ORDER BY supporter_id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var supporters []models.Supporter
+	for rows.Next() {
+		supporter, err := scanSupporter(rows)
+		if err != nil {
+			return nil, err
+		}
+		supporters = append(supporters, supporter)
+	}
+	return supporters, rows.Err()
+}
+
+// 🟢 ListSupporters
+func (r *SupporterRepo) ListSupporters(q listing.Query) (*listing.Page[models.Supporter], error) {
+	return listPage(context.Background(), r.pool, supporterSelect, "supporters", q, scanSupporter)
+}
+
+// 🟢 GetSupporterByID
+func (r *SupporterRepo) GetSupporterByID(supporterID string) (*models.Supporter, error) {
+	supporter, err := scanSupporter(r.pool.QueryRow(context.Background(),
+		supporterSelect+" WHERE supporter_id = $1 AND deleted_at IS NULL", supporterID))
+	if err != nil {
+		return nil, wrapNoRows(err, "supporter", supporterID)
+	}
+	return &supporter, nil
+}
+
+// 🟢 GetSupporterByCaseID
+func (r *SupporterRepo) GetSupporterByCaseID(caseID string) (*models.Supporter, error) {
+	supporter, err := scanSupporter(r.pool.QueryRow(context.Background(),
+		supporterSelect+" WHERE case_id = $1 AND deleted_at IS NULL ORDER BY supporter_id LIMIT 1", caseID))
+	if err != nil {
+		return nil, wrapNoRows(err, "supporter for case", caseID)
+	}
+	return &supporter, nil
+}
+
+// 🟢 CreateSupporter - auto-generate ID SP-00001
+func (r *SupporterRepo) CreateSupporter(s *models.Supporter) error {
+	ctx := context.Background()
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		id, err := nextID(ctx, tx, "SP")
+		if err != nil {
+			return err
+		}
+		s.SupporterID = id
+		now := time.Now()
+		s.CreatedAt = now
+		s.UpdatedAt = now
+		return insertSupporter(ctx, tx, s)
+	})
+}
+
+// insertSupporter - the INSERT of CreateSupporter, for callers with their own ID and transaction
+func insertSupporter(ctx context.Context, q querier, s *models.Supporter) error {
+	_, err := q.Exec(ctx, `INSERT INTO supporters (supporter_id, case_id, support_research, support_vdc,
+			support_sieic, need_protect_intellectual_property, need_co_developers, need_activities, need_test,
+			need_capital, need_partners, need_guidelines, need_certification, need_account, need,
+			additional_documents, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)`,
+		s.SupporterID, s.CaseID, s.SupportResearch, s.SupportVDC, s.SupportSiEIC,
+		s.NeedProtectIntellectualProperty, s.NeedCoDevelopers, s.NeedActivities, s.NeedTest,
+		s.NeedCapital, s.NeedPartners, s.NeedGuidelines, s.NeedCertification, s.NeedAccount,
+		s.Need, s.AdditionalDocuments, s.CreatedAt, s.UpdatedAt)
+	return err
+}
+
+// 🟢 UpdateSupporterByID
+func (r *SupporterRepo) UpdateSupporterByID(supporterID string, data map[string]interface{}) error {
+	data["updated_at"] = time.Now()
+	return updateByKey(context.Background(), r.pool, "supporters", "supporter_id", supporterID, supporterColumns, data)
+}
+
+// 🟢 DeleteSupporter - soft delete
+func (r *SupporterRepo) DeleteSupporter(supporterID, deletedBy string) error {
+	return trash(context.Background(), r.pool, "supporters", "supporter_id", "supporter", supporterID, deletedBy)
+}
+
+// 🟢 RestoreSupporter
+func (r *SupporterRepo) RestoreSupporter(supporterID string) error {
+	return restore(context.Background(), r.pool, "supporters", "supporter_id", "supporter", supporterID)
+}
+
+// 🟢 PurgeSupporters
+func (r *SupporterRepo) PurgeSupporters(cutoff time.Time) ([]string, error) {
+	return purge(context.Background(), r.pool, "supporters", "supporter_id", cutoff, "")
+}
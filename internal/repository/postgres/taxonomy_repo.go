@@ -0,0 +1,125 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"trl-research-backend/internal/models"
+	"trl-research-backend/internal/repository"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type TaxonomyRepo struct {
+	pool *pgxpool.Pool
+}
+
+const termSelect = `SELECT id, kind, label_th, label_en, synonyms, merged_into, created_at, updated_at
+	FROM taxonomy_terms`
+
+func scanTerm(row pgx.Row) (models.TaxonomyTerm, error) {
+	var t models.TaxonomyTerm
+	err := row.Scan(&t.ID, &t.Kind, &t.Label.TH, &t.Label.EN, &t.Synonyms, &t.MergedInto, &t.CreatedAt, &t.UpdatedAt)
+	return t, err
+}
+
+// 🟢 GetTaxonomyTerms
+func (r *TaxonomyRepo) GetTaxonomyTerms(kind string) ([]models.TaxonomyTerm, error) {
+	rows, err := r.pool.Query(context.Background(), termSelect+" WHERE $1 = '' OR kind = $1 ORDER BY id", kind)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	terms := []models.TaxonomyTerm{}
+	for rows.Next() {
+		t, err := scanTerm(rows)
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, t)
+	}
+	return terms, rows.Err()
+}
+
+// 🟢 GetTaxonomyTermByID
+func (r *TaxonomyRepo) GetTaxonomyTermByID(id string) (*models.TaxonomyTerm, error) {
+	t, err := scanTerm(r.pool.QueryRow(context.Background(), termSelect+" WHERE id = $1", id))
+	if err != nil {
+		return nil, wrapNoRows(err, "taxonomy term", id)
+	}
+	return &t, nil
+}
+
+// 🟢 CreateTaxonomyTerm - auto generate ID (TX-00001)
+func (r *TaxonomyRepo) CreateTaxonomyTerm(t *models.TaxonomyTerm) error {
+	ctx := context.Background()
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		id, err := nextID(ctx, tx, "TX")
+		if err != nil {
+			return err
+		}
+		t.ID = id
+		now := time.Now()
+		t.CreatedAt = now
+		t.UpdatedAt = now
+		_, err = tx.Exec(ctx, `INSERT INTO taxonomy_terms (id, kind, label_th, label_en, synonyms, merged_into,
+			created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+			t.ID, t.Kind, t.Label.TH, t.Label.EN, emptyStrings(t.Synonyms), t.MergedInto, t.CreatedAt, t.UpdatedAt)
+		return err
+	})
+}
+
+// 🟢 UpdateTaxonomyTerm
+func (r *TaxonomyRepo) UpdateTaxonomyTerm(t *models.TaxonomyTerm) error {
+	t.UpdatedAt = time.Now()
+	tag, err := r.pool.Exec(context.Background(), `UPDATE taxonomy_terms SET label_th = $1, label_en = $2,
+		synonyms = $3, merged_into = $4, updated_at = $5 WHERE id = $6`,
+		t.Label.TH, t.Label.EN, emptyStrings(t.Synonyms), t.MergedInto, t.UpdatedAt, t.ID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return notFound("taxonomy term", t.ID)
+	}
+	return nil
+}
+
+// 🟢 ApplyTermChange - the case rows are locked before anything is written
+func (r *TaxonomyRepo) ApplyTermChange(terms []models.TaxonomyTerm, cases []repository.CasePatch) error {
+	ctx := context.Background()
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		for _, p := range cases {
+			var current int64
+			err := tx.QueryRow(ctx, "SELECT revision FROM cases WHERE case_id = $1 FOR UPDATE", p.CaseID).Scan(&current)
+			if err != nil {
+				return wrapNoRows(err, "case", p.CaseID)
+			}
+			if current != p.Revision {
+				return stale("case", p.CaseID)
+			}
+		}
+		now := time.Now()
+		for i, t := range terms {
+			terms[i].UpdatedAt = now
+			tag, err := tx.Exec(ctx, `UPDATE taxonomy_terms SET label_th = $1, label_en = $2,
+				synonyms = $3, merged_into = $4, updated_at = $5 WHERE id = $6`,
+				t.Label.TH, t.Label.EN, emptyStrings(t.Synonyms), t.MergedInto, now, t.ID)
+			if err != nil {
+				return err
+			}
+			if tag.RowsAffected() == 0 {
+				return notFound("taxonomy term", t.ID)
+			}
+		}
+		for _, p := range cases {
+			p.Data["updated_at"] = now
+			p.Data["revision"] = p.Revision + 1
+			if err := updateByKey(ctx, tx, "cases", "case_id", p.CaseID, caseColumns, p.Data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
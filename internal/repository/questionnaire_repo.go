@@ -0,0 +1,229 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"trl-research-backend/internal/models"
+)
+
+type QuestionnaireRepo struct {
+	Client *firestore.Client
+}
+
+func NewQuestionnaireRepo(client *firestore.Client) *QuestionnaireRepo {
+	return &QuestionnaireRepo{Client: client}
+}
+
+func (r *QuestionnaireRepo) col() *firestore.CollectionRef {
+	return r.Client.Collection("questionnaire_templates")
+}
+
+// doc - the version number is the document ID
+func (r *QuestionnaireRepo) doc(version int) *firestore.DocumentRef {
+	return r.col().Doc(strconv.Itoa(version))
+}
+
+// getIn reads one template inside a transaction
+func (r *QuestionnaireRepo) getIn(tx *firestore.Transaction, version int) (*models.QuestionnaireTemplate, error) {
+	doc, err := tx.Get(r.doc(version))
+	if status.Code(err) == codes.NotFound {
+		return nil, fmt.Errorf("questionnaire %d: %w", version, ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var t models.QuestionnaireTemplate
+	if err := doc.DataTo(&t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// 🟢 GetQuestionnaireTemplates - ordered by version
+func (r *QuestionnaireRepo) GetQuestionnaireTemplates() ([]models.QuestionnaireTemplate, error) {
+	ctx := context.Background()
+	docs, err := r.col().OrderBy("version", firestore.Asc).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var templates []models.QuestionnaireTemplate
+	for _, doc := range docs {
+		var t models.QuestionnaireTemplate
+		if err := doc.DataTo(&t); err != nil {
+			return nil, err
+		}
+		templates = append(templates, t)
+	}
+	return templates, nil
+}
+
+// 🟢 GetQuestionnaireTemplate
+func (r *QuestionnaireRepo) GetQuestionnaireTemplate(version int) (*models.QuestionnaireTemplate, error) {
+	ctx := context.Background()
+	doc, err := r.doc(version).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return nil, fmt.Errorf("questionnaire %d: %w", version, ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var t models.QuestionnaireTemplate
+	if err := doc.DataTo(&t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// 🟢 GetActiveQuestionnaireTemplate
+func (r *QuestionnaireRepo) GetActiveQuestionnaireTemplate() (*models.QuestionnaireTemplate, error) {
+	ctx := context.Background()
+	docs, err := r.col().Where("active", "==", true).Limit(1).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) == 0 {
+		return nil, fmt.Errorf("questionnaire active: %w", ErrNotFound)
+	}
+
+	var t models.QuestionnaireTemplate
+	if err := docs[0].DataTo(&t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// 🟢 CreateQuestionnaireTemplate - next version computed in a transaction, Create fails on a race
+func (r *QuestionnaireRepo) CreateQuestionnaireTemplate(t *models.QuestionnaireTemplate, minVersion int) error {
+	ctx := context.Background()
+	err := r.Client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		docs, err := tx.Documents(r.col().OrderBy("version", firestore.Desc).Limit(1)).GetAll()
+		if err != nil {
+			return err
+		}
+		version := minVersion
+		if len(docs) > 0 {
+			var last models.QuestionnaireTemplate
+			if err := docs[0].DataTo(&last); err != nil {
+				return err
+			}
+			if last.Version >= version {
+				version = last.Version + 1
+			}
+		}
+
+		now := time.Now()
+		t.Version = version
+		t.Published, t.Active = false, false
+		t.PublishedAt = time.Time{}
+		t.CreatedAt = now
+		t.UpdatedAt = now
+		return tx.Create(r.doc(version), t)
+	})
+	if status.Code(err) == codes.AlreadyExists {
+		return fmt.Errorf("questionnaire %d: %w", t.Version, ErrConflict)
+	}
+	return err
+}
+
+// 🟢 UpdateQuestionnaireTemplate - only drafts, checked inside a transaction
+func (r *QuestionnaireRepo) UpdateQuestionnaireTemplate(t *models.QuestionnaireTemplate) error {
+	ctx := context.Background()
+	return r.Client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		old, err := r.getIn(tx, t.Version)
+		if err != nil {
+			return err
+		}
+		if old.Published {
+			return fmt.Errorf("questionnaire %d: %w", t.Version, ErrConflict)
+		}
+		old.Title = t.Title
+		old.Description = t.Description
+		old.Questions = t.Questions
+		old.UpdatedAt = time.Now()
+		*t = *old
+		return tx.Set(r.doc(t.Version), old)
+	})
+}
+
+// 🟢 PublishQuestionnaireTemplate
+func (r *QuestionnaireRepo) PublishQuestionnaireTemplate(version int) error {
+	ctx := context.Background()
+	return r.Client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		t, err := r.getIn(tx, version)
+		if err != nil {
+			return err
+		}
+		if t.Published {
+			return fmt.Errorf("questionnaire %d: %w", version, ErrConflict)
+		}
+		now := time.Now()
+		return tx.Update(r.doc(version), []firestore.Update{
+			{Path: "published", Value: true},
+			{Path: "published_at", Value: now},
+			{Path: "updated_at", Value: now},
+		})
+	})
+}
+
+// 🟢 SetActiveQuestionnaireTemplate - switches the active flag of every template in one transaction
+func (r *QuestionnaireRepo) SetActiveQuestionnaireTemplate(version int) error {
+	ctx := context.Background()
+	return r.Client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		if version != 0 {
+			t, err := r.getIn(tx, version)
+			if err != nil {
+				return err
+			}
+			if !t.Published {
+				return fmt.Errorf("questionnaire %d: %w", version, ErrConflict)
+			}
+		}
+		active, err := tx.Documents(r.col().Where("active", "==", true)).GetAll()
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for _, doc := range active {
+			if doc.Ref.ID == strconv.Itoa(version) {
+				continue
+			}
+			if err := tx.Update(doc.Ref, []firestore.Update{
+				{Path: "active", Value: false},
+				{Path: "updated_at", Value: now},
+			}); err != nil {
+				return err
+			}
+		}
+		if version == 0 {
+			return nil
+		}
+		return tx.Update(r.doc(version), []firestore.Update{
+			{Path: "active", Value: true},
+			{Path: "updated_at", Value: now},
+		})
+	})
+}
+
+// 🟢 DeleteQuestionnaireTemplate - only drafts
+func (r *QuestionnaireRepo) DeleteQuestionnaireTemplate(version int) error {
+	ctx := context.Background()
+	return r.Client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		t, err := r.getIn(tx, version)
+		if err != nil {
+			return err
+		}
+		if t.Published {
+			return fmt.Errorf("questionnaire %d: %w", version, ErrConflict)
+		}
+		return tx.Delete(r.doc(version))
+	})
+}
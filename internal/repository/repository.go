@@ -0,0 +1,364 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"trl-research-backend/internal/listing"
+	"trl-research-backend/internal/models"
+)
+
+// ErrNotFound is returned by backends that can tell a missing record apart from other failures
+var ErrNotFound = errors.New("not found")
+
+// ErrConflict is returned when a conditional write lost against a concurrent change
+var ErrConflict = errors.New("conflict")
+
+// ErrStale is returned by the IfRevision updates when the record is no longer at the revision the
+// client read
+var ErrStale = errors.New("stale revision")
+
+// Soft delete: the records embedding models.Deletion have a DeleteX that moves them to the trash,
+// a RestoreX that brings them back and a PurgeXs that removes the ones deleted before a cutoff
+// for good and returns the IDs of what it removed. Every other method of their repository only sees live records; a List with
+// listing.Query.Deleted set also lists deleted ones. DeleteX and RestoreX return ErrNotFound
+// when there is no live / deleted record with that ID.
+
+// AdminRepository - storage for admin_info
+type AdminRepository interface {
+	GetAdminAll() ([]models.AdminInfo, error)
+	ListAdmins(q listing.Query) (*listing.Page[models.AdminInfo], error)
+	GetAdminByID(adminID string) (*models.AdminInfo, error)
+	GetAdminByEmail(email string) (*models.AdminInfo, error)
+	CreateAdmin(admin *models.AdminInfo) error
+	Login(email string, password string) (*models.AdminInfo, error)
+	UpdatePasswordByEmail(email string, password string) error
+	UpdateAdminByID(id string, data *models.AdminInfo) error
+	DeleteAdmin(adminID, deletedBy string) error
+	RestoreAdmin(adminID string) error
+	PurgeAdmins(cutoff time.Time) ([]string, error)
+}
+
+// ResearcherRepository - storage for researchers
+type ResearcherRepository interface {
+	Login(email string, password string) (*models.ResearcherInfo, error)
+	GetResearcherAll() ([]models.ResearcherInfo, error)
+	ListResearchers(q listing.Query) (*listing.Page[models.ResearcherInfo], error)
+	GetResearcherByID(researcherID string) (*models.ResearcherInfo, error)
+	GetResearcherByIDDirect(researcherID string) (*models.ResearcherInfo, error)
+	GetResearcherByCaseID(caseID string) (*models.ResearcherInfo, error)
+	GetResearcherByEmail(email string) (*models.ResearcherInfo, error)
+	CreateResearcher(researcher *models.ResearcherInfo) error
+	UpdateResearcherByID(researcherID string, data *models.ResearcherInfo) error
+	UpdateResearcherPasswordByID(researcherID string, password string) error
+	DeleteResearcher(researcherID, deletedBy string) error
+	RestoreResearcher(researcherID string) error
+	// PurgeResearchers keeps researchers that still have cases, deleted ones included
+	PurgeResearchers(cutoff time.Time) ([]string, error)
+}
+
+// CoordinatorRepository - storage for coordinators (email is the key)
+type CoordinatorRepository interface {
+	Login(email string, password string) (*models.CoordinatorInfo, error)
+	GetCoordinatorAll() ([]models.CoordinatorInfo, error)
+	ListCoordinators(q listing.Query) (*listing.Page[models.CoordinatorInfo], error)
+	GetCoordinatorByEmail(email string) (*models.CoordinatorInfo, error)
+	GetCoordinatorByCaseID(caseID string) (*models.CoordinatorInfo, error)
+	CreateCoordinator(coordinator *models.CoordinatorInfo) error
+	UpdateCoordinatorByEmail(email string, data map[string]interface{}) error
+	UpdateCoordinatorPasswordByEmail(email string, password string) error
+	DeleteCoordinator(email, deletedBy string) error
+	RestoreCoordinator(email string) error
+	PurgeCoordinators(cutoff time.Time) ([]string, error)
+}
+
+// SupporterRepository - storage for supporters
+type SupporterRepository interface {
+	GetSupporterAll() ([]models.Supporter, error)
+	ListSupporters(q listing.Query) (*listing.Page[models.Supporter], error)
+	GetSupporterByID(supporterID string) (*models.Supporter, error)
+	GetSupporterByCaseID(caseID string) (*models.Supporter, error)
+	CreateSupporter(supporter *models.Supporter) error
+	UpdateSupporterByID(supporterID string, data map[string]interface{}) error
+	DeleteSupporter(supporterID, deletedBy string) error
+	RestoreSupporter(supporterID string) error
+	PurgeSupporters(cutoff time.Time) ([]string, error)
+}
+
+// AppointmentRepository - storage for appointments
+type AppointmentRepository interface {
+	GetAppointmentAll() ([]models.Appointment, error)
+	ListAppointments(q listing.Query) (*listing.Page[models.Appointment], error)
+	GetAppointmentByID(appointmentID string) (*models.Appointment, error)
+	GetAppointmentByCaseID(caseID string) ([]models.Appointment, error)
+	CreateAppointment(ap *models.Appointment) error
+	UpdateAppointmentByID(appointmentID string, data map[string]interface{}) error
+	DeleteAppointment(appointmentID, deletedBy string) error
+	RestoreAppointment(appointmentID string) error
+	PurgeAppointments(cutoff time.Time) ([]string, error)
+}
+
+// CaseRepository - storage for cases
+type CaseRepository interface {
+	GetCaseAll() ([]models.CaseInfo, error)
+	ListCases(q listing.Query) (*listing.Page[models.CaseInfo], error)
+	GetCaseAllByResearcher_id(researcher_id string) ([]models.CaseInfo, error)
+	GetCaseByID(caseID string) (*models.CaseInfo, error)
+	CreateCase(cs *models.CaseInfo) error
+	UpdateCaseByID(caseID string, data map[string]interface{}) error
+	// UpdateCaseIfRevision is UpdateCaseByID for a client that read the case at revision;
+	// ErrStale once it was changed since
+	UpdateCaseIfRevision(caseID string, revision int64, data map[string]interface{}) error
+	// TransitionCaseStatus moves the case from t.From to t.To, stamps the time of entering t.To
+	// and appends t to the status history; ErrConflict when the case is no longer in t.From
+	TransitionCaseStatus(t *models.CaseStatusTransition) error
+	// GetCaseStatusHistory - oldest first
+	GetCaseStatusHistory(caseID string) ([]models.CaseStatusTransition, error)
+	// DeleteCase moves the case and its live appointments, IPs, supporters and assessments to
+	// the trash together; RestoreCase brings back the ones deleted with it
+	DeleteCase(caseID, deletedBy string) error
+	RestoreCase(caseID string) error
+	// PurgeCases also removes whatever else still refers to the purged cases (their records
+	// above and the status history)
+	PurgeCases(cutoff time.Time) ([]string, error)
+}
+
+// IntellectualPropertyRepository - storage for intellectual_properties
+type IntellectualPropertyRepository interface {
+	GetIPAll() ([]models.IntellectualProperty, error)
+	ListIPs(q listing.Query) (*listing.Page[models.IntellectualProperty], error)
+	GetIPByID(ipID string) (*models.IntellectualProperty, error)
+	GetIPByCaseID(caseID string) (*models.IntellectualProperty, error)
+	CreateIP(ip *models.IntellectualProperty) error
+	UpdateIPByID(ipID string, data map[string]interface{}) error
+	DeleteIP(ipID, deletedBy string) error
+	RestoreIP(ipID string) error
+	PurgeIPs(cutoff time.Time) ([]string, error)
+}
+
+// AssessmentTrlRepository - storage for assessment_trl
+type AssessmentTrlRepository interface {
+	GetAssessmentTrlAll() ([]models.AssessmentTrl, error)
+	ListAssessmentTrls(q listing.Query) (*listing.Page[models.AssessmentTrl], error)
+	GetAssessmentTrlByID(id string) (*models.AssessmentTrl, error)
+	// GetAssessmentTrlByCaseID - the latest assessment of the case
+	GetAssessmentTrlByCaseID(caseID string) (*models.AssessmentTrl, error)
+	// GetAssessmentTrlsByCaseID - every assessment of the case, oldest first
+	GetAssessmentTrlsByCaseID(caseID string) ([]models.AssessmentTrl, error)
+	CreateAssessmentTrl(a *models.AssessmentTrl) error
+	// UpdateAssessmentTrlByID changes a pending assessment; ErrConflict once it is reviewed
+	UpdateAssessmentTrlByID(id string, data map[string]interface{}) error
+	// UpdateAssessmentTrlIfRevision is UpdateAssessmentTrlByID for a client that read the
+	// assessment at revision; ErrStale once it was changed since
+	UpdateAssessmentTrlIfRevision(id string, revision int64, data map[string]interface{}) error
+	// ReviewAssessmentTrl moves a pending assessment to approved / rejected; ErrConflict
+	// when it was already reviewed
+	ReviewAssessmentTrl(id, status, reviewerID, note string) error
+	DeleteAssessmentTrl(id, deletedBy string) error
+	RestoreAssessmentTrl(id string) error
+	PurgeAssessmentTrls(cutoff time.Time) ([]string, error)
+}
+
+// FileRepository - storage for uploaded file metadata
+type FileRepository interface {
+	SaveFile(ctx context.Context, file *models.FileMetadata) error
+	GetFileByID(ctx context.Context, fileID string) (*models.FileMetadata, error)
+	// GetFilesByCaseID - the files uploaded for a case, oldest first
+	GetFilesByCaseID(ctx context.Context, caseID string) ([]models.FileMetadata, error)
+}
+
+// SessionRepository - storage for login sessions (refresh tokens)
+type SessionRepository interface {
+	CreateSession(s *models.Session) error
+	GetSessionByID(sessionID string) (*models.Session, error)
+	GetSessionsByUserID(userID string) ([]models.Session, error)
+	// RotateSession swaps the refresh token hash only if it still equals oldHash,
+	// otherwise it returns ErrConflict so a replayed token can be detected
+	RotateSession(sessionID, oldHash, newHash string, expiresAt time.Time) error
+	RevokeSession(sessionID string) error
+	RevokeSessionsByUserID(userID string) (int, error)
+}
+
+// PasswordResetRepository - storage for single-use password reset tokens
+type PasswordResetRepository interface {
+	CreatePasswordReset(reset *models.PasswordReset) error
+	// ConsumePasswordReset marks the token used and returns it; ErrNotFound when unknown,
+	// ErrConflict when already used or expired
+	ConsumePasswordReset(tokenHash string) (*models.PasswordReset, error)
+	// InvalidatePasswordResetsByUserID burns every unused token of a user
+	InvalidatePasswordResetsByUserID(userID string) error
+}
+
+// LoginAttemptRepository - failed login counters and the login audit trail
+type LoginAttemptRepository interface {
+	GetLoginThrottle(key string) (*models.LoginThrottle, error)
+	// RecordLoginFailure adds one failure, starting from zero again when the previous
+	// failure is older than resetAfter
+	RecordLoginFailure(key string, resetAfter time.Duration) (*models.LoginThrottle, error)
+	ClearLoginFailures(key string) error
+	CreateLoginAudit(audit *models.LoginAudit) error
+	// GetLoginAuditsByEmail - newest first, at most limit entries
+	GetLoginAuditsByEmail(email string, limit int) ([]models.LoginAudit, error)
+}
+
+// MFARepository - TOTP enrollments (one per user)
+type MFARepository interface {
+	GetMFA(userID string) (*models.MFAEnrollment, error)
+	// SaveMFA creates or replaces the enrollment of m.UserID
+	SaveMFA(m *models.MFAEnrollment) error
+	DeleteMFA(userID string) error
+	// UseTOTPStep records that the code of step was used; ErrConflict when that step
+	// (or a later one) was already accepted, so a code cannot be replayed
+	UseTOTPStep(userID string, step int64) error
+	// UseRecoveryCode removes one recovery code and returns how many are left;
+	// ErrNotFound when the code is unknown or already used
+	UseRecoveryCode(userID, codeHash string) (int, error)
+}
+
+// QuestionnaireRepository - versions of the TRL questionnaire. The built-in version 1
+// (internal/trl) is not stored here.
+type QuestionnaireRepository interface {
+	GetQuestionnaireTemplates() ([]models.QuestionnaireTemplate, error)
+	GetQuestionnaireTemplate(version int) (*models.QuestionnaireTemplate, error)
+	// GetActiveQuestionnaireTemplate - ErrNotFound when no stored version is active
+	GetActiveQuestionnaireTemplate() (*models.QuestionnaireTemplate, error)
+	// CreateQuestionnaireTemplate stores t as a draft with the next version after the highest
+	// stored one, never below minVersion
+	CreateQuestionnaireTemplate(t *models.QuestionnaireTemplate, minVersion int) error
+	// UpdateQuestionnaireTemplate replaces the questions and text of a draft; ErrConflict once published
+	UpdateQuestionnaireTemplate(t *models.QuestionnaireTemplate) error
+	// PublishQuestionnaireTemplate freezes a draft; ErrConflict when it is already published
+	PublishQuestionnaireTemplate(version int) error
+	// SetActiveQuestionnaireTemplate makes version the only active one (0 = none is active);
+	// ErrConflict when that version is still a draft
+	SetActiveQuestionnaireTemplate(version int) error
+	// DeleteQuestionnaireTemplate removes a draft; ErrConflict once published
+	DeleteQuestionnaireTemplate(version int) error
+}
+
+// TaxonomyRepository - the curated terms cases refer to (domains, case types, keywords)
+type TaxonomyRepository interface {
+	// GetTaxonomyTerms - the terms of one kind ("" = every kind) ordered by ID, merged ones included
+	GetTaxonomyTerms(kind string) ([]models.TaxonomyTerm, error)
+	GetTaxonomyTermByID(id string) (*models.TaxonomyTerm, error)
+	// CreateTaxonomyTerm stores t with the next TX- ID
+	CreateTaxonomyTerm(t *models.TaxonomyTerm) error
+	// UpdateTaxonomyTerm replaces the label, synonyms and merged_into of a stored term
+	UpdateTaxonomyTerm(t *models.TaxonomyTerm) error
+	// ApplyTermChange writes the terms of a rename / merge (as UpdateTaxonomyTerm) and the patches
+	// of the cases using them in one transaction. Deleted cases are patched too; ErrStale, and
+	// nothing written, once a case is no longer at the revision of its patch.
+	ApplyTermChange(terms []models.TaxonomyTerm, cases []CasePatch) error
+}
+
+// CasePatch - a PATCH body for a case, applied only at Revision
+type CasePatch struct {
+	CaseID   string
+	Revision int64
+	Data     map[string]interface{}
+}
+
+// AuditRepository - the audit log of mutating API calls, append-only
+type AuditRepository interface {
+	// CreateAuditEntry stores e with a new ID and the current time
+	CreateAuditEntry(e *models.AuditEntry) error
+	ListAuditEntries(q listing.Query) (*listing.Page[models.AuditEntry], error)
+}
+
+// SubmissionRepository - POST /submissions: a case and its first records in one transaction
+type SubmissionRepository interface {
+	// CreateSubmission stores s, filling in the IDs, the case ID of its records and the
+	// timestamps, together with key; all of it or nothing. When the user already used key.Key
+	// and it hasn't expired, nothing is written: ErrConflict if that was for another request
+	// (key.RequestHash), otherwise s becomes the submission stored then and replayed is true.
+	// A key.Key of "" is no key, every call creates a case.
+	CreateSubmission(key models.SubmissionKey, s *models.Submission) (replayed bool, err error)
+}
+
+// DraftRepository - submissions researchers are still filling in. An expired draft is
+// ErrNotFound like a missing one.
+type DraftRepository interface {
+	// CreateDraft - auto generate ID DR-00001
+	CreateDraft(d *models.Draft) error
+	GetDraftByID(draftID string) (*models.Draft, error)
+	// GetDraftsByResearcherID - the live drafts of a researcher, last saved first
+	GetDraftsByResearcherID(researcherID string) ([]models.Draft, error)
+	// SaveDraft replaces the content and ExpiresAt of a live draft
+	SaveDraft(d *models.Draft) error
+	// DeleteDraft removes the draft for good, drafts have no trash
+	DeleteDraft(draftID string) error
+	// PurgeDrafts removes the drafts expired at now
+	PurgeDrafts(now time.Time) ([]string, error)
+}
+
+// Repositories groups one implementation of every repository so the router can be
+// wired with Firestore, in-memory or any other backend
+type Repositories struct {
+	Admin                AdminRepository
+	Researcher           ResearcherRepository
+	Coordinator          CoordinatorRepository
+	Supporter            SupporterRepository
+	Appointment          AppointmentRepository
+	Case                 CaseRepository
+	IntellectualProperty IntellectualPropertyRepository
+	AssessmentTrl        AssessmentTrlRepository
+	File                 FileRepository
+	Session              SessionRepository
+	PasswordReset        PasswordResetRepository
+	LoginAttempt         LoginAttemptRepository
+	MFA                  MFARepository
+	Questionnaire        QuestionnaireRepository
+	Taxonomy             TaxonomyRepository
+	Audit                AuditRepository
+	Submission           SubmissionRepository
+	Draft                DraftRepository
+	Sequence             SequenceGenerator
+}
+
+// NewFirestoreRepositories - every repository backed by the given Firestore client
+func NewFirestoreRepositories(client *firestore.Client) *Repositories {
+	return &Repositories{
+		Admin:                NewAdminRepo(client),
+		Researcher:           NewResearcherRepo(client),
+		Coordinator:          NewCoordinatorRepo(client),
+		Supporter:            NewSupporterRepo(client),
+		Appointment:          NewAppointmentRepo(client),
+		Case:                 NewCaseRepo(client),
+		IntellectualProperty: NewIntellectualPropertyRepo(client),
+		AssessmentTrl:        NewAssessmentTrlRepo(client),
+		File:                 NewFileRepo(client),
+		Session:              NewSessionRepo(client),
+		PasswordReset:        NewPasswordResetRepo(client),
+		LoginAttempt:         NewLoginAttemptRepo(client),
+		MFA:                  NewMFARepo(client),
+		Questionnaire:        NewQuestionnaireRepo(client),
+		Taxonomy:             NewTaxonomyRepo(client),
+		Audit:                NewAuditRepo(client),
+		Submission:           NewSubmissionRepo(client),
+		Draft:                NewDraftRepo(client),
+		Sequence:             NewFirestoreSequence(client),
+	}
+}
+
+var (
+	_ AdminRepository                = (*AdminRepo)(nil)
+	_ ResearcherRepository           = (*ResearcherRepo)(nil)
+	_ CoordinatorRepository          = (*CoordinatorRepo)(nil)
+	_ SupporterRepository            = (*SupporterRepo)(nil)
+	_ AppointmentRepository          = (*AppointmentRepo)(nil)
+	_ CaseRepository                 = (*CaseRepo)(nil)
+	_ IntellectualPropertyRepository = (*IntellectualPropertyRepo)(nil)
+	_ AssessmentTrlRepository        = (*AssessmentTrlRepo)(nil)
+	_ FileRepository                 = (*FileRepo)(nil)
+	_ SessionRepository              = (*SessionRepo)(nil)
+	_ PasswordResetRepository        = (*PasswordResetRepo)(nil)
+	_ LoginAttemptRepository         = (*LoginAttemptRepo)(nil)
+	_ MFARepository                  = (*MFARepo)(nil)
+	_ QuestionnaireRepository        = (*QuestionnaireRepo)(nil)
+	_ TaxonomyRepository             = (*TaxonomyRepo)(nil)
+	_ AuditRepository                = (*AuditRepo)(nil)
+	_ SubmissionRepository           = (*SubmissionRepo)(nil)
+	_ DraftRepository                = (*DraftRepo)(nil)
+)
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"cloud.google.com/go/firestore"
+	"trl-research-backend/internal/models"
+)
+
+// Researcher documents used to be written in two shapes: the model format of
+// models.ResearcherInfo (researcher_id, researcher_first_name, ...) and the frontend format
+// (id, first_name, ...). This file is the only place that knows the frontend format;
+// internal/script/migrate_researchers.go rewrites old documents to the model format.
+
+// legacyResearcherFields - frontend-format key → model-format key
+var legacyResearcherFields = map[string]string{
+	"id":                "researcher_id",
+	"prefix":            "researcher_prefix",
+	"academic_position": "researcher_academic_position",
+	"first_name":        "researcher_first_name",
+	"last_name":         "researcher_last_name",
+	"department":        "researcher_department",
+	"phone_number":      "researcher_phone_number",
+	"email":             "researcher_email",
+}
+
+// legacyResearcherKey - the frontend-format twin of a model-format key
+func legacyResearcherKey(key string) string {
+	for legacy, k := range legacyResearcherFields {
+		if k == key {
+			return legacy
+		}
+	}
+	return key
+}
+
+// researcherStrings - the string fields of r by model-format key
+func researcherStrings(r *models.ResearcherInfo) map[string]*string {
+	return map[string]*string{
+		"researcher_id":                &r.ResearcherID,
+		"admin_id":                     &r.AdminID,
+		"researcher_prefix":            &r.ResearcherPrefix,
+		"researcher_academic_position": &r.ResearcherAcademicPosition,
+		"researcher_first_name":        &r.ResearcherFirstName,
+		"researcher_last_name":         &r.ResearcherLastName,
+		"researcher_department":        &r.ResearcherDepartment,
+		"researcher_phone_number":      &r.ResearcherPhoneNumber,
+		"researcher_email":             &r.ResearcherEmail,
+		"researcher_password":          &r.ResearcherPassword,
+	}
+}
+
+// decodeResearcher reads a researcher document of either shape. A frontend-format field wins
+// over its model-format twin, as the profile endpoints always showed it; a document without
+// any researcher_id takes its document ID.
+func decodeResearcher(doc *firestore.DocumentSnapshot) (*models.ResearcherInfo, error) {
+	var researcher models.ResearcherInfo
+	if err := doc.DataTo(&researcher); err != nil {
+		return nil, err
+	}
+	fields := researcherStrings(&researcher)
+	for legacy, key := range legacyResearcherFields {
+		if v, ok := doc.Data()[legacy].(string); ok {
+			*fields[key] = v
+		}
+	}
+	if researcher.ResearcherID == "" {
+		researcher.ResearcherID = doc.Ref.ID
+	}
+	return &researcher, nil
+}
+
+// encodeResearcher - the model-format document of r for a Set with MergeAll; it deletes the
+// frontend-format keys so an update also normalizes a document that wasn't migrated yet
+func encodeResearcher(r *models.ResearcherInfo) map[string]interface{} {
+	data := map[string]interface{}{
+		"created_at": r.CreatedAt,
+		"updated_at": r.UpdatedAt,
+	}
+	for key, v := range researcherStrings(r) {
+		data[key] = *v
+	}
+	for legacy := range legacyResearcherFields {
+		data[legacy] = firestore.Delete
+	}
+	return data
+}
+
+// isLegacyResearcher - the document has a frontend-format key or no researcher_id
+func isLegacyResearcher(doc *firestore.DocumentSnapshot) bool {
+	data := doc.Data()
+	if _, ok := data["researcher_id"]; !ok {
+		return true
+	}
+	for legacy := range legacyResearcherFields {
+		if _, ok := data[legacy]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeResearcherValue is decodeResearcher for listDocs
+func decodeResearcherValue(doc *firestore.DocumentSnapshot) (models.ResearcherInfo, error) {
+	r, err := decodeResearcher(doc)
+	if err != nil {
+		return models.ResearcherInfo{}, err
+	}
+	return *r, nil
+}
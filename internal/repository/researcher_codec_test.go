@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// documents not migrated yet are found by researcher_id and researcher_email too
+func TestResearcherLookupsFindLegacyDocuments(t *testing.T) {
+	client := emulatorClient(t)
+	ctx := context.Background()
+	docs := map[string]map[string]interface{}{
+		"RS-00001": {"researcher_id": "RS-00001", "researcher_email": "model@example.com", "researcher_first_name": "Model"},
+		"RS-00002": {"id": "RS-00002", "email": "front@example.com", "first_name": "Front"},
+		// no id at all: the document ID is the researcher_id
+		"RS-00003": {"email": "bare@example.com", "first_name": "Bare"},
+		// both formats, the frontend value is the one shown and found
+		"RS-00004": {"researcher_id": "RS-00004", "researcher_email": "stale@example.com", "email": "fresh@example.com"},
+	}
+	for id, data := range docs {
+		if _, err := client.Collection("researchers").Doc(id).Set(ctx, data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	repo := &ResearcherRepo{Client: client}
+
+	emails := map[string]string{
+		"RS-00001": "model@example.com",
+		"RS-00002": "front@example.com",
+		"RS-00003": "bare@example.com",
+		"RS-00004": "fresh@example.com",
+	}
+	for id, email := range emails {
+		byID, err := repo.GetResearcherByID(id)
+		if err != nil || byID.ResearcherEmail != email {
+			t.Errorf("by ID %s: %+v, %v", id, byID, err)
+		}
+		byEmail, err := repo.GetResearcherByEmail(email)
+		if err != nil || byEmail.ResearcherID != id {
+			t.Errorf("by email %s: %+v, %v", email, byEmail, err)
+		}
+	}
+	if _, err := repo.GetResearcherByEmail("stale@example.com"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("overridden email: %v, want ErrNotFound", err)
+	}
+}
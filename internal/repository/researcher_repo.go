@@ -3,48 +3,44 @@ package repository
 import (
 	"context"
 	"fmt"
-	"strconv"
-	"strings"
 	"time"
 
+	"trl-research-backend/internal/listing"
 	"trl-research-backend/internal/models"
 
 	"cloud.google.com/go/firestore"
 	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type ResearcherRepo struct {
 	Client *firestore.Client
+	Seq    SequenceGenerator
 }
 
 // 🟢 Login with password verification
 func (r *ResearcherRepo) Login(email string, password string) (*models.ResearcherInfo, error) {
-	ctx := context.Background()
-
 	// Query by email field instead of using email as document ID
-	docs, err := r.Client.Collection("researchers").Where("researcher_email", "==", email).Limit(1).Documents(ctx).GetAll()
+	researcher, ok, err := r.findResearcher("researcher_email", email)
 	if err != nil {
 		return nil, err
 	}
-
-	if len(docs) == 0 {
+	if !ok {
 		return nil, fmt.Errorf("researcher not found")
 	}
 
-	var researcher models.ResearcherInfo
-	docs[0].DataTo(&researcher)
-
 	// Verify password
 	err = bcrypt.CompareHashAndPassword([]byte(researcher.ResearcherPassword), []byte(password))
 	if err != nil {
 		return nil, fmt.Errorf("invalid password")
 	}
 
-	return &researcher, nil
+	return researcher, nil
 }
 
 func NewResearcherRepo(client *firestore.Client) *ResearcherRepo {
-	return &ResearcherRepo{Client: client}
+	return &ResearcherRepo{Client: client, Seq: NewFirestoreSequence(client)}
 }
 
 // 🟢 GetResearcherAll - fetch all researchers
@@ -57,289 +53,110 @@ func (r *ResearcherRepo) GetResearcherAll() ([]models.ResearcherInfo, error) {
 
 	var researchers []models.ResearcherInfo
 	for _, doc := range docs {
-		var researcher models.ResearcherInfo
-		doc.DataTo(&researcher)
-		researchers = append(researchers, researcher)
-	}
-	return researchers, nil
-}
-
-// 🟢 GetResearcherByID - fetch one researcher by ID (field query - eventual consistency)
-func (r *ResearcherRepo) GetResearcherByID(researcherID string) (*models.ResearcherInfo, error) {
-	ctx := context.Background()
-	// Try querying by researcher_id field first, then by id field (frontend format)
-	docs, err := r.Client.Collection("researchers").Where("researcher_id", "==", researcherID).Limit(1).Documents(ctx).GetAll()
-	if err != nil || len(docs) == 0 {
-		// Fallback to query by id field (frontend format)
-		docs, err = r.Client.Collection("researchers").Where("id", "==", researcherID).Limit(1).Documents(ctx).GetAll()
+		researcher, err := decodeResearcher(doc)
 		if err != nil {
 			return nil, err
 		}
+		researchers = append(researchers, *researcher)
 	}
+	return live(researchers), nil
+}
 
-	if len(docs) == 0 {
-		return nil, fmt.Errorf("researcher not found")
-	}
-
-	// Manually map fields to support both frontend and model formats
-	docData := docs[0].Data()
-	researcher := models.ResearcherInfo{}
-
-	// Map fields - support both frontend format and model format
-	if val, ok := docData["id"]; ok {
-		if str, ok := val.(string); ok {
-			researcher.ResearcherID = str
-		}
-	} else if val, ok := docData["researcher_id"]; ok {
-		if str, ok := val.(string); ok {
-			researcher.ResearcherID = str
-		}
-	}
-
-	if val, ok := docData["admin_id"]; ok {
-		if str, ok := val.(string); ok {
-			researcher.AdminID = str
-		}
-	}
-
-	if val, ok := docData["prefix"]; ok {
-		if str, ok := val.(string); ok {
-			researcher.ResearcherPrefix = str
-		}
-	} else if val, ok := docData["researcher_prefix"]; ok {
-		if str, ok := val.(string); ok {
-			researcher.ResearcherPrefix = str
-		}
-	}
-
-	if val, ok := docData["academic_position"]; ok {
-		if str, ok := val.(string); ok {
-			researcher.ResearcherAcademicPosition = str
-		}
-	} else if val, ok := docData["researcher_academic_position"]; ok {
-		if str, ok := val.(string); ok {
-			researcher.ResearcherAcademicPosition = str
-		}
-	}
-
-	if val, ok := docData["first_name"]; ok {
-		if str, ok := val.(string); ok {
-			researcher.ResearcherFirstName = str
-		}
-	} else if val, ok := docData["researcher_first_name"]; ok {
-		if str, ok := val.(string); ok {
-			researcher.ResearcherFirstName = str
-		}
-	}
-
-	if val, ok := docData["last_name"]; ok {
-		if str, ok := val.(string); ok {
-			researcher.ResearcherLastName = str
-		}
-	} else if val, ok := docData["researcher_last_name"]; ok {
-		if str, ok := val.(string); ok {
-			researcher.ResearcherLastName = str
-		}
-	}
-
-	if val, ok := docData["department"]; ok {
-		if str, ok := val.(string); ok {
-			researcher.ResearcherDepartment = str
-		}
-	} else if val, ok := docData["researcher_department"]; ok {
-		if str, ok := val.(string); ok {
-			researcher.ResearcherDepartment = str
-		}
-	}
-
-	if val, ok := docData["phone_number"]; ok {
-		if str, ok := val.(string); ok {
-			researcher.ResearcherPhoneNumber = str
-		}
-	} else if val, ok := docData["researcher_phone_number"]; ok {
-		if str, ok := val.(string); ok {
-			researcher.ResearcherPhoneNumber = str
-		}
-	}
-
-	if val, ok := docData["email"]; ok {
-		if str, ok := val.(string); ok {
-			researcher.ResearcherEmail = str
-		}
-	} else if val, ok := docData["researcher_email"]; ok {
-		if str, ok := val.(string); ok {
-			researcher.ResearcherEmail = str
-		}
-	}
-
-	if val, ok := docData["researcher_password"]; ok {
-		if str, ok := val.(string); ok {
-			researcher.ResearcherPassword = str
-		}
-	}
-
-	if val, ok := docData["created_at"]; ok {
-		if t, ok := val.(time.Time); ok {
-			researcher.CreatedAt = t
-		}
-	}
+// 🟢 ListResearchers - one page of researchers for GET with list parameters
+func (r *ResearcherRepo) ListResearchers(q listing.Query) (*listing.Page[models.ResearcherInfo], error) {
+	return listDocs(r.Client.Collection("researchers"), q, decodeResearcherValue, "researcher_email", "researcher_department") // also frontend-format keys
+}
 
-	if val, ok := docData["updated_at"]; ok {
-		if t, ok := val.(time.Time); ok {
-			researcher.UpdatedAt = t
-		}
+// 🟢 GetResearcherByID - fetch one researcher by ID (field query - eventual consistency)
+func (r *ResearcherRepo) GetResearcherByID(researcherID string) (*models.ResearcherInfo, error) {
+	researcher, ok, err := r.findResearcher("researcher_id", researcherID)
+	if err == nil && !ok {
+		err = fmt.Errorf("researcher %s: %w", researcherID, ErrNotFound)
 	}
-
-	return &researcher, nil
+	return researcher, err
 }
 
 // 🟢 GetResearcherByIDDirect - fetch one researcher by ID using document ID lookup (immediate consistency)
 func (r *ResearcherRepo) GetResearcherByIDDirect(researcherID string) (*models.ResearcherInfo, error) {
 	ctx := context.Background()
 	doc, err := r.Client.Collection("researchers").Doc(researcherID).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return nil, fmt.Errorf("researcher %s: %w", researcherID, ErrNotFound)
+	}
 	if err != nil {
 		return nil, err
 	}
-
-	// Manually map fields because Firestore uses frontend format (id, first_name)
-	// but struct expects model format (researcher_id, researcher_first_name)
-	docData := doc.Data()
-	researcher := models.ResearcherInfo{}
-
-	// Map fields - support both frontend format and model format
-	if val, ok := docData["id"]; ok {
-		if str, ok := val.(string); ok {
-			researcher.ResearcherID = str
-		}
-	} else if val, ok := docData["researcher_id"]; ok {
-		if str, ok := val.(string); ok {
-			researcher.ResearcherID = str
-		}
-	}
-
-	if val, ok := docData["admin_id"]; ok {
-		if str, ok := val.(string); ok {
-			researcher.AdminID = str
-		}
-	}
-
-	if val, ok := docData["prefix"]; ok {
-		if str, ok := val.(string); ok {
-			researcher.ResearcherPrefix = str
-		}
-	} else if val, ok := docData["researcher_prefix"]; ok {
-		if str, ok := val.(string); ok {
-			researcher.ResearcherPrefix = str
-		}
-	}
-
-	if val, ok := docData["academic_position"]; ok {
-		if str, ok := val.(string); ok {
-			researcher.ResearcherAcademicPosition = str
-		}
-	} else if val, ok := docData["researcher_academic_position"]; ok {
-		if str, ok := val.(string); ok {
-			researcher.ResearcherAcademicPosition = str
-		}
-	}
-
-	if val, ok := docData["first_name"]; ok {
-		if str, ok := val.(string); ok {
-			researcher.ResearcherFirstName = str
-		}
-	} else if val, ok := docData["researcher_first_name"]; ok {
-		if str, ok := val.(string); ok {
-			researcher.ResearcherFirstName = str
-		}
+	researcher, err := decodeResearcher(doc)
+	if err == nil && researcher.IsDeleted() {
+		return nil, fmt.Errorf("researcher %s: %w", researcherID, ErrNotFound)
 	}
+	return researcher, err
+}
 
-	if val, ok := docData["last_name"]; ok {
-		if str, ok := val.(string); ok {
-			researcher.ResearcherLastName = str
-		}
-	} else if val, ok := docData["researcher_last_name"]; ok {
-		if str, ok := val.(string); ok {
-			researcher.ResearcherLastName = str
-		}
+// 🟢 GetResearcherByCaseID
+func (r *ResearcherRepo) GetResearcherByCaseID(caseID string) (*models.ResearcherInfo, error) {
+	ctx := context.Background()
+	docs, err := r.Client.Collection("researchers").Where("case_id", "==", caseID).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
 	}
-
-	if val, ok := docData["department"]; ok {
-		if str, ok := val.(string); ok {
-			researcher.ResearcherDepartment = str
-		}
-	} else if val, ok := docData["researcher_department"]; ok {
-		if str, ok := val.(string); ok {
-			researcher.ResearcherDepartment = str
-		}
+	researcher, ok, err := firstLive(docs, decodeResearcher)
+	if err == nil && !ok {
+		err = fmt.Errorf("researcher for case %s: %w", caseID, ErrNotFound)
 	}
+	return researcher, err
+}
 
-	if val, ok := docData["phone_number"]; ok {
-		if str, ok := val.(string); ok {
-			researcher.ResearcherPhoneNumber = str
-		}
-	} else if val, ok := docData["researcher_phone_number"]; ok {
-		if str, ok := val.(string); ok {
-			researcher.ResearcherPhoneNumber = str
-		}
+// 🟢 GetResearcherByEmail
+func (r *ResearcherRepo) GetResearcherByEmail(email string) (*models.ResearcherInfo, error) {
+	researcher, ok, err := r.findResearcher("researcher_email", email)
+	if err == nil && !ok {
+		err = fmt.Errorf("researcher %s: %w", email, ErrNotFound)
 	}
+	return researcher, err
+}
 
-	if val, ok := docData["email"]; ok {
-		if str, ok := val.(string); ok {
-			researcher.ResearcherEmail = str
-		}
-	} else if val, ok := docData["researcher_email"]; ok {
-		if str, ok := val.(string); ok {
-			researcher.ResearcherEmail = str
+// findResearcher - the first live researcher whose key (model format) is value. Documents not
+// migrated yet are found by the frontend-format twin of the key, and by document ID for
+// researcher_id; a match counts on the decoded value, the one the profile shows.
+func (r *ResearcherRepo) findResearcher(key, value string) (*models.ResearcherInfo, bool, error) {
+	ctx := context.Background()
+	col := r.Client.Collection("researchers")
+	var docs []*firestore.DocumentSnapshot
+	for _, field := range []string{key, legacyResearcherKey(key)} {
+		found, err := col.Where(field, "==", value).Documents(ctx).GetAll()
+		if err != nil {
+			return nil, false, err
 		}
+		docs = append(docs, found...)
 	}
-
-	if val, ok := docData["researcher_password"]; ok {
-		if str, ok := val.(string); ok {
-			researcher.ResearcherPassword = str
+	if key == "researcher_id" {
+		doc, err := col.Doc(value).Get(ctx)
+		if err == nil {
+			docs = append(docs, doc)
+		} else if status.Code(err) != codes.NotFound {
+			return nil, false, err
 		}
 	}
-
-	if val, ok := docData["created_at"]; ok {
-		if t, ok := val.(time.Time); ok {
-			researcher.CreatedAt = t
+	for _, doc := range docs {
+		researcher, err := decodeResearcher(doc)
+		if err != nil {
+			return nil, false, err
 		}
-	}
-
-	if val, ok := docData["updated_at"]; ok {
-		if t, ok := val.(time.Time); ok {
-			researcher.UpdatedAt = t
+		if !researcher.IsDeleted() && *researcherStrings(researcher)[key] == value {
+			return researcher, true, nil
 		}
 	}
-
-	return &researcher, nil
-}
-
-// 🟢 GetResearcherByCaseID
-func (r *ResearcherRepo) GetResearcherByCaseID(caseID string) (*models.ResearcherInfo, error) {
-	ctx := context.Background()
-	doc, err := r.Client.Collection("researchers").Where("case_id", "==", caseID).Documents(ctx).GetAll()
-	if err != nil {
-		return nil, err
-	}
-
-	var researcher models.ResearcherInfo
-	doc[0].DataTo(&researcher)
-	return &researcher, nil
+	return nil, false, nil
 }
 
 // 🟢 CreateResearcher - auto-generate ResearcherID and create new record
 func (r *ResearcherRepo) CreateResearcher(researcher *models.ResearcherInfo) error {
 	ctx := context.Background()
 
-	// find last ID to generate next
-	docs, err := r.Client.Collection("researchers").OrderBy("researcher_id", firestore.Desc).Limit(1).Documents(ctx).GetAll()
-	nextID := "RS-00001"
-	if err == nil && len(docs) > 0 {
-		lastID := docs[0].Data()["researcher_id"].(string)
-		numStr := strings.TrimPrefix(lastID, "RS-")
-		if n, err := strconv.Atoi(numStr); err == nil {
-			nextID = fmt.Sprintf("RS-%05d", n+1)
-		}
+	nextID, err := r.Seq.Next("RS")
+	if err != nil {
+		return err
 	}
 
 	researcher.ResearcherID = nextID
@@ -347,7 +164,8 @@ func (r *ResearcherRepo) CreateResearcher(researcher *models.ResearcherInfo) err
 	researcher.CreatedAt = now
 	researcher.UpdatedAt = now
 
-	_, err = r.Client.Collection("researchers").Doc(researcher.ResearcherID).Set(ctx, researcher)
+	// Create (not Set) fails instead of silently overwriting an existing document
+	_, err = r.Client.Collection("researchers").Doc(researcher.ResearcherID).Create(ctx, researcher)
 	return err
 }
 
@@ -357,34 +175,82 @@ func (r *ResearcherRepo) UpdateResearcherByID(researcherID string, data *models.
 	// Note: For researchers, document ID = researcher_id
 	data.UpdatedAt = time.Now()
 
-	// Convert struct to map for Firestore update (using MergeAll like other repos)
-	// Update BOTH frontend format and model format fields to keep them in sync
-	updateMap := map[string]interface{}{
-		// Model format fields
-		"researcher_id":                data.ResearcherID,
-		"admin_id":                     data.AdminID,
-		"researcher_prefix":            data.ResearcherPrefix,
-		"researcher_academic_position": data.ResearcherAcademicPosition,
-		"researcher_first_name":        data.ResearcherFirstName,
-		"researcher_last_name":         data.ResearcherLastName,
-		"researcher_department":        data.ResearcherDepartment,
-		"researcher_phone_number":      data.ResearcherPhoneNumber,
-		"researcher_email":             data.ResearcherEmail,
-		"researcher_password":          data.ResearcherPassword,
-		"created_at":                   data.CreatedAt,
-		"updated_at":                   data.UpdatedAt,
-		// Frontend format fields (keep in sync with model format)
-		"id":                data.ResearcherID,
-		"prefix":            data.ResearcherPrefix,
-		"academic_position": data.ResearcherAcademicPosition,
-		"first_name":        data.ResearcherFirstName,
-		"last_name":         data.ResearcherLastName,
-		"department":        data.ResearcherDepartment,
-		"phone_number":      data.ResearcherPhoneNumber,
-		"email":             data.ResearcherEmail,
-	}
-
 	docRef := r.Client.Collection("researchers").Doc(researcherID)
-	_, err := docRef.Set(ctx, updateMap, firestore.MergeAll)
+	_, err := docRef.Set(ctx, encodeResearcher(data), firestore.MergeAll)
 	return err
 }
+
+// 🟢 UpdateResearcherPasswordByID - password must already be a bcrypt hash
+func (r *ResearcherRepo) UpdateResearcherPasswordByID(researcherID string, password string) error {
+	ctx := context.Background()
+	_, err := r.Client.Collection("researchers").Doc(researcherID).Update(ctx, []firestore.Update{
+		{Path: "researcher_password", Value: password},
+		{Path: "updated_at", Value: time.Now()},
+	})
+	return err
+}
+
+// 🟢 NormalizeResearcherDocuments - rewrite every researcher document still in the frontend
+// format (or without researcher_id) to the model format. Returns the IDs of those documents;
+// with dryRun nothing is written.
+func (r *ResearcherRepo) NormalizeResearcherDocuments(dryRun bool) ([]string, error) {
+	ctx := context.Background()
+	docs, err := r.Client.Collection("researchers").Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	var jobs []*firestore.BulkWriterJob
+	var bw *firestore.BulkWriter
+	if !dryRun {
+		bw = r.Client.BulkWriter(ctx)
+		defer bw.End() // no-op after the End below
+	}
+	for _, doc := range docs {
+		if !isLegacyResearcher(doc) {
+			continue
+		}
+		researcher, err := decodeResearcher(doc)
+		if err != nil {
+			return nil, fmt.Errorf("researcher %s: %w", doc.Ref.ID, err)
+		}
+		ids = append(ids, doc.Ref.ID)
+		if dryRun {
+			continue
+		}
+		job, err := bw.Set(doc.Ref, encodeResearcher(researcher), firestore.MergeAll)
+		if err != nil {
+			return nil, fmt.Errorf("researcher %s: %w", doc.Ref.ID, err)
+		}
+		jobs = append(jobs, job)
+	}
+	if bw != nil {
+		bw.End()
+	}
+	// a job only reports its error once the writer flushed
+	for i, job := range jobs {
+		if _, err := job.Results(); err != nil {
+			return nil, fmt.Errorf("researcher %s: %w", ids[i], err)
+		}
+	}
+	return ids, nil
+}
+
+// 🟢 DeleteResearcher - soft delete
+func (r *ResearcherRepo) DeleteResearcher(researcherID, deletedBy string) error {
+	return trash(r.Client, "researchers", "researcher", researcherID, deletedBy)
+}
+
+// 🟢 RestoreResearcher
+func (r *ResearcherRepo) RestoreResearcher(researcherID string) error {
+	return restore(r.Client, "researchers", "researcher", researcherID)
+}
+
+// 🟢 PurgeResearchers - not while they still have cases
+func (r *ResearcherRepo) PurgeResearchers(cutoff time.Time) ([]string, error) {
+	return purge(r.Client.Collection("researchers"), "", cutoff, func(doc *firestore.DocumentSnapshot) (bool, error) {
+		cases, err := r.Client.Collection("cases").Where("researcher_id", "==", doc.Ref.ID).Limit(1).Documents(context.Background()).GetAll()
+		return len(cases) > 0, err
+	})
+}
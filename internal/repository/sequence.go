@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SequenceGenerator hands out collision-free PREFIX-00001 style IDs
+type SequenceGenerator interface {
+	Next(prefix string) (string, error)
+}
+
+// FormatID renders the n-th ID of a prefix, e.g. FormatID("CS", 42) = "CS-00042"
+func FormatID(prefix string, n int64) string {
+	return fmt.Sprintf("%s-%05d", prefix, n)
+}
+
+// sequenceSource is where a prefix's IDs live, used once to seed a missing counter
+type sequenceSource struct {
+	collection string
+	field      string
+}
+
+// sequenceSources lets a new counter continue from the highest ID already stored,
+// so existing CS-00042 data is never handed out again
+var sequenceSources = map[string]sequenceSource{
+	"AD": {"admin_info", "admin_id"},
+	"RS": {"researchers", "researcher_id"},
+	"CS": {"cases", "case_id"},
+	"AP": {"appointments", "appointment_id"},
+	"AS": {"assessment_trl", "id"},
+	"SP": {"supporters", "supporter_id"},
+	"IP": {"intellectual_properties", "id"},
+	"TX": {"taxonomy_terms", "id"},
+	"DR": {"drafts", "id"},
+}
+
+// FirestoreSequence keeps one counter document per prefix in the "counters" collection
+// and increments it inside a transaction, so parallel creates never share an ID
+type FirestoreSequence struct {
+	Client *firestore.Client
+}
+
+func NewFirestoreSequence(client *firestore.Client) *FirestoreSequence {
+	return &FirestoreSequence{Client: client}
+}
+
+// Next - transactional read-increment-write of counters/{prefix}
+func (s *FirestoreSequence) Next(prefix string) (string, error) {
+	ctx := context.Background()
+	ref := s.Client.Collection("counters").Doc(prefix)
+
+	var next int64
+	err := s.Client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		var current int64
+		doc, err := tx.Get(ref)
+		switch {
+		case status.Code(err) == codes.NotFound:
+			current, err = highestExistingID(tx, s.Client, prefix)
+			if err != nil {
+				return err
+			}
+		case err != nil:
+			return err
+		default:
+			value, err := doc.DataAt("value")
+			if err != nil {
+				return err
+			}
+			n, ok := value.(int64)
+			if !ok {
+				return fmt.Errorf("counter %s has non-integer value %v", prefix, value)
+			}
+			current = n
+		}
+
+		next = current + 1
+		return tx.Set(ref, map[string]interface{}{
+			"value":      next,
+			"updated_at": time.Now(),
+		})
+	}, firestore.MaxAttempts(20))
+	if err != nil {
+		return "", fmt.Errorf("sequence %s: %w", prefix, err)
+	}
+	return FormatID(prefix, next), nil
+}
+
+// highestExistingID reads the largest numeric suffix stored for prefix (0 when empty). An ID that
+// isn't PREFIX-<number> is an error: starting over at 0 would hand out IDs already stored.
+func highestExistingID(tx *firestore.Transaction, client *firestore.Client, prefix string) (int64, error) {
+	src, ok := sequenceSources[prefix]
+	if !ok {
+		return 0, nil
+	}
+
+	q := client.Collection(src.collection).OrderBy(src.field, firestore.Desc).Limit(1)
+	docs, err := tx.Documents(q).GetAll()
+	if err != nil || len(docs) == 0 {
+		return 0, err
+	}
+	lastID, _ := docs[0].Data()[src.field].(string)
+	n, err := strconv.ParseInt(strings.TrimPrefix(lastID, prefix+"-"), 10, 64)
+	if err != nil || !strings.HasPrefix(lastID, prefix+"-") {
+		return 0, fmt.Errorf("highest %s.%s is %q, not %s-<number>; set counters/%s {value: <highest number>} by hand",
+			src.collection, src.field, lastID, prefix, prefix)
+	}
+	return n, nil
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/firestore"
+)
+
+// emulatorClient - a client on a fresh project of the Firestore emulator, skips without
+// FIRESTORE_EMULATOR_HOST
+func emulatorClient(t *testing.T) *firestore.Client {
+	t.Helper()
+	if os.Getenv("FIRESTORE_EMULATOR_HOST") == "" {
+		t.Skip("FIRESTORE_EMULATOR_HOST is not set")
+	}
+	client, err := firestore.NewClient(context.Background(), fmt.Sprintf("trl-test-%d", time.Now().UnixNano()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestFirestoreSequenceParallelIDsAreUnique(t *testing.T) {
+	const n = 50
+	seq := NewFirestoreSequence(emulatorClient(t))
+
+	var wg sync.WaitGroup
+	ids := make([]string, n)
+	errs := make([]error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ids[i], errs[i] = seq.Next("CS")
+		}(i)
+	}
+	wg.Wait()
+
+	seen := map[string]bool{}
+	for i, id := range ids {
+		if errs[i] != nil {
+			t.Fatalf("Next: %v", errs[i])
+		}
+		if seen[id] {
+			t.Fatalf("duplicate ID %s", id)
+		}
+		seen[id] = true
+	}
+}
+
+func TestFirestoreSequenceContinuesFromHighestStoredID(t *testing.T) {
+	client := emulatorClient(t)
+	ctx := context.Background()
+	for _, id := range []string{"CS-00007", "CS-00041"} {
+		if _, err := client.Collection("cases").Doc(id).Set(ctx, map[string]interface{}{"case_id": id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	seq := NewFirestoreSequence(client)
+	for _, want := range []string{"CS-00042", "CS-00043"} {
+		got, err := seq.Next("CS")
+		if err != nil {
+			t.Fatalf("Next: %v", err)
+		}
+		if got != want {
+			t.Errorf("got %s, want %s", got, want)
+		}
+	}
+	if got, _ := seq.Next("AP"); got != "AP-00001" {
+		t.Errorf("got %s, want AP-00001", got)
+	}
+}
+
+// an ID the counter can't continue from fails the create instead of starting over at 1
+func TestFirestoreSequenceRefusesUnparseableStoredID(t *testing.T) {
+	client := emulatorClient(t)
+	ctx := context.Background()
+	for _, id := range []string{"CS-00007", "CS-legacy"} {
+		if _, err := client.Collection("cases").Doc(id).Set(ctx, map[string]interface{}{"case_id": id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := NewFirestoreSequence(client).Next("CS")
+	if err == nil || !strings.Contains(err.Error(), `"CS-legacy"`) {
+		t.Errorf("got %s, %v; want an error naming CS-legacy", got, err)
+	}
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"trl-research-backend/internal/models"
+)
+
+type SessionRepo struct {
+	Client *firestore.Client
+}
+
+func NewSessionRepo(client *firestore.Client) *SessionRepo {
+	return &SessionRepo{Client: client}
+}
+
+// 🟢 CreateSession - session_id is the document ID
+func (r *SessionRepo) CreateSession(s *models.Session) error {
+	ctx := context.Background()
+	now := time.Now()
+	s.CreatedAt = now
+	s.LastUsedAt = now
+
+	_, err := r.Client.Collection("sessions").Doc(s.SessionID).Create(ctx, s)
+	return err
+}
+
+// 🟢 GetSessionByID
+func (r *SessionRepo) GetSessionByID(sessionID string) (*models.Session, error) {
+	ctx := context.Background()
+	doc, err := r.Client.Collection("sessions").Doc(sessionID).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return nil, fmt.Errorf("session %s: %w", sessionID, ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var s models.Session
+	if err := doc.DataTo(&s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// 🟢 GetSessionsByUserID - every session of a user, revoked ones included
+func (r *SessionRepo) GetSessionsByUserID(userID string) ([]models.Session, error) {
+	ctx := context.Background()
+	docs, err := r.Client.Collection("sessions").Where("user_id", "==", userID).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var sessions []models.Session
+	for _, doc := range docs {
+		var s models.Session
+		doc.DataTo(&s)
+		sessions = append(sessions, s)
+	}
+	return sessions, nil
+}
+
+// 🟢 RotateSession - compare-and-swap of the refresh token hash inside a transaction
+func (r *SessionRepo) RotateSession(sessionID, oldHash, newHash string, expiresAt time.Time) error {
+	ctx := context.Background()
+	ref := r.Client.Collection("sessions").Doc(sessionID)
+
+	return r.Client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(ref)
+		if status.Code(err) == codes.NotFound {
+			return fmt.Errorf("session %s: %w", sessionID, ErrNotFound)
+		}
+		if err != nil {
+			return err
+		}
+		var s models.Session
+		if err := doc.DataTo(&s); err != nil {
+			return err
+		}
+		if s.Revoked || s.RefreshTokenHash != oldHash {
+			return fmt.Errorf("session %s: %w", sessionID, ErrConflict)
+		}
+
+		return tx.Update(ref, []firestore.Update{
+			{Path: "refresh_token_hash", Value: newHash},
+			{Path: "expires_at", Value: expiresAt},
+			{Path: "last_used_at", Value: time.Now()},
+		})
+	})
+}
+
+// 🟢 RevokeSession
+func (r *SessionRepo) RevokeSession(sessionID string) error {
+	ctx := context.Background()
+	_, err := r.Client.Collection("sessions").Doc(sessionID).Update(ctx, []firestore.Update{
+		{Path: "revoked", Value: true},
+		{Path: "revoked_at", Value: time.Now()},
+	})
+	if status.Code(err) == codes.NotFound {
+		return fmt.Errorf("session %s: %w", sessionID, ErrNotFound)
+	}
+	return err
+}
+
+// 🟢 RevokeSessionsByUserID - returns how many sessions were still active
+func (r *SessionRepo) RevokeSessionsByUserID(userID string) (int, error) {
+	ctx := context.Background()
+	docs, err := r.Client.Collection("sessions").
+		Where("user_id", "==", userID).
+		Where("revoked", "==", false).
+		Documents(ctx).GetAll()
+	if err != nil {
+		return 0, err
+	}
+
+	bw := r.Client.BulkWriter(ctx)
+	now := time.Now()
+	for _, doc := range docs {
+		if _, err := bw.Update(doc.Ref, []firestore.Update{
+			{Path: "revoked", Value: true},
+			{Path: "revoked_at", Value: now},
+		}); err != nil {
+			bw.End()
+			return 0, err
+		}
+	}
+	bw.End()
+	return len(docs), nil
+}
@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"trl-research-backend/internal/models"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// PrepareSubmission gives the records of s their IDs (from next) and timestamps like the
+// CreateX of each repository do, and points them at the new case
+func PrepareSubmission(s *models.Submission, next func(prefix string) (string, error), now time.Time) error {
+	caseID, err := next("CS")
+	if err != nil {
+		return err
+	}
+	s.Case.CaseID = caseID
+	s.Case.CreatedAt, s.Case.UpdatedAt, s.Case.Revision = now, now, 1
+
+	if s.Supporter != nil {
+		if s.Supporter.SupporterID, err = next("SP"); err != nil {
+			return err
+		}
+		s.Supporter.CaseID = caseID
+		s.Supporter.CreatedAt, s.Supporter.UpdatedAt = now, now
+	}
+	for i := range s.IPs {
+		if s.IPs[i].ID, err = next("IP"); err != nil {
+			return err
+		}
+		s.IPs[i].CaseID = caseID
+		s.IPs[i].CreatedAt, s.IPs[i].UpdatedAt = now, now
+	}
+	if s.Assessment != nil {
+		if s.Assessment.ID, err = next("AS"); err != nil {
+			return err
+		}
+		s.Assessment.CaseID = caseID
+		s.Assessment.CreatedAt, s.Assessment.UpdatedAt, s.Assessment.Revision = now, now, 1
+	}
+	for i := range s.Files {
+		s.Files[i].BelongsToCaseID = caseID
+	}
+	return nil
+}
+
+// ReplayOf checks a stored key against a new request with the same key: nil when it can be
+// used again (expired), the stored record when the request is a retry, ErrConflict when the
+// key was used for another request
+func ReplayOf(stored *models.SubmissionRecord, key models.SubmissionKey, now time.Time) (*models.SubmissionRecord, error) {
+	if stored.Expired(now) {
+		return nil, nil
+	}
+	if stored.RequestHash != key.RequestHash {
+		return nil, fmt.Errorf("idempotency key %q was used for another submission: %w", key.Key, ErrConflict)
+	}
+	return stored, nil
+}
+
+type SubmissionRepo struct {
+	Client *firestore.Client
+	Seq    SequenceGenerator
+}
+
+func NewSubmissionRepo(client *firestore.Client) *SubmissionRepo {
+	return &SubmissionRepo{Client: client, Seq: NewFirestoreSequence(client)}
+}
+
+// keyRef - the document of a user's key; keys are client text, so the document ID is a hash
+func (r *SubmissionRepo) keyRef(userID, key string) *firestore.DocumentRef {
+	sum := sha256.Sum256([]byte(userID + "\x00" + key))
+	return r.Client.Collection("submission_keys").Doc(hex.EncodeToString(sum[:]))
+}
+
+// replay reads the key document (in or outside a transaction) for ReplayOf
+func replay(doc *firestore.DocumentSnapshot, err error, key models.SubmissionKey) (*models.SubmissionRecord, error) {
+	if status.Code(err) == codes.NotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var stored models.SubmissionRecord
+	if err := doc.DataTo(&stored); err != nil {
+		return nil, err
+	}
+	return ReplayOf(&stored, key, time.Now())
+}
+
+// 🟢 CreateSubmission - the IDs are taken before the transaction (Firestore transactions can be
+// retried), a failed or replayed submission leaves a gap in the sequences
+func (r *SubmissionRepo) CreateSubmission(key models.SubmissionKey, s *models.Submission) (bool, error) {
+	ctx := context.Background()
+	var ref *firestore.DocumentRef
+	if key.Key != "" {
+		ref = r.keyRef(key.UserID, key.Key)
+		// a retry doesn't use up IDs
+		doc, err := ref.Get(ctx)
+		stored, err := replay(doc, err, key)
+		if err != nil {
+			return false, err
+		}
+		if stored != nil {
+			*s = stored.Submission
+			return true, nil
+		}
+	}
+
+	if err := PrepareSubmission(s, r.Seq.Next, time.Now()); err != nil {
+		return false, err
+	}
+	var stored *models.SubmissionRecord
+	err := r.Client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		stored = nil
+		if ref != nil {
+			doc, err := tx.Get(ref)
+			if stored, err = replay(doc, err, key); err != nil || stored != nil {
+				return err
+			}
+		}
+
+		// Create (not Set) fails instead of silently overwriting an existing document
+		if err := tx.Create(r.Client.Collection("cases").Doc(s.Case.CaseID), s.Case); err != nil {
+			return err
+		}
+		if s.Supporter != nil {
+			if err := tx.Create(r.Client.Collection("supporters").Doc(s.Supporter.SupporterID), s.Supporter); err != nil {
+				return err
+			}
+		}
+		for _, ip := range s.IPs {
+			if err := tx.Create(r.Client.Collection("intellectual_properties").Doc(ip.ID), ip); err != nil {
+				return err
+			}
+		}
+		if s.Assessment != nil {
+			if err := tx.Create(r.Client.Collection("assessment_trl").Doc(s.Assessment.ID), s.Assessment); err != nil {
+				return err
+			}
+		}
+		for _, file := range s.Files {
+			if err := tx.Create(r.Client.Collection("files").Doc(file.ID), file); err != nil {
+				return err
+			}
+		}
+		if ref == nil {
+			return nil
+		}
+		return tx.Set(ref, models.SubmissionRecord{SubmissionKey: key, Submission: *s})
+	})
+	if err != nil {
+		return false, err
+	}
+	if stored != nil {
+		*s = stored.Submission
+		return true, nil
+	}
+	return false, nil
+}
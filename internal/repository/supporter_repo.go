@@ -3,20 +3,20 @@ package repository
 import (
 	"context"
 	"fmt"
-	"strconv"
-	"strings"
 	"time"
 
 	"cloud.google.com/go/firestore"
+	"trl-research-backend/internal/listing"
 	"trl-research-backend/internal/models"
 )
 
 type SupporterRepo struct {
 	Client *firestore.Client
+	Seq    SequenceGenerator
 }
 
 func NewSupporterRepo(client *firestore.Client) *SupporterRepo {
-	return &SupporterRepo{Client: client}
+	return &SupporterRepo{Client: client, Seq: NewFirestoreSequence(client)}
 }
 
 // 🟢 GetSupporterAll
@@ -33,7 +33,12 @@ func (r *SupporterRepo) GetSupporterAll() ([]models.Supporter, error) {
 		doc.DataTo(&supporter)
 		supporters = append(supporters, supporter)
 	}
-	return supporters, nil
+	return live(supporters), nil
+}
+
+// 🟢 ListSupporters - one page of supporters for GET with list parameters
+func (r *SupporterRepo) ListSupporters(q listing.Query) (*listing.Page[models.Supporter], error) {
+	return listDocs(r.Client.Collection("supporters"), q, dataTo[models.Supporter])
 }
 
 // 🟢 GetSupporterByID
@@ -46,19 +51,27 @@ func (r *SupporterRepo) GetSupporterByID(supporterID string) (*models.Supporter,
 
 	var supporter models.Supporter
 	doc.DataTo(&supporter)
+	if supporter.IsDeleted() {
+		return nil, fmt.Errorf("supporter %s: %w", supporterID, ErrNotFound)
+	}
 	return &supporter, nil
 }
 
 // 🟢 GetSupporterByCaseID
 func (r *SupporterRepo) GetSupporterByCaseID(caseID string) (*models.Supporter, error) {
 	ctx := context.Background()
-	doc, err := r.Client.Collection("supporters").Where("case_id", "==", caseID).Documents(ctx).GetAll()
+	docs, err := r.Client.Collection("supporters").Where("case_id", "==", caseID).Documents(ctx).GetAll()
 	if err != nil {
 		return nil, err
 	}
 
-	var supporter models.Supporter
-	doc[0].DataTo(&supporter)
+	supporter, ok, err := firstLive(docs, dataTo[models.Supporter])
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("supporter for case %s: %w", caseID, ErrNotFound)
+	}
 	return &supporter, nil
 }
 
@@ -66,14 +79,9 @@ func (r *SupporterRepo) GetSupporterByCaseID(caseID string) (*models.Supporter,
 func (r *SupporterRepo) CreateSupporter(supporter *models.Supporter) error {
 	ctx := context.Background()
 
-	docs, err := r.Client.Collection("supporters").OrderBy("supporter_id", firestore.Desc).Limit(1).Documents(ctx).GetAll()
-	nextID := "SP-00001"
-	if err == nil && len(docs) > 0 {
-		lastID := docs[0].Data()["supporter_id"].(string)
-		numStr := strings.TrimPrefix(lastID, "SP-")
-		if n, err := strconv.Atoi(numStr); err == nil {
-			nextID = fmt.Sprintf("SP-%05d", n+1)
-		}
+	nextID, err := r.Seq.Next("SP")
+	if err != nil {
+		return err
 	}
 
 	supporter.SupporterID = nextID
@@ -81,7 +89,8 @@ func (r *SupporterRepo) CreateSupporter(supporter *models.Supporter) error {
 	supporter.CreatedAt = now
 	supporter.UpdatedAt = now
 
-	_, err = r.Client.Collection("supporters").Doc(supporter.SupporterID).Set(ctx, supporter)
+	// Create (not Set) fails instead of silently overwriting an existing document
+	_, err = r.Client.Collection("supporters").Doc(supporter.SupporterID).Create(ctx, supporter)
 	return err
 }
 
@@ -92,3 +101,18 @@ func (r *SupporterRepo) UpdateSupporterByID(supporterID string, data map[string]
 	_, err := r.Client.Collection("supporters").Doc(supporterID).Set(ctx, data, firestore.MergeAll)
 	return err
 }
+
+// 🟢 DeleteSupporter - soft delete
+func (r *SupporterRepo) DeleteSupporter(supporterID, deletedBy string) error {
+	return trash(r.Client, "supporters", "supporter", supporterID, deletedBy)
+}
+
+// 🟢 RestoreSupporter
+func (r *SupporterRepo) RestoreSupporter(supporterID string) error {
+	return restore(r.Client, "supporters", "supporter", supporterID)
+}
+
+// 🟢 PurgeSupporters
+func (r *SupporterRepo) PurgeSupporters(cutoff time.Time) ([]string, error) {
+	return purge(r.Client.Collection("supporters"), "", cutoff, nil)
+}
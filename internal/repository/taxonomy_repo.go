@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"trl-research-backend/internal/models"
+)
+
+type TaxonomyRepo struct {
+	Client *firestore.Client
+	Seq    SequenceGenerator
+}
+
+func NewTaxonomyRepo(client *firestore.Client) *TaxonomyRepo {
+	return &TaxonomyRepo{Client: client, Seq: NewFirestoreSequence(client)}
+}
+
+func (r *TaxonomyRepo) col() *firestore.CollectionRef {
+	return r.Client.Collection("taxonomy_terms")
+}
+
+// 🟢 GetTaxonomyTerms
+func (r *TaxonomyRepo) GetTaxonomyTerms(kind string) ([]models.TaxonomyTerm, error) {
+	query := r.col().Query
+	if kind != "" {
+		query = query.Where("kind", "==", kind)
+	}
+	docs, err := query.Documents(context.Background()).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	terms := []models.TaxonomyTerm{}
+	for _, doc := range docs {
+		var t models.TaxonomyTerm
+		if err := doc.DataTo(&t); err != nil {
+			return nil, err
+		}
+		terms = append(terms, t)
+	}
+	// sorted here instead of OrderBy so no composite index is needed
+	sort.Slice(terms, func(i, j int) bool { return terms[i].ID < terms[j].ID })
+	return terms, nil
+}
+
+// 🟢 GetTaxonomyTermByID
+func (r *TaxonomyRepo) GetTaxonomyTermByID(id string) (*models.TaxonomyTerm, error) {
+	doc, err := r.col().Doc(id).Get(context.Background())
+	if status.Code(err) == codes.NotFound {
+		return nil, fmt.Errorf("taxonomy term %s: %w", id, ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var t models.TaxonomyTerm
+	if err := doc.DataTo(&t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// 🟢 CreateTaxonomyTerm - auto generate ID (TX-00001)
+func (r *TaxonomyRepo) CreateTaxonomyTerm(t *models.TaxonomyTerm) error {
+	id, err := r.Seq.Next("TX")
+	if err != nil {
+		return err
+	}
+	t.ID = id
+	now := time.Now()
+	t.CreatedAt = now
+	t.UpdatedAt = now
+	_, err = r.col().Doc(t.ID).Create(context.Background(), t)
+	return err
+}
+
+// 🟢 UpdateTaxonomyTerm
+func (r *TaxonomyRepo) UpdateTaxonomyTerm(t *models.TaxonomyTerm) error {
+	t.UpdatedAt = time.Now()
+	_, err := r.col().Doc(t.ID).Update(context.Background(), []firestore.Update{
+		{Path: "label", Value: t.Label},
+		{Path: "synonyms", Value: t.Synonyms},
+		{Path: "merged_into", Value: t.MergedInto},
+		{Path: "updated_at", Value: t.UpdatedAt},
+	})
+	if status.Code(err) == codes.NotFound {
+		return fmt.Errorf("taxonomy term %s: %w", t.ID, ErrNotFound)
+	}
+	return err
+}
+
+// 🟢 ApplyTermChange - the case revisions are read first, Firestore transactions write last
+func (r *TaxonomyRepo) ApplyTermChange(terms []models.TaxonomyTerm, cases []CasePatch) error {
+	ctx := context.Background()
+	now := time.Now()
+	for i := range terms {
+		terms[i].UpdatedAt = now
+	}
+	for i := range cases {
+		caseData(cases[i].Data)
+		cases[i].Data["revision"] = cases[i].Revision + 1
+	}
+	err := r.Client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		for _, p := range cases {
+			doc, err := tx.Get(r.Client.Collection("cases").Doc(p.CaseID))
+			if status.Code(err) == codes.NotFound {
+				return fmt.Errorf("case %s: %w", p.CaseID, ErrNotFound)
+			}
+			if err != nil {
+				return err
+			}
+			cs, err := caseFromDoc(doc)
+			if err != nil {
+				return err
+			}
+			if cs.Revision != p.Revision {
+				return fmt.Errorf("case %s is at revision %d: %w", p.CaseID, cs.Revision, ErrStale)
+			}
+		}
+		for _, t := range terms {
+			err := tx.Update(r.col().Doc(t.ID), []firestore.Update{
+				{Path: "label", Value: t.Label},
+				{Path: "synonyms", Value: t.Synonyms},
+				{Path: "merged_into", Value: t.MergedInto},
+				{Path: "updated_at", Value: now},
+			})
+			if err != nil {
+				return err
+			}
+		}
+		for _, p := range cases {
+			if err := tx.Set(r.Client.Collection("cases").Doc(p.CaseID), p.Data, firestore.MergeAll); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if status.Code(err) == codes.NotFound {
+		return fmt.Errorf("taxonomy term: %w", ErrNotFound)
+	}
+	return err
+}
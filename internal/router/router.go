@@ -4,131 +4,276 @@ import (
 	"net/http"
 	"time"
 
+	"trl-research-backend/internal/audit"
 	auth "trl-research-backend/internal/auth"
-	"trl-research-backend/internal/database"
 	"trl-research-backend/internal/handlers"
 	"trl-research-backend/internal/repository"
 	"trl-research-backend/internal/storage"
+	"trl-research-backend/internal/trash"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 )
 
-func SetupRouter(gcsClient *storage.GCSClient) *gin.Engine {
+func SetupRouter(repos *repository.Repositories, gcsClient *storage.GCSClient, searchIndex *handlers.SearchIndex, purger *trash.Purger) *gin.Engine {
 	gin.SetMode(gin.ReleaseMode) // ปิด debug log ของ Gin
 	r := gin.Default()
 	r.SetTrustedProxies([]string{"127.0.0.1"})
+	r.Use(audit.RequestID())
 
 	// ✅ CORS config
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:3000", "https://punyanuch-h.github.io"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "If-Match", "If-None-Match", audit.RequestIDHeader, handlers.IdempotencyKeyHeader},
+		ExposeHeaders:    []string{"Content-Length", "ETag", audit.RequestIDHeader, handlers.IdempotentReplayedHeader},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
 
-	// ✅ Firestore repositories
-	adminRepo := repository.NewAdminRepo(database.FirestoreClient)
-	researcherRepo := repository.NewResearcherRepo(database.FirestoreClient)
-	coordinatorRepo := repository.NewCoordinatorRepo(database.FirestoreClient)
-	supporterRepo := repository.NewSupporterRepo(database.FirestoreClient)
-	appointmentRepo := repository.NewAppointmentRepo(database.FirestoreClient)
-	caseRepo := repository.NewCaseRepo(database.FirestoreClient)
-	ipRepo := repository.NewIntellectualPropertyRepo(database.FirestoreClient)
-	assessmentTrlRepo := repository.NewAssessmentTrlRepo(database.FirestoreClient)
-	fileRepo := repository.NewFileRepo(database.FirestoreClient)
-
 	// ✅ Handlers
-	adminHandler := &handlers.AdminHandler{Repo: adminRepo}
-	researcherHandler := &handlers.ResearcherHandler{Repo: researcherRepo}
-	coordinatorHandler := &handlers.CoordinatorHandler{Repo: coordinatorRepo}
-	supporterHandler := &handlers.SupporterHandler{Repo: supporterRepo}
-	appointmentHandler := &handlers.AppointmentHandler{Repo: appointmentRepo}
-	caseHandler := &handlers.CaseHandler{Repo: caseRepo}
-	ipHandler := &handlers.IntellectualPropertyHandler{Repo: ipRepo}
-	assessmentTrlHandler := &handlers.AssessmentTrlHandler{Repo: assessmentTrlRepo}
+	auditLog := &audit.Logger{Repo: repos.Audit, Routes: audit.Routes(repos)}
+	adminHandler := &handlers.AdminHandler{Repo: repos.Admin, Sessions: repos.Session}
+	researcherHandler := &handlers.ResearcherHandler{Repo: repos.Researcher, Sessions: repos.Session, Search: searchIndex}
+	coordinatorHandler := &handlers.CoordinatorHandler{Repo: repos.Coordinator}
+	caseTrl := &handlers.CaseTrl{
+		Assessments: repos.AssessmentTrl,
+		Templates:   repos.Questionnaire,
+		Cases:       repos.Case,
+		Supporters:  repos.Supporter,
+		Audit:       auditLog,
+	}
+	supporterHandler := &handlers.SupporterHandler{Repo: repos.Supporter, Trl: caseTrl}
+	appointmentHandler := &handlers.AppointmentHandler{Repo: repos.Appointment, Cases: repos.Case}
+	caseHandler := &handlers.CaseHandler{
+		Repo:   repos.Case,
+		Trl:    caseTrl,
+		Search: searchIndex,
+		Terms:  repos.Taxonomy,
+		Records: handlers.CaseRecords{
+			Appointments: repos.Appointment,
+			IPs:          repos.IntellectualProperty,
+			Supporters:   repos.Supporter,
+			Assessments:  repos.AssessmentTrl,
+		},
+		Audit: auditLog,
+	}
+	caseFullHandler := &handlers.CaseFullHandler{
+		Cases:        repos.Case,
+		Researchers:  repos.Researcher,
+		Coordinators: repos.Coordinator,
+		Appointments: repos.Appointment,
+		IPs:          repos.IntellectualProperty,
+		Supporters:   repos.Supporter,
+		Assessments:  repos.AssessmentTrl,
+		Files:        repos.File,
+	}
+	submissionHandler := &handlers.SubmissionHandler{
+		Repo:      repos.Submission,
+		Terms:     repos.Taxonomy,
+		Templates: repos.Questionnaire,
+		Trl:       caseTrl,
+		Search:    searchIndex,
+		Audit:     auditLog,
+	}
+	draftHandler := &handlers.DraftHandler{Repo: repos.Draft, Submissions: submissionHandler}
+	ipHandler := &handlers.IntellectualPropertyHandler{
+		Repo:   repos.IntellectualProperty,
+		Cases:  repos.Case,
+		Search: searchIndex,
+	}
+	assessmentTrlHandler := &handlers.AssessmentTrlHandler{
+		Repo:      repos.AssessmentTrl,
+		Templates: repos.Questionnaire,
+		Trl:       caseTrl,
+	}
+	questionnaireHandler := &handlers.QuestionnaireHandler{Repo: repos.Questionnaire}
+	taxonomyHandler := &handlers.TaxonomyHandler{Repo: repos.Taxonomy, Cases: repos.Case, Search: searchIndex, Audit: auditLog}
 	presignHandler := &handlers.PresignHandler{GCS: gcsClient}
-	fileHandler := &handlers.FileHandler{Repo: fileRepo}
-	fileDownloadHandler := &handlers.FileDownloadHandler{FileRepo: fileRepo, GCS: gcsClient}
+	fileHandler := &handlers.FileHandler{Repo: repos.File}
+	fileDownloadHandler := &handlers.FileDownloadHandler{FileRepo: repos.File, GCS: gcsClient}
+	auditHandler := &handlers.AuditHandler{Repo: repos.Audit}
+	trashHandler := &handlers.TrashHandler{Purger: purger}
 
 	// ✅ Auth Handlers
+	loginGuard := &auth.LoginGuard{Attempts: repos.LoginAttempt}
 	loginHandler := &auth.LoginHandler{
-		AdminRepo:      adminRepo,
-		ResearcherRepo: researcherRepo,
+		AdminRepo:       repos.Admin,
+		ResearcherRepo:  repos.Researcher,
+		CoordinatorRepo: repos.Coordinator,
+		Sessions:        repos.Session,
+		MFA:             repos.MFA,
+		Guard:           loginGuard,
+	}
+	mfaHandler := &auth.MFAHandler{MFA: repos.MFA, Sessions: repos.Session, Guard: loginGuard, Audit: auditLog}
+	sessionHandler := &auth.SessionHandler{Sessions: repos.Session}
+	forgotHandler := &auth.ForgotHandler{
+		AdminRepo:       repos.Admin,
+		ResearcherRepo:  repos.Researcher,
+		CoordinatorRepo: repos.Coordinator,
+		Resets:          repos.PasswordReset,
+	}
+	resetHandler := &auth.ResetHandler{
+		AdminRepo:       repos.Admin,
+		ResearcherRepo:  repos.Researcher,
+		CoordinatorRepo: repos.Coordinator,
+		Resets:          repos.PasswordReset,
+		Sessions:        repos.Session,
+		Guard:           loginGuard,
+		Audit:           auditLog,
 	}
-	forgotHandler := &auth.ForgotHandler{AdminRepo: *adminRepo}
-	resetHandler := &auth.ResetHandler{AdminRepo: *adminRepo}
 
 	// ✅ Health check
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"message": "OK"})
 	})
 
+	// ✅ Public keys for validating our JWTs
+	r.GET("/.well-known/jwks.json", auth.JWKS)
+
 	// ✅ Public Auth
 	r.POST("/auth/login", loginHandler.Login)
+	r.POST("/auth/mfa/verify", mfaHandler.Verify)
+	r.POST("/auth/mfa/enroll", mfaHandler.EnrollPending)
+	r.POST("/auth/mfa/enroll/confirm", mfaHandler.ConfirmPending)
+	r.POST("/auth/refresh", sessionHandler.Refresh)
+	r.POST("/auth/logout", sessionHandler.Logout)
 	r.POST("/auth/forgot-password", forgotHandler.ForgotPassword)
 	r.POST("/auth/reset-password", resetHandler.ResetPassword)
-	r.POST("/admin", adminHandler.CreateAdmin)
+	r.POST("/auth/reset-password/confirm", resetHandler.ConfirmReset)
+	r.POST("/admin", auditLog.Middleware(), adminHandler.BootstrapAdmin) // the first admin only
+
+	// ✅ Protected APIs - every route declares who may call it
+	access := &auth.Access{Repos: repos}
+	can := access.Require
 
-	// ✅ Protected APIs
 	api := r.Group("/trl")
-	// api.Use(auth.AuthMiddleware())
+	api.Use(auth.AuthMiddleware(repos.Session), auditLog.Middleware())
 	{
-		api.GET("/admins", adminHandler.GetAllAdmins)
-		api.GET("/admin/:id", adminHandler.GetAdminByID)
-		api.GET("/admin/profile", adminHandler.GetAdminProfile)
-		api.PATCH("/admin/:id", adminHandler.UpdateAdminProfileByID)
-
-		api.GET("/researchers", researcherHandler.GetResearcherAll)
-		api.GET("/researcher/:id", researcherHandler.GetResearcherByID)
-		api.GET("/researcher/case/:id", researcherHandler.GetResearcherByCaseID)
-		api.POST("/researcher", researcherHandler.CreateResearcher)
-		api.PATCH("/researcher/:id", researcherHandler.UpdateResearcherProfileByID)
-		api.GET("/researcher/profile", researcherHandler.GetResearcherProfile)
-
-		api.GET("/coordinators", coordinatorHandler.GetCoordinatorAll)
-		api.GET("/coordinator/:id", coordinatorHandler.GetCoordinatorByEmail)
-		api.GET("/coordinator/case/:id", coordinatorHandler.GetCoordinatorByCaseID)
-		api.POST("/coordinator", coordinatorHandler.CreateCoordinator)
-		api.PATCH("/coordinator/:id", coordinatorHandler.UpdateCoordinatorByEmail)
-
-		api.GET("/supporters", supporterHandler.GetSupporterAll)
-		api.GET("/supporter/:id", supporterHandler.GetSupporterByID)
-		api.GET("/supporter/case/:id", supporterHandler.GetSupporterByCaseID)
-		api.POST("/supporter", supporterHandler.CreateSupporter)
-		api.PATCH("/supporter/:id", supporterHandler.UpdateSupporterByID)
-
-		api.GET("/appointments", appointmentHandler.GetAppointmentAll)
-		api.GET("/appointment/:id", appointmentHandler.GetAppointmentByID)
-		api.GET("/appointment/case/:id", appointmentHandler.GetAppointmentByCaseID)
-		api.POST("/appointment", appointmentHandler.CreateAppointment)
-		api.PATCH("/appointment/:id", appointmentHandler.UpdateAppointmentByID)
-
-		api.GET("/cases", caseHandler.GetCaseAll)
-		api.GET("/case/researcher/:id", caseHandler.GetCaseAllByResearcher_id)
-		api.GET("/case/:id", caseHandler.GetCaseByID)
-		api.POST("/case", caseHandler.CreateCase)
-		api.PATCH("/case/:id", caseHandler.UpdateCaseByID)
-		api.PATCH("/case/update-status/:id", caseHandler.UpdateCaseStatusByID)
-
-		api.GET("/ips", ipHandler.GetIPAll)
-		api.GET("/ip/:id", ipHandler.GetIPByID)
-		api.GET("/ip/case/:id", ipHandler.GetIPByCaseID)
-		api.POST("/ip", ipHandler.CreateIP)
-		api.PATCH("/ip/:id", ipHandler.UpdateIPByID)
-
-		api.GET("/assessment_trl", assessmentTrlHandler.GetAssessmentTrlAll)
-		api.GET("/assessment_trl/:id", assessmentTrlHandler.GetAssessmentTrlByID)
-		api.GET("/assessment_trl/case/:id", assessmentTrlHandler.GetAssessmentTrlByCaseID)
-		api.POST("/assessment_trl", assessmentTrlHandler.CreateAssessmentTrl)
-		api.PATCH("/assessment_trl/:id", assessmentTrlHandler.UpdateAssessmentTrlByID)
-
-		// 🟢 File Management
-		api.POST("/presign/upload", presignHandler.PresignUpload)
-		api.POST("/file/upload", fileHandler.FileUploaded)
-		api.GET("/file/download-url/:fileID", fileDownloadHandler.GetDownloadURL)
+		api.GET("/admins", can(auth.AdminOnly()), adminHandler.GetAllAdmins)
+		api.POST("/admin", can(auth.AdminOnly()), adminHandler.CreateAdmin)
+		api.GET("/admin/:id", can(auth.AdminOnly()), adminHandler.GetAdminByID)
+		api.GET("/admin/profile", can(auth.AdminOnly()), adminHandler.GetAdminProfile)
+		api.PATCH("/admin/:id", can(auth.AdminOnly()), adminHandler.UpdateAdminProfileByID)
+		api.DELETE("/admin/:id", can(auth.AdminOnly()), adminHandler.DeleteAdmin)
+		api.POST("/admin/:id/restore", can(auth.AdminOnly()), adminHandler.RestoreAdmin)
+
+		api.GET("/sessions/user/:id", can(auth.AdminOnly()), sessionHandler.GetSessionsByUserID)
+		api.POST("/sessions/user/:id/revoke", can(auth.AdminOnly()), sessionHandler.RevokeSessionsByUserID)
+		api.GET("/login-lock", can(auth.AdminOnly()), loginGuard.GetLockStatus)
+		api.POST("/login-lock/unlock", can(auth.AdminOnly()), loginGuard.Unlock)
+		api.GET("/login-audit", can(auth.AdminOnly()), loginGuard.GetLoginAudit)
+		api.GET("/audit", can(auth.AdminOnly()), auditHandler.GetAuditLog)
+		api.POST("/trash/purge", can(auth.AdminOnly()), trashHandler.PurgeTrash)
+
+		api.GET("/mfa", can(auth.AdminOnly()), mfaHandler.GetStatus)
+		api.POST("/mfa/enroll", can(auth.AdminOnly()), mfaHandler.Enroll)
+		api.POST("/mfa/enroll/confirm", can(auth.AdminOnly()), mfaHandler.ConfirmEnroll)
+		api.POST("/mfa/recovery-codes", can(auth.AdminOnly()), mfaHandler.RegenerateRecoveryCodes)
+		api.POST("/mfa/disable", can(auth.AdminOnly()), mfaHandler.Disable)
+		api.POST("/mfa/user/:id/reset", can(auth.AdminOnly()), mfaHandler.ResetUserMFA)
+
+		api.GET("/researchers", can(auth.Staff()), researcherHandler.GetResearcherAll)
+		api.GET("/researcher/:id", can(auth.StaffOrOwner(auth.SelfParam("id"))), researcherHandler.GetResearcherByID)
+		api.GET("/researcher/case/:id", can(auth.StaffOrOwner(auth.OwnsCaseParam("id"))), researcherHandler.GetResearcherByCaseID)
+		api.POST("/researcher", can(auth.AdminOnly()), researcherHandler.CreateResearcher)
+		api.PATCH("/researcher/:id", can(auth.AdminOrOwner(auth.SelfParam("id"))), researcherHandler.UpdateResearcherProfileByID)
+		api.DELETE("/researcher/:id", can(auth.AdminOnly()), researcherHandler.DeleteResearcher)
+		api.POST("/researcher/:id/restore", can(auth.AdminOnly()), researcherHandler.RestoreResearcher)
+		api.GET("/researcher/profile", can(auth.Policy{Roles: []string{auth.RoleResearcher}}), researcherHandler.GetResearcherProfile)
+
+		api.GET("/coordinators", can(auth.Staff()), coordinatorHandler.GetCoordinatorAll)
+		api.GET("/coordinator/:id", can(auth.Staff()), coordinatorHandler.GetCoordinatorByEmail)
+		api.GET("/coordinator/case/:id", can(auth.StaffOrOwner(auth.OwnsCaseParam("id"))), coordinatorHandler.GetCoordinatorByCaseID)
+		api.POST("/coordinator", can(auth.AdminOnly()), coordinatorHandler.CreateCoordinator)
+		api.PATCH("/coordinator/:id", can(auth.AdminOnly()), coordinatorHandler.UpdateCoordinatorByEmail)
+		api.DELETE("/coordinator/:id", can(auth.AdminOnly()), coordinatorHandler.DeleteCoordinator)
+		api.POST("/coordinator/:id/restore", can(auth.AdminOnly()), coordinatorHandler.RestoreCoordinator)
+
+		api.GET("/supporters", can(auth.Staff()), supporterHandler.GetSupporterAll)
+		api.GET("/supporter/:id", can(auth.StaffOrOwner(auth.OwnsSupporterParam("id"))), supporterHandler.GetSupporterByID)
+		api.GET("/supporter/case/:id", can(auth.StaffOrOwner(auth.OwnsCaseParam("id"))), supporterHandler.GetSupporterByCaseID)
+		api.POST("/supporter", can(auth.AdminOrOwner(auth.OwnsBodyCase("case_id", true))), supporterHandler.CreateSupporter)
+		api.PATCH("/supporter/:id", can(auth.AdminOrOwner(auth.OwnsSupporterParam("id"), auth.OwnsBodyCase("case_id", false))), supporterHandler.UpdateSupporterByID)
+		api.DELETE("/supporter/:id", can(auth.AdminOrOwner(auth.OwnsSupporterParam("id"))), supporterHandler.DeleteSupporter)
+		api.POST("/supporter/:id/restore", can(auth.AdminOnly()), supporterHandler.RestoreSupporter)
+
+		// coordinators schedule meetings, so they may write appointments too
+		appointmentWriters := auth.StaffOrOwner(auth.OwnsBodyCase("case_id", true))
+		api.GET("/appointments", can(auth.Staff()), appointmentHandler.GetAppointmentAll)
+		api.GET("/appointment/:id", can(auth.StaffOrOwner(auth.OwnsAppointmentParam("id"))), appointmentHandler.GetAppointmentByID)
+		api.GET("/appointment/case/:id", can(auth.StaffOrOwner(auth.OwnsCaseParam("id"))), appointmentHandler.GetAppointmentByCaseID)
+		api.POST("/appointment", can(appointmentWriters), appointmentHandler.CreateAppointment)
+		api.PATCH("/appointment/:id", can(auth.StaffOrOwner(auth.OwnsAppointmentParam("id"), auth.OwnsBodyCase("case_id", false))), appointmentHandler.UpdateAppointmentByID)
+		api.DELETE("/appointment/:id", can(auth.StaffOrOwner(auth.OwnsAppointmentParam("id"))), appointmentHandler.DeleteAppointment)
+		api.POST("/appointment/:id/restore", can(auth.AdminOnly()), appointmentHandler.RestoreAppointment)
+
+		api.GET("/cases", can(auth.Staff()), caseHandler.GetCaseAll)
+		api.GET("/case/researcher/:id", can(auth.StaffOrOwner(auth.SelfParam("id"))), caseHandler.GetCaseAllByResearcher_id)
+		api.GET("/case/:id", can(auth.StaffOrOwner(auth.OwnsCaseParam("id"))), caseHandler.GetCaseByID)
+		api.GET("/case/:id/full", can(auth.StaffOrOwner(auth.OwnsCaseParam("id"))), caseFullHandler.GetCaseFull)
+		api.POST("/case", can(auth.AdminOrOwner(auth.SelfBody("researcher_id", true))), caseHandler.CreateCase)
+		api.PATCH("/case/:id", can(auth.AdminOrOwner(auth.OwnsCaseParam("id"), auth.SelfBody("researcher_id", false))), caseHandler.UpdateCaseByID)
+		api.PATCH("/case/update-status/:id", can(auth.StaffOrOwner(auth.OwnsCaseParam("id"))), caseHandler.UpdateCaseStatusByID)
+		api.POST("/case/:id/transition", can(auth.StaffOrOwner(auth.OwnsCaseParam("id"))), caseHandler.TransitionCaseStatus)
+		api.GET("/case/:id/transitions", can(auth.StaffOrOwner(auth.OwnsCaseParam("id"))), caseHandler.GetCaseTransitions)
+		api.GET("/case/:id/status-history", can(auth.StaffOrOwner(auth.OwnsCaseParam("id"))), caseHandler.GetCaseStatusHistory)
+		api.POST("/case/:id/trl-suggestion", can(auth.Staff()), caseHandler.RefreshTrlSuggestion)
+		api.DELETE("/case/:id", can(auth.AdminOrOwner(auth.OwnsCaseParam("id"))), caseHandler.DeleteCase)
+		api.POST("/case/:id/restore", can(auth.AdminOnly()), caseHandler.RestoreCase)
+		api.POST("/submissions", can(auth.AdminOrOwner(auth.SelfBody("case.researcher_id", false))), submissionHandler.CreateSubmission)
+
+		draftOwner := auth.AdminOrOwner(auth.OwnsDraftParam("id"))
+		api.GET("/drafts", can(auth.ResearcherOnly()), draftHandler.GetMyDrafts)
+		api.POST("/drafts", can(auth.ResearcherOnly()), draftHandler.CreateDraft)
+		api.GET("/drafts/:id", can(draftOwner), draftHandler.GetDraftByID)
+		api.PUT("/drafts/:id", can(draftOwner), draftHandler.SaveDraft)
+		api.PUT("/drafts/:id/:part", can(draftOwner), draftHandler.SaveDraftPart)
+		api.DELETE("/drafts/:id", can(draftOwner), draftHandler.DeleteDraft)
+		api.POST("/drafts/:id/submit", can(draftOwner), draftHandler.SubmitDraft)
+
+		api.GET("/ips", can(auth.Staff()), ipHandler.GetIPAll)
+		api.GET("/ip/:id", can(auth.StaffOrOwner(auth.OwnsIPParam("id"))), ipHandler.GetIPByID)
+		api.GET("/ip/case/:id", can(auth.StaffOrOwner(auth.OwnsCaseParam("id"))), ipHandler.GetIPByCaseID)
+		api.POST("/ip", can(auth.AdminOrOwner(auth.OwnsBodyCase("case_id", true))), ipHandler.CreateIP)
+		api.PATCH("/ip/:id", can(auth.AdminOrOwner(auth.OwnsIPParam("id"), auth.OwnsBodyCase("case_id", false))), ipHandler.UpdateIPByID)
+		api.DELETE("/ip/:id", can(auth.AdminOrOwner(auth.OwnsIPParam("id"))), ipHandler.DeleteIP)
+		api.POST("/ip/:id/restore", can(auth.AdminOnly()), ipHandler.RestoreIP)
+
+		api.GET("/search", can(auth.Staff()), searchIndex.Search)
+
+		api.GET("/taxonomy", can(auth.AnyRole()), taxonomyHandler.GetTerms)
+		api.GET("/taxonomy/report", can(auth.Staff()), taxonomyHandler.GetReport)
+		api.GET("/taxonomy/:id", can(auth.AnyRole()), taxonomyHandler.GetTerm)
+		api.POST("/taxonomy", can(auth.AdminOnly()), taxonomyHandler.CreateTerm)
+		api.PATCH("/taxonomy/:id", can(auth.AdminOnly()), taxonomyHandler.UpdateTerm)
+		api.POST("/taxonomy/:id/merge", can(auth.AdminOnly()), taxonomyHandler.MergeTerm)
+
+		api.GET("/questionnaires", can(auth.AnyRole()), questionnaireHandler.GetQuestionnaires)
+		api.GET("/questionnaire/active", can(auth.AnyRole()), questionnaireHandler.GetActiveQuestionnaire)
+		api.GET("/questionnaire/:version", can(auth.AnyRole()), questionnaireHandler.GetQuestionnaire)
+		api.POST("/questionnaire", can(auth.AdminOnly()), questionnaireHandler.CreateQuestionnaire)
+		api.PUT("/questionnaire/:version", can(auth.AdminOnly()), questionnaireHandler.UpdateQuestionnaire)
+		api.DELETE("/questionnaire/:version", can(auth.AdminOnly()), questionnaireHandler.DeleteQuestionnaire)
+		api.POST("/questionnaire/:version/publish", can(auth.AdminOnly()), questionnaireHandler.PublishQuestionnaire)
+		api.POST("/questionnaire/:version/activate", can(auth.AdminOnly()), questionnaireHandler.ActivateQuestionnaire)
+
+		api.GET("/assessment_trl", can(auth.Staff()), assessmentTrlHandler.GetAssessmentTrlAll)
+		api.GET("/assessment_trl/:id", can(auth.StaffOrOwner(auth.OwnsAssessmentParam("id"))), assessmentTrlHandler.GetAssessmentTrlByID)
+		api.GET("/assessment_trl/case/:id", can(auth.StaffOrOwner(auth.OwnsCaseParam("id"))), assessmentTrlHandler.GetAssessmentTrlByCaseID)
+		api.GET("/assessment_trl/case/:id/history", can(auth.StaffOrOwner(auth.OwnsCaseParam("id"))), assessmentTrlHandler.GetAssessmentTrlHistory)
+		api.GET("/assessment_trl/case/:id/timeline", can(auth.StaffOrOwner(auth.OwnsCaseParam("id"))), assessmentTrlHandler.GetAssessmentTrlTimeline)
+		api.POST("/assessment_trl/:id/review", can(auth.Staff()), assessmentTrlHandler.ReviewAssessmentTrl)
+		api.POST("/assessment_trl/score", can(auth.AnyRole()), assessmentTrlHandler.ScoreAssessmentTrl)
+		api.POST("/assessment_trl", can(auth.AdminOrOwner(auth.OwnsBodyCase("case_id", true))), assessmentTrlHandler.CreateAssessmentTrl)
+		api.PATCH("/assessment_trl/:id", can(auth.AdminOrOwner(auth.OwnsAssessmentParam("id"))), assessmentTrlHandler.UpdateAssessmentTrlByID)
+		api.DELETE("/assessment_trl/:id", can(auth.AdminOrOwner(auth.OwnsAssessmentParam("id"))), assessmentTrlHandler.DeleteAssessmentTrl)
+		api.POST("/assessment_trl/:id/restore", can(auth.AdminOnly()), assessmentTrlHandler.RestoreAssessmentTrl)
+
+		// 🟢 File Management (download permission is checked per file in the handler)
+		api.POST("/presign/upload", can(auth.AnyRole()), presignHandler.PresignUpload)
+		api.POST("/file/upload", can(auth.StaffOrOwner(auth.OwnsBodyCase("belongs_to_case_id", true))), fileHandler.FileUploaded)
+		api.GET("/file/download-url/:fileID", can(auth.AnyRole()), fileDownloadHandler.GetDownloadURL)
 	}
 
 	return r
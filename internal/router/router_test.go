@@ -0,0 +1,31 @@
+package router
+
+import (
+	"net/http"
+	"testing"
+
+	"trl-research-backend/internal/audit"
+	"trl-research-backend/internal/repository/memory"
+)
+
+// every call that writes is in the audit route table, logged or skipped on purpose
+func TestMutatingRoutesAreAudited(t *testing.T) {
+	repos := memory.NewRepositories()
+	routes := audit.Routes(repos)
+	r := SetupRouter(repos, nil, nil, nil)
+
+	checked := 0
+	for _, route := range r.Routes() {
+		switch route.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			continue
+		}
+		checked++
+		if _, ok := routes[route.Method+" "+route.Path]; !ok {
+			t.Errorf("%s %s has no audit.Routes entry", route.Method, route.Path)
+		}
+	}
+	if checked == 0 {
+		t.Fatal("no mutating routes")
+	}
+}
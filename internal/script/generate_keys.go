@@ -1,15 +1,44 @@
+//go:build ignore
+
 package main
 
 import (
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
+	"encoding/base64"
 	"encoding/pem"
+	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
+
+	"trl-research-backend/internal/utils"
 )
 
+// go run internal/script/generate_keys.go [-kid v2] [-dir keys]
+//
+// Writes private_key_<kid>.pem / public_key_<kid>.pem, the layout JWT_KEYS_DIR expects.
+// The kid is written the way the server reads it back (utils.ParseKID): -kid Prod-1 -> prod_1.
 func main() {
+	kidFlag := flag.String("kid", "v1", "key id written into the JWT header")
+	dir := flag.String("dir", ".", "output directory")
+	flag.Parse()
+
+	kid, err := utils.ParseKID(*kidFlag)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := os.MkdirAll(*dir, 0o700); err != nil {
+		fmt.Printf("Error creating output directory: %v\n", err)
+		return
+	}
+	privatePath := filepath.Join(*dir, "private_key_"+kid+".pem")
+	publicPath := filepath.Join(*dir, "public_key_"+kid+".pem")
+
 	// Generate private key
 	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
 	if err != nil {
@@ -29,7 +58,7 @@ func main() {
 		Bytes: privateKeyBytes,
 	}
 
-	privateKeyFile, err := os.Create("private_key_v1.pem")
+	privateKeyFile, err := os.OpenFile(privatePath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
 	if err != nil {
 		fmt.Printf("Error creating private key file: %v\n", err)
 		return
@@ -54,7 +83,7 @@ func main() {
 		Bytes: publicKeyBytes,
 	}
 
-	publicKeyFile, err := os.Create("public_key_v1.pem")
+	publicKeyFile, err := os.OpenFile(publicPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
 	if err != nil {
 		fmt.Printf("Error creating public key file: %v\n", err)
 		return
@@ -68,6 +97,12 @@ func main() {
 	}
 
 	fmt.Println("✅ RSA key pair generated successfully!")
-	fmt.Println("📁 private_key_v1.pem (PKCS#8 format)")
-	fmt.Println("📁 public_key_v1.pem (PKIX format)")
+	fmt.Printf("📁 %s (PKCS#8 format)\n", privatePath)
+	fmt.Printf("📁 %s (PKIX format)\n", publicPath)
+
+	// same keys for env-based deployments (e.g. Cloud Run secrets)
+	envName := strings.ToUpper(kid)
+	fmt.Printf("\nPRIVATE_KEY_%s_B64=%s\n", envName, base64.StdEncoding.EncodeToString(pem.EncodeToMemory(privateKeyPEM)))
+	fmt.Printf("PUBLIC_KEY_%s_B64=%s\n", envName, base64.StdEncoding.EncodeToString(pem.EncodeToMemory(publicKeyPEM)))
+	fmt.Printf("\nsign with it: JWT_ACTIVE_KID=%s\n", kid)
 }
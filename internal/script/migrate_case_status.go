@@ -0,0 +1,76 @@
+//go:build ignore
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"cloud.google.com/go/firestore"
+
+	"trl-research-backend/internal/database"
+	"trl-research-backend/internal/models"
+)
+
+// go run internal/script/migrate_case_status.go [-dry-run]
+//
+// Rewrites "status" of every Firestore case into a workflow state: the old bool
+// (true = approved, false = in process) and the free-text values of the old status endpoint.
+// Unknown values become under_review and are listed so someone can check them.
+// PostgreSQL is converted by migration 0006_case_status.sql.
+func main() {
+	dryRun := flag.Bool("dry-run", false, "only print what would change")
+	flag.Parse()
+
+	database.InitFirebase("trl-research-service-account.json")
+	defer database.CloseFirebase()
+	client := database.FirestoreClient
+	ctx := context.Background()
+
+	docs, err := client.Collection("cases").Documents(ctx).GetAll()
+	if err != nil {
+		log.Fatalf("❌ read cases: %v", err)
+	}
+
+	bw := client.BulkWriter(ctx)
+	changed, guessed := 0, 0
+	for _, doc := range docs {
+		raw := doc.Data()["status"]
+		if s, ok := raw.(string); ok && models.CaseStatus(s).Valid() {
+			continue
+		}
+		status, ok := models.ParseLegacyCaseStatus(raw)
+		if !ok {
+			guessed++
+			fmt.Printf("⚠️ %s: unknown status %v, set to %s\n", doc.Ref.ID, raw, status)
+		} else {
+			fmt.Printf("🔁 %s: %v → %s\n", doc.Ref.ID, raw, status)
+		}
+		changed++
+		if *dryRun {
+			continue
+		}
+
+		// the real time of the old state is unknown, updated_at is the best guess
+		at, _ := doc.Data()["updated_at"].(time.Time)
+		if at.IsZero() {
+			at = time.Now()
+		}
+		if _, err := bw.Update(doc.Ref, []firestore.Update{
+			{Path: "status", Value: status},
+			{Path: "status_changed_at", Value: at},
+			{FieldPath: firestore.FieldPath{"status_timestamps", string(status)}, Value: at},
+		}); err != nil {
+			log.Fatalf("❌ update %s: %v", doc.Ref.ID, err)
+		}
+	}
+	bw.End()
+
+	fmt.Printf("✅ %d of %d cases migrated (%d unknown values)\n", changed, len(docs), guessed)
+	if *dryRun {
+		fmt.Println("dry run, nothing written")
+	}
+}
@@ -0,0 +1,107 @@
+//go:build ignore
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+
+	"trl-research-backend/internal/database"
+	"trl-research-backend/internal/models"
+	"trl-research-backend/internal/repository"
+	"trl-research-backend/internal/repository/postgres"
+	"trl-research-backend/internal/taxonomy"
+)
+
+// go run internal/script/migrate_case_taxonomy.go [-backend firestore|postgres] [-dry-run]
+//
+// Gives every case that only has the old texts its taxonomy references: case_keywords is split
+// at commas and each keyword, like case_type, is matched against the labels and synonyms of the
+// terms. Unmatched texts become new terms; afterwards admins merge the duplicates through
+// POST /trl/taxonomy/:id/merge. Cases that already have references are left alone, so the script
+// can run again. firestore uses trl-research-service-account.json, postgres uses DB_URL.
+func main() {
+	backend := flag.String("backend", "firestore", "firestore or postgres")
+	dryRun := flag.Bool("dry-run", false, "only print what would change")
+	flag.Parse()
+
+	var repos *repository.Repositories
+	switch *backend {
+	case "firestore":
+		database.InitFirebase("trl-research-service-account.json")
+		defer database.CloseFirebase()
+		repos = repository.NewFirestoreRepositories(database.FirestoreClient)
+	case "postgres":
+		database.InitPostgres(os.Getenv("DB_URL"))
+		defer database.ClosePostgres()
+		repos = postgres.NewRepositories(database.PostgresPool)
+	default:
+		log.Fatalf("❌ unknown backend %q", *backend)
+	}
+
+	cases, err := repos.Case.GetCaseAll()
+	if err != nil {
+		log.Fatalf("❌ read cases: %v", err)
+	}
+
+	resolver := taxonomy.NewResolver(repos.Taxonomy)
+	newTerms := map[string]bool{}
+	changed := 0
+	for _, cs := range cases {
+		data := map[string]interface{}{}
+		if len(cs.KeywordIDs) == 0 && cs.CaseKeywords != "" {
+			data["case_keywords"] = cs.CaseKeywords
+		}
+		if cs.CaseTypeID == "" && cs.CaseType != "" {
+			data["case_type"] = cs.CaseType
+		}
+		if len(data) == 0 {
+			continue
+		}
+		changed++
+
+		if *dryRun {
+			for _, kw := range taxonomy.SplitKeywords(cs.CaseKeywords) {
+				noteNew(resolver, newTerms, models.TermKeyword, kw)
+			}
+			if data["case_type"] != nil {
+				noteNew(resolver, newTerms, models.TermCaseType, cs.CaseType)
+			}
+			fmt.Printf("🔁 %s: %q / %q\n", cs.CaseID, cs.CaseType, cs.CaseKeywords)
+			continue
+		}
+
+		if err := resolver.ResolvePatch(data); err != nil {
+			log.Fatalf("❌ %s: %v", cs.CaseID, err)
+		}
+		if err := repos.Case.UpdateCaseByID(cs.CaseID, data); err != nil {
+			log.Fatalf("❌ %s: %v", cs.CaseID, err)
+		}
+		fmt.Printf("🔁 %s: %s / %s\n", cs.CaseID, data["case_type_id"], data["case_keywords"])
+	}
+
+	fmt.Printf("✅ %d of %d cases migrated\n", changed, len(cases))
+	if *dryRun {
+		names := make([]string, 0, len(newTerms))
+		for name := range newTerms {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fmt.Printf("%d new terms: %v\n", len(names), names)
+		fmt.Println("dry run, nothing written")
+	}
+}
+
+// noteNew remembers a text that matches no term yet
+func noteNew(resolver *taxonomy.Resolver, newTerms map[string]bool, kind, name string) {
+	t, err := resolver.ByName(kind, name, false)
+	if err != nil {
+		log.Fatalf("❌ read taxonomy: %v", err)
+	}
+	if t == nil {
+		newTerms[kind+":"+taxonomy.Normalize(name)] = true
+	}
+}
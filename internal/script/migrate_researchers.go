@@ -0,0 +1,40 @@
+//go:build ignore
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+
+	"trl-research-backend/internal/database"
+	"trl-research-backend/internal/repository"
+)
+
+// go run internal/script/migrate_researchers.go [-dry-run]
+//
+// Rewrites every Firestore researcher document still in the frontend format (id, first_name,
+// email, ...) to the model format of models.ResearcherInfo and drops the frontend keys.
+// The server reads and finds both formats, this only saves the lookups their extra queries and
+// the list filters their check in Go. PostgreSQL and the memory store only have the model format.
+func main() {
+	dryRun := flag.Bool("dry-run", false, "only print what would change")
+	flag.Parse()
+
+	database.InitFirebase("trl-research-service-account.json")
+	defer database.CloseFirebase()
+
+	repo := repository.NewResearcherRepo(database.FirestoreClient)
+	ids, err := repo.NormalizeResearcherDocuments(*dryRun)
+	if err != nil {
+		log.Fatalf("❌ migrate researchers: %v", err)
+	}
+	for _, id := range ids {
+		fmt.Printf("🔁 %s\n", id)
+	}
+
+	fmt.Printf("✅ %d researcher documents migrated\n", len(ids))
+	if *dryRun {
+		fmt.Println("dry run, nothing written")
+	}
+}
@@ -1,3 +1,5 @@
+//go:build ignore
+
 package main
 
 import (
@@ -76,6 +78,13 @@ func main() {
 		"assessment_trl",
 		"intellectual_properties",
 		"supporters",
+		"counters", // ID sequences re-seed themselves from the data below
+		"sessions", // seeded users get new IDs, old logins are meaningless
+		"password_resets",
+		"login_throttles",
+		"login_audit",
+		"mfa",
+		"case_status_history",
 	}
 
 	for _, col := range collections {
@@ -92,11 +101,21 @@ func main() {
 	// 1️⃣ Admins
 	// =============================
 	admins := []models.AdminInfo{
-		{"AD-00001", "Dr.", "Assistant Professor", "Ann", "Smith", "Computer Science", "+66-81-234-5678", "admin1@example.com", "password123", "CS-00001", now, now},
-		{"AD-00002", "Prof.", "Professor", "John", "Doe", "Information Tech", "+66-82-234-5678", "admin2@example.com", "password123", "CS-00002", now, now},
-		{"AD-00003", "Dr.", "Lecturer", "May", "Tan", "AI Research", "+66-83-234-5678", "admin3@example.com", "password123", "CS-00003", now, now},
-		{"AD-00004", "Dr.", "Assistant Professor", "Nina", "Park", "Robotics", "+66-84-234-5678", "admin4@example.com", "password123", "CS-00004", now, now},
-		{"AD-00005", "Prof.", "Dean", "Tom", "Lee", "Innovation", "+66-85-234-5678", "admin5@example.com", "password123", "CS-00005", now, now},
+		{AdminID: "AD-00001", AdminPrefix: "Dr.", AdminAcademicPosition: "Assistant Professor", AdminFirstName: "Ann", AdminLastName: "Smith",
+			AdminDepartment: "Computer Science", AdminPhoneNumber: "+66-81-234-5678", AdminEmail: "admin1@example.com", AdminPassword: "password123", CaseID: "CS-00001",
+			CreatedAt: now, UpdatedAt: now},
+		{AdminID: "AD-00002", AdminPrefix: "Prof.", AdminAcademicPosition: "Professor", AdminFirstName: "John", AdminLastName: "Doe",
+			AdminDepartment: "Information Tech", AdminPhoneNumber: "+66-82-234-5678", AdminEmail: "admin2@example.com", AdminPassword: "password123", CaseID: "CS-00002",
+			CreatedAt: now, UpdatedAt: now},
+		{AdminID: "AD-00003", AdminPrefix: "Dr.", AdminAcademicPosition: "Lecturer", AdminFirstName: "May", AdminLastName: "Tan",
+			AdminDepartment: "AI Research", AdminPhoneNumber: "+66-83-234-5678", AdminEmail: "admin3@example.com", AdminPassword: "password123", CaseID: "CS-00003",
+			CreatedAt: now, UpdatedAt: now},
+		{AdminID: "AD-00004", AdminPrefix: "Dr.", AdminAcademicPosition: "Assistant Professor", AdminFirstName: "Nina", AdminLastName: "Park",
+			AdminDepartment: "Robotics", AdminPhoneNumber: "+66-84-234-5678", AdminEmail: "admin4@example.com", AdminPassword: "password123", CaseID: "CS-00004",
+			CreatedAt: now, UpdatedAt: now},
+		{AdminID: "AD-00005", AdminPrefix: "Prof.", AdminAcademicPosition: "Dean", AdminFirstName: "Tom", AdminLastName: "Lee",
+			AdminDepartment: "Innovation", AdminPhoneNumber: "+66-85-234-5678", AdminEmail: "admin5@example.com", AdminPassword: "password123", CaseID: "CS-00005",
+			CreatedAt: now, UpdatedAt: now},
 	}
 
 	for _, admin := range admins {
@@ -115,11 +134,21 @@ func main() {
 	// 2️⃣ Researchers
 	// =============================
 	researchers := []models.ResearcherInfo{
-		{"RS-00001", "A-00001", "Dr.", "Research Fellow", "Pair", "Brown", "Software Engineering", "+66-83-111-2222", "researcher1@example.com", "password123", now, now},
-		{"RS-00002", "A-00002", "Dr.", "Postdoc", "Kate", "Miller", "Bioinformatics", "+66-84-222-3333", "researcher2@example.com", "password123", now, now},
-		{"RS-00003", "A-00003", "Mr.", "Assistant", "Jay", "Wong", "Electronics", "+66-85-333-4444", "researcher3@example.com", "password123", now, now},
-		{"RS-00004", "A-00004", "Ms.", "Analyst", "Sue", "Kim", "Chemical", "+66-86-444-5555", "researcher4@example.com", "password123", now, now},
-		{"RS-00005", "A-00005", "Dr.", "Scientist", "Beam", "Chan", "AI Systems", "+66-87-555-6666", "researcher5@example.com", "password123", now, now},
+		{ResearcherID: "RS-00001", AdminID: "A-00001", ResearcherPrefix: "Dr.", ResearcherAcademicPosition: "Research Fellow",
+			ResearcherFirstName: "Pair", ResearcherLastName: "Brown", ResearcherDepartment: "Software Engineering",
+			ResearcherPhoneNumber: "+66-83-111-2222", ResearcherEmail: "researcher1@example.com", ResearcherPassword: "password123", CreatedAt: now, UpdatedAt: now},
+		{ResearcherID: "RS-00002", AdminID: "A-00002", ResearcherPrefix: "Dr.", ResearcherAcademicPosition: "Postdoc",
+			ResearcherFirstName: "Kate", ResearcherLastName: "Miller", ResearcherDepartment: "Bioinformatics",
+			ResearcherPhoneNumber: "+66-84-222-3333", ResearcherEmail: "researcher2@example.com", ResearcherPassword: "password123", CreatedAt: now, UpdatedAt: now},
+		{ResearcherID: "RS-00003", AdminID: "A-00003", ResearcherPrefix: "Mr.", ResearcherAcademicPosition: "Assistant",
+			ResearcherFirstName: "Jay", ResearcherLastName: "Wong", ResearcherDepartment: "Electronics",
+			ResearcherPhoneNumber: "+66-85-333-4444", ResearcherEmail: "researcher3@example.com", ResearcherPassword: "password123", CreatedAt: now, UpdatedAt: now},
+		{ResearcherID: "RS-00004", AdminID: "A-00004", ResearcherPrefix: "Ms.", ResearcherAcademicPosition: "Analyst",
+			ResearcherFirstName: "Sue", ResearcherLastName: "Kim", ResearcherDepartment: "Chemical",
+			ResearcherPhoneNumber: "+66-86-444-5555", ResearcherEmail: "researcher4@example.com", ResearcherPassword: "password123", CreatedAt: now, UpdatedAt: now},
+		{ResearcherID: "RS-00005", AdminID: "A-00005", ResearcherPrefix: "Dr.", ResearcherAcademicPosition: "Scientist",
+			ResearcherFirstName: "Beam", ResearcherLastName: "Chan", ResearcherDepartment: "AI Systems",
+			ResearcherPhoneNumber: "+66-87-555-6666", ResearcherEmail: "researcher5@example.com", ResearcherPassword: "password123", CreatedAt: now, UpdatedAt: now},
 	}
 
 	for _, r := range researchers {
@@ -201,7 +230,7 @@ func main() {
 			CoordinatorEmail: "coordinator1@university.edu",
 			TrlScore:         "5",
 			TrlSuggestion:    "Excellent progress",
-			Status:           true,
+			Status:           models.CaseApproved,
 			IsUrgent:         false,
 			UrgentReason:     "",
 			UrgentFeedback:   "",
@@ -216,7 +245,7 @@ func main() {
 			CoordinatorEmail: "coordinator2@university.edu",
 			TrlScore:         "4",
 			TrlSuggestion:    "Ready for pilot testing",
-			Status:           true,
+			Status:           models.CaseApproved,
 			IsUrgent:         false,
 			UrgentReason:     "",
 			UrgentFeedback:   "",
@@ -233,7 +262,7 @@ func main() {
 			CoordinatorEmail: "coordinator3@university.edu",
 			TrlScore:         "2",
 			TrlSuggestion:    "Need prototype validation",
-			Status:           false,
+			Status:           models.CaseUnderReview,
 			IsUrgent:         false,
 			UrgentReason:     "",
 			UrgentFeedback:   "",
@@ -248,7 +277,7 @@ func main() {
 			CoordinatorEmail: "coordinator4@university.edu",
 			TrlScore:         "3",
 			TrlSuggestion:    "Improve prototype stability",
-			Status:           false,
+			Status:           models.CaseUnderReview,
 			IsUrgent:         true,
 			UrgentReason:     "ไม่ urgent",
 			UrgentFeedback:   "",
@@ -263,7 +292,7 @@ func main() {
 			CoordinatorEmail: "coordinator5@university.edu",
 			TrlScore:         "1",
 			TrlSuggestion:    "In concept phase",
-			Status:           false,
+			Status:           models.CaseUnderReview,
 			IsUrgent:         false,
 			UrgentReason:     "",
 			UrgentFeedback:   "",
@@ -277,6 +306,8 @@ func main() {
 	for _, c := range cases {
 		c.CreatedAt = time.Now()
 		c.UpdatedAt = time.Now()
+		c.StatusChangedAt = c.UpdatedAt
+		c.StatusTimestamps = map[string]time.Time{string(c.Status): c.UpdatedAt}
 		docRef := client.Collection("cases").Doc(c.CaseID)
 		_, err := docRef.Set(ctx, c)
 		if err != nil {
@@ -290,11 +321,16 @@ func main() {
 	// 5️⃣ Appointments (case1 has 2)
 	// =============================
 	appointments := []models.Appointment{
-		{"AP-00001", "CS-00001", now.AddDate(0, 0, 7), "attended", "Conference Room A", "Discuss progress", "Kickoff meeting", now, now},
-		{"AP-00002", "CS-00001", now.AddDate(0, 0, 14), "absent", "Conference Room A", "Follow-up", "Researcher sick", now, now},
-		{"AP-00003", "CS-00002", now.AddDate(0, 0, 10), "pending", "Conference Room B", "Prototype review", "Awaiting confirmation", now, now},
-		{"AP-00004", "CS-00003", now.AddDate(0, 0, 12), "attended", "Meeting Room 2", "Test field setup", "Completed", now, now},
-		{"AP-00005", "CS-00004", now.AddDate(0, 0, 20), "pending", "Zoom", "Online sync", "Progress update", now, now},
+		{AppointmentID: "AP-00001", CaseID: "CS-00001", Date: now.AddDate(0, 0, 7), Status: "attended",
+			Location: "Conference Room A", Note: "Discuss progress", Summary: "Kickoff meeting", CreatedAt: now, UpdatedAt: now},
+		{AppointmentID: "AP-00002", CaseID: "CS-00001", Date: now.AddDate(0, 0, 14), Status: "absent",
+			Location: "Conference Room A", Note: "Follow-up", Summary: "Researcher sick", CreatedAt: now, UpdatedAt: now},
+		{AppointmentID: "AP-00003", CaseID: "CS-00002", Date: now.AddDate(0, 0, 10), Status: "pending",
+			Location: "Conference Room B", Note: "Prototype review", Summary: "Awaiting confirmation", CreatedAt: now, UpdatedAt: now},
+		{AppointmentID: "AP-00004", CaseID: "CS-00003", Date: now.AddDate(0, 0, 12), Status: "attended",
+			Location: "Meeting Room 2", Note: "Test field setup", Summary: "Completed", CreatedAt: now, UpdatedAt: now},
+		{AppointmentID: "AP-00005", CaseID: "CS-00004", Date: now.AddDate(0, 0, 20), Status: "pending",
+			Location: "Zoom", Note: "Online sync", Summary: "Progress update", CreatedAt: now, UpdatedAt: now},
 	}
 	for _, a := range appointments {
 		docRef := client.Collection("appointments").Doc(a.AppointmentID)
@@ -1,7 +1,11 @@
+//go:build ignore
+
 package main
 
 import (
 	"fmt"
+	"time"
+
 	"trl-research-backend/internal/utils"
 )
 
@@ -9,16 +13,16 @@ func main() {
 	fmt.Println("🔑 Testing KeyProvider...")
 
 	// Test key provider initialization
-	kp, err := utils.NewEnvKeyProvider()
+	kp, err := utils.SharedKeyProvider()
 	if err != nil {
 		fmt.Printf("❌ KeyProvider initialization failed: %v\n", err)
 		return
 	}
 
-	fmt.Println("✅ KeyProvider initialized successfully!")
+	fmt.Printf("✅ KeyProvider initialized successfully! (active kid=%s, verifying %v)\n", kp.ActiveKID(), kp.VerificationKIDs())
 
 	// Test private key retrieval
-	priv, err := kp.GetPrivateKey("v1")
+	priv, err := kp.GetPrivateKey(kp.ActiveKID())
 	if err != nil {
 		fmt.Printf("❌ Private key retrieval failed: %v\n", err)
 		return
@@ -26,7 +30,7 @@ func main() {
 	fmt.Printf("✅ Private key retrieved successfully (bits=%d)\n", priv.N.BitLen())
 
 	// Test public key retrieval
-	pub, err := kp.GetPublicKey("v1")
+	pub, err := kp.GetPublicKey(kp.ActiveKID())
 	if err != nil {
 		fmt.Printf("❌ Public key retrieval failed: %v\n", err)
 		return
@@ -40,10 +44,11 @@ func main() {
 		"admin",
 		"",
 		"",
+		"test-session-id",
 		"test-issuer",
 		"test-audience",
-		"v1",
-		24,
+		kp.ActiveKID(),
+		24*time.Hour,
 		*kp,
 	)
 	if err != nil {
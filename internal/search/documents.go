@@ -0,0 +1,69 @@
+package search
+
+import (
+	"slices"
+	"strings"
+
+	"trl-research-backend/internal/models"
+)
+
+// document types, also the values of ?type= on GET /trl/search
+const (
+	TypeCase       = "case"
+	TypeResearcher = "researcher"
+	TypeIP         = "ip"
+)
+
+var Types = []string{TypeCase, TypeResearcher, TypeIP}
+
+// weights - the searchable fields of all types; a match scores by the weight of its field
+var weights = map[string]float64{
+	"case_title":        3,
+	"case_keywords":     2,
+	"case_description":  1,
+	"researcher_name":   3,
+	"ip_request_number": 3,
+}
+
+// CaseDocument - title, keywords (comma-joined, the commas split them like any punctuation)
+// and description of a case
+func CaseDocument(cs models.CaseInfo) Document {
+	return Document{
+		Type:   TypeCase,
+		ID:     cs.CaseID,
+		CaseID: cs.CaseID,
+		Title:  cs.CaseTitle,
+		Fields: []Field{
+			{Name: "case_title", Text: cs.CaseTitle},
+			{Name: "case_keywords", Text: cs.CaseKeywords},
+			{Name: "case_description", Text: cs.CaseDescription},
+		},
+	}
+}
+
+// ResearcherDocument - the name of a researcher
+func ResearcherDocument(r models.ResearcherInfo) Document {
+	name := strings.TrimSpace(r.ResearcherFirstName + " " + r.ResearcherLastName)
+	return Document{
+		Type:   TypeResearcher,
+		ID:     r.ResearcherID,
+		Title:  strings.TrimSpace(r.ResearcherPrefix + " " + name),
+		Fields: []Field{{Name: "researcher_name", Text: name}},
+	}
+}
+
+// IPDocument - the request number of an intellectual property
+func IPDocument(ip models.IntellectualProperty) Document {
+	return Document{
+		Type:   TypeIP,
+		ID:     ip.ID,
+		CaseID: ip.CaseID,
+		Title:  ip.IPRequestNumber,
+		Fields: []Field{{Name: "ip_request_number", Text: ip.IPRequestNumber}},
+	}
+}
+
+// IsType - t is one of Types
+func IsType(t string) bool {
+	return slices.Contains(Types, t)
+}
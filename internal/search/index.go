@@ -0,0 +1,289 @@
+// Package search is the full-text index behind GET /trl/search, a Bleve index on disk. The
+// handlers that write cases, researchers and IPs index what they write, and a periodic sync
+// from the database (handlers.SearchIndex.Sync) picks up the writes of other server instances.
+//
+// Thai is written without spaces between words, so a run of Thai letters is split into words
+// with a dictionary (thai.go). Other scripts are split into lowercase words. A query word also
+// matches the words it starts ("sens" finds "sensor").
+package search
+
+import (
+	"errors"
+	"log"
+	"math"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/analysis/analyzer/custom"
+	"github.com/blevesearch/bleve/v2/analysis/analyzer/keyword"
+	"github.com/blevesearch/bleve/v2/analysis/token/lowercase"
+	"github.com/blevesearch/bleve/v2/mapping"
+	"github.com/blevesearch/bleve/v2/search/highlight/highlighter/html"
+	"github.com/blevesearch/bleve/v2/search/query"
+)
+
+// indexVersion changes with the mapping or the analysis; an index built with another version,
+// or another dictionary, is built again
+const indexVersion = "2"
+
+const (
+	analyzerName = "th"
+	minPrefix    = 2 // a query word shorter than this only matches itself, not the words it starts
+)
+
+var (
+	versionKey = []byte("version")
+	syncedKey  = []byte("synced")
+)
+
+// Field - a searchable text of a document; the weight of its name in weights scales the score
+// of its matches
+type Field struct {
+	Name string
+	Text string
+}
+
+// Document - one indexed record
+type Document struct {
+	Type   string // TypeCase, TypeResearcher or TypeIP
+	ID     string
+	CaseID string // the case the record belongs to, "" for researchers
+	Title  string
+	Fields []Field
+}
+
+// Hit - a document matching a query. Highlights holds, per matching field, an HTML-escaped
+// excerpt with the matches in <mark></mark>.
+type Hit struct {
+	Type       string            `json:"type"`
+	ID         string            `json:"id"`
+	CaseID     string            `json:"case_id,omitempty"`
+	Title      string            `json:"title"`
+	Score      float64           `json:"score"`
+	Highlights map[string]string `json:"highlights"`
+}
+
+// Index - the Bleve index; safe for concurrent use
+type Index struct {
+	bleve bleve.Index
+}
+
+// Open opens the index at path, or creates it; "" keeps it in memory. dictionary is a file of
+// Thai words on top of the embedded ones ("" for none). An index that can't be opened or was
+// built with another version or dictionary is deleted and created empty: Synced is then zero
+// and the next sync fills it.
+func Open(path, dictionary string) (*Index, error) {
+	dict, err := loadDictionary(dictionary)
+	if err != nil {
+		return nil, err
+	}
+	version := indexVersion + "/" + dict.hash
+
+	im, err := newMapping(dictionary)
+	if err != nil {
+		return nil, err
+	}
+	if path == "" {
+		idx, err := bleve.NewMemOnly(im)
+		if err != nil {
+			return nil, err
+		}
+		return &Index{bleve: idx}, nil
+	}
+
+	idx, err := bleve.Open(path)
+	switch {
+	case errors.Is(err, bleve.ErrorIndexPathDoesNotExist):
+	case err != nil:
+		log.Printf("❌ [search] %s: %v, building the index again", path, err)
+	default:
+		stored, err := idx.GetInternal(versionKey)
+		if err == nil && string(stored) == version {
+			return &Index{bleve: idx}, nil
+		}
+		log.Printf("⚠️ [search] %s was built with another version or dictionary, building the index again", path)
+		idx.Close()
+	}
+
+	if err := os.RemoveAll(path); err != nil {
+		return nil, err
+	}
+	idx, err = bleve.New(path, im)
+	if err != nil {
+		return nil, err
+	}
+	if err := idx.SetInternal(versionKey, []byte(version)); err != nil {
+		idx.Close()
+		return nil, err
+	}
+	return &Index{bleve: idx}, nil
+}
+
+// newMapping - the text fields analyzed with the Thai dictionary tokenizer, the rest stored as is
+func newMapping(dictionary string) (*mapping.IndexMappingImpl, error) {
+	im := bleve.NewIndexMapping()
+	if err := im.AddCustomTokenizer(thaiTokenizerType, map[string]interface{}{
+		"type":       thaiTokenizerType,
+		"dictionary": dictionary,
+	}); err != nil {
+		return nil, err
+	}
+	if err := im.AddCustomAnalyzer(analyzerName, map[string]interface{}{
+		"type":          custom.Name,
+		"tokenizer":     thaiTokenizerType,
+		"token_filters": []string{lowercase.Name},
+	}); err != nil {
+		return nil, err
+	}
+	im.DefaultAnalyzer = analyzerName
+
+	stored := bleve.NewTextFieldMapping()
+	stored.Index = false
+	stored.IncludeInAll = false
+	typ := bleve.NewTextFieldMapping()
+	typ.Analyzer = keyword.Name
+	typ.IncludeInAll = false
+	text := bleve.NewTextFieldMapping()
+	text.Analyzer = analyzerName
+	text.IncludeInAll = false
+	text.IncludeTermVectors = true // where the matches are, for the highlights
+
+	doc := bleve.NewDocumentStaticMapping()
+	doc.AddFieldMappingsAt("type", typ)
+	for _, name := range []string{"id", "case_id", "title"} {
+		doc.AddFieldMappingsAt(name, stored)
+	}
+	for name := range weights {
+		doc.AddFieldMappingsAt(name, text)
+	}
+	im.DefaultMapping = doc
+	return im, nil
+}
+
+func (i *Index) Close() error { return i.bleve.Close() }
+
+func docKey(typ, id string) string { return typ + "/" + id }
+
+// Put adds a document or replaces the one with the same type and ID
+func (i *Index) Put(d Document) error {
+	fields := map[string]interface{}{"type": d.Type, "id": d.ID, "case_id": d.CaseID, "title": d.Title}
+	for _, f := range d.Fields {
+		fields[f.Name] = f.Text
+	}
+	return i.bleve.Index(docKey(d.Type, d.ID), fields)
+}
+
+// Remove drops a document; unknown documents are ignored
+func (i *Index) Remove(typ, id string) error {
+	return i.bleve.Delete(docKey(typ, id))
+}
+
+// Len - number of indexed documents
+func (i *Index) Len() (int, error) {
+	n, err := i.bleve.DocCount()
+	return int(n), err
+}
+
+// Synced - when the index was last brought up to date with the database, zero for a new index
+func (i *Index) Synced() (time.Time, error) {
+	var t time.Time
+	b, err := i.bleve.GetInternal(syncedKey)
+	if err != nil || len(b) == 0 {
+		return t, err
+	}
+	err = t.UnmarshalText(b)
+	return t, err
+}
+
+func (i *Index) SetSynced(t time.Time) error {
+	b, err := t.MarshalText()
+	if err != nil {
+		return err
+	}
+	return i.bleve.SetInternal(syncedKey, b)
+}
+
+// Search returns the documents matching every word of text, best first, and how many matched
+// in total. types limits the document types (all when empty); limit 0 returns every hit.
+func (i *Index) Search(text string, types []string, limit int) ([]Hit, int, error) {
+	words := i.bleve.Mapping().AnalyzerNamed(analyzerName).Analyze([]byte(text))
+	if len(words) == 0 {
+		return []Hit{}, 0, nil
+	}
+
+	// every word must match in one of the fields, by itself or as the start of a longer word
+	all := bleve.NewConjunctionQuery()
+	for _, w := range words {
+		word := string(w.Term)
+		anyField := bleve.NewDisjunctionQuery()
+		for field, weight := range weights {
+			term := bleve.NewTermQuery(word)
+			term.SetField(field)
+			term.SetBoost(weight)
+			anyField.AddQuery(term)
+			if utf8.RuneCountInString(word) >= minPrefix {
+				prefix := bleve.NewPrefixQuery(word)
+				prefix.SetField(field)
+				prefix.SetBoost(weight / 2)
+				anyField.AddQuery(prefix)
+			}
+		}
+		all.AddQuery(anyField)
+	}
+	if len(types) > 0 {
+		anyType := bleve.NewDisjunctionQuery()
+		for _, typ := range types {
+			q := bleve.NewTermQuery(typ)
+			q.SetField("type")
+			anyType.AddQuery(q)
+		}
+		all.AddQuery(anyType)
+	}
+
+	if limit == 0 {
+		n, err := i.bleve.DocCount()
+		if err != nil {
+			return nil, 0, err
+		}
+		limit = int(n)
+	}
+	req := bleve.NewSearchRequestOptions(query.Query(all), limit, 0, false)
+	req.Fields = []string{"type", "id", "case_id", "title"}
+	req.SortBy([]string{"-_score", "_id"})
+	req.Highlight = bleve.NewHighlightWithStyle(html.Name)
+	for field := range weights {
+		req.Highlight.AddField(field)
+	}
+	res, err := i.bleve.Search(req)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	hits := make([]Hit, 0, len(res.Hits))
+	for _, h := range res.Hits {
+		hit := Hit{
+			Type:       stringField(h.Fields, "type"),
+			ID:         stringField(h.Fields, "id"),
+			CaseID:     stringField(h.Fields, "case_id"),
+			Title:      stringField(h.Fields, "title"),
+			Score:      math.Round(h.Score*1000) / 1000,
+			Highlights: map[string]string{},
+		}
+		// the best excerpt of the fields that matched; Bleve also has the others, unmarked
+		for field, fragments := range h.Fragments {
+			if len(fragments) > 0 && strings.Contains(fragments[0], "<mark>") {
+				hit.Highlights[field] = fragments[0]
+			}
+		}
+		hits = append(hits, hit)
+	}
+	return hits, int(res.Total), nil
+}
+
+func stringField(fields map[string]interface{}, name string) string {
+	s, _ := fields[name].(string)
+	return s
+}
@@ -0,0 +1,189 @@
+package search
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"trl-research-backend/internal/models"
+)
+
+func testIndex(t *testing.T) *Index {
+	t.Helper()
+	idx, err := Open("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { idx.Close() })
+	for _, d := range []Document{
+		CaseDocument(models.CaseInfo{CaseID: "CS-00001", CaseTitle: "เครื่องวัดความชื้นในดิน", CaseKeywords: "เกษตร, IoT", CaseDescription: "Soil moisture sensor <b>v2</b>"}),
+		CaseDocument(models.CaseInfo{CaseID: "CS-00002", CaseTitle: "ระบบตรวจวินิจฉัยโรคด้วยปัญญาประดิษฐ์", CaseKeywords: "AI, การแพทย์"}),
+		CaseDocument(models.CaseInfo{CaseID: "CS-00003", CaseTitle: "Sensing glove", CaseDescription: "เซนเซอร์วัดแรงบีบของมือ"}),
+		ResearcherDocument(models.ResearcherInfo{ResearcherID: "RS-00001", ResearcherPrefix: "ดร.", ResearcherFirstName: "สมชาย", ResearcherLastName: "Sensen"}),
+		IPDocument(models.IntellectualProperty{ID: "IP-00001", CaseID: "CS-00001", IPRequestNumber: "2301001234"}),
+	} {
+		if err := idx.Put(d); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return idx
+}
+
+func hitIDs(hits []Hit) string {
+	var ids []string
+	for _, h := range hits {
+		ids = append(ids, h.ID)
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestSearch(t *testing.T) {
+	idx := testIndex(t)
+	tests := []struct {
+		query string
+		types []string
+		want  string
+	}{
+		{"ความชื้น", nil, "CS-00001"},
+		{"ชื้น", nil, "CS-00001"}, // a word inside a Thai run
+		{"ดิน เกษตร", nil, "CS-00001"},
+		{"ปัญญาประดิษฐ์", nil, "CS-00002"},
+		{"ดิน ปัญญา", nil, ""}, // every word has to match
+		{"iot", nil, "CS-00001"},
+		{"2301001234", nil, "IP-00001"},
+		// a word also matches the words it starts, in Thai too; the title weighs the most
+		{"sens", nil, "CS-00003,RS-00001,CS-00001"},
+		{"sens", []string{TypeCase}, "CS-00003,CS-00001"},
+		{"เซน", nil, "CS-00003"},
+		{"วินิจ", nil, "CS-00002"},
+		{"s", nil, ""}, // too short to match as a prefix
+		{"  ,. ", nil, ""},
+	}
+	for _, tt := range tests {
+		hits, total, err := idx.Search(tt.query, tt.types, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hitIDs(hits); got != tt.want || total != len(hits) {
+			t.Errorf("%q %v: got %q (total %d), want %q", tt.query, tt.types, got, total, tt.want)
+		}
+	}
+
+	hits, total, _ := idx.Search("sens", nil, 1)
+	if len(hits) != 1 || total != 3 {
+		t.Errorf("limit 1: got %d hits of %d, want 1 of 3", len(hits), total)
+	}
+	if err := idx.Remove(TypeCase, "CS-00003"); err != nil {
+		t.Fatal(err)
+	}
+	if hits, _, _ := idx.Search("sens", []string{TypeCase}, 10); hitIDs(hits) != "CS-00001" {
+		t.Errorf("after remove: got %q", hitIDs(hits))
+	}
+}
+
+func TestSearchHighlights(t *testing.T) {
+	idx := testIndex(t)
+	long := strings.Repeat("ข้อมูลทั่วไป ", 40) + "ระบบเซนเซอร์" + strings.Repeat(" ข้อมูลทั่วไป", 40)
+	if err := idx.Put(CaseDocument(models.CaseInfo{CaseID: "CS-00004", CaseTitle: "Long", CaseDescription: long})); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		query, id, field, want string
+	}{
+		{"ชื้น", "CS-00001", "case_title", "เครื่องวัดความ<mark>ชื้น</mark>ในดิน"},
+		{"ความชื้น ดิน", "CS-00001", "case_title", "เครื่องวัด<mark>ความ</mark><mark>ชื้น</mark>ใน<mark>ดิน</mark>"},
+		// the text around a match is HTML-escaped
+		{"sensor", "CS-00001", "case_description", "Soil moisture <mark>sensor</mark> &lt;b&gt;v2&lt;/b&gt;"},
+		// a prefix marks the whole word it matched
+		{"sens", "RS-00001", "researcher_name", "สมชาย <mark>Sensen</mark>"},
+		{"เซนเซ", "CS-00003", "case_description", "<mark>เซนเซอร์</mark>วัดแรงบีบของมือ"},
+	}
+	for _, tt := range tests {
+		hits, _, err := idx.Search(tt.query, nil, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got *Hit
+		for k := range hits {
+			if hits[k].ID == tt.id {
+				got = &hits[k]
+			}
+		}
+		if got == nil {
+			t.Errorf("%q: no hit %s in %q", tt.query, tt.id, hitIDs(hits))
+			continue
+		}
+		if got.Highlights[tt.field] != tt.want || len(got.Highlights) != 1 {
+			t.Errorf("%q: highlights %q, want %s %q only", tt.query, got.Highlights, tt.field, tt.want)
+		}
+	}
+
+	// a long field is cut to an excerpt around the match
+	hits, _, _ := idx.Search("เซนเซอร์", []string{TypeCase}, 10)
+	excerpt := ""
+	for _, h := range hits {
+		if h.ID == "CS-00004" {
+			excerpt = h.Highlights["case_description"]
+		}
+	}
+	if !strings.Contains(excerpt, "ระบบ<mark>เซนเซอร์</mark>") || !strings.HasPrefix(excerpt, "…") || len(excerpt) >= len(long) {
+		t.Errorf("excerpt %q", excerpt)
+	}
+}
+
+func TestOpenKeepsOrRebuilds(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "search.bleve")
+	synced := time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)
+
+	idx, err := Open(path, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s, err := idx.Synced(); err != nil || !s.IsZero() {
+		t.Fatalf("new index synced %v, %v", s, err)
+	}
+	if err := idx.Put(CaseDocument(models.CaseInfo{CaseID: "CS-00001", CaseTitle: "ระบบฆฌฎ"})); err != nil {
+		t.Fatal(err)
+	}
+	if err := idx.SetSynced(synced); err != nil {
+		t.Fatal(err)
+	}
+	idx.Close()
+
+	// opened again, the documents and the sync time are still there
+	idx, err = Open(path, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, _ := idx.Len()
+	s, _ := idx.Synced()
+	if n != 1 || !s.Equal(synced) {
+		t.Errorf("reopened: %d documents, synced %v", n, s)
+	}
+	idx.Close()
+
+	// another dictionary splits text differently, the index starts over
+	dict := filepath.Join(t.TempDir(), "words.txt")
+	if err := os.WriteFile(dict, []byte("# more words\nฆฌ\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	idx, err = Open(path, dict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+	n, _ = idx.Len()
+	s, _ = idx.Synced()
+	if n != 0 || !s.IsZero() {
+		t.Errorf("other dictionary: %d documents, synced %v", n, s)
+	}
+	if err := idx.Put(CaseDocument(models.CaseInfo{CaseID: "CS-00001", CaseTitle: "ระบบฆฌฎ"})); err != nil {
+		t.Fatal(err)
+	}
+	if hits, _, _ := idx.Search("ฆฌ", nil, 10); hitIDs(hits) != "CS-00001" {
+		t.Errorf("word of the added dictionary: got %q", hitIDs(hits))
+	}
+}
@@ -0,0 +1,235 @@
+package search
+
+import (
+	"crypto/sha256"
+	_ "embed"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/blevesearch/bleve/v2/analysis"
+	"github.com/blevesearch/bleve/v2/registry"
+)
+
+// thaiTokenizerType - the Bleve tokenizer that splits Thai text into dictionary words; a
+// mapping configures it with "dictionary", a file of more words ("" for the embedded ones)
+const thaiTokenizerType = "thai_dictionary"
+
+//go:embed words_th.txt
+var embeddedWords string
+
+func init() {
+	err := registry.RegisterTokenizer(thaiTokenizerType, func(config map[string]interface{}, _ *registry.Cache) (analysis.Tokenizer, error) {
+		path, _ := config["dictionary"].(string)
+		d, err := loadDictionary(path)
+		if err != nil {
+			return nil, err
+		}
+		return &thaiTokenizer{dict: d}, nil
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+// dictionary - a trie of the known Thai words; hash identifies the word lists it was built from
+type dictionary struct {
+	root *trieNode
+	hash string
+}
+
+type trieNode struct {
+	next map[rune]*trieNode
+	word bool // a word ends here
+}
+
+var dictionaries sync.Map // path → *dictionary
+
+// loadDictionary - the embedded words and, when path isn't "", the words of that file: one per
+// line, # starts a comment line
+func loadDictionary(path string) (*dictionary, error) {
+	if d, ok := dictionaries.Load(path); ok {
+		return d.(*dictionary), nil
+	}
+	text := embeddedWords
+	if path != "" {
+		extra, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("thai dictionary: %w", err)
+		}
+		text += "\n" + string(extra)
+	}
+
+	sum := sha256.Sum256([]byte(text))
+	d := &dictionary{root: &trieNode{}, hash: hex.EncodeToString(sum[:8])}
+	for _, line := range strings.Split(text, "\n") {
+		word := strings.TrimSpace(line)
+		if word == "" || strings.HasPrefix(word, "#") {
+			continue
+		}
+		node := d.root
+		for _, r := range word {
+			if node.next == nil {
+				node.next = map[rune]*trieNode{}
+			}
+			if node.next[r] == nil {
+				node.next[r] = &trieNode{}
+			}
+			node = node.next[r]
+		}
+		node.word = true
+	}
+	actual, _ := dictionaries.LoadOrStore(path, d)
+	return actual.(*dictionary), nil
+}
+
+// thaiTokenizer splits text into words: runs of Thai letters by the dictionary, other letters
+// and digits at everything else (spaces, punctuation, a change of script)
+type thaiTokenizer struct {
+	dict *dictionary
+}
+
+func (t *thaiTokenizer) Tokenize(input []byte) analysis.TokenStream {
+	var stream analysis.TokenStream
+	emit := func(start, end int) {
+		stream = append(stream, &analysis.Token{
+			Term:     input[start:end],
+			Start:    start,
+			End:      end,
+			Position: len(stream) + 1,
+			Type:     analysis.AlphaNumeric,
+		})
+	}
+
+	runStart, runThai := -1, false
+	flush := func(end int) {
+		switch {
+		case runStart < 0:
+		case runThai:
+			for _, w := range t.dict.segment(string(input[runStart:end])) {
+				emit(runStart+w.start, runStart+w.end)
+			}
+		default:
+			emit(runStart, end)
+		}
+		runStart = -1
+	}
+	for pos, r := range string(input) {
+		thai := isThaiLetter(r)
+		word := thai || unicode.IsDigit(r) || (!unicode.Is(unicode.Thai, r) && (unicode.IsLetter(r) || unicode.IsMark(r)))
+		if !word || (runStart >= 0 && thai != runThai) {
+			flush(pos)
+		}
+		if word && runStart < 0 {
+			runStart, runThai = pos, thai
+		}
+	}
+	flush(len(input))
+	return stream
+}
+
+// span - a word of a text, as byte offsets
+type span struct{ start, end int }
+
+// segment splits a run of Thai letters into words. Of the ways to split it into dictionary words
+// and letters the dictionary doesn't know, it takes the one with the fewest unknown letters, then
+// the fewest words (maximal matching); unknown letters next to each other stay one word.
+func (d *dictionary) segment(run string) []span {
+	type char struct {
+		r   rune
+		pos int
+	}
+	var chars []char
+	for pos, r := range run {
+		chars = append(chars, char{r, pos})
+	}
+	n := len(chars)
+	offset := func(i int) int {
+		if i == n {
+			return len(run)
+		}
+		return chars[i].pos
+	}
+	// a word can't end before a vowel or tone mark that belongs to its last letter, nor after
+	// a vowel that is written before the letter it follows
+	boundary := func(i int) bool {
+		return i == n || (!isFollowing(chars[i].r) && !isLeading(chars[i-1].r))
+	}
+	// cluster - where the letter at i ends with its vowels and tone marks, the unit of unknown text
+	cluster := func(i int) int {
+		if isLeading(chars[i].r) && i+1 < n {
+			i++
+		}
+		for i++; i < n && isFollowing(chars[i].r); i++ {
+		}
+		return i
+	}
+
+	// best[i] - the best split of the first i letters, and where its last word starts
+	type split struct {
+		unknown, words, from int
+		known, reached       bool
+	}
+	best := make([]split, n+1)
+	best[0].reached = true
+	try := func(i int, s split) {
+		b := best[i]
+		if !b.reached || s.unknown < b.unknown || (s.unknown == b.unknown && s.words < b.words) {
+			best[i] = s
+		}
+	}
+	for i := 0; i < n; i++ {
+		at := best[i]
+		if !at.reached {
+			continue
+		}
+		node := d.root
+		for j := i; j < n && node != nil; j++ {
+			node = node.next[chars[j].r]
+			if node != nil && node.word && boundary(j+1) {
+				try(j+1, split{unknown: at.unknown, words: at.words + 1, from: i, known: true, reached: true})
+			}
+		}
+		end := cluster(i)
+		try(end, split{unknown: at.unknown + end - i, words: at.words + 1, from: i, reached: true})
+	}
+
+	var words []span
+	for i := n; i > 0; {
+		s := best[i]
+		from := s.from
+		for !s.known && from > 0 && !best[from].known {
+			from = best[from].from
+		}
+		words = append(words, span{offset(from), offset(i)})
+		i = from
+	}
+	for a, b := 0, len(words)-1; a < b; a, b = a+1, b-1 {
+		words[a], words[b] = words[b], words[a]
+	}
+	return words
+}
+
+// isThaiLetter - a Thai consonant, vowel or tone mark; not the Thai digits, ๆ, ฯ or ฿
+func isThaiLetter(r rune) bool {
+	return r >= 0x0E01 && r <= 0x0E4E && r != 'ฯ' && r != '฿' && r != 'ๆ'
+}
+
+// isLeading - เ แ โ ใ ไ, written before the consonant they follow
+func isLeading(r rune) bool {
+	return r >= 0x0E40 && r <= 0x0E44
+}
+
+// isFollowing - a vowel or tone mark that belongs to the letter before it
+func isFollowing(r rune) bool {
+	switch {
+	case r == 0x0E30, r == 0x0E31, r == 0x0E32, r == 0x0E33, r == 0x0E45:
+		return true
+	case r >= 0x0E34 && r <= 0x0E3A, r >= 0x0E47 && r <= 0x0E4E:
+		return true
+	}
+	return false
+}
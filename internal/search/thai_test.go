@@ -0,0 +1,45 @@
+package search
+
+import (
+	"strings"
+	"testing"
+)
+
+func words(t *testing.T, text string) string {
+	t.Helper()
+	d, err := loadDictionary("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out []string
+	for _, tok := range (&thaiTokenizer{dict: d}).Tokenize([]byte(text)) {
+		if string(tok.Term) != text[tok.Start:tok.End] {
+			t.Errorf("%q: term %q at %d-%d", text, tok.Term, tok.Start, tok.End)
+		}
+		out = append(out, string(tok.Term))
+	}
+	return strings.Join(out, "|")
+}
+
+func TestThaiTokenizer(t *testing.T) {
+	tests := []struct{ text, want string }{
+		{"เครื่องวัดความชื้นในดิน", "เครื่อง|วัด|ความ|ชื้น|ใน|ดิน"},
+		{"ระบบตรวจวินิจฉัยโรคมะเร็ง", "ระบบ|ตรวจ|วินิจฉัย|โรค|มะเร็ง"},
+		{"ปัญญาประดิษฐ์สำหรับการแพทย์", "ปัญญา|ประดิษฐ์|สำหรับ|การ|แพทย์"},
+		// the longest word, not เกษตร + letters it doesn't know
+		{"เกษตรกรปลูกข้าวโพด", "เกษตรกร|ปลูก|ข้าวโพด"},
+		// unknown letters stay together, with their vowels and tone marks
+		{"ระบบฆฌฎเซนเซอร์", "ระบบ|ฆฌฎ|เซนเซอร์"},
+		{"โครงการสมศักดิ์", "โครงการ|สมศักดิ์"},
+		// other scripts, digits and punctuation
+		{"Smart Farm IoT, 2024", "Smart|Farm|IoT|2024"},
+		{"AIสำหรับโรงงาน", "AI|สำหรับ|โรงงาน"},
+		{"ต่างๆ (ฉบับที่ ๒)", "ต่าง|ฉบับ|ที่|๒"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := words(t, tt.text); got != tt.want {
+			t.Errorf("%q: got %s, want %s", tt.text, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package taxonomy
+
+import (
+	"fmt"
+
+	"trl-research-backend/internal/models"
+)
+
+// ResolveCase fills the references and label texts of a case about to be created. IDs win over
+// texts; a case type or keyword text that matches no term creates one.
+func (r *Resolver) ResolveCase(cs *models.CaseInfo) error {
+	domains, err := r.ByIDs("domain_ids", models.TermDomain, cs.DomainIDs)
+	if err != nil {
+		return err
+	}
+	cs.DomainIDs = IDs(domains)
+
+	switch {
+	case cs.CaseTypeID != "":
+		t, err := r.ByID("case_type_id", models.TermCaseType, cs.CaseTypeID)
+		if err != nil {
+			return err
+		}
+		cs.CaseTypeID, cs.CaseType = t.ID, t.DisplayLabel()
+	case cs.CaseType != "":
+		t, err := r.ByName(models.TermCaseType, cs.CaseType, true)
+		if err != nil {
+			return err
+		}
+		cs.CaseTypeID, cs.CaseType = t.ID, t.DisplayLabel()
+	}
+
+	var keywords []models.TaxonomyTerm
+	if len(cs.KeywordIDs) > 0 {
+		keywords, err = r.ByIDs("keyword_ids", models.TermKeyword, cs.KeywordIDs)
+	} else {
+		keywords, err = r.ByNames(models.TermKeyword, SplitKeywords(cs.CaseKeywords))
+	}
+	if err != nil {
+		return err
+	}
+	cs.KeywordIDs, cs.CaseKeywords = IDs(keywords), JoinLabels(keywords)
+	return nil
+}
+
+// ResolvePatch does the same for the taxonomy fields present in a PATCH body and adds the
+// fields that follow from them, e.g. case_keywords for keyword_ids
+func (r *Resolver) ResolvePatch(data map[string]interface{}) error {
+	if v, ok := data["domain_ids"]; ok {
+		ids, err := stringList("domain_ids", v)
+		if err != nil {
+			return err
+		}
+		domains, err := r.ByIDs("domain_ids", models.TermDomain, ids)
+		if err != nil {
+			return err
+		}
+		data["domain_ids"] = IDs(domains)
+	}
+
+	if v, ok := data["case_type_id"]; ok {
+		id, err := str("case_type_id", v)
+		if err != nil {
+			return err
+		}
+		data["case_type_id"], data["case_type"] = "", ""
+		if id != "" {
+			t, err := r.ByID("case_type_id", models.TermCaseType, id)
+			if err != nil {
+				return err
+			}
+			data["case_type_id"], data["case_type"] = t.ID, t.DisplayLabel()
+		}
+	} else if v, ok := data["case_type"]; ok {
+		name, err := str("case_type", v)
+		if err != nil {
+			return err
+		}
+		data["case_type_id"], data["case_type"] = "", ""
+		if name != "" {
+			t, err := r.ByName(models.TermCaseType, name, true)
+			if err != nil {
+				return err
+			}
+			data["case_type_id"], data["case_type"] = t.ID, t.DisplayLabel()
+		}
+	}
+
+	var keywords []models.TaxonomyTerm
+	if v, ok := data["keyword_ids"]; ok {
+		ids, err := stringList("keyword_ids", v)
+		if err != nil {
+			return err
+		}
+		if keywords, err = r.ByIDs("keyword_ids", models.TermKeyword, ids); err != nil {
+			return err
+		}
+	} else if v, ok := data["case_keywords"]; ok {
+		text, err := str("case_keywords", v)
+		if err != nil {
+			return err
+		}
+		if keywords, err = r.ByNames(models.TermKeyword, SplitKeywords(text)); err != nil {
+			return err
+		}
+	} else {
+		return nil
+	}
+	data["keyword_ids"], data["case_keywords"] = IDs(keywords), JoinLabels(keywords)
+	return nil
+}
+
+func str(field string, v interface{}) (string, error) {
+	if v == nil {
+		return "", nil
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", &Error{field, "expected a string"}
+	}
+	return s, nil
+}
+
+func stringList(field string, v interface{}) ([]string, error) {
+	if ids, ok := v.([]string); ok {
+		return ids, nil
+	}
+	list, ok := v.([]interface{})
+	if v != nil && !ok {
+		return nil, &Error{field, "expected an array of IDs"}
+	}
+	ids := make([]string, 0, len(list))
+	for _, item := range list {
+		id, ok := item.(string)
+		if !ok {
+			return nil, &Error{field, fmt.Sprintf("expected an ID, got %v", item)}
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
@@ -0,0 +1,85 @@
+package taxonomy
+
+import (
+	"fmt"
+
+	"trl-research-backend/internal/models"
+)
+
+// Merge folds from into into: into takes the labels and synonyms of from as synonyms and from
+// points at into from now on. Merging again into the same term is allowed, it only moves cases
+// saved with from since.
+func Merge(from, into *models.TaxonomyTerm) error {
+	switch {
+	case from.ID == into.ID:
+		return &Error{"into", "a term can't be merged into itself"}
+	case from.Kind != into.Kind:
+		return &Error{"into", fmt.Sprintf("%s is a %s, %s a %s", from.ID, from.Kind, into.ID, into.Kind)}
+	case into.MergedInto != "":
+		return &Error{"into", fmt.Sprintf("%s was merged into %s, merge into that one", into.ID, into.MergedInto)}
+	case from.MergedInto != "" && from.MergedInto != into.ID:
+		return &Error{"id", fmt.Sprintf("%s was already merged into %s", from.ID, from.MergedInto)}
+	}
+
+	known := map[string]bool{}
+	for _, n := range into.Names() {
+		known[Normalize(n)] = true
+	}
+	for _, n := range from.Names() {
+		if !known[Normalize(n)] {
+			known[Normalize(n)] = true
+			into.Synonyms = append(into.Synonyms, n)
+		}
+	}
+	from.MergedInto = into.ID
+	return nil
+}
+
+// Repoint - the PATCH body that moves a case from one term to another, nil when the case doesn't
+// use from. With from == into it only writes the label texts again, e.g. after a rename.
+func (r *Resolver) Repoint(cs models.CaseInfo, from, into *models.TaxonomyTerm) (map[string]interface{}, error) {
+	switch from.Kind {
+	case models.TermDomain:
+		if from.ID == into.ID {
+			return nil, nil // no label text to write
+		}
+		ids, ok := replace(cs.DomainIDs, from.ID, into.ID)
+		if !ok {
+			return nil, nil
+		}
+		return map[string]interface{}{"domain_ids": ids}, nil
+	case models.TermCaseType:
+		if cs.CaseTypeID != from.ID {
+			return nil, nil
+		}
+		return map[string]interface{}{"case_type_id": into.ID, "case_type": into.DisplayLabel()}, nil
+	case models.TermKeyword:
+		ids, ok := replace(cs.KeywordIDs, from.ID, into.ID)
+		if !ok {
+			return nil, nil
+		}
+		keywords, err := r.ByIDs("keyword_ids", models.TermKeyword, ids)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"keyword_ids": IDs(keywords), "case_keywords": JoinLabels(keywords)}, nil
+	}
+	return nil, nil
+}
+
+// replace swaps from for to in ids, keeping the order and dropping a second to
+func replace(ids []string, from, to string) ([]string, bool) {
+	found := false
+	out := []string{}
+	seen := map[string]bool{}
+	for _, id := range ids {
+		if id == from {
+			found, id = true, to
+		}
+		if !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+	return out, found
+}
@@ -0,0 +1,110 @@
+package taxonomy
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+
+	"trl-research-backend/internal/models"
+	"trl-research-backend/internal/repository/memory"
+)
+
+func TestSplitKeywords(t *testing.T) {
+	cases := map[string][]string{
+		"":                           nil,
+		" , ;":                       nil,
+		"AI, machine  learning ;IoT": {"AI", "machine learning", "IoT"},
+		"ai, AI,Ai":                  {"ai"},
+		"ยาง，พลังงาน、อาหาร\nน้ำ": {"ยาง", "พลังงาน", "อาหาร", "น้ำ"},
+	}
+	for in, want := range cases {
+		if got := SplitKeywords(in); !slices.Equal(got, want) {
+			t.Errorf("SplitKeywords(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	term := func(id, kind, th, en string, synonyms ...string) *models.TaxonomyTerm {
+		return &models.TaxonomyTerm{ID: id, Kind: kind, Label: models.LocalizedText{TH: th, EN: en}, Synonyms: synonyms}
+	}
+	from := term("TX-00001", models.TermDomain, "", "Artificial Intelligence", "AI")
+	into := term("TX-00002", models.TermDomain, "ปัญญาประดิษฐ์", "AI")
+	if err := Merge(from, into); err != nil {
+		t.Fatal(err)
+	}
+	if from.MergedInto != into.ID || !slices.Equal(into.Synonyms, []string{"Artificial Intelligence"}) {
+		t.Errorf("merged: %q into %q, synonyms %q", from.MergedInto, into.ID, into.Synonyms)
+	}
+	if err := Merge(from, into); err != nil {
+		t.Errorf("merging again into the same term: %v", err)
+	}
+
+	for name, c := range map[string]struct{ from, into *models.TaxonomyTerm }{
+		"itself":           {into, into},
+		"other kind":       {term("TX-00003", models.TermKeyword, "", "AI"), term("TX-00004", models.TermDomain, "", "ML")},
+		"into merged":      {term("TX-00005", models.TermDomain, "", "ML"), from},
+		"merged elsewhere": {from, term("TX-00006", models.TermDomain, "", "Robotics")},
+	} {
+		var terr *Error
+		if err := Merge(c.from, c.into); !errors.As(err, &terr) {
+			t.Errorf("%s: %v, want a taxonomy error", name, err)
+		}
+	}
+}
+
+func TestRepoint(t *testing.T) {
+	repos := memory.NewRepositories()
+	create := func(kind, th string) *models.TaxonomyTerm {
+		t.Helper()
+		term := &models.TaxonomyTerm{Kind: kind, Label: models.LocalizedText{TH: th}, Synonyms: []string{}}
+		if err := repos.Taxonomy.CreateTaxonomyTerm(term); err != nil {
+			t.Fatal(err)
+		}
+		return term
+	}
+	d1, d2 := create(models.TermDomain, "เกษตร"), create(models.TermDomain, "การเกษตร")
+	t1, t2 := create(models.TermCaseType, "สิ่งประดิษฐ์"), create(models.TermCaseType, "งานวิจัย")
+	k1, k2, k3 := create(models.TermKeyword, "ยาง"), create(models.TermKeyword, "ยางพารา"), create(models.TermKeyword, "น้ำ")
+	cs := models.CaseInfo{
+		DomainIDs:  []string{d1.ID, d2.ID},
+		CaseTypeID: t1.ID,
+		KeywordIDs: []string{k1.ID, k3.ID, k2.ID},
+	}
+	r := NewResolver(repos.Taxonomy)
+
+	tests := []struct {
+		name     string
+		from, to *models.TaxonomyTerm
+		want     map[string]interface{}
+	}{
+		{"domain", d1, d2, map[string]interface{}{"domain_ids": []string{d2.ID}}},
+		{"domain rename", d1, d1, nil},
+		{"case type", t1, t2, map[string]interface{}{"case_type_id": t2.ID, "case_type": "งานวิจัย"}},
+		{"case type not used", t2, t1, nil},
+		{"keyword", k1, k2, map[string]interface{}{"keyword_ids": []string{k2.ID, k3.ID}, "case_keywords": "ยางพารา, น้ำ"}},
+	}
+	for _, tt := range tests {
+		got, err := r.Repoint(cs, tt.from, tt.to)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	// a rename not written yet: the case gets the new label once the resolver knows it
+	renamed := *k3
+	renamed.Label.TH = "แหล่งน้ำ"
+	r.Pending(renamed)
+	got, err := r.Repoint(cs, &renamed, &renamed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{"keyword_ids": cs.KeywordIDs, "case_keywords": "ยาง, แหล่งน้ำ, ยางพารา"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("rename: %v, want %v", got, want)
+	}
+}
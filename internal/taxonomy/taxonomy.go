@@ -0,0 +1,215 @@
+// Package taxonomy resolves the taxonomy references of a case. Cases point at curated terms by
+// ID (domain_ids, case_type_id, keyword_ids) and keep the labels of those terms in the old text
+// fields case_type and case_keywords, so clients that only know the texts keep working: a case
+// saved with texts only gets them matched against the labels and synonyms of the terms.
+package taxonomy
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"trl-research-backend/internal/models"
+	"trl-research-backend/internal/repository"
+)
+
+// maxMergeDepth bounds following merged_into, in case a bad edit made a loop
+const maxMergeDepth = 10
+
+// Error - a reference the taxonomy can't resolve: unknown ID or a term of another kind
+type Error struct {
+	Field string
+	Msg   string
+}
+
+func (e *Error) Error() string {
+	return e.Field + ": " + e.Msg
+}
+
+// Normalize - the form names are compared in: lowercase, single spaces
+func Normalize(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+// SplitKeywords splits a keyword text at commas (also the full-width and Thai-style ones) and
+// semicolons, drops empty parts and repeats
+func SplitKeywords(s string) []string {
+	parts := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ';' || r == '，' || r == '、' || r == '\n'
+	})
+	var out []string
+	seen := map[string]bool{}
+	for _, p := range parts {
+		p = strings.Join(strings.Fields(p), " ")
+		if p != "" && !seen[Normalize(p)] {
+			seen[Normalize(p)] = true
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// Resolver looks terms up for one batch of work. It caches what it read, so a long run (the
+// migration script) should use one Resolver and a request a fresh one.
+type Resolver struct {
+	Terms repository.TaxonomyRepository
+
+	byKind  map[string][]models.TaxonomyTerm
+	pending map[string]models.TaxonomyTerm
+}
+
+func NewResolver(terms repository.TaxonomyRepository) *Resolver {
+	return &Resolver{Terms: terms, byKind: map[string][]models.TaxonomyTerm{}, pending: map[string]models.TaxonomyTerm{}}
+}
+
+// Pending makes the resolver see terms as they will be once a rename / merge is written, instead
+// of as stored
+func (r *Resolver) Pending(terms ...models.TaxonomyTerm) {
+	for _, t := range terms {
+		r.pending[t.ID] = t
+	}
+	r.byKind = map[string][]models.TaxonomyTerm{}
+}
+
+func (r *Resolver) terms(kind string) ([]models.TaxonomyTerm, error) {
+	if terms, ok := r.byKind[kind]; ok {
+		return terms, nil
+	}
+	terms, err := r.Terms.GetTaxonomyTerms(kind)
+	if err != nil {
+		return nil, err
+	}
+	for i := range terms {
+		if t, ok := r.pending[terms[i].ID]; ok {
+			terms[i] = t
+		}
+	}
+	r.byKind[kind] = terms
+	return terms, nil
+}
+
+func (r *Resolver) term(id string) (*models.TaxonomyTerm, error) {
+	if t, ok := r.pending[id]; ok {
+		return &t, nil
+	}
+	return r.Terms.GetTaxonomyTermByID(id)
+}
+
+// ByID - the term of kind behind id, following merged_into to the term in use
+func (r *Resolver) ByID(field, kind, id string) (*models.TaxonomyTerm, error) {
+	for depth := 0; depth < maxMergeDepth; depth++ {
+		t, err := r.term(id)
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, &Error{field, fmt.Sprintf("unknown term %s", id)}
+		}
+		if err != nil {
+			return nil, err
+		}
+		if t.Kind != kind {
+			return nil, &Error{field, fmt.Sprintf("%s is a %s, not a %s", id, t.Kind, kind)}
+		}
+		if t.MergedInto == "" {
+			return t, nil
+		}
+		id = t.MergedInto
+	}
+	return nil, &Error{field, fmt.Sprintf("merge loop at %s", id)}
+}
+
+// ByIDs is ByID for a list; terms merged into the same one come back once
+func (r *Resolver) ByIDs(field, kind string, ids []string) ([]models.TaxonomyTerm, error) {
+	terms := []models.TaxonomyTerm{}
+	seen := map[string]bool{}
+	for _, id := range ids {
+		t, err := r.ByID(field, kind, id)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[t.ID] {
+			seen[t.ID] = true
+			terms = append(terms, *t)
+		}
+	}
+	return terms, nil
+}
+
+// ByName - the term of kind with name as label or synonym. An unknown name becomes a new term
+// when create is set (for admins to curate or merge later); otherwise the result is nil.
+func (r *Resolver) ByName(kind, name string, create bool) (*models.TaxonomyTerm, error) {
+	name = strings.Join(strings.Fields(name), " ")
+	terms, err := r.terms(kind)
+	if err != nil {
+		return nil, err
+	}
+	key := Normalize(name)
+	for i := range terms {
+		if terms[i].MergedInto != "" {
+			continue
+		}
+		for _, n := range terms[i].Names() {
+			if Normalize(n) == key {
+				return &terms[i], nil
+			}
+		}
+	}
+	if !create {
+		return nil, nil
+	}
+
+	t := &models.TaxonomyTerm{Kind: kind, Synonyms: []string{}}
+	if isThai(name) {
+		t.Label.TH = name
+	} else {
+		t.Label.EN = name
+	}
+	if err := r.Terms.CreateTaxonomyTerm(t); err != nil {
+		return nil, err
+	}
+	r.byKind[kind] = append(terms, *t)
+	return t, nil
+}
+
+// ByNames is ByName with create for a list of names
+func (r *Resolver) ByNames(kind string, names []string) ([]models.TaxonomyTerm, error) {
+	terms := []models.TaxonomyTerm{}
+	seen := map[string]bool{}
+	for _, name := range names {
+		t, err := r.ByName(kind, name, true)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[t.ID] {
+			seen[t.ID] = true
+			terms = append(terms, *t)
+		}
+	}
+	return terms, nil
+}
+
+// IDs - the IDs of terms
+func IDs(terms []models.TaxonomyTerm) []string {
+	ids := make([]string, len(terms))
+	for i := range terms {
+		ids[i] = terms[i].ID
+	}
+	return ids
+}
+
+// JoinLabels - the case_keywords text of terms
+func JoinLabels(terms []models.TaxonomyTerm) string {
+	labels := make([]string, len(terms))
+	for i := range terms {
+		labels[i] = terms[i].DisplayLabel()
+	}
+	return strings.Join(labels, ", ")
+}
+
+func isThai(s string) bool {
+	for _, r := range s {
+		if unicode.Is(unicode.Thai, r) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+// Package trash removes soft-deleted records for good once they have been in the trash longer
+// than the retention period.
+package trash
+
+import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"trl-research-backend/internal/audit"
+	"trl-research-backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PurgeInterval - how often the scheduled purge runs
+const PurgeInterval = 24 * time.Hour
+
+// Retention - TRASH_RETENTION_DAYS (default 30)
+func Retention() time.Duration {
+	d, _ := strconv.Atoi(os.Getenv("TRASH_RETENTION_DAYS"))
+	if d <= 0 {
+		d = 30
+	}
+	return time.Duration(d) * 24 * time.Hour
+}
+
+// Counts - what a purge removed, by entity
+type Counts struct {
+	Cutoff       time.Time `json:"cutoff"`
+	Assessments  int       `json:"assessments"`
+	IPs          int       `json:"ips"`
+	Supporters   int       `json:"supporters"`
+	Appointments int       `json:"appointments"`
+	Cases        int       `json:"cases"`
+	Coordinators int       `json:"coordinators"`
+	Admins       int       `json:"admins"`
+	Researchers  int       `json:"researchers"`
+	Drafts       int       `json:"drafts"` // expired, not deleted
+}
+
+// Total - the records of every entity
+func (c Counts) Total() int {
+	return c.Assessments + c.IPs + c.Supporters + c.Appointments + c.Cases + c.Coordinators + c.Admins + c.Researchers +
+		c.Drafts
+}
+
+// Purger hard-deletes the records deleted more than Retention ago
+type Purger struct {
+	Repos     *repository.Repositories
+	Retention time.Duration
+	Audit     *audit.Logger // an entry per removed record
+}
+
+// Purge purges every repository, the records of a case before the cases and the researchers last:
+// a researcher is kept while a case (even a deleted one) still refers to them. Expired drafts
+// go too. c is the request that asked for it, nil for the scheduled purge. Stops at the first
+// error, with the counts so far.
+func (p *Purger) Purge(c *gin.Context) (Counts, error) {
+	now := time.Now()
+	cutoff := now.Add(-p.Retention)
+	counts := Counts{Cutoff: cutoff}
+	steps := []struct {
+		entity string
+		n      *int
+		purge  func(time.Time) ([]string, error)
+		at     time.Time
+	}{
+		{"assessment_trl", &counts.Assessments, p.Repos.AssessmentTrl.PurgeAssessmentTrls, cutoff},
+		{"ip", &counts.IPs, p.Repos.IntellectualProperty.PurgeIPs, cutoff},
+		{"supporter", &counts.Supporters, p.Repos.Supporter.PurgeSupporters, cutoff},
+		{"appointment", &counts.Appointments, p.Repos.Appointment.PurgeAppointments, cutoff},
+		{"case", &counts.Cases, p.Repos.Case.PurgeCases, cutoff},
+		{"coordinator", &counts.Coordinators, p.Repos.Coordinator.PurgeCoordinators, cutoff},
+		{"admin", &counts.Admins, p.Repos.Admin.PurgeAdmins, cutoff},
+		{"researcher", &counts.Researchers, p.Repos.Researcher.PurgeResearchers, cutoff},
+		{"draft", &counts.Drafts, p.Repos.Draft.PurgeDrafts, now}, // expired, not deleted
+	}
+	for _, step := range steps {
+		ids, err := step.purge(step.at)
+		*step.n = len(ids)
+		for _, id := range ids {
+			p.Audit.Record(c, step.entity, id, "purge", nil, nil)
+		}
+		if err != nil {
+			return counts, err
+		}
+	}
+	return counts, nil
+}
+
+// Run purges right away and then every interval until ctx is done
+func (p *Purger) Run(ctx context.Context, every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	for {
+		counts, err := p.Purge(nil)
+		if err != nil {
+			log.Printf("❌ [Trash] purge: %v", err)
+		} else {
+			log.Printf("🗑️ [Trash] purged %d records deleted before %s", counts.Total(), counts.Cutoff.Format(time.RFC3339))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
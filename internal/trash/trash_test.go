@@ -0,0 +1,67 @@
+package trash
+
+import (
+	"testing"
+	"time"
+
+	"trl-research-backend/internal/models"
+	"trl-research-backend/internal/repository/memory"
+)
+
+// records stay in the trash for the retention period, and a researcher stays while a case still
+// refers to them
+func TestPurgeHonoursRetentionAndKeepsResearchersWithCases(t *testing.T) {
+	repos := memory.NewRepositories()
+	researcher := func() string {
+		t.Helper()
+		r := &models.ResearcherInfo{ResearcherEmail: time.Now().String() + "@example.com"}
+		if err := repos.Researcher.CreateResearcher(r); err != nil {
+			t.Fatal(err)
+		}
+		return r.ResearcherID
+	}
+	withCase, withDeletedCase := researcher(), researcher()
+	live := &models.CaseInfo{ResearcherID: withCase}
+	deleted := &models.CaseInfo{ResearcherID: withDeletedCase}
+	for _, cs := range []*models.CaseInfo{live, deleted} {
+		if err := repos.Case.CreateCase(cs); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := repos.Case.DeleteCase(deleted.CaseID, "AD-00001"); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{withCase, withDeletedCase} {
+		if err := repos.Researcher.DeleteResearcher(id, "AD-00001"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	p := &Purger{Repos: repos, Retention: time.Hour}
+	counts, err := p.Purge(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if counts.Total() != 0 || !counts.Cutoff.Before(time.Now().Add(-59*time.Minute)) {
+		t.Errorf("within retention: purged %d, cutoff %s", counts.Total(), counts.Cutoff)
+	}
+
+	// past retention: the deleted case goes and with it its researcher; the other one keeps
+	// their live case and so stays
+	p.Retention = 0
+	if counts, err = p.Purge(nil); err != nil {
+		t.Fatal(err)
+	}
+	if counts.Cases != 1 || counts.Researchers != 1 || counts.Total() != 2 {
+		t.Errorf("past retention: %+v", counts)
+	}
+	if err := repos.Case.DeleteCase(live.CaseID, "AD-00001"); err != nil {
+		t.Fatal(err)
+	}
+	if counts, err = p.Purge(nil); err != nil {
+		t.Fatal(err)
+	}
+	if counts.Cases != 1 || counts.Researchers != 1 {
+		t.Errorf("once the last case is gone: %+v", counts)
+	}
+}
@@ -0,0 +1,133 @@
+package trl
+
+import (
+	"fmt"
+
+	"trl-research-backend/internal/models"
+)
+
+// BuiltInVersion - the questionnaire the server ships with. It is the form the fixed
+// rq1_answer ... cq9_answer fields were written for and is never stored, so it can't be edited.
+const BuiltInVersion = 1
+
+// text pairs Thai and English wording
+type text [2]string
+
+func (t text) localized() models.LocalizedText {
+	return models.LocalizedText{TH: t[0], EN: t[1]}
+}
+
+// v1Readiness - what a "yes" to rq1 ... rq7 claims and the levels it gates. They are stage
+// gates: a yes opens the checklists of those levels and needs a yes on every earlier question.
+var v1Readiness = []struct {
+	text   text
+	levels []int
+}{
+	{text{"มีการศึกษาหลักการพื้นฐาน และสมมุติฐานมีทฤษฎีรองรับแล้ว", "Basic principles observed, the hypothesis is backed by theory"}, []int{1}},
+	{text{"มีการกำหนดแนวคิดของเทคโนโลยีและการประยุกต์ใช้แล้ว", "Technology concept and application formulated"}, []int{2}},
+	{text{"มีการพิสูจน์แนวคิดด้วยการทดลองแล้ว", "Proof of concept by experiment"}, []int{3}},
+	{text{"ส่วนประกอบ / ต้นแบบผ่านการทดสอบในห้องปฏิบัติการแล้ว", "Components / prototype validated in the laboratory"}, []int{4}},
+	{text{"ผ่านการทดสอบและสาธิตในสภาพแวดล้อมที่เกี่ยวข้องแล้ว", "Validated and demonstrated in a relevant environment"}, []int{5, 6}},
+	{text{"ต้นแบบผ่านการสาธิตในสภาพแวดล้อมการใช้งานจริงแล้ว", "Prototype demonstrated in the operational environment"}, []int{7}},
+	{text{"ระบบสมบูรณ์ ผ่านการรับรอง และพิสูจน์แล้วในการใช้งานจริง", "System complete, qualified and proven in operation"}, []int{8, 9}},
+}
+
+// v1Criteria - the checklists of TRL 1 ... 9. The Thai text is the option value, same wording
+// as checkboxQuestionList of the frontend, so stored answers keep matching.
+var v1Criteria = [][]text{
+	{
+		{"สมมุติฐานมีทฤษฎีทางวิทยาศาสตร์หรือคณิตศาสตร์รองรับ", "The hypothesis is supported by scientific or mathematical theory"},
+		{"สมมุติฐานเป็นไปตามงานวิจัยที่เกี่ยวข้อง", "The hypothesis is consistent with related research"},
+		{"ผู้วิจัยมีการพัฒนาแนวคิดหรือสมการเพื่อสนับสนุนสมมุติฐาน", "The researcher developed concepts or equations supporting the hypothesis"},
+	},
+	{
+		{"สมมุติฐานผ่านการตรวจสอบโดยผู้เชี่ยวชาญ และยืนยันหลักการทางวิทยาศาสตร์พื้นฐาน", "The hypothesis was reviewed by experts and the basic scientific principles confirmed"},
+		{"สมมุติฐานแสดงแนวทางที่เป็นไปได้พร้อม ระบุส่วนประกอบสำคัญของเทคโนโลยี", "The hypothesis shows a feasible approach and names the key technology components"},
+		{"สมมุติฐานมีการประเมินหรือคาดการณ์ประสิทธิภาพเบื้องต้นขององค์ประกอบหลัก", "Initial performance of the main components is estimated or predicted"},
+		{"มีการศึกษาเบื้องต้นยืนยันความเป็นไปได้ของการจำลอง กระบวนการอย่างง่าย (การศึกษาโดยไม่มีการทดลองในห้องปฏิบัติการ", "A preliminary study confirms a simple simulation of the process is feasible (without laboratory experiments)"},
+		{"สมมุติฐานมีการทดสอบแนวคิด (Proof of Concept) ด้วยข้อมูลสังเคราะห์", "The concept was tested (proof of concept) with synthetic data"},
+	},
+	{
+		{"สมมุติฐานถูกพิสูจน์ด้วยการทดลองเบื้องต้นแล้ว", "The hypothesis was proven by initial experiments"},
+		{"การทดลองสามารถคาดการณ์ของส่วนประกอบเทคโนโลยีได้", "Experiments can predict the behaviour of the technology components"},
+		{"มีการสร้างตัวชี้วัดประสิทธิภาพเทคโนโลยีหรือระบบ", "Performance indicators of the technology or system are defined"},
+		{"มีข้อเท็จจริงวิทยาศาสตร์ที่เกี่ยวข้องกับการพัฒนาเทคโนโลยีที่สามารถจำลองทำซ้ำได้", "The scientific findings behind the technology are reproducible"},
+		{"มีการยืนยันคุณสมบัติและประสิทธิภาพของเทคโนโลยีหรือระบบด้วยสมการ หรือตัวแปร", "Properties and performance are confirmed with equations or parameters"},
+		{"มีหลักฐานงานวิจัยที่เผยแพร่แล้วว่าการรวมเทคโนโลยีและส่วนประกอบของระบบประสบความสำเร็จ", "Published research shows the technology and system components were integrated successfully"},
+		{"มีการระบุความเสี่ยงและมีการบริหารความเสี่ยงสำหรับงานวิจัย", "Research risks are identified and managed"},
+	},
+	{
+		{"มีการสรุปและจัดทำข้อกำหนดของระบบ/การออกแบบ โดยอ้างอิงจากความต้องการจริง", "System / design requirements are specified from real needs"},
+		{"มีการระบุวัสดุ กระบวนการ และเทคนิคที่เกี่ยวข้อง", "Materials, processes and techniques are identified"},
+		{"มีต้นแบบเทคโนโลยีที่ปรับขนาดได้", "A scalable technology prototype exists"},
+		{"มีการทดสอบและแสดงประสิทธิภาพของส่วนประกอบและต้นแบบในห้องปฏิบัติการ", "Components and prototype were tested and shown to perform in the laboratory"},
+		{"มีการจำลองและตรวจสอบความเป็นไปได้ของกระบวนการ", "The process was simulated and its feasibility checked"},
+		{"มีส่วนประกอบของระบบครบถ้วนและเพียงพอ", "The system components are complete and sufficient"},
+		{"มีการเริ่มศึกษาบูรณาการกับการใช้งานอื่น", "Integration with other applications has started"},
+		{"มีการระบุปัจจัยต้นทุน", "Cost drivers are identified"},
+		{"มีการริเริ่มโปรแกรมการจัดการความเสี่ยงอย่างเป็นทางการและบูรณาการกับการจัดการโครงการ", "A formal risk management programme is started and integrated with project management"},
+	},
+	{
+		{"ต้นแบบถูกพัฒนาและทำงานได้จริง โดยมีการรวมโมดูล/ฟังก์ชันสำคัญ และทดสอบการทำงานภายใต้สภาวะที่ใกล้เคียงหรือเป็นจริง", "A working prototype integrates the key modules / functions and was tested under near-real or real conditions"},
+		{"ส่วนประกอบและส่วนต่อประสานของระบบได้รับการกำหนด ตรวจสอบ และรับรองตามมาตรฐานที่ยอมรับได้", "System components and interfaces are defined, verified and certified to accepted standards"},
+		{"มีการวัดผลกระบวนการที่เที่ยงตรง", "The process is measured accurately"},
+		{"มีการระบุปัญหาและประเมินความน่าเชื่อถือด้านคุณภาพ", "Problems are identified and quality reliability is assessed"},
+		{"มีการสรุปกระบวนการออกแบบสำหรับการใช้งานจริง", "The design process for real use is finalised"},
+		{"มีการจัดทำและดำเนินการตามแผนบริหารความเสี่ยง", "A risk management plan is written and followed"},
+	},
+	{
+		{"มีการทดสอบและสาธิตต้นแบบในสภาพแวดล้อมที่เกี่ยวข้อง/จำลองจริง พร้อมการยืนยันคุณสมบัติทางวิศวกรรมและประสิทธิภาพของระบบ", "The prototype was tested and demonstrated in a relevant / simulated environment, confirming engineering properties and system performance"},
+		{"ส่วนประกอบของสินค้าหรือบริการต้นแบบนั้นสามารถทำงานร่วมกันได้ในการทดสอบการแก้ปัญหาจริง", "The components of the prototype product or service work together when solving the real problem"},
+		{"มีการจัดเตรียมวัสดุ/อุปกรณ์ภายนอกครบถ้วน", "External materials / equipment are fully prepared"},
+		{"มีการรวบรวมข้อมูลด้านการบำรุงรักษาและระบบสนับสนุนที่เชื่อถือได้", "Reliable maintenance and support data is collected"},
+	},
+	{
+		{"มีการทดสอบและตรวจสอบการปฏิบัติงานของอุปกรณ์/กระบวนการในสภาวะจริง เพื่อหาข้อจำกัด จุดบกพร่อง และยืนยันความถูกต้องกับระบบที่ใช้งานอยู่", "Equipment / process operation was tested under real conditions to find limits and defects and to validate against the system in use"},
+		{"มีต้นแบบและส่วนประกอบที่ใกล้เคียงของจริง แสดงให้เห็นถึงความพอดีและฟังก์ชันการทำงานที่สอดคล้องกับการผลิตจริง", "Near-final prototype and components show fit and function consistent with production"},
+		{"มีข้อมูลสนับสนุนด้านความน่าเชื่อถือ การบำรุงรักษา", "Reliability and maintenance data supports the design"},
+		{"มีอุปกรณ์และวัสดุที่ใช้ได้จริงในกระบวนการผลิต", "Equipment and materials usable in production are available"},
+	},
+	{
+		{"ทุกองค์ประกอบของเทคโนโลยี/ระบบมีความพอดี ฟังก์ชันเข้ากันได้ และเหมาะสมกับสภาพแวดล้อมการทำงานจริง", "Every part of the technology / system fits, is functionally compatible and suits the operational environment"},
+		{"วัสดุทั้งหมดในการผลิตและพร้อมใช้งาน", "All production materials are available"},
+		{"มีข้อมูลและเอกสารการบำรุงรักษา/การสนับสนุนที่สมบูรณ์และอยู่ภายใต้การควบคุมการกำหนดค่า", "Maintenance / support data and documents are complete and under configuration control"},
+	},
+	{
+		{"เทคโนโลยี/ระบบทำงานได้ตามที่กำหนดในเอกสารแนวคิด มีการนำไปปรับใช้ในสภาพแวดล้อมจริง และแสดงศักยภาพได้อย่างสมบูรณ์", "The technology / system works as specified, is deployed in the real environment and shows its full capability"},
+		{"มีการทดสอบและประเมินผลการปฏิบัติงานสำเร็จแล้วและจัดทำเป็นเอกสาร", "Operational test and evaluation was completed and documented"},
+		{"มีการออกแบบโดยคำนึงถึงเป้าหมายด้านต้นทุน", "The design meets its cost targets"},
+		{"มีการระบุและบรรเทาความเสี่ยงด้านความปลอดภัยและผลข้างเคียง", "Safety risks and side effects are identified and mitigated"},
+	},
+}
+
+// BuiltInTemplate - version 1, the questionnaire that used to be hard-wired into AssessmentTrl
+func BuiltInTemplate() *models.QuestionnaireTemplate {
+	t := &models.QuestionnaireTemplate{
+		Version:     BuiltInVersion,
+		Title:       text{"แบบประเมินระดับความพร้อมของเทคโนโลยี (TRL)", "Technology Readiness Level (TRL) assessment"}.localized(),
+		Description: text{"แบบประเมินมาตรฐานของระบบ", "Built-in questionnaire"}.localized(),
+		Published:   true,
+		BuiltIn:     true,
+	}
+	for i, rq := range v1Readiness {
+		t.Questions = append(t.Questions, models.Question{
+			Key:    fmt.Sprintf("rq%d", i+1),
+			Type:   models.QuestionReadiness,
+			Text:   rq.text.localized(),
+			Levels: rq.levels,
+		})
+	}
+	for i, criteria := range v1Criteria {
+		q := models.Question{
+			Key:    fmt.Sprintf("cq%d", i+1),
+			Type:   models.QuestionCriteria,
+			Text:   text{fmt.Sprintf("เกณฑ์ TRL %d", i+1), fmt.Sprintf("TRL %d criteria", i+1)}.localized(),
+			Levels: []int{i + 1},
+		}
+		for _, c := range criteria {
+			q.Options = append(q.Options, models.AnswerOption{Value: c[0], Text: c.localized()})
+		}
+		t.Questions = append(t.Questions, q)
+	}
+	return t
+}
@@ -0,0 +1,197 @@
+package trl
+
+import (
+	"fmt"
+	"strings"
+
+	"trl-research-backend/internal/models"
+)
+
+// Rule set
+//
+//  1. a level is met when its readiness question is answered yes and every criterion of
+//     its checklist is ticked
+//  2. the TRL is the highest level L such that levels 1..L are all met; levels cannot be
+//     skipped, so a complete level 5 checklist does not count while level 3 is open.
+//     0 means not even TRL 1 is reached
+//  3. a submission is rejected when it contradicts itself: a readiness yes after an earlier
+//     no, ticked criteria of a level whose readiness question is no, options that are not
+//     in the level's checklist / ticked twice, or answers to questions the template lacks.
+//     An update of a stored assessment is only rejected for what it adds (Rescore): records
+//     from before these rules may contradict themselves and still have to be editable
+//
+// Which question gates which level and what the checklists contain comes from the
+// questionnaire template the assessment was answered against.
+
+// LevelEvidence - how one level was judged
+type LevelEvidence struct {
+	Level           int      `json:"level"`
+	Readiness       string   `json:"readiness"` // "rq3"
+	ReadinessAnswer bool     `json:"readiness_answer"`
+	CriteriaTotal   int      `json:"criteria_total"`
+	CriteriaMet     []string `json:"criteria_met"`
+	CriteriaMissing []string `json:"criteria_missing"`
+	Met             bool     `json:"met"`
+	Counted         bool     `json:"counted"` // met and every lower level met too
+}
+
+// Result - the derived TRL and the evidence for every level
+type Result struct {
+	Level                int             `json:"trl_level"`
+	QuestionnaireVersion int             `json:"questionnaire_version"`
+	Evidence             []LevelEvidence `json:"evidence"`
+}
+
+// ValidationError lists everything inconsistent in a submission
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "inconsistent assessment: " + strings.Join(e.Problems, "; ")
+}
+
+// Score validates the answers of a against template t and derives its TRL level
+func Score(t *models.QuestionnaireTemplate, a *models.AssessmentTrl) (*Result, error) {
+	res, problems, err := score(t, a)
+	if err != nil {
+		return nil, err
+	}
+	if len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+	return res, nil
+}
+
+// Rescore scores updated, an update of the stored assessment, like Score but lets through the
+// problems stored already has (rule 3): only the ones the update adds reject it
+func Rescore(t *models.QuestionnaireTemplate, stored, updated *models.AssessmentTrl) (*Result, error) {
+	_, before, err := score(t, stored)
+	if err != nil {
+		return nil, err
+	}
+	res, after, err := score(t, updated)
+	if err != nil {
+		return nil, err
+	}
+	known := map[string]bool{}
+	for _, p := range before {
+		known[p] = true
+	}
+	var added []string
+	for _, p := range after {
+		if !known[p] {
+			added = append(added, p)
+		}
+	}
+	if len(added) > 0 {
+		return nil, &ValidationError{Problems: added}
+	}
+	return res, nil
+}
+
+// score derives the level of a and lists what contradicts rule 3; err is for a template that
+// can't be scored
+func score(t *models.QuestionnaireTemplate, a *models.AssessmentTrl) (*Result, []string, error) {
+	levels, err := plan(t)
+	if err != nil {
+		return nil, nil, err
+	}
+	var problems []string
+
+	for key := range a.ReadinessAnswers {
+		if q := t.Question(key); q == nil || q.Type != models.QuestionReadiness {
+			problems = append(problems, fmt.Sprintf("%s: not a readiness question of questionnaire v%d", key, t.Version))
+		}
+	}
+	for key := range a.CriteriaAnswers {
+		if q := t.Question(key); q == nil || q.Type != models.QuestionCriteria {
+			problems = append(problems, fmt.Sprintf("%s: not a criteria question of questionnaire v%d", key, t.Version))
+		}
+	}
+
+	var previous *models.Question
+	for i := range t.Questions {
+		q := &t.Questions[i]
+		if q.Type != models.QuestionReadiness {
+			continue
+		}
+		if previous != nil && a.Readiness(q.Key) && !a.Readiness(previous.Key) {
+			problems = append(problems, fmt.Sprintf("%s is yes but %s is no", q.Key, previous.Key))
+		}
+		previous = q
+	}
+
+	res := &Result{QuestionnaireVersion: t.Version}
+	counting := true
+	for _, lvl := range levels {
+		answers := a.Criteria(lvl.criteria.Key)
+		ev := LevelEvidence{
+			Level:           lvl.level,
+			Readiness:       lvl.readiness.Key,
+			ReadinessAnswer: a.Readiness(lvl.readiness.Key),
+			CriteriaTotal:   len(lvl.criteria.Options),
+			CriteriaMet:     []string{},
+			CriteriaMissing: []string{},
+		}
+
+		ticked := map[string]bool{}
+		for _, label := range answers {
+			label = strings.TrimSpace(label)
+			switch {
+			case !hasOption(lvl.criteria, label):
+				problems = append(problems, fmt.Sprintf("%s: unknown criterion %q", lvl.criteria.Key, label))
+			case ticked[label]:
+				problems = append(problems, fmt.Sprintf("%s: %q ticked twice", lvl.criteria.Key, label))
+			}
+			ticked[label] = true
+		}
+		if len(answers) > 0 && !ev.ReadinessAnswer {
+			problems = append(problems, fmt.Sprintf("%s has answers but %s is no", lvl.criteria.Key, ev.Readiness))
+		}
+
+		for _, o := range lvl.criteria.Options {
+			if ticked[o.Value] {
+				ev.CriteriaMet = append(ev.CriteriaMet, o.Value)
+			} else {
+				ev.CriteriaMissing = append(ev.CriteriaMissing, o.Value)
+			}
+		}
+		ev.Met = ev.ReadinessAnswer && len(ev.CriteriaMissing) == 0
+		counting = counting && ev.Met
+		ev.Counted = counting
+		if ev.Counted {
+			res.Level = lvl.level
+		}
+		res.Evidence = append(res.Evidence, ev)
+	}
+
+	return res, problems, nil
+}
+
+// Normalize writes an answer for every question of t into the answer maps (and the fixed
+// rq/cq fields they mirror), so a stored assessment is complete for its template version
+func Normalize(t *models.QuestionnaireTemplate, a *models.AssessmentTrl) {
+	a.QuestionnaireVersion = t.Version
+	for _, q := range t.Questions {
+		switch q.Type {
+		case models.QuestionReadiness:
+			a.SetReadiness(q.Key, a.Readiness(q.Key))
+		case models.QuestionCriteria:
+			var trimmed []string
+			for _, label := range a.Criteria(q.Key) {
+				trimmed = append(trimmed, strings.TrimSpace(label))
+			}
+			a.SetCriteria(q.Key, trimmed)
+		}
+	}
+}
+
+func hasOption(q *models.Question, value string) bool {
+	for _, o := range q.Options {
+		if o.Value == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,236 @@
+package trl
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"trl-research-backend/internal/models"
+)
+
+// options - every criterion of a checklist
+func options(q models.Question) []string {
+	var values []string
+	for _, o := range q.Options {
+		values = append(values, o.Value)
+	}
+	return values
+}
+
+// upTo - answers of t that meet every level up to level: the readiness questions gating them
+// yes, their checklists complete
+func upTo(t *models.QuestionnaireTemplate, level int) *models.AssessmentTrl {
+	a := &models.AssessmentTrl{}
+	for _, q := range t.Questions {
+		if q.Levels[0] > level {
+			continue
+		}
+		if q.Type == models.QuestionReadiness {
+			a.SetReadiness(q.Key, true)
+		} else {
+			a.SetCriteria(q.Key, options(q))
+		}
+	}
+	return a
+}
+
+func TestScoreLevels(t *testing.T) {
+	tpl := BuiltInTemplate()
+	for level := 0; level <= MaxLevel; level++ {
+		res, err := Score(tpl, upTo(tpl, level))
+		if err != nil {
+			t.Fatalf("TRL %d: %v", level, err)
+		}
+		if res.Level != level {
+			t.Errorf("TRL %d: scored %d", level, res.Level)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		answer func(a *models.AssessmentTrl)
+		want   int
+	}{
+		{"one criterion short", func(a *models.AssessmentTrl) {
+			a.SetCriteria("cq4", a.Criteria("cq4")[1:])
+		}, 3},
+		{"rq5 yes opens levels 5 and 6, only 5 complete", func(a *models.AssessmentTrl) {
+			a.SetReadiness("rq5", true)
+			a.SetCriteria("cq5", options(*tpl.Question("cq5")))
+		}, 5},
+		{"readiness yes without criteria", func(a *models.AssessmentTrl) {
+			a.SetReadiness("rq5", true)
+		}, 4},
+		{"a complete level above an open one isn't counted", func(a *models.AssessmentTrl) {
+			a.SetCriteria("cq2", nil)
+			a.SetReadiness("rq5", true)
+			a.SetCriteria("cq5", options(*tpl.Question("cq5")))
+		}, 1},
+		{"labels with spaces around them", func(a *models.AssessmentTrl) {
+			var padded []string
+			for _, label := range a.Criteria("cq4") {
+				padded = append(padded, " "+label+" ")
+			}
+			a.SetCriteria("cq4", padded)
+		}, 4},
+	}
+	for _, tt := range tests {
+		a := upTo(tpl, 4)
+		tt.answer(a)
+		res, err := Score(tpl, a)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if res.Level != tt.want {
+			t.Errorf("%s: scored %d, want %d", tt.name, res.Level, tt.want)
+		}
+	}
+}
+
+func TestScoreRejectsInconsistentAnswers(t *testing.T) {
+	tpl := BuiltInTemplate()
+	first := options(*tpl.Question("cq1"))[0]
+	tests := []struct {
+		name   string
+		answer func(a *models.AssessmentTrl)
+		want   []string
+	}{
+		{"yes after a no", func(a *models.AssessmentTrl) {
+			a.SetReadiness("rq2", false)
+			a.SetCriteria("cq2", nil)
+		}, []string{"rq3 is yes but rq2 is no"}},
+		{"criteria of a level answered no", func(a *models.AssessmentTrl) {
+			a.SetReadiness("rq4", false)
+		}, []string{"cq4 has answers but rq4 is no"}},
+		{"unknown criterion", func(a *models.AssessmentTrl) {
+			a.SetCriteria("cq1", []string{first, "something else"})
+		}, []string{`cq1: unknown criterion "something else"`}},
+		{"ticked twice", func(a *models.AssessmentTrl) {
+			a.SetCriteria("cq1", append(a.Criteria("cq1"), " "+first))
+		}, []string{fmt.Sprintf("cq1: %q ticked twice", first)}},
+		{"questions the template lacks", func(a *models.AssessmentTrl) {
+			a.ReadinessAnswers["rq8"] = true
+			a.ReadinessAnswers["cq1"] = true
+			a.CriteriaAnswers["cq10"] = []string{first}
+		}, []string{
+			"rq8: not a readiness question of questionnaire v1",
+			"cq1: not a readiness question of questionnaire v1",
+			"cq10: not a criteria question of questionnaire v1",
+		}},
+	}
+	for _, tt := range tests {
+		a := upTo(tpl, 4)
+		tt.answer(a)
+		res, err := Score(tpl, a)
+		var verr *ValidationError
+		if !errors.As(err, &verr) {
+			t.Errorf("%s: got %v, %v, want a ValidationError", tt.name, res, err)
+			continue
+		}
+		got := slices.Clone(verr.Problems)
+		slices.Sort(got)
+		want := slices.Clone(tt.want)
+		slices.Sort(want)
+		if !slices.Equal(got, want) {
+			t.Errorf("%s: problems %q, want %q", tt.name, verr.Problems, tt.want)
+		}
+	}
+}
+
+func TestScoreEvidence(t *testing.T) {
+	tpl := BuiltInTemplate()
+	a := upTo(tpl, 2)
+	cq3 := options(*tpl.Question("cq3"))
+	a.SetReadiness("rq3", true)
+	a.SetCriteria("cq3", cq3[:2])
+
+	res, err := Score(tpl, a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Level != 2 || res.QuestionnaireVersion != 1 || len(res.Evidence) != MaxLevel {
+		t.Fatalf("level %d, version %d, %d levels of evidence", res.Level, res.QuestionnaireVersion, len(res.Evidence))
+	}
+	tests := []struct {
+		level, total, met         int
+		readiness                 string
+		answer, levelMet, counted bool
+	}{
+		{1, 3, 3, "rq1", true, true, true},
+		{2, 5, 5, "rq2", true, true, true},
+		{3, 7, 2, "rq3", true, false, false},
+		{5, 6, 0, "rq5", false, false, false},
+		{6, 4, 0, "rq5", false, false, false}, // rq5 gates two levels
+		{9, 4, 0, "rq7", false, false, false},
+	}
+	for _, tt := range tests {
+		ev := res.Evidence[tt.level-1]
+		if ev.Level != tt.level || ev.Readiness != tt.readiness || ev.ReadinessAnswer != tt.answer ||
+			ev.CriteriaTotal != tt.total || len(ev.CriteriaMet) != tt.met ||
+			len(ev.CriteriaMissing) != tt.total-tt.met || ev.Met != tt.levelMet || ev.Counted != tt.counted {
+			t.Errorf("TRL %d: evidence %+v", tt.level, ev)
+		}
+	}
+	if ev := res.Evidence[2]; !slices.Equal(ev.CriteriaMet, cq3[:2]) || !slices.Equal(ev.CriteriaMissing, cq3[2:]) {
+		t.Errorf("TRL 3: met %q, missing %q", ev.CriteriaMet, ev.CriteriaMissing)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tpl := BuiltInTemplate()
+	first := options(*tpl.Question("cq1"))[0]
+	a := &models.AssessmentTrl{Rq1Answer: true, Cq1Answer: []string{"  " + first}}
+	Normalize(tpl, a)
+
+	if a.QuestionnaireVersion != 1 || len(a.ReadinessAnswers) != 7 || len(a.CriteriaAnswers) != MaxLevel {
+		t.Fatalf("version %d, %d readiness and %d criteria answers", a.QuestionnaireVersion, len(a.ReadinessAnswers), len(a.CriteriaAnswers))
+	}
+	if !a.ReadinessAnswers["rq1"] || a.ReadinessAnswers["rq2"] {
+		t.Errorf("readiness answers %v", a.ReadinessAnswers)
+	}
+	if !slices.Equal(a.CriteriaAnswers["cq1"], []string{first}) || !slices.Equal(a.Cq1Answer, []string{first}) {
+		t.Errorf("cq1 %q, cq1_answer %q", a.CriteriaAnswers["cq1"], a.Cq1Answer)
+	}
+	if a.CriteriaAnswers["cq9"] == nil || a.Cq9Answer == nil {
+		t.Errorf("cq9 %v, cq9_answer %v, want empty lists", a.CriteriaAnswers["cq9"], a.Cq9Answer)
+	}
+}
+
+func TestRescoreKeepsLegacyRecordsEditable(t *testing.T) {
+	tpl := BuiltInTemplate()
+	cq1 := options(*tpl.Question("cq1"))
+	// seeded before the rules: rq3 yes after rq2 no, criteria of rq4 answered no
+	stored := &models.AssessmentTrl{
+		Rq1Answer: true, Rq3Answer: true,
+		Cq1Answer: cq1[:1],
+		Cq4Answer: options(*tpl.Question("cq4"))[:1],
+	}
+	if _, err := Score(tpl, stored); err == nil {
+		t.Fatal("the legacy record scores without problems, the test needs one that has them")
+	}
+
+	edit := func(change func(a *models.AssessmentTrl)) (*Result, error) {
+		updated := *stored
+		change(&updated)
+		return Rescore(tpl, stored, &updated)
+	}
+
+	// finishing the TRL 1 checklist is fine, the old contradictions stay
+	res, err := edit(func(a *models.AssessmentTrl) { a.Cq1Answer = cq1 })
+	if err != nil || res.Level != 1 {
+		t.Errorf("completing cq1: %v, %v", res, err)
+	}
+	// fixing one of them is fine too
+	if _, err := edit(func(a *models.AssessmentTrl) { a.Rq2Answer = true }); err != nil {
+		t.Errorf("answering rq2 yes: %v", err)
+	}
+	// a new contradiction is rejected, with only that problem
+	_, err = edit(func(a *models.AssessmentTrl) { a.Rq5Answer = true })
+	var verr *ValidationError
+	if !errors.As(err, &verr) || strings.Join(verr.Problems, "; ") != "rq5 is yes but rq4 is no" {
+		t.Errorf("answering rq5 yes: %v", err)
+	}
+}
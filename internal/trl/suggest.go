@@ -0,0 +1,224 @@
+package trl
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"trl-research-backend/internal/models"
+)
+
+// Suggestion rules
+//
+//  1. the target is the level after the one the assessment reached
+//  2. what the questionnaire still asks for at the target level comes first (high): the
+//     readiness question when it is answered no, the criteria that aren't ticked
+//  3. the usual work of the target stage (medium), e.g. prototype testing before TRL 4
+//  4. every need ticked in the supporter form becomes a step; medium once the target
+//     reaches the stage the need belongs to, low before that
+//  5. steps with the same code are merged, keeping the higher priority and every source
+//
+// The text of each step is fixed here so the stored recommendation doesn't depend on the
+// language of the client.
+
+// stepText - the Thai and English title and detail of a step
+type stepText struct {
+	code   string
+	title  text
+	detail text
+}
+
+// stageSteps - the usual work to reach each level; 0 is the work after the top level
+var stageSteps = map[int]stepText{
+	1: {"basic_research",
+		text{"ศึกษาหลักการพื้นฐานและทบทวนวรรณกรรม", "Study the basic principles and review the literature"},
+		text{"รวบรวมงานวิจัยที่เกี่ยวข้องและสรุปหลักการทางวิทยาศาสตร์ที่ใช้", "Collect related research and write down the scientific principles involved"}},
+	2: {"concept_formulation",
+		text{"กำหนดแนวคิดและการประยุกต์ใช้เทคโนโลยี", "Formulate the technology concept and its application"},
+		text{"ระบุปัญหาที่ต้องการแก้และแนวทางการนำเทคโนโลยีไปใช้", "Describe the problem to solve and how the technology would be applied"}},
+	3: {"proof_of_concept",
+		text{"ทดลองพิสูจน์แนวคิดในห้องปฏิบัติการ", "Prove the concept experimentally in the lab"},
+		text{"ออกแบบการทดลองและเก็บผลยืนยันว่าแนวคิดใช้งานได้จริง", "Design experiments and record results showing the concept works"}},
+	4: {"prototype_testing",
+		text{"สร้างและทดสอบต้นแบบในห้องปฏิบัติการ", "Build and test a prototype in the lab"},
+		text{"ประกอบชิ้นส่วนหลักเป็นต้นแบบและทดสอบการทำงานร่วมกัน", "Integrate the key components into a prototype and test them together"}},
+	5: {"relevant_environment_testing",
+		text{"ทดสอบต้นแบบในสภาพแวดล้อมที่เกี่ยวข้อง", "Test the prototype in a relevant environment"},
+		text{"ทดสอบในสภาพที่ใกล้เคียงการใช้งานจริงและบันทึกผลเทียบกับเป้าหมาย", "Test under conditions close to real use and compare the results with the targets"}},
+	6: {"system_demonstration",
+		text{"สาธิตระบบต้นแบบในสภาพแวดล้อมที่เกี่ยวข้อง", "Demonstrate the system prototype in a relevant environment"},
+		text{"สาธิตต้นแบบที่มีขนาดและการทำงานใกล้เคียงผลิตภัณฑ์จริง", "Demonstrate a prototype close to the final product in size and function"}},
+	7: {"pilot",
+		text{"ทดลองใช้งานต้นแบบในสภาพแวดล้อมจริง", "Pilot the prototype in the operational environment"},
+		text{"หาพื้นที่หรือผู้ใช้งานจริงเพื่อทดลองใช้และเก็บข้อมูลผลการใช้งาน", "Find a site or real users for a pilot and collect data on its use"}},
+	8: {"certification",
+		text{"ทดสอบและขอรับรองมาตรฐานผลิตภัณฑ์", "Qualify the system and obtain certification"},
+		text{"ทดสอบตามมาตรฐานที่เกี่ยวข้องและยื่นขอการรับรองจากหน่วยงานที่กำหนด", "Test against the applicable standards and apply to the certifying body"}},
+	9: {"commercialization",
+		text{"นำเทคโนโลยีไปใช้งานจริงเชิงพาณิชย์", "Deploy the technology commercially"},
+		text{"เตรียมการผลิต การตลาด และการถ่ายทอดเทคโนโลยีสู่ผู้ใช้", "Prepare production, marketing and the transfer of the technology to its users"}},
+	0: {"scale_up",
+		text{"ขยายผลและติดตามการใช้งานจริง", "Scale up and monitor the technology in use"},
+		text{"ติดตามผลการใช้งาน ปรับปรุงผลิตภัณฑ์ และขยายตลาด", "Follow up on its use, improve the product and grow the market"}},
+}
+
+// needRule - a step for one tick box of the supporter form. from is the first target level
+// the need usually matters at.
+type needRule struct {
+	field string // JSON name of the supporter field
+	need  func(s *models.Supporter) bool
+	from  int
+	step  stepText
+}
+
+var needRules = []needRule{
+	{"need_protect_intellectual_property", func(s *models.Supporter) bool { return s.NeedProtectIntellectualProperty }, 3,
+		stepText{"ip_filing",
+			text{"ยื่นคำขอคุ้มครองทรัพย์สินทางปัญญา", "File for intellectual property protection"},
+			text{"ยื่นคำขอสิทธิบัตรหรืออนุสิทธิบัตรก่อนเผยแพร่หรือสาธิตผลงาน", "File a patent or petty patent before publishing or demonstrating the work"}}},
+	{"need_test", func(s *models.Supporter) bool { return s.NeedTest }, 4,
+		stepText{"testing_service",
+			text{"จัดหาหน่วยทดสอบสำหรับต้นแบบ", "Arrange a testing lab for the prototype"},
+			text{"ติดต่อห้องปฏิบัติการหรือหน่วยทดสอบที่มีเครื่องมือตามที่ต้องใช้", "Contact a lab or testing service with the equipment needed"}}},
+	{"need_certification", func(s *models.Supporter) bool { return s.NeedCertification }, 8,
+		stageSteps[8]},
+	{"need_co_developers", func(s *models.Supporter) bool { return s.NeedCoDevelopers }, 3,
+		stepText{"co_development",
+			text{"หาผู้ร่วมพัฒนาเทคโนโลยี", "Find co-developers"},
+			text{"หานักวิจัยหรือหน่วยงานที่มีความเชี่ยวชาญที่ยังขาดมาร่วมพัฒนา", "Bring in researchers or organisations with the expertise the team lacks"}}},
+	{"need_partners", func(s *models.Supporter) bool { return s.NeedPartners }, 6,
+		stepText{"industry_partner",
+			text{"หาพันธมิตรภาคอุตสาหกรรม", "Find industry partners"},
+			text{"หาผู้ประกอบการที่สนใจร่วมทดลองใช้ ผลิต หรือรับถ่ายทอดเทคโนโลยี", "Find companies interested in piloting, producing or licensing the technology"}}},
+	{"need_capital", func(s *models.Supporter) bool { return s.NeedCapital }, 4,
+		stepText{"funding",
+			text{"ขอทุนสนับสนุนการพัฒนา", "Apply for development funding"},
+			text{"ยื่นขอทุนวิจัยหรือทุนพัฒนาต่อยอดที่ตรงกับระดับ TRL ของผลงาน", "Apply for research or development grants that match the TRL of the work"}}},
+	{"need_guidelines", func(s *models.Supporter) bool { return s.NeedGuidelines }, 1,
+		stepText{"mentoring",
+			text{"ขอคำปรึกษาแนวทางการพัฒนา", "Get guidance on the development path"},
+			text{"นัดหมายผู้ประสานงานหรือผู้เชี่ยวชาญเพื่อวางแผนการพัฒนา", "Book a coordinator or expert to plan the development"}}},
+	{"need_activities", func(s *models.Supporter) bool { return s.NeedActivities }, 1,
+		stepText{"networking_activities",
+			text{"เข้าร่วมกิจกรรมส่งเสริมนวัตกรรม", "Join innovation support activities"},
+			text{"เข้าร่วมอบรม งานแสดงผลงาน หรือกิจกรรมจับคู่ธุรกิจ", "Attend training, showcases or business matching events"}}},
+	{"need_account", func(s *models.Supporter) bool { return s.NeedAccount }, 6,
+		stepText{"financial_planning",
+			text{"วางแผนบัญชีและการเงินของโครงการ", "Plan the accounts and finances of the project"},
+			text{"จัดทำประมาณการต้นทุนและแผนการเงินสำหรับการขยายผล", "Prepare cost estimates and a financial plan for scaling up"}}},
+}
+
+// Suggest generates the next steps for a case from its latest assessment a (answered against
+// template t) and the supporter form s of the case, which may be nil. a is a stored assessment:
+// contradictions a legacy record was saved with (see Rescore) don't stop it.
+func Suggest(t *models.QuestionnaireTemplate, a *models.AssessmentTrl, s *models.Supporter) (*models.TrlRecommendation, error) {
+	result, _, err := score(t, a)
+	if err != nil {
+		return nil, err
+	}
+	levels, err := plan(t)
+	if err != nil {
+		return nil, err
+	}
+
+	rec := &models.TrlRecommendation{
+		AssessmentID:         a.ID,
+		QuestionnaireVersion: t.Version,
+		CurrentLevel:         result.Level,
+		Steps:                []models.TrlNextStep{},
+	}
+	if result.Level < len(result.Evidence) {
+		rec.TargetLevel = result.Level + 1
+	}
+	add := func(priority string, st stepText, source string, criteria []string) {
+		for i := range rec.Steps {
+			step := &rec.Steps[i]
+			if step.Code != st.code {
+				continue
+			}
+			if priorityRank(priority) < priorityRank(step.Priority) {
+				step.Priority = priority
+			}
+			step.Sources = append(step.Sources, source)
+			return
+		}
+		rec.Steps = append(rec.Steps, models.TrlNextStep{
+			Code:     st.code,
+			Priority: priority,
+			Title:    st.title.localized(),
+			Detail:   st.detail.localized(),
+			Criteria: criteria,
+			Sources:  []string{source},
+		})
+	}
+
+	for i, ev := range result.Evidence {
+		if ev.Level != rec.TargetLevel {
+			continue
+		}
+		if !ev.ReadinessAnswer {
+			q := levels[i].readiness
+			add(models.StepPriorityHigh, stepText{"readiness",
+				text{fmt.Sprintf("บรรลุเป้าหมายของ TRL %d", ev.Level), fmt.Sprintf("Reach the TRL %d milestone", ev.Level)},
+				text{q.Text.TH, q.Text.EN}}, "readiness:"+ev.Readiness, nil)
+		}
+		if len(ev.CriteriaMissing) > 0 {
+			add(models.StepPriorityHigh, stepText{"complete_criteria",
+				text{fmt.Sprintf("ทำเกณฑ์ของ TRL %d ที่ยังขาดให้ครบ (%d ข้อ)", ev.Level, len(ev.CriteriaMissing)),
+					fmt.Sprintf("Complete the %d open TRL %d criteria", len(ev.CriteriaMissing), ev.Level)},
+				text{"รวบรวมหลักฐานของเกณฑ์ที่ยังไม่ได้ทำเครื่องหมายในแบบประเมิน", "Collect evidence for the criteria not yet ticked in the assessment"}},
+				"criteria:"+levels[i].criteria.Key, ev.CriteriaMissing)
+		}
+	}
+	add(models.StepPriorityMedium, stageSteps[rec.TargetLevel], fmt.Sprintf("stage:%d", rec.TargetLevel), nil)
+
+	if s != nil {
+		for _, rule := range needRules {
+			if !rule.need(s) {
+				continue
+			}
+			priority := models.StepPriorityLow
+			if rec.TargetLevel == 0 || rec.TargetLevel >= rule.from {
+				priority = models.StepPriorityMedium
+			}
+			add(priority, rule.step, "need:"+rule.field, nil)
+		}
+		if need := strings.TrimSpace(s.Need); need != "" {
+			add(models.StepPriorityMedium, stepText{"discuss_need",
+				text{"หารือความต้องการเพิ่มเติมกับผู้ประสานงาน", "Discuss the additional need with the coordinator"},
+				text{need, need}}, "need:need", nil)
+		}
+	}
+
+	sort.SliceStable(rec.Steps, func(i, j int) bool {
+		return priorityRank(rec.Steps[i].Priority) < priorityRank(rec.Steps[j].Priority)
+	})
+	return rec, nil
+}
+
+// SuggestionText - the steps as the numbered Thai list shown in trl_suggestion
+func SuggestionText(rec *models.TrlRecommendation) string {
+	var b strings.Builder
+	if rec.TargetLevel == 0 {
+		fmt.Fprintf(&b, "TRL %d", rec.CurrentLevel)
+	} else {
+		fmt.Fprintf(&b, "TRL %d → %d", rec.CurrentLevel, rec.TargetLevel)
+	}
+	for i, step := range rec.Steps {
+		fmt.Fprintf(&b, "\n%d. %s", i+1, step.Title.TH)
+		if len(step.Criteria) > 0 {
+			fmt.Fprintf(&b, ": %s", strings.Join(step.Criteria, ", "))
+		}
+	}
+	return b.String()
+}
+
+func priorityRank(priority string) int {
+	switch priority {
+	case models.StepPriorityHigh:
+		return 0
+	case models.StepPriorityMedium:
+		return 1
+	}
+	return 2
+}
@@ -0,0 +1,221 @@
+package trl
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"trl-research-backend/internal/models"
+)
+
+// codes - "code/priority" of every step, in order
+func codes(rec *models.TrlRecommendation) string {
+	var out []string
+	for _, s := range rec.Steps {
+		out = append(out, s.Code+"/"+s.Priority)
+	}
+	return strings.Join(out, " ")
+}
+
+func TestSuggestPerLevel(t *testing.T) {
+	tpl := BuiltInTemplate()
+	tests := []struct {
+		level, target int
+		want          string
+	}{
+		{0, 1, "readiness/high complete_criteria/high basic_research/medium"},
+		{1, 2, "readiness/high complete_criteria/high concept_formulation/medium"},
+		{2, 3, "readiness/high complete_criteria/high proof_of_concept/medium"},
+		{3, 4, "readiness/high complete_criteria/high prototype_testing/medium"},
+		{4, 5, "readiness/high complete_criteria/high relevant_environment_testing/medium"},
+		// rq5 gates TRL 5 and 6: at 5 it is answered yes already, only the checklist is left
+		{5, 6, "complete_criteria/high system_demonstration/medium"},
+		{6, 7, "readiness/high complete_criteria/high pilot/medium"},
+		{7, 8, "readiness/high complete_criteria/high certification/medium"},
+		{8, 9, "complete_criteria/high commercialization/medium"},
+		{9, 0, "scale_up/medium"},
+	}
+	for _, tt := range tests {
+		a := upTo(tpl, tt.level)
+		a.ID = "AS-00001"
+		rec, err := Suggest(tpl, a, nil)
+		if err != nil {
+			t.Fatalf("TRL %d: %v", tt.level, err)
+		}
+		if rec.CurrentLevel != tt.level || rec.TargetLevel != tt.target || rec.AssessmentID != "AS-00001" || rec.QuestionnaireVersion != 1 {
+			t.Errorf("TRL %d: current %d, target %d, %s v%d", tt.level, rec.CurrentLevel, rec.TargetLevel, rec.AssessmentID, rec.QuestionnaireVersion)
+		}
+		if got := codes(rec); got != tt.want {
+			t.Errorf("TRL %d: steps %s, want %s", tt.level, got, tt.want)
+		}
+		for _, s := range rec.Steps {
+			switch s.Code {
+			case "readiness":
+				rq := levelsOf(t, tpl)[tt.target-1].readiness
+				if s.Detail.TH != rq.Text.TH || !slices.Equal(s.Sources, []string{"readiness:" + rq.Key}) {
+					t.Errorf("TRL %d: readiness step %+v", tt.level, s)
+				}
+			case "complete_criteria":
+				cq := levelsOf(t, tpl)[tt.target-1].criteria
+				if !slices.Equal(s.Criteria, options(*cq)) || !slices.Equal(s.Sources, []string{"criteria:" + cq.Key}) {
+					t.Errorf("TRL %d: criteria step %+v", tt.level, s)
+				}
+			}
+		}
+	}
+
+	// only the criteria still open are listed
+	a := upTo(tpl, 2)
+	cq3 := options(*tpl.Question("cq3"))
+	a.SetReadiness("rq3", true)
+	a.SetCriteria("cq3", cq3[:1])
+	rec, err := Suggest(tpl, a, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if codes(rec) != "complete_criteria/high proof_of_concept/medium" || !slices.Equal(rec.Steps[0].Criteria, cq3[1:]) {
+		t.Errorf("half of cq3: %s, criteria %q", codes(rec), rec.Steps[0].Criteria)
+	}
+	if !strings.HasPrefix(rec.Steps[0].Title.EN, fmt.Sprintf("Complete the %d open TRL 3 criteria", len(cq3)-1)) {
+		t.Errorf("title %q", rec.Steps[0].Title.EN)
+	}
+}
+
+func levelsOf(t *testing.T, tpl *models.QuestionnaireTemplate) []levelPlan {
+	t.Helper()
+	levels, err := plan(tpl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return levels
+}
+
+func TestSuggestSupporterNeeds(t *testing.T) {
+	tpl := BuiltInTemplate()
+	// every need on its own: low below the level it belongs to, medium from there
+	needs := []struct {
+		field string
+		set   func(s *models.Supporter)
+		code  string
+		from  int
+	}{
+		{"need_protect_intellectual_property", func(s *models.Supporter) { s.NeedProtectIntellectualProperty = true }, "ip_filing", 3},
+		{"need_test", func(s *models.Supporter) { s.NeedTest = true }, "testing_service", 4},
+		{"need_certification", func(s *models.Supporter) { s.NeedCertification = true }, "certification", 8},
+		{"need_co_developers", func(s *models.Supporter) { s.NeedCoDevelopers = true }, "co_development", 3},
+		{"need_partners", func(s *models.Supporter) { s.NeedPartners = true }, "industry_partner", 6},
+		{"need_capital", func(s *models.Supporter) { s.NeedCapital = true }, "funding", 4},
+		{"need_guidelines", func(s *models.Supporter) { s.NeedGuidelines = true }, "mentoring", 1},
+		{"need_activities", func(s *models.Supporter) { s.NeedActivities = true }, "networking_activities", 1},
+		{"need_account", func(s *models.Supporter) { s.NeedAccount = true }, "financial_planning", 6},
+	}
+	if len(needs) != len(needRules) {
+		t.Fatalf("%d needs tested, %d rules", len(needs), len(needRules))
+	}
+	find := func(rec *models.TrlRecommendation, code string) *models.TrlNextStep {
+		for i := range rec.Steps {
+			if rec.Steps[i].Code == code {
+				return &rec.Steps[i]
+			}
+		}
+		return nil
+	}
+	for _, n := range needs {
+		for level := 0; level <= MaxLevel; level++ {
+			s := &models.Supporter{}
+			n.set(s)
+			rec, err := Suggest(tpl, upTo(tpl, level), s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := models.StepPriorityLow
+			if level+1 >= n.from || level == MaxLevel {
+				want = models.StepPriorityMedium
+			}
+			step := find(rec, n.code)
+			if step == nil || step.Priority != want || !slices.Contains(step.Sources, "need:"+n.field) {
+				t.Errorf("%s at TRL %d: %+v, want %s", n.field, level, step, want)
+			}
+		}
+	}
+
+	// every need together: one step each, ordered by priority, the free-text need last among equals
+	all := &models.Supporter{Need: "  ต้องการพื้นที่ทดลอง  "}
+	for _, n := range needs {
+		n.set(all)
+	}
+	rec, err := Suggest(tpl, upTo(tpl, 3), all)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "readiness/high complete_criteria/high prototype_testing/medium ip_filing/medium testing_service/medium " +
+		"co_development/medium funding/medium mentoring/medium networking_activities/medium discuss_need/medium " +
+		"certification/low industry_partner/low financial_planning/low"
+	if got := codes(rec); got != want {
+		t.Errorf("TRL 3, every need:\n got %s\nwant %s", got, want)
+	}
+	if step := find(rec, "discuss_need"); step.Detail.TH != "ต้องการพื้นที่ทดลอง" || step.Sources[0] != "need:need" {
+		t.Errorf("free-text need: %+v", step)
+	}
+
+	// need_certification and the TRL 8 stage are the same step: merged, with both sources
+	s := &models.Supporter{NeedCertification: true}
+	rec, err = Suggest(tpl, upTo(tpl, 7), s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if step := find(rec, "certification"); codes(rec) != "readiness/high complete_criteria/high certification/medium" ||
+		!slices.Equal(step.Sources, []string{"stage:8", "need:need_certification"}) {
+		t.Errorf("TRL 7 with need_certification: %s, sources %q", codes(rec), step.Sources)
+	}
+
+	// no supporter form, or one without needs, only the assessment counts
+	empty, _ := Suggest(tpl, upTo(tpl, 3), &models.Supporter{Need: " "})
+	none, _ := Suggest(tpl, upTo(tpl, 3), nil)
+	if codes(empty) != codes(none) {
+		t.Errorf("empty form %s, none %s", codes(empty), codes(none))
+	}
+}
+
+// a legacy record keeps the contradictions it was stored with, its case still gets next steps
+func TestSuggestForLegacyRecord(t *testing.T) {
+	tpl := BuiltInTemplate()
+	a := upTo(tpl, 3)
+	a.SetReadiness("rq2", false)
+	a.SetCriteria("cq2", nil)
+	rec, err := Suggest(tpl, a, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.CurrentLevel != 1 || codes(rec) != "readiness/high complete_criteria/high concept_formulation/medium" {
+		t.Errorf("rq3 yes after rq2 no: TRL %d, %s", rec.CurrentLevel, codes(rec))
+	}
+}
+
+func TestSuggestionText(t *testing.T) {
+	tpl := BuiltInTemplate()
+	a := upTo(tpl, 2)
+	cq3 := options(*tpl.Question("cq3"))
+	a.SetReadiness("rq3", true)
+	a.SetCriteria("cq3", cq3[:len(cq3)-1])
+	rec, err := Suggest(tpl, a, &models.Supporter{NeedGuidelines: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "TRL 2 → 3\n" +
+		fmt.Sprintf("1. ทำเกณฑ์ของ TRL 3 ที่ยังขาดให้ครบ (1 ข้อ): %s\n", cq3[len(cq3)-1]) +
+		"2. ทดลองพิสูจน์แนวคิดในห้องปฏิบัติการ\n" +
+		"3. ขอคำปรึกษาแนวทางการพัฒนา"
+	if got := SuggestionText(rec); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+
+	top, err := Suggest(tpl, upTo(tpl, MaxLevel), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := SuggestionText(top); got != "TRL 9\n1. ขยายผลและติดตามการใช้งานจริง" {
+		t.Errorf("top level: %q", got)
+	}
+}
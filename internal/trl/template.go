@@ -0,0 +1,166 @@
+package trl
+
+import (
+	"fmt"
+	"regexp"
+
+	"trl-research-backend/internal/models"
+)
+
+// MaxLevel - TRL 9 is the top of the scale
+const MaxLevel = 9
+
+var questionKey = regexp.MustCompile(`^[a-z][a-z0-9_]{0,31}$`)
+
+// levelPlan - the readiness question and checklist that decide one level
+type levelPlan struct {
+	level     int
+	readiness *models.Question
+	criteria  *models.Question
+}
+
+// plan orders the levels of a template; every level from 1 up has exactly one of each
+func plan(t *models.QuestionnaireTemplate) ([]levelPlan, error) {
+	byLevel := map[int]*levelPlan{}
+	max := 0
+	for i := range t.Questions {
+		q := &t.Questions[i]
+		for _, l := range q.Levels {
+			p := byLevel[l]
+			if p == nil {
+				p = &levelPlan{level: l}
+				byLevel[l] = p
+			}
+			if q.Type == models.QuestionReadiness {
+				p.readiness = q
+			} else {
+				p.criteria = q
+			}
+			if l > max {
+				max = l
+			}
+		}
+	}
+	var levels []levelPlan
+	for l := 1; l <= max; l++ {
+		p := byLevel[l]
+		if p == nil || p.readiness == nil || p.criteria == nil {
+			return nil, fmt.Errorf("questionnaire v%d: level %d has no readiness question or criteria", t.Version, l)
+		}
+		levels = append(levels, *p)
+	}
+	return levels, nil
+}
+
+// ValidateTemplate checks that a template can be scored: unique keys, Thai and English text,
+// and a level mapping where TRL 1 ... N each have one readiness gate and one checklist, with
+// readiness questions listed in level order (the "no yes after a no" rule depends on it)
+func ValidateTemplate(t *models.QuestionnaireTemplate) error {
+	var problems []string
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+	checkText := func(where string, txt models.LocalizedText) {
+		if txt.TH == "" || txt.EN == "" {
+			add("%s: Thai and English text are required", where)
+		}
+	}
+
+	checkText("title", t.Title)
+	if len(t.Questions) == 0 {
+		add("questions: at least one question is required")
+	}
+
+	keys := map[string]bool{}
+	readinessOf := map[int]string{}
+	criteriaOf := map[int]string{}
+	lastGated := 0
+	for i, q := range t.Questions {
+		where := fmt.Sprintf("questions[%d]", i)
+		if !questionKey.MatchString(q.Key) {
+			add("%s: key %q must be lower case letters, digits or _", where, q.Key)
+		} else if keys[q.Key] {
+			add("%s: duplicate key %q", where, q.Key)
+		} else {
+			where = q.Key
+		}
+		keys[q.Key] = true
+		checkText(where, q.Text)
+
+		for _, l := range q.Levels {
+			if l < 1 || l > MaxLevel {
+				add("%s: level %d is outside 1-%d", where, l, MaxLevel)
+			}
+		}
+
+		switch q.Type {
+		case models.QuestionReadiness:
+			if len(q.Levels) == 0 {
+				add("%s: a readiness question must gate at least one level", where)
+			}
+			if len(q.Options) > 0 {
+				add("%s: a readiness question is yes/no and has no options", where)
+			}
+			for _, l := range q.Levels {
+				if other, ok := readinessOf[l]; ok {
+					add("%s: level %d is already gated by %s", where, l, other)
+				}
+				readinessOf[l] = q.Key
+				if l <= lastGated {
+					add("%s: readiness questions must be listed in level order", where)
+				}
+				lastGated = l
+			}
+		case models.QuestionCriteria:
+			if len(q.Levels) != 1 {
+				add("%s: a criteria question belongs to exactly one level", where)
+			}
+			for _, l := range q.Levels {
+				if other, ok := criteriaOf[l]; ok {
+					add("%s: level %d already has criteria %s", where, l, other)
+				}
+				criteriaOf[l] = q.Key
+			}
+			if len(q.Options) == 0 {
+				add("%s: a criteria question needs at least one option", where)
+			}
+			values := map[string]bool{}
+			for j, o := range q.Options {
+				if o.Value == "" {
+					add("%s.options[%d]: value is required", where, j)
+				} else if values[o.Value] {
+					add("%s.options[%d]: duplicate value %q", where, j, o.Value)
+				}
+				values[o.Value] = true
+				checkText(fmt.Sprintf("%s.options[%d]", where, j), o.Text)
+			}
+		default:
+			add("%s: type must be %q or %q", where, models.QuestionReadiness, models.QuestionCriteria)
+		}
+	}
+
+	top := 0
+	for l := range readinessOf {
+		if l > top {
+			top = l
+		}
+	}
+	for l := range criteriaOf {
+		if l > top {
+			top = l
+		}
+	}
+	for l := 1; l <= top; l++ {
+		if _, ok := readinessOf[l]; !ok {
+			add("level %d has no readiness question", l)
+		}
+		if _, ok := criteriaOf[l]; !ok {
+			add("level %d has no criteria question", l)
+		}
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package trl
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"trl-research-backend/internal/models"
+)
+
+// the built-in version is the form of the fixed rq1_answer ... cq9_answer fields
+func TestBuiltInTemplateMapping(t *testing.T) {
+	tpl := BuiltInTemplate()
+	if err := ValidateTemplate(tpl); err != nil {
+		t.Fatal(err)
+	}
+	if tpl.Version != BuiltInVersion || !tpl.Published || !tpl.BuiltIn {
+		t.Errorf("version %d, published %v, built in %v", tpl.Version, tpl.Published, tpl.BuiltIn)
+	}
+
+	levels, err := plan(tpl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"rq1 cq1", "rq2 cq2", "rq3 cq3", "rq4 cq4", "rq5 cq5", "rq5 cq6", "rq6 cq7", "rq7 cq8", "rq7 cq9"}
+	var got []string
+	for _, p := range levels {
+		got = append(got, p.readiness.Key+" "+p.criteria.Key)
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("levels %q, want %q", got, want)
+	}
+
+	// every question has its fixed field, and the options are the Thai labels the frontend stores
+	for _, q := range tpl.Questions {
+		if key, ok := models.LegacyAnswerKey(q.Key + "_answer"); !ok || key != q.Key {
+			t.Errorf("%s has no %s_answer field", q.Key, q.Key)
+		}
+		for _, o := range q.Options {
+			if o.Value != o.Text.TH || o.Text.EN == "" {
+				t.Errorf("%s: option %q / %q", q.Key, o.Value, o.Text.EN)
+			}
+		}
+	}
+
+	// a record from before versions scores on it through the fixed fields
+	a := &models.AssessmentTrl{Rq1Answer: true, Cq1Answer: options(*tpl.Question("cq1"))}
+	res, err := Score(tpl, a)
+	if err != nil || res.Level != 1 || res.QuestionnaireVersion != BuiltInVersion {
+		t.Errorf("legacy record: %+v, %v", res, err)
+	}
+}
+
+func TestValidateTemplate(t *testing.T) {
+	txt := models.LocalizedText{TH: "ข้อ", EN: "item"}
+	readiness := func(key string, levels ...int) models.Question {
+		return models.Question{Key: key, Type: models.QuestionReadiness, Text: txt, Levels: levels}
+	}
+	criteria := func(key string, level int, values ...string) models.Question {
+		q := models.Question{Key: key, Type: models.QuestionCriteria, Text: txt, Levels: []int{level}}
+		for _, v := range values {
+			q.Options = append(q.Options, models.AnswerOption{Value: v, Text: txt})
+		}
+		return q
+	}
+
+	tests := []struct {
+		name      string
+		questions []models.Question
+		want      []string
+	}{
+		{"two levels", []models.Question{readiness("a", 1), readiness("b", 2), criteria("c1", 1, "x"), criteria("c2", 2, "y")}, nil},
+		{"no questions", nil, []string{"questions: at least one question is required"}},
+		{"a level without criteria", []models.Question{readiness("a", 1, 2), criteria("c1", 1, "x")}, []string{
+			"level 2 has no criteria question",
+		}},
+		{"gates out of order", []models.Question{readiness("a", 2), readiness("b", 1), criteria("c1", 1, "x"), criteria("c2", 2, "y")}, []string{
+			"b: readiness questions must be listed in level order",
+		}},
+		{"duplicate keys and options", []models.Question{readiness("a", 1), criteria("a", 1, "x", "x")}, []string{
+			`questions[1]: duplicate key "a"`,
+			`questions[1].options[1]: duplicate value "x"`,
+		}},
+		{"level 0", []models.Question{readiness("a", 0), criteria("c", 1, "x")}, []string{
+			"a: level 0 is outside 1-9",
+			"a: readiness questions must be listed in level order",
+			"level 1 has no readiness question",
+		}},
+	}
+	for _, tt := range tests {
+		err := ValidateTemplate(&models.QuestionnaireTemplate{Title: txt, Questions: tt.questions})
+		var problems []string
+		var verr *ValidationError
+		if errors.As(err, &verr) {
+			problems = verr.Problems
+		} else if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if fmt.Sprint(problems) != fmt.Sprint(tt.want) {
+			t.Errorf("%s: problems %q, want %q", tt.name, problems, tt.want)
+		}
+	}
+}
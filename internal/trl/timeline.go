@@ -0,0 +1,157 @@
+package trl
+
+import (
+	"sort"
+	"time"
+
+	"trl-research-backend/internal/models"
+)
+
+// AnswerSet - every answer of an assessment by question key, as its questionnaire sees them
+type AnswerSet struct {
+	Readiness map[string]bool
+	Criteria  map[string][]string
+}
+
+// Answers reads the answers of a for each question of t (map first, then the fixed rq/cq field)
+func Answers(t *models.QuestionnaireTemplate, a *models.AssessmentTrl) AnswerSet {
+	set := AnswerSet{Readiness: map[string]bool{}, Criteria: map[string][]string{}}
+	for _, q := range t.Questions {
+		switch q.Type {
+		case models.QuestionReadiness:
+			set.Readiness[q.Key] = a.Readiness(q.Key)
+		case models.QuestionCriteria:
+			set.Criteria[q.Key] = a.Criteria(q.Key)
+		}
+	}
+	return set
+}
+
+// ReadinessChange - a yes/no answer that flipped
+type ReadinessChange struct {
+	Key  string `json:"key"`
+	From bool   `json:"from"`
+	To   bool   `json:"to"`
+}
+
+// CriteriaChange - options ticked / unticked on one checklist
+type CriteriaChange struct {
+	Key     string   `json:"key"`
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+}
+
+// AnswerDiff - what changed between two answer sets, keys in alphabetical order
+type AnswerDiff struct {
+	Readiness []ReadinessChange `json:"readiness"`
+	Criteria  []CriteriaChange  `json:"criteria"`
+}
+
+// DiffAnswers compares two answer sets; a question only one side has counts as no / nothing ticked
+func DiffAnswers(prev, next AnswerSet) AnswerDiff {
+	diff := AnswerDiff{Readiness: []ReadinessChange{}, Criteria: []CriteriaChange{}}
+
+	for _, key := range unionKeys(prev.Readiness, next.Readiness) {
+		if prev.Readiness[key] != next.Readiness[key] {
+			diff.Readiness = append(diff.Readiness, ReadinessChange{Key: key, From: prev.Readiness[key], To: next.Readiness[key]})
+		}
+	}
+	for _, key := range unionKeys(prev.Criteria, next.Criteria) {
+		added := missingFrom(next.Criteria[key], prev.Criteria[key])
+		removed := missingFrom(prev.Criteria[key], next.Criteria[key])
+		if len(added) > 0 || len(removed) > 0 {
+			diff.Criteria = append(diff.Criteria, CriteriaChange{Key: key, Added: added, Removed: removed})
+		}
+	}
+	return diff
+}
+
+// TimelineEntry - one assessment of a case in the TRL timeline
+type TimelineEntry struct {
+	AssessmentID         string      `json:"assessment_id"`
+	QuestionnaireVersion int         `json:"questionnaire_version"`
+	Status               string      `json:"status"`
+	TrlLevel             int         `json:"trl_level"`
+	LevelChange          int         `json:"level_change"` // against the previous assessment
+	CreatedAt            time.Time   `json:"created_at"`
+	ReviewedAt           time.Time   `json:"reviewed_at"`
+	Diff                 *AnswerDiff `json:"diff"` // nil for the first assessment
+}
+
+// Timeline lists assessments (oldest first) with the level change and answer diff against the
+// one before. questionnaire looks up the template each assessment was answered against.
+func Timeline(assessments []models.AssessmentTrl, questionnaire func(version int) (*models.QuestionnaireTemplate, error)) ([]TimelineEntry, error) {
+	entries := []TimelineEntry{}
+	var prev *AnswerSet
+	prevLevel := 0
+	for i := range assessments {
+		a := &assessments[i]
+		t, err := questionnaire(a.Version())
+		if err != nil {
+			return nil, err
+		}
+		answers := Answers(t, a)
+		entry := TimelineEntry{
+			AssessmentID:         a.ID,
+			QuestionnaireVersion: a.Version(),
+			Status:               a.ReviewStatus(),
+			TrlLevel:             a.TrlLevelResult,
+			CreatedAt:            a.CreatedAt,
+			ReviewedAt:           a.ReviewedAt,
+		}
+		if prev != nil {
+			diff := DiffAnswers(*prev, answers)
+			entry.Diff = &diff
+			entry.LevelChange = a.TrlLevelResult - prevLevel
+		}
+		entries = append(entries, entry)
+		prev = &answers
+		prevLevel = a.TrlLevelResult
+	}
+	return entries, nil
+}
+
+// LatestApproved - the newest approved assessment of a list sorted oldest first, nil when none is
+func LatestApproved(assessments []models.AssessmentTrl) *models.AssessmentTrl {
+	for i := len(assessments) - 1; i >= 0; i-- {
+		if assessments[i].ReviewStatus() == models.AssessmentApproved {
+			return &assessments[i]
+		}
+	}
+	return nil
+}
+
+func unionKeys[T any](a, b map[string]T) []string {
+	seen := map[string]bool{}
+	var keys []string
+	for _, m := range []map[string]T{a, b} {
+		for k := range m {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// missingFrom - the items of list that other doesn't have, never nil
+func missingFrom(list, other []string) []string {
+	out := []string{}
+	for _, item := range list {
+		if !contains(other, item) {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package trl
+
+import (
+	"fmt"
+	"testing"
+
+	"trl-research-backend/internal/models"
+)
+
+func TestTimeline(t *testing.T) {
+	tpl := BuiltInTemplate()
+	cq1 := options(*tpl.Question("cq1"))
+	cq2 := options(*tpl.Question("cq2"))
+	assessments := []models.AssessmentTrl{
+		// a legacy record: fixed fields only, no version, no status
+		{ID: "AS-00001", TrlLevelResult: 1, Rq1Answer: true, Cq1Answer: cq1},
+		{ID: "AS-00002", TrlLevelResult: 2, Status: models.AssessmentApproved, QuestionnaireVersion: 1,
+			ReadinessAnswers: map[string]bool{"rq1": true, "rq2": true},
+			CriteriaAnswers:  map[string][]string{"cq1": cq1, "cq2": cq2}},
+		{ID: "AS-00003", TrlLevelResult: 0, Status: models.AssessmentRejected, QuestionnaireVersion: 1,
+			ReadinessAnswers: map[string]bool{"rq1": true},
+			CriteriaAnswers:  map[string][]string{"cq1": cq1[1:]}},
+	}
+	var asked []int
+	entries, err := Timeline(assessments, func(version int) (*models.QuestionnaireTemplate, error) {
+		asked = append(asked, version)
+		return tpl, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 || fmt.Sprint(asked) != "[1 1 1]" {
+		t.Fatalf("%d entries, questionnaires %v", len(entries), asked)
+	}
+
+	first, second, third := entries[0], entries[1], entries[2]
+	if first.Diff != nil || first.LevelChange != 0 || first.Status != models.AssessmentPending || first.QuestionnaireVersion != 1 {
+		t.Errorf("first: %+v", first)
+	}
+	if second.LevelChange != 1 || fmt.Sprint(second.Diff.Readiness) != "[{rq2 false true}]" ||
+		fmt.Sprint(second.Diff.Criteria) != fmt.Sprint([]CriteriaChange{{Key: "cq2", Added: cq2, Removed: []string{}}}) {
+		t.Errorf("second: %+v, diff %+v", second, *second.Diff)
+	}
+	if third.LevelChange != -2 || fmt.Sprint(third.Diff.Readiness) != "[{rq2 true false}]" ||
+		fmt.Sprint(third.Diff.Criteria) != fmt.Sprint([]CriteriaChange{
+			{Key: "cq1", Added: []string{}, Removed: cq1[:1]},
+			{Key: "cq2", Added: []string{}, Removed: cq2},
+		}) {
+		t.Errorf("third: %+v, diff %+v", third, *third.Diff)
+	}
+}
+
+func TestDiffAnswersAcrossVersions(t *testing.T) {
+	// a question only one side has is a no / nothing ticked there
+	prev := AnswerSet{Readiness: map[string]bool{"rq1": true, "old": true}, Criteria: map[string][]string{"cq1": {"a", "b"}}}
+	next := AnswerSet{Readiness: map[string]bool{"rq1": true, "new": false}, Criteria: map[string][]string{"cq1": {"b", "c"}, "extra": {}}}
+	diff := DiffAnswers(prev, next)
+	if fmt.Sprint(diff.Readiness) != "[{old true false}]" || fmt.Sprint(diff.Criteria) != "[{cq1 [c] [a]}]" {
+		t.Errorf("diff %+v", diff)
+	}
+	if diff := DiffAnswers(next, next); len(diff.Readiness) != 0 || len(diff.Criteria) != 0 {
+		t.Errorf("no change: %+v", diff)
+	}
+}
+
+func TestLatestApproved(t *testing.T) {
+	tests := []struct {
+		statuses []string
+		want     string
+	}{
+		{nil, ""},
+		{[]string{"", models.AssessmentRejected}, ""}, // pending (legacy, no status) and rejected don't count
+		{[]string{models.AssessmentApproved, models.AssessmentPending}, "AS-1"},
+		{[]string{models.AssessmentApproved, models.AssessmentApproved, models.AssessmentRejected}, "AS-2"},
+	}
+	for _, tt := range tests {
+		var assessments []models.AssessmentTrl
+		for i, s := range tt.statuses {
+			assessments = append(assessments, models.AssessmentTrl{ID: fmt.Sprintf("AS-%d", i+1), Status: s})
+		}
+		got := ""
+		if a := LatestApproved(assessments); a != nil {
+			got = a.ID
+		}
+		if got != tt.want {
+			t.Errorf("%q: got %q, want %q", tt.statuses, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"encoding/base64"
+	"math/big"
+)
+
+// JWK - RSA public key in RFC 7517 form
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// JWKSet - body of /.well-known/jwks.json
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS แปลง public key ทุกตัวที่ยังใช้ตรวจสอบได้ (รวม key เก่าที่อยู่ใน grace period)
+func (f *KeyProvider) JWKS() JWKSet {
+	set := JWKSet{Keys: []JWK{}}
+	for _, kid := range f.VerificationKIDs() {
+		pub := f.publicKeys[kid]
+		set.Keys = append(set.Keys, JWK{
+			Kty: "RSA",
+			Use: "sig",
+			Alg: "RS256",
+			Kid: kid,
+			N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		})
+	}
+	return set
+}
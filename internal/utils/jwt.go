@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 type Claims struct {
@@ -13,11 +14,12 @@ type Claims struct {
 	Role             string `json:"role"`
 	ClientID         string `json:"client_id"`
 	ClientName       string `json:"client_name"`
+	SessionID        string `json:"sid"` // server-side session, checked on every request
 	jwt.RegisteredClaims
 }
 
-// GenerateJWT
-func GenerateJWT(userID, userEmail, role, clientID, clientName, issuer, audience, kid string, ttl time.Duration, kp KeyProvider) (string, error) {
+// GenerateJWT - ttl is the full lifetime of the token (e.g. 15 * time.Minute)
+func GenerateJWT(userID, userEmail, role, clientID, clientName, sessionID, issuer, audience, kid string, ttl time.Duration, kp KeyProvider) (string, error) {
 	now := time.Now()
 
 	claims := Claims{
@@ -26,12 +28,14 @@ func GenerateJWT(userID, userEmail, role, clientID, clientName, issuer, audience
 		Role:             role,
 		ClientID:         clientID,
 		ClientName:       clientName,
+		SessionID:        sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
 			Issuer:    issuer,
 			Audience:  []string{audience},
 			IssuedAt:  jwt.NewNumericDate(now),
 			NotBefore: jwt.NewNumericDate(now.Add(-30 * time.Second)), // leeway
-			ExpiresAt: jwt.NewNumericDate(now.Add(ttl * time.Hour)),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
 		},
 	}
 
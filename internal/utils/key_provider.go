@@ -8,53 +8,187 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 )
 
 // KeyProvider จัดการ private/public key ที่ใช้เซ็นและตรวจสอบ JWT
+//
+// Keys are versioned by kid. Only activeKID signs new tokens; every other loaded public
+// key keeps verifying tokens it signed earlier until its notAfter (grace period) passes.
 type KeyProvider struct {
 	privateKeys map[string]*rsa.PrivateKey
 	publicKeys  map[string]*rsa.PublicKey
+	notAfter    map[string]time.Time // kid -> stop verifying after (retired keys only)
+	activeKID   string
 }
 
-// NewEnvKeyProvider โหลด key จาก Environment Variables (ไม่ใช้ไฟล์)
-func NewEnvKeyProvider() (*KeyProvider, error) {
-	// อ่านค่าจาก environment variables
-	privKeyB64 := os.Getenv("PRIVATE_KEY_V1_B64")
-	pubKeyB64 := os.Getenv("PUBLIC_KEY_V1_B64")
+var (
+	kidPattern     = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+	envKeyPattern  = regexp.MustCompile(`^(PRIVATE|PUBLIC)_KEY_([A-Z0-9_]+)_B64$`)
+	fileKeyPattern = regexp.MustCompile(`^(private|public)_key_([A-Za-z0-9_-]+)\.pem$`)
 
-	if privKeyB64 == "" {
-		return nil, errors.New("missing PRIVATE_KEY_V1_B64 in environment")
+	sharedKeys    *KeyProvider
+	sharedKeysErr error
+	sharedOnce    sync.Once
+)
+
+// ParseKID checks a kid and returns its one spelling: lower case with "-" as "_", the way an
+// env name PRIVATE_KEY_<KID>_B64 can carry it. "Prod-1", "PROD_1" and "prod_1" are one key.
+func ParseKID(kid string) (string, error) {
+	if !kidPattern.MatchString(kid) {
+		return "", fmt.Errorf("invalid kid %q: use letters, digits, - and _", kid)
+	}
+	return canonicalKID(kid), nil
+}
+
+func canonicalKID(kid string) string {
+	return strings.ToLower(strings.ReplaceAll(kid, "-", "_"))
+}
+
+// SharedKeyProvider โหลด key ครั้งเดียวต่อ process (เปลี่ยน key แล้วต้อง restart)
+func SharedKeyProvider() (*KeyProvider, error) {
+	sharedOnce.Do(func() {
+		sharedKeys, sharedKeysErr = NewKeyProvider()
+	})
+	return sharedKeys, sharedKeysErr
+}
+
+// NewKeyProvider โหลด key ทุกเวอร์ชันจาก
+//   - env PRIVATE_KEY_<KID>_B64 / PUBLIC_KEY_<KID>_B64 (base64 PEM, e.g. PRIVATE_KEY_V2_B64 -> kid "v2")
+//   - JWT_KEYS_DIR ที่มีไฟล์ private_key_<kid>.pem / public_key_<kid>.pem (จาก script/generate_keys.go)
+//
+// kid ทุกแหล่งผ่าน ParseKID: private_key_2024-01.pem, PRIVATE_KEY_2024_01_B64 และ
+// JWT_ACTIVE_KID=2024-01 คือ kid "2024_01" เดียวกัน
+//
+// JWT_ACTIVE_KID เลือก key ที่ใช้เซ็น (ไม่ต้องตั้งถ้ามี private key เดียว) และ
+// JWT_KEY_<KID>_NOT_AFTER (RFC3339) กำหนดวันหมด grace period ของ key เก่า
+func NewKeyProvider() (*KeyProvider, error) {
+	kp := &KeyProvider{
+		privateKeys: map[string]*rsa.PrivateKey{},
+		publicKeys:  map[string]*rsa.PublicKey{},
+		notAfter:    map[string]time.Time{},
 	}
-	if pubKeyB64 == "" {
-		return nil, errors.New("missing PUBLIC_KEY_V1_B64 in environment")
+
+	// 1️⃣ environment variables
+	for _, kv := range os.Environ() {
+		name, value, _ := strings.Cut(kv, "=")
+		m := envKeyPattern.FindStringSubmatch(name)
+		if m == nil || value == "" {
+			continue
+		}
+		pemBytes, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %v", name, err)
+		}
+		if err := kp.add(canonicalKID(m[2]), m[1] == "PRIVATE", pemBytes); err != nil {
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
 	}
 
-	// Decode จาก Base64
-	privKeyBytes, err := base64.StdEncoding.DecodeString(privKeyB64)
-	if err != nil {
-		return nil, fmt.Errorf("failed to decode private key base64: %v", err)
+	// 2️⃣ PEM directory
+	if dir := os.Getenv("JWT_KEYS_DIR"); dir != "" {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JWT_KEYS_DIR: %v", err)
+		}
+		for _, e := range entries {
+			m := fileKeyPattern.FindStringSubmatch(e.Name())
+			if m == nil || e.IsDir() {
+				continue
+			}
+			pemBytes, err := os.ReadFile(filepath.Join(dir, e.Name()))
+			if err != nil {
+				return nil, err
+			}
+			if err := kp.add(canonicalKID(m[2]), m[1] == "private", pemBytes); err != nil {
+				return nil, fmt.Errorf("%s: %v", e.Name(), err)
+			}
+		}
 	}
 
-	pubKeyBytes, err := base64.StdEncoding.DecodeString(pubKeyB64)
-	if err != nil {
-		return nil, fmt.Errorf("failed to decode public key base64: %v", err)
+	// a private key always implies its public key; a given public key must match it
+	for kid, priv := range kp.privateKeys {
+		pub, ok := kp.publicKeys[kid]
+		if !ok {
+			kp.publicKeys[kid] = &priv.PublicKey
+			continue
+		}
+		if !pub.Equal(&priv.PublicKey) {
+			return nil, fmt.Errorf("public key for kid=%s does not match its private key", kid)
+		}
 	}
 
-	// แปลงเป็น object key จริง
-	privKey, err := parseStrictPrivateKey(privKeyBytes)
-	if err != nil {
-		return nil, fmt.Errorf("invalid private key: %v", err)
+	if len(kp.publicKeys) == 0 {
+		return nil, errors.New("no JWT keys found: set PRIVATE_KEY_<KID>_B64/PUBLIC_KEY_<KID>_B64 or JWT_KEYS_DIR")
 	}
 
-	pubKey, err := parseStrictPublicKey(pubKeyBytes)
-	if err != nil {
-		return nil, fmt.Errorf("invalid public key: %v", err)
+	// 3️⃣ active (signing) key
+	if active := os.Getenv("JWT_ACTIVE_KID"); active != "" {
+		kid, err := ParseKID(active)
+		if err != nil {
+			return nil, fmt.Errorf("JWT_ACTIVE_KID: %v", err)
+		}
+		kp.activeKID = kid
+	} else {
+		if len(kp.privateKeys) != 1 {
+			return nil, fmt.Errorf("found %d private keys, set JWT_ACTIVE_KID to pick the signing key", len(kp.privateKeys))
+		}
+		for kid := range kp.privateKeys {
+			kp.activeKID = kid
+		}
+	}
+	if _, ok := kp.privateKeys[kp.activeKID]; !ok {
+		return nil, fmt.Errorf("JWT_ACTIVE_KID=%s has no private key", kp.activeKID)
+	}
+
+	// 4️⃣ grace period of retired keys
+	for kid := range kp.publicKeys {
+		envName := "JWT_KEY_" + strings.ToUpper(kid) + "_NOT_AFTER"
+		raw := os.Getenv(envName)
+		if raw == "" {
+			continue
+		}
+		if kid == kp.activeKID {
+			return nil, fmt.Errorf("%s is set but %s is the active key", envName, kid)
+		}
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %v", envName, err)
+		}
+		kp.notAfter[kid] = t
+	}
+
+	return kp, nil
+}
+
+// add parses one PEM key into the kid slot
+func (f *KeyProvider) add(kid string, private bool, pemBytes []byte) error {
+	if private {
+		if _, dup := f.privateKeys[kid]; dup {
+			return fmt.Errorf("private key for kid=%s loaded twice", kid)
+		}
+		key, err := parseStrictPrivateKey(pemBytes)
+		if err != nil {
+			return fmt.Errorf("invalid private key: %v", err)
+		}
+		f.privateKeys[kid] = key
+		return nil
 	}
 
-	return &KeyProvider{
-		privateKeys: map[string]*rsa.PrivateKey{"v1": privKey},
-		publicKeys:  map[string]*rsa.PublicKey{"v1": pubKey},
-	}, nil
+	if _, dup := f.publicKeys[kid]; dup {
+		return fmt.Errorf("public key for kid=%s loaded twice", kid)
+	}
+	key, err := parseStrictPublicKey(pemBytes)
+	if err != nil {
+		return fmt.Errorf("invalid public key: %v", err)
+	}
+	f.publicKeys[kid] = key
+	return nil
 }
 
 // parseStrictPrivateKey รองรับเฉพาะ PKCS#8 (RSA)
@@ -99,18 +233,42 @@ func parseStrictPublicKey(pemBytes []byte) (*rsa.PublicKey, error) {
 
 // GetPrivateKey ดึง private key ตาม kid
 func (f *KeyProvider) GetPrivateKey(kid string) (*rsa.PrivateKey, error) {
-	k, ok := f.privateKeys[kid]
+	k, ok := f.privateKeys[canonicalKID(kid)]
 	if !ok {
 		return nil, fmt.Errorf("private key for kid=%s not found", kid)
 	}
 	return k, nil
 }
 
-// GetPublicKey ดึง public key ตาม kid
+// GetPublicKey ดึง public key ตาม kid (key ที่หมด grace period แล้วถือว่าไม่มี).
+// token เก่าที่ header มี kid แบบอื่น (เช่น "2024-01") ยังตรวจได้
 func (f *KeyProvider) GetPublicKey(kid string) (*rsa.PublicKey, error) {
-	k, ok := f.publicKeys[kid]
-	if !ok {
+	k, ok := f.publicKeys[canonicalKID(kid)]
+	if !ok || f.retired(canonicalKID(kid), time.Now()) {
 		return nil, fmt.Errorf("public key for kid=%s not found", kid)
 	}
 	return k, nil
 }
+
+// ActiveKID - kid ที่ใช้เซ็น token ใหม่
+func (f *KeyProvider) ActiveKID() string {
+	return f.activeKID
+}
+
+// VerificationKIDs - every kid still accepted for verification, sorted
+func (f *KeyProvider) VerificationKIDs() []string {
+	now := time.Now()
+	kids := make([]string, 0, len(f.publicKeys))
+	for kid := range f.publicKeys {
+		if !f.retired(kid, now) {
+			kids = append(kids, kid)
+		}
+	}
+	sort.Strings(kids)
+	return kids
+}
+
+func (f *KeyProvider) retired(kid string, now time.Time) bool {
+	t, ok := f.notAfter[kid]
+	return ok && now.After(t)
+}
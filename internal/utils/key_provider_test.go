@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testKeyPEM(t *testing.T) []byte {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+func TestParseKID(t *testing.T) {
+	for in, want := range map[string]string{"v1": "v1", "Prod1": "prod1", "2024-01": "2024_01", "PROD_1": "prod_1"} {
+		if got, err := ParseKID(in); err != nil || got != want {
+			t.Errorf("ParseKID(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "a/b", "v1.2", "../v1"} {
+		if _, err := ParseKID(in); err == nil {
+			t.Errorf("ParseKID(%q) accepted", in)
+		}
+	}
+}
+
+// the kid the generator prints for JWT_ACTIVE_KID finds its key from env and from files
+func TestNewKeyProviderActiveKIDSpellings(t *testing.T) {
+	keyPEM := testKeyPEM(t)
+
+	t.Run("env", func(t *testing.T) {
+		t.Setenv("JWT_KEYS_DIR", "")
+		t.Setenv("PRIVATE_KEY_2024_01_B64", base64.StdEncoding.EncodeToString(keyPEM))
+		for _, active := range []string{"2024-01", "2024_01"} {
+			t.Setenv("JWT_ACTIVE_KID", active)
+			kp, err := NewKeyProvider()
+			if err != nil {
+				t.Fatalf("JWT_ACTIVE_KID=%s: %v", active, err)
+			}
+			if kp.ActiveKID() != "2024_01" {
+				t.Errorf("JWT_ACTIVE_KID=%s: active kid %q", active, kp.ActiveKID())
+			}
+		}
+	})
+
+	t.Run("files", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "private_key_Prod1.pem"), keyPEM, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv("JWT_KEYS_DIR", dir)
+		t.Setenv("JWT_ACTIVE_KID", "Prod1")
+		kp, err := NewKeyProvider()
+		if err != nil {
+			t.Fatal(err)
+		}
+		// tokens signed before with the kid as typed still verify
+		for _, kid := range []string{"prod1", "Prod1", "PROD1"} {
+			if _, err := kp.GetPublicKey(kid); err != nil {
+				t.Errorf("GetPublicKey(%q): %v", kid, err)
+			}
+		}
+	})
+
+	t.Run("same kid twice", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "private_key_v-2.pem"), keyPEM, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv("JWT_KEYS_DIR", dir)
+		t.Setenv("PRIVATE_KEY_V_2_B64", base64.StdEncoding.EncodeToString(keyPEM))
+		t.Setenv("JWT_ACTIVE_KID", "")
+		if _, err := NewKeyProvider(); err == nil || !strings.Contains(err.Error(), "loaded twice") {
+			t.Errorf("got %v, want loaded twice", err)
+		}
+	})
+
+	t.Run("invalid active kid", func(t *testing.T) {
+		t.Setenv("JWT_KEYS_DIR", "")
+		t.Setenv("PRIVATE_KEY_V1_B64", base64.StdEncoding.EncodeToString(keyPEM))
+		t.Setenv("JWT_ACTIVE_KID", "v1.0")
+		if _, err := NewKeyProvider(); err == nil {
+			t.Error("JWT_ACTIVE_KID=v1.0 accepted")
+		}
+	})
+}